@@ -0,0 +1,153 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	mathrand "math/rand/v2"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	requestIDHeader = "X-Request-ID"
+	requestIDKey    = "request_id"
+
+	// ids passed in by a proxy longer than this are replaced rather than logged
+	maxRequestIDLength = 64
+
+	// polled by monitoring often enough to drown out everything else
+	defaultAccessLogExclude = "/health,/metrics"
+)
+
+// requestID tags the request with the id a proxy passed in X-Request-ID, or a new one, and echoes
+// it in the response so a request can be matched up with the frame's logs
+func requestID(c *gin.Context) {
+	id := c.GetHeader(requestIDHeader)
+	if !validRequestID(id) {
+		id = newRequestID()
+	}
+	c.Set(requestIDKey, id)
+	c.Header(requestIDHeader, id)
+	c.Next()
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// requestLogger returns the logger for a request, which tags entries with the request's id
+func requestLogger(c *gin.Context) *slog.Logger {
+	return slog.With(requestIDKey, c.GetString(requestIDKey))
+}
+
+// accessLogFromEnv returns middleware logging each request as configured by DPF_ACCESS_LOG,
+// DPF_ACCESS_LOG_FILE, DPF_ACCESS_LOG_SAMPLE, and DPF_ACCESS_LOG_EXCLUDE, or nil when access
+// logging is turned off
+func accessLogFromEnv() gin.HandlerFunc {
+	format := os.Getenv("DPF_ACCESS_LOG")
+	if format == "off" {
+		return nil
+	}
+
+	var out io.Writer = os.Stdout
+	if path := os.Getenv("DPF_ACCESS_LOG_FILE"); path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			slog.Warn("unable to open DPF_ACCESS_LOG_FILE, logging requests to stdout", "DPF_ACCESS_LOG_FILE", path, "error", err)
+		} else {
+			out = f
+		}
+	}
+
+	var handler slog.Handler
+	switch format {
+	case "json":
+		handler = slog.NewJSONHandler(out, nil)
+	case "", "text":
+		handler = slog.NewTextHandler(out, nil)
+	default:
+		slog.Warn("unknown DPF_ACCESS_LOG format, using text", "DPF_ACCESS_LOG", format)
+		handler = slog.NewTextHandler(out, nil)
+	}
+
+	sample := 1.0
+	if sampleStr := os.Getenv("DPF_ACCESS_LOG_SAMPLE"); sampleStr != "" {
+		parsed, err := strconv.ParseFloat(sampleStr, 64)
+		if err != nil || parsed <= 0 || parsed > 1 {
+			slog.Warn("unable to parse DPF_ACCESS_LOG_SAMPLE, logging every request", "DPF_ACCESS_LOG_SAMPLE", sampleStr)
+		} else {
+			sample = parsed
+		}
+	}
+
+	excludeStr, ok := os.LookupEnv("DPF_ACCESS_LOG_EXCLUDE")
+	if !ok {
+		excludeStr = defaultAccessLogExclude
+	}
+	exclude := mapset.NewSet[string]()
+	for path := range strings.SplitSeq(excludeStr, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			exclude.Add(path)
+		}
+	}
+
+	return newAccessLog(slog.New(handler), sample, exclude)
+}
+
+// newAccessLog logs the requests not excluded by path, keeping a sample fraction of successful
+// ones while every failed request is kept
+func newAccessLog(logger *slog.Logger, sample float64, exclude mapset.Set[string]) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		path := c.Request.URL.Path
+		status := c.Writer.Status()
+		if exclude.Contains(path) {
+			return
+		}
+		if status < http.StatusBadRequest && sample < 1 && mathrand.Float64() >= sample {
+			return
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+		// the query is left out since it can carry tokens, such as the feed token
+		logger.LogAttrs(c.Request.Context(), level, "request",
+			slog.String("method", c.Request.Method),
+			slog.String("path", path),
+			slog.Int("status", status),
+			slog.Duration("duration", time.Since(start)),
+			slog.Int("bytes", max(c.Writer.Size(), 0)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.String(requestIDKey, c.GetString(requestIDKey)),
+		)
+	}
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// events kept for the activity feed, dropping the oldest
+	maxEvents = 1000
+
+	maxFeedLimit = 100
+)
+
+// recordEvent adds an event to the activity feed. Failing to record is logged rather than failing
+// the change it's about.
+func recordEvent(db *store.Database, kind, actor string, count int, detail string) {
+	event := &store.Event{
+		Kind:       kind,
+		OccurredAt: time.Now(),
+		Actor:      actor,
+		Count:      count,
+		Detail:     detail,
+	}
+	if err := db.InsertEvent(event, maxEvents); err != nil {
+		slog.Warn("unable to record event", "kind", kind, "actor", actor, "error", err)
+	}
+}
+
+// eventMessage describes what happened in the language of the request
+func eventMessage(c *gin.Context, e store.Event) string {
+	switch e.Kind {
+	case store.EventUpload:
+		switch {
+		case e.Actor == "" && e.Count == 1:
+			return tr(c, "1 photo was added to the frame")
+		case e.Actor == "":
+			return tr(c, "%d photos were added to the frame", e.Count)
+		case e.Count == 1:
+			return tr(c, "%s added 1 photo to the frame", e.Actor)
+		default:
+			return tr(c, "%s added %d photos to the frame", e.Actor, e.Count)
+		}
+	case store.EventSync:
+		// sources without a translation, such as an rclone remote, are shown as they are
+		if e.Count == 1 {
+			return tr(c, "1 new photo from %s", tr(c, e.Actor))
+		}
+		return tr(c, "%d new photos from %s", e.Count, tr(c, e.Actor))
+	case store.EventSyncFailed:
+		return tr(c, "Syncing photos from %s failed: %v", tr(c, e.Actor), e.Detail)
+	case store.EventApproved:
+		return tr(c, "%s approved %s", e.Actor, e.Detail)
+	case store.EventRejected:
+		return tr(c, "%s rejected %s", e.Actor, e.Detail)
+	case store.EventSettings:
+		return tr(c, "%s changed the settings", e.Actor)
+	case store.EventSchedule:
+		return tr(c, "%s changed the schedule", e.Actor)
+	}
+	return e.Kind
+}
+
+// handleFeed lists what changed on the frame, newest first, a page at a time
+func (ws *WebServer) handleFeed(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid page parameter")})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > maxFeedLimit {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "limit must be between 1 and %d", maxFeedLimit)})
+		return
+	}
+
+	total, err := ws.db.GetEventCount()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
+		return
+	}
+	events, err := ws.db.GetEvents(limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
+		return
+	}
+
+	items := make([]models.FeedItem, 0, len(events))
+	for _, e := range events {
+		items = append(items, models.FeedItem{
+			Event:   e,
+			Message: eventMessage(c, e),
+		})
+	}
+	c.JSON(http.StatusOK, models.FeedResponse{
+		Items: items,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
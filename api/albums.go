@@ -0,0 +1,92 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+// maxAlbumLength limits album names to about what fits in the ui
+const maxAlbumLength = 64
+
+// albumGroups splits photos into their albums in the order each album first appears. Photos that
+// aren't in an album are grouped by category instead.
+func albumGroups(photos []store.Photo) [][]store.Photo {
+	type groupKey struct {
+		album    string
+		category int
+	}
+
+	index := make(map[groupKey]int)
+	var groups [][]store.Photo
+	for _, photo := range photos {
+		key := groupKey{album: photo.Album}
+		if photo.Album == "" {
+			key.category = photo.Category
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], photo)
+	}
+	return groups
+}
+
+// handleListAlbums lists the names of the albums with photos in them
+func (ws *WebServer) handleListAlbums(c *gin.Context) {
+	albums, err := ws.db.GetAlbums()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get albums: %v", err)})
+		return
+	}
+	if albums == nil {
+		albums = []string{}
+	}
+	c.JSON(http.StatusOK, albums)
+}
+
+// handleUpdatePhotoAlbum moves a photo into an album, or out of its album when the name is empty
+func (ws *WebServer) handleUpdatePhotoAlbum(c *gin.Context) {
+	category, name, ok := parsePhotoFileParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.PhotoAlbumRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
+		return
+	}
+	album := strings.TrimSpace(req.Album)
+	if len([]rune(album)) > maxAlbumLength {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "album must be at most %d characters", maxAlbumLength)})
+		return
+	}
+
+	exists, err := ws.db.PhotoExists(name, category)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Photo '%s' in category %d not found", name, category)})
+		return
+	}
+
+	if err := ws.db.UpdatePhotoAlbum(name, category, album, time.Time{}); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to update album: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PhotoAlbumRequest{Album: album})
+
+	// trigger slideshow restart
+	ws.requestRestart()
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+// albumThemes returns the albums with schedules where none of them cover now, which are left out
+// of the slideshow, and the albums with an exclusive schedule covering now, which are all that's
+// shown. Albums without schedules are always on.
+func albumThemes(schedules []store.AlbumSchedule, now time.Time) (off, exclusive []string) {
+	on := make(map[string]bool)
+	for _, as := range schedules {
+		active := yearlyRangeActive(as.Start, as.End, now)
+		on[as.Album] = on[as.Album] || active
+		if active && as.Exclusive && !slices.Contains(exclusive, as.Album) {
+			exclusive = append(exclusive, as.Album)
+		}
+	}
+
+	for album, isOn := range on {
+		if !isOn {
+			off = append(off, album)
+		}
+	}
+	slices.Sort(off)
+	slices.Sort(exclusive)
+	return off, exclusive
+}
+
+// applyAlbumSchedules takes the photos of albums that are off out of each group, and when albums
+// are on exclusively, the photos of every other album. Exclusive albums without any photos to show
+// leave the groups as they would be otherwise so the slideshow isn't left empty.
+func applyAlbumSchedules(groups [][]store.Photo, schedules []store.AlbumSchedule, now time.Time) [][]store.Photo {
+	off, exclusive := albumThemes(schedules, now)
+	if len(off) == 0 && len(exclusive) == 0 {
+		return groups
+	}
+
+	filter := func(keep func(store.Photo) bool) ([][]store.Photo, int) {
+		filtered := make([][]store.Photo, len(groups))
+		var n int
+		for i, group := range groups {
+			filtered[i] = slices.DeleteFunc(slices.Clone(group), func(photo store.Photo) bool {
+				return !keep(photo)
+			})
+			n += len(filtered[i])
+		}
+		return filtered, n
+	}
+
+	if len(exclusive) > 0 {
+		themed, n := filter(func(photo store.Photo) bool {
+			return slices.Contains(exclusive, photo.Album)
+		})
+		if n > 0 {
+			return themed
+		}
+	}
+	scheduled, _ := filter(func(photo store.Photo) bool {
+		return !slices.Contains(off, photo.Album)
+	})
+	return scheduled
+}
+
+func (ws *WebServer) handleListAlbumSchedules(c *gin.Context) {
+	schedules, err := ws.db.GetAlbumSchedules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get album schedules: %v", err)})
+		return
+	}
+	if schedules == nil {
+		schedules = []store.AlbumSchedule{}
+	}
+	c.JSON(http.StatusOK, schedules)
+}
+
+// bindAlbumSchedule reads and validates an album schedule from the request body, writing the error
+// response when it isn't valid
+func bindAlbumSchedule(c *gin.Context) (*store.AlbumSchedule, bool) {
+	var req store.AlbumSchedule
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
+		return nil, false
+	}
+
+	req.Album = strings.TrimSpace(req.Album)
+	if req.Album == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "album is required")})
+		return nil, false
+	}
+	if len([]rune(req.Album)) > maxAlbumLength {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "album must be at most %d characters", maxAlbumLength)})
+		return nil, false
+	}
+	if !validSeasonalDate.MatchString(req.Start) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid start date format: need 12-01, got %s", req.Start)})
+		return nil, false
+	}
+	if !validSeasonalDate.MatchString(req.End) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid end date format: need 12-31, got %s", req.End)})
+		return nil, false
+	}
+
+	return &store.AlbumSchedule{
+		Album:     req.Album,
+		Start:     req.Start,
+		End:       req.End,
+		Exclusive: req.Exclusive,
+	}, true
+}
+
+func (ws *WebServer) handleCreateAlbumSchedule(c *gin.Context) {
+	as, ok := bindAlbumSchedule(c)
+	if !ok {
+		return
+	}
+
+	if err := ws.db.InsertAlbumSchedule(as); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to create album schedule: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusCreated, as)
+
+	// a new schedule can take its album out of the slideshow as well as make it the only one shown
+	ws.requestRestart()
+}
+
+func (ws *WebServer) handleUpdateAlbumSchedule(c *gin.Context) {
+	id, ok := parseAlbumScheduleID(c)
+	if !ok {
+		return
+	}
+	as, ok := bindAlbumSchedule(c)
+	if !ok {
+		return
+	}
+	as.ID = id
+
+	updated, err := ws.db.UpdateAlbumSchedule(as)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to update album schedule: %v", err)})
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Album schedule %d not found", id)})
+		return
+	}
+
+	c.JSON(http.StatusOK, as)
+
+	ws.requestRestart()
+}
+
+func (ws *WebServer) handleDeleteAlbumSchedule(c *gin.Context) {
+	id, ok := parseAlbumScheduleID(c)
+	if !ok {
+		return
+	}
+
+	deleted, err := ws.db.DeleteAlbumSchedule(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to delete album schedule: %v", err)})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Album schedule %d not found", id)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "Album schedule %d deleted successfully", id)})
+
+	ws.requestRestart()
+}
+
+func parseAlbumScheduleID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid album schedule id")})
+		return 0, false
+	}
+	return id, true
+}
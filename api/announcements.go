@@ -0,0 +1,202 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/slideshow"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxAnnouncementLength = 200
+	maxAnnouncementLines  = 4
+
+	// announcementEvery shows an announcement after every this many photos, taking turns when
+	// there are several
+	announcementEvery = 10
+)
+
+// announcementActive reports whether now falls within the announcement's dates
+func announcementActive(a store.Announcement, now time.Time) bool {
+	today := now.Format(time.DateOnly)
+	return a.Start <= today && today <= a.End
+}
+
+// activeAnnouncementIDs lists the announcements showing as of now, to tell when that changes
+func activeAnnouncementIDs(announcements []store.Announcement, now time.Time) []int64 {
+	var ids []int64
+	for _, a := range announcements {
+		if announcementActive(a, now) {
+			ids = append(ids, a.ID)
+		}
+	}
+	return ids
+}
+
+// announcementSlides returns the slides of the announcements showing as of now, rendering any
+// that are missing, such as after the cache was cleared
+func (ws *WebServer) announcementSlides(now time.Time) ([]string, error) {
+	announcements, err := ws.db.GetAnnouncements()
+	if err != nil {
+		return nil, err
+	}
+
+	var slides []string
+	for _, a := range announcements {
+		if !announcementActive(a, now) {
+			continue
+		}
+		slide := ws.paths.Announcement(a.ID)
+		if _, err := os.Stat(slide); errors.Is(err, os.ErrNotExist) {
+			if err := slideshow.RenderSlide(slide, a.Text); err != nil {
+				slog.Warn("unable to render announcement, leaving it out", "id", a.ID, "error", err)
+				continue
+			}
+		}
+		slides = append(slides, slide)
+	}
+	return slides, nil
+}
+
+// mixSlides inserts slides into imgPaths after every so many images, taking turns between them.
+// A playlist too short to reach them all still shows each slide once at the end.
+func mixSlides(imgPaths, slides []string, every int) []string {
+	if len(slides) == 0 {
+		return imgPaths
+	}
+
+	mixed := make([]string, 0, len(imgPaths)+len(imgPaths)/every+len(slides))
+	var next int
+	for i, imgPath := range imgPaths {
+		mixed = append(mixed, imgPath)
+		if (i+1)%every == 0 {
+			mixed = append(mixed, slides[next%len(slides)])
+			next++
+		}
+	}
+	for ; next < len(slides); next++ {
+		mixed = append(mixed, slides[next])
+	}
+	return mixed
+}
+
+func (ws *WebServer) handleListAnnouncements(c *gin.Context) {
+	announcements, err := ws.db.GetAnnouncements()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get announcements: %v", err)})
+		return
+	}
+	if announcements == nil {
+		announcements = []store.Announcement{}
+	}
+	c.JSON(http.StatusOK, announcements)
+}
+
+func (ws *WebServer) handleCreateAnnouncement(c *gin.Context) {
+	var req store.Announcement
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
+		return
+	}
+
+	req.Text = strings.TrimSpace(strings.ReplaceAll(req.Text, "\r\n", "\n"))
+	if req.Text == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "text is required")})
+		return
+	}
+	if len([]rune(req.Text)) > maxAnnouncementLength || strings.Count(req.Text, "\n") >= maxAnnouncementLines {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "text must be at most %d characters on %d lines", maxAnnouncementLength, maxAnnouncementLines)})
+		return
+	}
+	if _, err := time.Parse(time.DateOnly, req.Start); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid start date format: need 2006-01-02, got %s", req.Start)})
+		return
+	}
+	if _, err := time.Parse(time.DateOnly, req.End); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid end date format: need 2006-01-02, got %s", req.End)})
+		return
+	}
+	if req.End < req.Start {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "end must not be before start")})
+		return
+	}
+
+	announcement := &store.Announcement{
+		Text:  req.Text,
+		Start: req.Start,
+		End:   req.End,
+	}
+	if err := ws.db.InsertAnnouncement(announcement); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to create announcement: %v", err)})
+		return
+	}
+	if err := slideshow.RenderSlide(ws.paths.Announcement(announcement.ID), announcement.Text); err != nil {
+		if _, delErr := ws.db.DeleteAnnouncement(announcement.ID); delErr != nil {
+			requestLogger(c).Warn("unable to remove announcement that failed to render", "id", announcement.ID, "error", delErr)
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to render announcement: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusCreated, announcement)
+
+	if announcementActive(*announcement, time.Now()) {
+		ws.requestRestart()
+	}
+}
+
+// handleAnnouncementImage serves the slide rendered for an announcement so it can be previewed
+func (ws *WebServer) handleAnnouncementImage(c *gin.Context) {
+	id, ok := parseAnnouncementID(c)
+	if !ok {
+		return
+	}
+
+	slide := ws.paths.Announcement(id)
+	if _, err := os.Stat(slide); err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Announcement %d not found", id)})
+		return
+	}
+	c.File(slide)
+}
+
+func (ws *WebServer) handleDeleteAnnouncement(c *gin.Context) {
+	id, ok := parseAnnouncementID(c)
+	if !ok {
+		return
+	}
+
+	deleted, err := ws.db.DeleteAnnouncement(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to delete announcement: %v", err)})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Announcement %d not found", id)})
+		return
+	}
+	if err := os.Remove(ws.paths.Announcement(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		requestLogger(c).Warn("unable to remove announcement slide", "id", id, "error", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "Announcement %d deleted successfully", id)})
+
+	ws.requestRestart()
+}
+
+func parseAnnouncementID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid announcement id")})
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/paths"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+// handlePendingPhotos lists the synced surprise photos and guest uploads waiting for approval
+func (ws *WebServer) handlePendingPhotos(c *gin.Context) {
+	photos := []store.Photo{}
+	for _, category := range []int{paths.CategorySurprise, paths.CategoryOriginal} {
+		for photo, err := range ws.db.AllPhotos(store.PhotoFilter{Category: category}) {
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
+				return
+			}
+			if photo.Pending {
+				photos = append(photos, photo)
+			}
+		}
+	}
+	c.JSON(http.StatusOK, photos)
+}
+
+// handleApprovePhoto lets a photo waiting for approval into the slideshow
+func (ws *WebServer) handleApprovePhoto(c *gin.Context) {
+	ws.updateApproval(c, true)
+}
+
+// handleRejectPhoto keeps a photo waiting for approval out of the slideshow by hiding it, since a
+// deleted photo would be synced again
+func (ws *WebServer) handleRejectPhoto(c *gin.Context) {
+	ws.updateApproval(c, false)
+}
+
+func (ws *WebServer) updateApproval(c *gin.Context, approved bool) {
+	category, name, ok := parsePhotoFileParams(c)
+	if !ok {
+		return
+	}
+
+	exists, err := ws.db.PhotoExists(name, category)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Photo '%s' in category %d not found", name, category)})
+		return
+	}
+
+	if err := ws.db.ApprovePhoto(name, category, approved); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to update photo: %v", err)})
+		return
+	}
+	c.Status(http.StatusOK)
+
+	kind := store.EventRejected
+	if approved {
+		kind = store.EventApproved
+	}
+	recordEvent(ws.db, kind, changedBy(c), 1, name)
+
+	if approved {
+		// trigger slideshow restart
+		ws.requestRestart()
+	}
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// assetHashLength is how many hex characters of the content hash are put in an asset's name
+const assetHashLength = 10
+
+// staticAssets serves the embedded static files under names containing a hash of their content,
+// which browsers may cache forever since a changed file gets a new name. The plain names are
+// still served for files referenced relatively, such as fonts from a stylesheet, but have to be
+// revalidated.
+type staticAssets struct {
+	// hashed maps each file's path to its path with the content hash in the name
+	hashed map[string]string
+
+	// files are the contents keyed by both the plain and hashed paths
+	files map[string]*staticFile
+}
+
+type staticFile struct {
+	content   []byte
+	etag      string
+	immutable bool
+}
+
+func newStaticAssets(fsys fs.FS) (*staticAssets, error) {
+	a := &staticAssets{
+		hashed: make(map[string]string),
+		files:  make(map[string]*staticFile),
+	}
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("unable to read static file %s, %w", name, err)
+		}
+
+		sum := sha256.Sum256(content)
+		hash := hex.EncodeToString(sum[:])[:assetHashLength]
+		ext := path.Ext(name)
+		hashedName := strings.TrimSuffix(name, ext) + "." + hash + ext
+
+		etag := `"` + hash + `"`
+		a.hashed[name] = hashedName
+		a.files[name] = &staticFile{content: content, etag: etag}
+		a.files[hashedName] = &staticFile{content: content, etag: etag, immutable: true}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// path returns the path of a static file with its content hash, relative to /static, or the
+// path as it is when there's no such file
+func (a *staticAssets) path(name string) string {
+	if hashed, ok := a.hashed[name]; ok {
+		return hashed
+	}
+	return name
+}
+
+// serve responds with a static file, letting hashed names be cached for a year
+func (a *staticAssets) serve(c *gin.Context) {
+	name := strings.TrimPrefix(c.Param("filepath"), "/")
+	file, ok := a.files[name]
+	if !ok {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	if file.immutable {
+		c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		c.Header("Cache-Control", "no-cache")
+	}
+	c.Header("ETag", file.etag)
+	http.ServeContent(c.Writer, c.Request, name, time.Time{}, bytes.NewReader(file.content))
+}
+
+// staticURL returns the url of a static file, by its path within /static, under the name with its
+// content hash
+func (ws *WebServer) staticURL(name string) string {
+	return ws.basePath + "/static/" + ws.assets.path(name)
+}
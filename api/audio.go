@@ -0,0 +1,82 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+const maxAudioVolume = 100
+
+// applyAudioDefaults trims the audio source, which is pasted in from elsewhere
+func applyAudioDefaults(s *store.AppSettings) {
+	s.AudioSource = strings.TrimSpace(s.AudioSource)
+}
+
+func validAudioVolume(volume int) bool {
+	return volume >= 0 && volume <= maxAudioVolume
+}
+
+// validAudioSource reports whether source is an internet stream or a file or directory on the
+// frame, which mpv is given as is
+func validAudioSource(source string) bool {
+	if source == "" || filepath.IsAbs(source) {
+		return true
+	}
+	u, err := url.Parse(source)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func (ws *WebServer) handleGetAudio(c *gin.Context) {
+	settings, err := ws.db.GetAppSettings()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get settings: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, ws.audioResponse(settings))
+}
+
+// handleUpdateAudioVolume changes the background audio's volume right away and remembers it in
+// the settings history, leaving the slideshow playing
+func (ws *WebServer) handleUpdateAudioVolume(c *gin.Context) {
+	var req models.AudioVolumeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
+		return
+	}
+	if !validAudioVolume(req.Volume) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "volume must be between 0 and %d", maxAudioVolume)})
+		return
+	}
+
+	settings, err := ws.db.GetAppSettings()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get settings: %v", err)})
+		return
+	}
+	settings.AudioVolume = req.Volume
+	if !ws.storeSettings(c, settings) {
+		return
+	}
+
+	if err := ws.audioManager.player.SetVolume(req.Volume); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to update volume: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, ws.audioResponse(settings))
+}
+
+func (ws *WebServer) audioResponse(settings *store.AppSettings) models.AudioResponse {
+	_, playing := ws.audioManager.player.Playing()
+	return models.AudioResponse{
+		Enabled: settings.AudioEnabled,
+		Source:  settings.AudioSource,
+		Volume:  settings.AudioVolume,
+		Playing: playing,
+	}
+}
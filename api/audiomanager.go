@@ -0,0 +1,103 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/audio"
+	"github.com/aouyang1/digitalphotoframe/slideshow"
+	"github.com/aouyang1/digitalphotoframe/store"
+)
+
+// audioInterval is how often the audio is brought in line with the schedule, which also restarts
+// mpv soon after a stream drops and it quits
+const audioInterval = 30 * time.Second
+
+// AudioManager plays the background audio while the slideshow is on screen, stopping it during
+// the schedule's quiet hours and while the display is off
+type AudioManager struct {
+	db         *store.Database
+	controller *slideshow.Controller
+	player     *audio.Player
+
+	// changed is signaled to check right away after the settings, schedule, or display change
+	changed chan bool
+}
+
+func NewAudioManager(db *store.Database, controller *slideshow.Controller, socketPath string) (*AudioManager, error) {
+	if db == nil {
+		return nil, errors.New("no database provided for audio manager")
+	}
+	if controller == nil {
+		return nil, errors.New("no slideshow controller provided for audio manager")
+	}
+
+	return &AudioManager{
+		db:         db,
+		controller: controller,
+		player:     audio.NewPlayer(socketPath),
+		changed:    make(chan bool, 1),
+	}, nil
+}
+
+// shouldPlayAudio reports whether the background audio plays at now, which is while it's turned on
+// with something to play, the display is on, and the schedule, if enabled, isn't in quiet hours
+func shouldPlayAudio(settings *store.AppSettings, schedule *store.Schedule, asleep bool, now time.Time) bool {
+	if !settings.AudioEnabled || settings.AudioSource == "" || asleep {
+		return false
+	}
+	if schedule != nil && schedule.Enabled {
+		if _, quiet := displayOffUntil(schedule, now); quiet {
+			return false
+		}
+	}
+	return true
+}
+
+// check starts or stops the background audio according to the settings and schedule
+func (a *AudioManager) check() {
+	now := time.Now()
+	settings, err := a.db.GetAppSettings()
+	if err != nil {
+		slog.Error("unable to get settings for audio", "error", err)
+		return
+	}
+	schedule, _, err := currentSchedule(a.db, now)
+	if err != nil {
+		slog.Error("unable to get schedule for audio", "error", err)
+		return
+	}
+
+	if !shouldPlayAudio(settings, schedule, a.controller.Asleep(), now) {
+		if err := a.player.Stop(); err != nil {
+			slog.Warn("unable to stop background audio", "error", err)
+		}
+		return
+	}
+	if err := a.player.Play(settings.AudioSource, settings.AudioVolume); err != nil {
+		slog.Warn("unable to play background audio", "source", settings.AudioSource, "error", err)
+	}
+}
+
+// Update has the audio brought in line with the settings, schedule, and display without waiting
+// for the next check
+func (a *AudioManager) Update() {
+	select {
+	case a.changed <- true:
+	default:
+	}
+}
+
+func (a *AudioManager) Run() {
+	ticker := time.NewTicker(audioInterval)
+
+	a.check()
+	for {
+		select {
+		case <-ticker.C:
+		case <-a.changed:
+		}
+		a.check()
+	}
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// requireAdminToken only allows requests carrying the DPF_ADMIN_TOKEN as a bearer token. The
+// protected endpoints are disabled entirely when no token is configured.
+func (ws *WebServer) requireAdminToken(c *gin.Context) {
+	if ws.adminToken == "" {
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponse{Error: tr(c, "Endpoint disabled, set DPF_ADMIN_TOKEN to enable")})
+		return
+	}
+
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(ws.adminToken)) != 1 {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponse{Error: tr(c, "Invalid or missing admin token")})
+		return
+	}
+	c.Next()
+}
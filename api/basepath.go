@@ -0,0 +1,39 @@
+package api
+
+import (
+	"net/http"
+	"os"
+	"strings"
+)
+
+// basePathFromEnv reads DPF_BASE_PATH, the path a reverse proxy serves the frame under such as
+// /frame, as a prefix starting with a slash and not ending in one
+func basePathFromEnv() string {
+	basePath := strings.Trim(os.Getenv("DPF_BASE_PATH"), "/")
+	if basePath == "" {
+		return ""
+	}
+	return "/" + basePath
+}
+
+// handler serves the routes under the base path as well as at the root, so the frame works both
+// behind a reverse proxy that passes the prefix through and when reached directly
+func (ws *WebServer) handler() http.Handler {
+	router := ws.router.Handler()
+	if ws.basePath == "" {
+		return router
+	}
+
+	stripped := http.StripPrefix(ws.basePath, router)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest, ok := strings.CutPrefix(r.URL.Path, ws.basePath)
+		switch {
+		case ok && rest == "":
+			http.Redirect(w, r, ws.basePath+"/", http.StatusMovedPermanently)
+		case ok && strings.HasPrefix(rest, "/"):
+			stripped.ServeHTTP(w, r)
+		default:
+			router.ServeHTTP(w, r)
+		}
+	})
+}
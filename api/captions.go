@@ -0,0 +1,127 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/i18n"
+	"github.com/aouyang1/digitalphotoframe/overlay"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxCaptionLength = 200
+
+	// watermarks are a single short line so they stay out of the way
+	maxWatermarkLength = 60
+)
+
+// applyOverlayDefaults fills in the overlay style for settings saved before it was configurable
+func applyOverlayDefaults(s *store.AppSettings) {
+	if s.OverlayPosition == "" {
+		s.OverlayPosition = string(overlay.BottomRight)
+	}
+	if s.OverlaySize == "" {
+		s.OverlaySize = string(overlay.Medium)
+	}
+}
+
+func validOverlay(s *store.AppSettings) bool {
+	return slices.Contains(overlay.Positions, overlay.Position(s.OverlayPosition)) &&
+		slices.Contains(overlay.Sizes, overlay.Size(s.OverlaySize))
+}
+
+// applyWatermarkDefaults trims the watermark, which is drawn on a single line
+func applyWatermarkDefaults(s *store.AppSettings) {
+	s.WatermarkText = strings.Join(strings.Fields(s.WatermarkText), " ")
+}
+
+func validWatermark(s *store.AppSettings) bool {
+	return len([]rune(s.WatermarkText)) <= maxWatermarkLength
+}
+
+func overlayOptions(s *store.AppSettings) overlay.Options {
+	return overlay.Options{
+		Position: overlay.Position(s.OverlayPosition),
+		Size:     overlay.Size(s.OverlaySize),
+	}
+}
+
+// overlayText builds the lines shown on screen over a photo according to the overlay settings,
+// returning an empty string when nothing should be shown
+func overlayText(photo store.Photo, settings *store.AppSettings) string {
+	var lines []string
+	if settings.ShowCaption && photo.Caption != "" {
+		lines = append(lines, photo.Caption)
+	}
+	if settings.ShowUploader && photo.UploadedBy != "" {
+		lines = append(lines, i18n.Translate(settings.Language, "from %s", photo.UploadedBy))
+	}
+	if settings.ShowDateTaken && !photo.TakenAt.IsZero() {
+		lines = append(lines, photo.TakenAt.Format("January 2, 2006"))
+	}
+	if settings.ShowFilename {
+		lines = append(lines, photo.PhotoName)
+	}
+	if settings.ShowReactions {
+		if text := reactionText(photo); text != "" {
+			lines = append(lines, text)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// watermarkText builds the watermark stamped on a photo from the configured text and the uploader
+// credit, returning an empty string when there is no watermark
+func watermarkText(photo store.Photo, settings *store.AppSettings) string {
+	var parts []string
+	if settings.WatermarkText != "" {
+		parts = append(parts, settings.WatermarkText)
+	}
+	if settings.WatermarkUploader && photo.UploadedBy != "" {
+		parts = append(parts, i18n.Translate(settings.Language, "Photo by %s", photo.UploadedBy))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// handleUpdatePhotoCaption sets the caption shown over a photo when captions are enabled
+func (ws *WebServer) handleUpdatePhotoCaption(c *gin.Context) {
+	category, name, ok := parsePhotoFileParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.PhotoCaptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
+		return
+	}
+	caption := strings.TrimSpace(req.Caption)
+	if len([]rune(caption)) > maxCaptionLength {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "caption must be at most %d characters", maxCaptionLength)})
+		return
+	}
+
+	exists, err := ws.db.PhotoExists(name, category)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Photo '%s' in category %d not found", name, category)})
+		return
+	}
+
+	if err := ws.db.UpdatePhotoCaption(name, category, caption); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to update caption: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PhotoCaptionRequest{Caption: caption})
+
+	// trigger slideshow restart
+	ws.requestRestart()
+}
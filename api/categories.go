@@ -0,0 +1,66 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/paths"
+	"github.com/gin-gonic/gin"
+)
+
+// handleListCategories lists every photo category with its name, photo count, and size, so
+// clients don't need to know which id is which
+func (ws *WebServer) handleListCategories(c *gin.Context) {
+	categories := make([]models.Category, 0, len(paths.Categories))
+	for _, category := range paths.Categories {
+		count, err := ws.db.GetPhotoCount(category)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get photo count: %v", err)})
+			return
+		}
+		size, err := ws.categorySize(category)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get category size: %v", err)})
+			return
+		}
+
+		categories = append(categories, models.Category{
+			ID:         category,
+			Name:       tr(c, paths.CategoryName(category)),
+			PhotoCount: count,
+			SizeBytes:  size,
+			Uploadable: ws.mirroredFrom(c, category) == "",
+		})
+	}
+	c.JSON(http.StatusOK, categories)
+}
+
+// categorySize adds up the size of a category's originals and slideshow derivatives
+func (ws *WebServer) categorySize(category int) (int64, error) {
+	var size int64
+	for _, dir := range []string{ws.paths.OriginalDir(category), ws.paths.DerivativeDir(category)} {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("unable to read directory, %s, %w", dir, err)
+		}
+
+		// the surprise directories are nested in those of My Photos, so only files are counted
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			size += info.Size()
+		}
+	}
+	return size, nil
+}
@@ -1,180 +1,224 @@
+// Package client drives a frame over its http api, for scripts, other services, and tests
 package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
-	"net/url"
-	"os"
-	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/aouyang1/digitalphotoframe/api/models"
-	"github.com/aouyang1/digitalphotoframe/store"
 )
 
-type PhotoClient struct {
-	baseURL string
-	client  *http.Client
-}
+const (
+	// requests are attempted this many times by default, doubling the wait between attempts
+	defaultAttempts   = 3
+	defaultMinBackoff = 500 * time.Millisecond
 
-func NewPhotoClient(baseURL string) *PhotoClient {
-	return &PhotoClient{
-		baseURL: baseURL,
-		client:  &http.Client{},
-	}
+	defaultTimeout = 30 * time.Second
+)
+
+// Errors that an *APIError matches with errors.Is by its status code
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrUnavailable  = errors.New("unavailable")
+)
+
+// APIError is a response from the frame with an error status
+type APIError struct {
+	StatusCode int
+
+	// Message is the error the frame responded with, or the body when it isn't an ErrorResponse
+	Message string
 }
 
-// RegisterPhoto registers an existing photo file in the database
-func (pc *PhotoClient) RegisterPhoto(photoPath string, category int) error {
-	photoName := filepath.Base(photoPath)
+func (e *APIError) Error() string {
+	return fmt.Sprintf("server returned status %d: %s", e.StatusCode, e.Message)
+}
 
-	// Check if file exists
-	if _, err := os.Stat(photoPath); os.IsNotExist(err) {
-		return fmt.Errorf("photo file does not exist: %s", photoPath)
+// Is matches the status code to one of the client's errors, such as ErrNotFound for a 404
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrUnavailable:
+		return e.StatusCode == http.StatusServiceUnavailable
 	}
+	return false
+}
 
-	reqBody := models.RegisterPhotoRequest{
-		PhotoName: photoName,
-		Category:  category,
-	}
+type PhotoClient struct {
+	baseURL string
+	client  *http.Client
 
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
-	}
+	// bearer token sent with every request for the system endpoints
+	token string
 
-	url := fmt.Sprintf("%s/photos/register", pc.baseURL)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
+	attempts   int
+	minBackoff time.Duration
+}
 
-	req.Header.Set("Content-Type", "application/json")
+// Option configures a PhotoClient
+type Option func(*PhotoClient)
 
-	resp, err := pc.client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+// WithHTTPClient sends requests with the given http client instead of one with a 30s timeout
+func WithHTTPClient(client *http.Client) Option {
+	return func(pc *PhotoClient) {
+		pc.client = client
 	}
-	defer resp.Body.Close()
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+// WithToken sends the admin token the frame was started with in DPF_ADMIN_TOKEN
+func WithToken(token string) Option {
+	return func(pc *PhotoClient) {
+		pc.token = token
 	}
+}
 
-	if resp.StatusCode != http.StatusOK {
-		var errResp models.ErrorResponse
-		if err := json.Unmarshal(body, &errResp); err == nil {
-			return fmt.Errorf("server error: %s", errResp.Error)
-		}
-		return fmt.Errorf("server returned status %d: %s", resp.StatusCode, string(body))
+// WithRetries sets how many times a request that can be safely repeated is attempted when the
+// frame can't be reached or is briefly unavailable, and the wait before the first retry
+func WithRetries(attempts int, minBackoff time.Duration) Option {
+	return func(pc *PhotoClient) {
+		pc.attempts = max(attempts, 1)
+		pc.minBackoff = minBackoff
 	}
+}
 
-	var registerResp models.RegisterPhotoResponse
-	if err := json.Unmarshal(body, &registerResp); err != nil {
-		return fmt.Errorf("failed to parse response: %w", err)
+// NewPhotoClient creates a client for the frame at baseURL, such as http://frame.local:8080 or
+// the url of a reverse proxy serving it under a path
+func NewPhotoClient(baseURL string, opts ...Option) *PhotoClient {
+	pc := &PhotoClient{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		client:     &http.Client{Timeout: defaultTimeout},
+		attempts:   defaultAttempts,
+		minBackoff: defaultMinBackoff,
 	}
+	for _, opt := range opts {
+		opt(pc)
+	}
+	return pc
+}
 
-	slog.Info("photo registered successfully", "name", photoName, "category", category, "order", registerResp.Order)
-	return nil
+// getJSON requests path and decodes the response into out
+func (pc *PhotoClient) getJSON(ctx context.Context, path string, out any) error {
+	return pc.doJSON(ctx, http.MethodGet, path, nil, out)
 }
 
-// RegisterPhotoIfNotExists registers a photo only if it doesn't already exist
-func (pc *PhotoClient) RegisterPhotoIfNotExists(photoPath string, category int) error {
-	err := pc.RegisterPhoto(photoPath, category)
-	if err != nil {
-		// Check if error is due to duplicate
-		if strings.Contains(err.Error(), "already exists") || strings.Contains(err.Error(), "409") {
-			slog.Debug("photo already registered, skipping", "path", photoPath)
-			return nil
+// doJSON sends in as the json body, if any, and decodes the response into out, if any
+func (pc *PhotoClient) doJSON(ctx context.Context, method, path string, in, out any) error {
+	var body []byte
+	contentType := ""
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
 		}
+		contentType = "application/json"
+	}
+
+	respBody, err := pc.do(ctx, method, path, contentType, body)
+	if err != nil {
 		return err
 	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
 	return nil
 }
 
-// GetPhotos retrieves all photos for a given category from the database
-func (pc *PhotoClient) GetPhotos(category int) ([]store.Photo, error) {
-	// Fetch all photos by using a large limit and paginating if needed
-	var allPhotos []store.Photo
-	page := 1
-	limit := 100
-
-	for {
-		url := fmt.Sprintf("%s/photos?category=%d&page=%d&limit=%d", pc.baseURL, category, page, limit)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
-		}
-
-		resp, err := pc.client.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("failed to send request: %w", err)
-		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read response: %w", err)
-		}
-
-		if resp.StatusCode != http.StatusOK {
-			var errResp models.ErrorResponse
-			if err := json.Unmarshal(body, &errResp); err == nil {
-				return nil, fmt.Errorf("server error: %s", errResp.Error)
-			}
-			return nil, fmt.Errorf("server returned status %d: %s", resp.StatusCode, string(body))
-		}
-
-		var listResp models.PhotoListResponse
-		if err := json.Unmarshal(body, &listResp); err != nil {
-			return nil, fmt.Errorf("failed to parse response: %w", err)
+// do sends a request, retrying those that can be repeated safely, and returns the response body.
+// Error statuses are returned as an *APIError.
+func (pc *PhotoClient) do(ctx context.Context, method, path, contentType string, body []byte) ([]byte, error) {
+	backoff := pc.minBackoff
+	for attempt := 1; ; attempt++ {
+		respBody, err := pc.send(ctx, method, path, contentType, body)
+		if err == nil || attempt >= pc.attempts || !retryable(method, err) {
+			return respBody, err
 		}
 
-		allPhotos = append(allPhotos, listResp.Photos...)
-
-		// Check if we've fetched all photos
-		if len(listResp.Photos) < limit || len(allPhotos) >= listResp.Total {
-			break
+		slog.Debug("retrying frame request", "method", method, "path", path, "attempt", attempt, "backoff", backoff, "error", err)
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w, %w", err, ctx.Err())
+		case <-time.After(backoff):
 		}
-
-		page++
+		backoff *= 2
 	}
-
-	return allPhotos, nil
 }
 
-// DeletePhoto deletes a photo from the database (and filesystem if it exists)
-func (pc *PhotoClient) DeletePhoto(name string, category int) error {
-	encodedName := url.PathEscape(name)
-	deleteURL := fmt.Sprintf("%s/photos/%s/category/%d", pc.baseURL, encodedName, category)
-	req, err := http.NewRequest("DELETE", deleteURL, nil)
+func (pc *PhotoClient) send(ctx context.Context, method, path, contentType string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, pc.baseURL+path, bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+	if pc.token != "" {
+		req.Header.Set("Authorization", "Bearer "+pc.token)
 	}
 
 	resp, err := pc.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(respBody))}
 		var errResp models.ErrorResponse
-		if err := json.Unmarshal(body, &errResp); err == nil {
-			return fmt.Errorf("server error: %s", errResp.Error)
+		if err := json.Unmarshal(respBody, &errResp); err == nil && errResp.Error != "" {
+			apiErr.Message = errResp.Error
 		}
-		return fmt.Errorf("server returned status %d: %s", resp.StatusCode, string(body))
+		return nil, apiErr
 	}
+	return respBody, nil
+}
 
-	return nil
+// retryable reports whether a failed request should be sent again. Only methods that can be
+// repeated without side effects are retried, when the frame couldn't be reached or was briefly
+// overloaded, such as while it restarts behind a proxy.
+func retryable(method string, err error) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return true
+	}
+	switch apiErr.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
 }
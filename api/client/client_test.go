@@ -0,0 +1,186 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/store"
+)
+
+func writeJSON(t *testing.T, w http.ResponseWriter, status int, v any) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRequestUnderBasePath(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/frame/settings" {
+			t.Errorf("path = %s, want /frame/settings", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q, want Bearer secret", got)
+		}
+		writeJSON(t, w, http.StatusOK, store.AppSettings{})
+	}))
+	defer srv.Close()
+
+	pc := NewPhotoClient(srv.URL+"/frame/", WithToken("secret"))
+	if _, err := pc.GetSettings(context.Background()); err != nil {
+		t.Errorf("GetSettings() error = %v", err)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    error
+		message string
+	}{
+		{"error response", http.StatusNotFound, `{"error":"Photo not found"}`, ErrNotFound, "Photo not found"},
+		{"plain body", http.StatusConflict, "already exists\n", ErrConflict, "already exists"},
+		{"forbidden", http.StatusForbidden, `{"error":"no token"}`, ErrUnauthorized, "no token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer srv.Close()
+
+			_, err := NewPhotoClient(srv.URL, WithRetries(1, 0)).GetSettings(context.Background())
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("GetSettings() error = %v, want %v", err, tt.want)
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status || apiErr.Message != tt.message {
+				t.Errorf("GetSettings() error = %#v, want status %d with message %q", err, tt.status, tt.message)
+			}
+		})
+	}
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		status   int
+		wantErr  bool
+		attempts int32
+	}{
+		{"get retried until available", http.MethodGet, http.StatusServiceUnavailable, false, 3},
+		{"get not retried on bad request", http.MethodGet, http.StatusBadRequest, true, 1},
+		{"post never retried", http.MethodPost, http.StatusServiceUnavailable, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// the frame recovers on the third attempt
+				if attempts.Add(1) < 3 {
+					w.WriteHeader(tt.status)
+					return
+				}
+				writeJSON(t, w, http.StatusOK, models.SyncResponse{})
+			}))
+			defer srv.Close()
+
+			pc := NewPhotoClient(srv.URL, WithRetries(3, 0))
+			err := pc.doJSON(context.Background(), tt.method, "/sync", nil, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("doJSON() error = %v, want error %v", err, tt.wantErr)
+			}
+			if got := attempts.Load(); got != tt.attempts {
+				t.Errorf("attempts = %d, want %d", got, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestGetPhotosPages(t *testing.T) {
+	const total = 250
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("category") != "1" {
+			t.Errorf("query = %s, want category 1", r.URL.RawQuery)
+		}
+		page, _ := strconv.Atoi(query.Get("page"))
+		limit, _ := strconv.Atoi(query.Get("limit"))
+
+		resp := models.PhotoListResponse{Total: total, Page: page, Limit: limit}
+		for i := (page - 1) * limit; i < min(page*limit, total); i++ {
+			resp.Photos = append(resp.Photos, store.Photo{PhotoName: fmt.Sprintf("%03d.jpg", i)})
+		}
+		writeJSON(t, w, http.StatusOK, resp)
+	}))
+	defer srv.Close()
+
+	photos, err := NewPhotoClient(srv.URL).GetPhotos(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("GetPhotos() error = %v", err)
+	}
+	if len(photos) != total || photos[total-1].PhotoName != "249.jpg" {
+		t.Errorf("GetPhotos() found %d photos, want all %d", len(photos), total)
+	}
+}
+
+func TestDeletePhotoAlreadyGone(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.EscapedPath() != "/photos/my%20photo.jpg/category/1" {
+			t.Errorf("request = %s %s, want DELETE /photos/my%%20photo.jpg/category/1", r.Method, r.URL.EscapedPath())
+		}
+		writeJSON(t, w, http.StatusNotFound, models.ErrorResponse{Error: "not found"})
+	}))
+	defer srv.Close()
+
+	if err := NewPhotoClient(srv.URL).DeletePhoto(context.Background(), "my photo.jpg", 1); err != nil {
+		t.Errorf("DeletePhoto() error = %v, want nil for a photo that's already gone", err)
+	}
+}
+
+func TestUploadPhoto(t *testing.T) {
+	photoPath := filepath.Join(t.TempDir(), "beach.jpg")
+	if err := os.WriteFile(photoPath, []byte("jpeg"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/upload" {
+			t.Errorf("request = %s %s, want POST /upload", r.Method, r.URL.Path)
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("FormFile() error = %v", err)
+			return
+		}
+		file.Close()
+		if header.Filename != "beach.jpg" {
+			t.Errorf("file name = %s, want beach.jpg", header.Filename)
+		}
+		if got := r.FormValue("from"); got != "grandma" {
+			t.Errorf("from = %q, want grandma", got)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	if err := NewPhotoClient(srv.URL).UploadPhoto(context.Background(), photoPath, "grandma"); err != nil {
+		t.Errorf("UploadPhoto() error = %v", err)
+	}
+}
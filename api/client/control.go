@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/store"
+)
+
+// GetSettings retrieves the slideshow and web ui settings
+func (pc *PhotoClient) GetSettings(ctx context.Context) (*store.AppSettings, error) {
+	var settings store.AppSettings
+	if err := pc.getJSON(ctx, "/settings", &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// UpdateSettings replaces the settings, restarting the slideshow with them, and returns the saved
+// settings. Fields left empty are reset to their defaults, so settings are best updated by
+// changing those from GetSettings.
+func (pc *PhotoClient) UpdateSettings(ctx context.Context, settings *store.AppSettings) (*store.AppSettings, error) {
+	var saved store.AppSettings
+	if err := pc.doJSON(ctx, http.MethodPut, "/settings", settings, &saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// GetSchedule retrieves the display on and off schedule along with the times in effect right now
+func (pc *PhotoClient) GetSchedule(ctx context.Context) (*models.ScheduleResponse, error) {
+	var schedule models.ScheduleResponse
+	if err := pc.getJSON(ctx, "/schedule", &schedule); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// UpdateSchedule sets the display's on and off times and quiet hours, returning the saved schedule
+func (pc *PhotoClient) UpdateSchedule(ctx context.Context, schedule *store.Schedule) (*store.Schedule, error) {
+	var saved store.Schedule
+	if err := pc.doJSON(ctx, http.MethodPut, "/schedule", schedule, &saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// GetDisplay reports whether the display is on
+func (pc *PhotoClient) GetDisplay(ctx context.Context) (*models.DisplayStateResponse, error) {
+	var state models.DisplayStateResponse
+	if err := pc.getJSON(ctx, "/display", &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// SetDisplay turns the display on or off
+func (pc *PhotoClient) SetDisplay(ctx context.Context, enabled bool) (*models.DisplayStateResponse, error) {
+	state := "0"
+	if enabled {
+		state = "1"
+	}
+	var resp models.DisplayStateResponse
+	if err := pc.doJSON(ctx, http.MethodPut, "/display/"+state, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// PlayFrom restarts the slideshow from a photo
+func (pc *PhotoClient) PlayFrom(ctx context.Context, name string, category int) error {
+	path := fmt.Sprintf("/slideshow/play/%s/category/%d", url.PathEscape(name), category)
+	_, err := pc.do(ctx, http.MethodPost, path, "", nil)
+	return err
+}
+
+// HoldSlideshow freezes the slideshow on the photo on screen until released
+func (pc *PhotoClient) HoldSlideshow(ctx context.Context) error {
+	return pc.doJSON(ctx, http.MethodPost, "/slideshow/hold", nil, nil)
+}
+
+// ReleaseSlideshow resumes a held slideshow
+func (pc *PhotoClient) ReleaseSlideshow(ctx context.Context) error {
+	return pc.doJSON(ctx, http.MethodPost, "/slideshow/release", nil, nil)
+}
+
+// ShowPhoto interrupts the slideshow to display a photo for the given number of minutes, or the
+// frame's default when zero. It fails with ErrConflict while the slideshow is held.
+func (pc *PhotoClient) ShowPhoto(ctx context.Context, name string, category, minutes int) (*models.SlideshowShowResponse, error) {
+	path := fmt.Sprintf("/slideshow/show/%d/%s", category, url.PathEscape(name))
+	if minutes > 0 {
+		path += fmt.Sprintf("?minutes=%d", minutes)
+	}
+	var resp models.SlideshowShowResponse
+	if err := pc.doJSON(ctx, http.MethodPost, path, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// TriggerSync starts syncing with s3 and the rclone remote in the background. It fails with
+// ErrConflict when neither is configured.
+func (pc *PhotoClient) TriggerSync(ctx context.Context) (*models.SyncResponse, error) {
+	var resp models.SyncResponse
+	if err := pc.doJSON(ctx, http.MethodPost, "/sync", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
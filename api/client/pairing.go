@@ -0,0 +1,24 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+)
+
+// GetPairedAlbum lists the photos in the album the frame shares with token, failing with
+// ErrNotFound once it's no longer shared
+func (pc *PhotoClient) GetPairedAlbum(ctx context.Context, token string) (*models.PairedAlbumResponse, error) {
+	var album models.PairedAlbumResponse
+	if err := pc.getJSON(ctx, "/pair/"+url.PathEscape(token), &album); err != nil {
+		return nil, err
+	}
+	return &album, nil
+}
+
+// DownloadPairedPhoto downloads a photo by its id from the album the frame shares with token
+func (pc *PhotoClient) DownloadPairedPhoto(ctx context.Context, token, id string) ([]byte, error) {
+	return pc.do(ctx, http.MethodGet, "/pair/"+url.PathEscape(token)+"/photos/"+url.PathEscape(id), "", nil)
+}
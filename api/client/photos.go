@@ -0,0 +1,172 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/paths"
+	"github.com/aouyang1/digitalphotoframe/store"
+)
+
+// UploadPhoto uploads a photo file to My Photos, recording who it was uploaded by if given
+func (pc *PhotoClient) UploadPhoto(ctx context.Context, photoPath, uploadedBy string) error {
+	return pc.UploadPhotoTo(ctx, photoPath, paths.CategoryOriginal, "", uploadedBy)
+}
+
+// UploadPhotoTo uploads a photo file to a category, adding it to an album if given. Uploads to
+// surprise photos fail with ErrConflict while they mirror a remote.
+func (pc *PhotoClient) UploadPhotoTo(ctx context.Context, photoPath string, category int, album, uploadedBy string) error {
+	f, err := os.Open(photoPath)
+	if err != nil {
+		return fmt.Errorf("unable to open photo, %w", err)
+	}
+	defer f.Close()
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", filepath.Base(photoPath))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if _, err := io.Copy(part, f); err != nil {
+		return fmt.Errorf("unable to read photo, %w", err)
+	}
+	fields := map[string]string{
+		"category": strconv.Itoa(category),
+		"album":    album,
+		"from":     uploadedBy,
+	}
+	for name, value := range fields {
+		if value == "" {
+			continue
+		}
+		if err := w.WriteField(name, value); err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	_, err = pc.do(ctx, http.MethodPost, "/upload", w.FormDataContentType(), body.Bytes())
+	return err
+}
+
+// RegisterPhoto registers an existing photo file in the database
+func (pc *PhotoClient) RegisterPhoto(ctx context.Context, photoPath string, category int) error {
+	photoName := filepath.Base(photoPath)
+
+	// Check if file exists
+	if _, err := os.Stat(photoPath); os.IsNotExist(err) {
+		return fmt.Errorf("photo file does not exist: %s", photoPath)
+	}
+
+	reqBody := models.RegisterPhotoRequest{
+		PhotoName: photoName,
+		Category:  category,
+	}
+
+	var registerResp models.RegisterPhotoResponse
+	if err := pc.doJSON(ctx, http.MethodPost, "/photos/register", reqBody, &registerResp); err != nil {
+		return err
+	}
+
+	slog.Info("photo registered successfully", "name", photoName, "category", category, "order", registerResp.Order)
+	return nil
+}
+
+// RegisterPhotoIfNotExists registers a photo only if it doesn't already exist
+func (pc *PhotoClient) RegisterPhotoIfNotExists(ctx context.Context, photoPath string, category int) error {
+	err := pc.RegisterPhoto(ctx, photoPath, category)
+	if errors.Is(err, ErrConflict) {
+		slog.Debug("photo already registered, skipping", "path", photoPath)
+		return nil
+	}
+	return err
+}
+
+// GetPhotos retrieves all photos for a given category from the database
+func (pc *PhotoClient) GetPhotos(ctx context.Context, category int) ([]store.Photo, error) {
+	return pc.FindPhotos(ctx, store.PhotoFilter{Category: category})
+}
+
+// FindPhotos retrieves the photos matching the filter, such as those a person uploaded since a
+// given time
+func (pc *PhotoClient) FindPhotos(ctx context.Context, filter store.PhotoFilter) ([]store.Photo, error) {
+	query := url.Values{}
+	query.Set("category", strconv.Itoa(filter.Category))
+	if !filter.AddedAfter.IsZero() {
+		query.Set("uploaded_after", filter.AddedAfter.Format(time.RFC3339))
+	}
+	if !filter.AddedBefore.IsZero() {
+		query.Set("uploaded_before", filter.AddedBefore.Format(time.RFC3339))
+	}
+	if filter.UploadedBy != "" {
+		query.Set("uploader", filter.UploadedBy)
+	}
+
+	// Fetch all photos by using a large limit and paginating if needed
+	var allPhotos []store.Photo
+	page := 1
+	limit := 100
+
+	for {
+		var listResp models.PhotoListResponse
+		query.Set("page", strconv.Itoa(page))
+		query.Set("limit", strconv.Itoa(limit))
+		if err := pc.getJSON(ctx, "/photos?"+query.Encode(), &listResp); err != nil {
+			return nil, err
+		}
+
+		allPhotos = append(allPhotos, listResp.Photos...)
+
+		// Check if we've fetched all photos
+		if len(listResp.Photos) < limit || len(allPhotos) >= listResp.Total {
+			break
+		}
+
+		page++
+	}
+
+	return allPhotos, nil
+}
+
+// ListCategories lists the photo categories with their names, photo counts, and sizes
+func (pc *PhotoClient) ListCategories(ctx context.Context) ([]models.Category, error) {
+	var categories []models.Category
+	if err := pc.getJSON(ctx, "/categories", &categories); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// GetPhoto retrieves a photo by its id, failing with ErrNotFound when there's no such photo
+func (pc *PhotoClient) GetPhoto(ctx context.Context, id string) (*store.Photo, error) {
+	var photo store.Photo
+	if err := pc.getJSON(ctx, "/photos/id/"+url.PathEscape(id), &photo); err != nil {
+		return nil, err
+	}
+	return &photo, nil
+}
+
+// DeletePhoto deletes a photo from the database (and filesystem if it exists). Deleting a photo
+// that's already gone isn't an error.
+func (pc *PhotoClient) DeletePhoto(ctx context.Context, name string, category int) error {
+	path := fmt.Sprintf("/photos/%s/category/%d", url.PathEscape(name), category)
+	if _, err := pc.do(ctx, http.MethodDelete, path, "", nil); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return nil
+}
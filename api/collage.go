@@ -0,0 +1,102 @@
+package api
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/store"
+	mapset "github.com/deckarep/golang-set/v2"
+)
+
+const (
+	// collageEvery mixes a collage into the playlist after every this many photos
+	collageEvery = 6
+
+	// collageSize is how many photos go in a collage when a group has enough, otherwise 2 are used
+	collageSize = 4
+)
+
+// collageGroups splits related photos, those in the same album or taken on the same day, into
+// groups of 4, or 2 for what's left over. Photos are grouped in a stable order so the same
+// collages are composed again after a restart and can be reused.
+func collageGroups(photos []store.Photo) [][]store.Photo {
+	related := make(map[string][]store.Photo)
+	seen := mapset.NewThreadUnsafeSet[string]()
+	for _, photo := range photos {
+		if !seen.Add(photo.ID) {
+			continue
+		}
+		var key string
+		switch {
+		case photo.Album != "":
+			key = "album:" + photo.Album
+		case !photo.TakenAt.IsZero():
+			key = "day:" + photo.TakenAt.Format("2006-01-02")
+		default:
+			continue
+		}
+		related[key] = append(related[key], photo)
+	}
+
+	var groups [][]store.Photo
+	for _, key := range slices.Sorted(maps.Keys(related)) {
+		members := related[key]
+		slices.SortFunc(members, func(a, b store.Photo) int {
+			return strings.Compare(a.ID, b.ID)
+		})
+		for len(members) >= 2 {
+			size := 2
+			if len(members) >= collageSize {
+				size = collageSize
+			}
+			groups = append(groups, members[:size])
+			members = members[size:]
+		}
+	}
+	return groups
+}
+
+// mixCollages inserts a collage of related photos into imgPaths after every collageEvery photos,
+// moving on to the next collages each day when the playlist is shuffled, so restarts during the
+// day show the same ones. It returns the playlist and the derivatives each collage is composed
+// from, keyed by the collage's path.
+func (ws *WebServer) mixCollages(imgPaths []string, photos []store.Photo, shuffled bool, now time.Time) ([]string, map[string][]string) {
+	groups := collageGroups(photos)
+	count := min(len(groups), len(imgPaths)/collageEvery)
+	if count == 0 {
+		return imgPaths, nil
+	}
+	if shuffled {
+		day := int(now.Unix() / (24 * 60 * 60))
+		start := day * count % len(groups)
+		groups = slices.Concat(groups[start:], groups[:start])
+	}
+	groups = groups[:count]
+
+	collages := make(map[string][]string, len(groups))
+	mixed := make([]string, 0, len(imgPaths)+len(groups))
+	for i, imgPath := range imgPaths {
+		mixed = append(mixed, imgPath)
+		if (i+1)%collageEvery != 0 || (i+1)/collageEvery > len(groups) {
+			continue
+		}
+
+		group := groups[(i+1)/collageEvery-1]
+		members := make([]string, len(group))
+		ids := make([]string, len(group))
+		for j, photo := range group {
+			members[j] = ws.paths.Derivative(photo.Category, photo.PhotoName)
+			ids[j] = photo.ID
+		}
+		key := sha1.Sum([]byte(strings.Join(ids, "|")))
+		collagePath := filepath.Join(ws.paths.CollagesDir(), hex.EncodeToString(key[:])+".jpg")
+		collages[collagePath] = members
+		mixed = append(mixed, collagePath)
+	}
+	return mixed, collages
+}
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// brotli above this level is too slow on the pi to be worth the smaller responses
+	brotliLevel = 4
+
+	// responses known to be smaller than this are sent as they are
+	minCompressSize = 1024
+)
+
+// compressibleTypes are the text responses worth compressing. Photos are already compressed, so
+// images other than svg are always sent as they are.
+var compressibleTypes = []string{
+	"text/html",
+	"text/css",
+	"text/plain",
+	"text/javascript",
+	"application/javascript",
+	"application/json",
+	"application/xml",
+	"application/atom+xml",
+	"image/svg+xml",
+}
+
+// uncompressedPrefixes are routes that stream or serve byte ranges, which compression would
+// buffer or break
+var uncompressedPrefixes = []string{
+	"/slideshow/stream",
+	webdavPrefix,
+}
+
+var (
+	gzipWriters   = sync.Pool{New: func() any { return gzip.NewWriter(io.Discard) }}
+	brotliWriters = sync.Pool{New: func() any { return brotli.NewWriterLevel(io.Discard, brotliLevel) }}
+)
+
+// compressor is a pooled gzip or brotli writer
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// compress encodes text responses with brotli or gzip when the client accepts it, which speeds up
+// large photo listings over the pi's wifi
+func compress(c *gin.Context) {
+	encoding := acceptedEncoding(c.GetHeader("Accept-Encoding"))
+	path := c.Request.URL.Path
+	uncompressed := slices.ContainsFunc(uncompressedPrefixes, func(prefix string) bool {
+		return strings.HasPrefix(path, prefix)
+	})
+	if encoding == "" || uncompressed || c.GetHeader("Range") != "" {
+		c.Next()
+		return
+	}
+
+	w := &compressWriter{ResponseWriter: c.Writer, encoding: encoding}
+	c.Writer = w
+	c.Next()
+
+	// gin writes its own 404 and 405 bodies after the middleware returns, which go out as they are
+	w.close()
+	c.Writer = w.ResponseWriter
+}
+
+// acceptedEncoding picks brotli over gzip from an Accept-Encoding header, returning empty when
+// the client accepts neither
+func acceptedEncoding(header string) string {
+	var gzipOK bool
+	for part := range strings.SplitSeq(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if weight, err := strconv.ParseFloat(q, 64); err == nil && weight == 0 {
+				continue
+			}
+		}
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "br":
+			return "br"
+		case "gzip":
+			gzipOK = true
+		}
+	}
+	if gzipOK {
+		return "gzip"
+	}
+	return ""
+}
+
+// compressWriter decides whether to compress on the first write, once the handler has set the
+// status and headers
+type compressWriter struct {
+	gin.ResponseWriter
+
+	encoding string
+	decided  bool
+	cw       compressor
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if !w.decided {
+		w.decide(b)
+	}
+	if w.cw == nil {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.cw.Write(b)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *compressWriter) Flush() {
+	if w.cw != nil {
+		w.cw.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *compressWriter) decide(b []byte) {
+	w.decided = true
+
+	header := w.Header()
+	// fragments rendered straight to the writer leave the type to be sniffed, which has to happen
+	// before the body is compressed
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", http.DetectContentType(b))
+	}
+	status := w.Status()
+	if header.Get("Content-Encoding") != "" || status < http.StatusOK || status == http.StatusNoContent ||
+		status == http.StatusPartialContent || status == http.StatusNotModified {
+		return
+	}
+	if length, err := strconv.Atoi(header.Get("Content-Length")); err == nil && length < minCompressSize {
+		return
+	}
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil || !slices.Contains(compressibleTypes, mediaType) {
+		return
+	}
+
+	header.Del("Content-Length")
+	header.Set("Content-Encoding", w.encoding)
+	header.Add("Vary", "Accept-Encoding")
+
+	if w.encoding == "br" {
+		w.cw = brotliWriters.Get().(*brotli.Writer)
+	} else {
+		w.cw = gzipWriters.Get().(*gzip.Writer)
+	}
+	w.cw.Reset(w.ResponseWriter)
+}
+
+// close finishes the compressed body and returns the writer to its pool
+func (w *compressWriter) close() {
+	if w.cw == nil {
+		return
+	}
+	w.cw.Close()
+	w.cw.Reset(io.Discard)
+	if w.encoding == "br" {
+		brotliWriters.Put(w.cw)
+	} else {
+		gzipWriters.Put(w.cw)
+	}
+	w.cw = nil
+}
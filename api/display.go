@@ -0,0 +1,249 @@
+package api
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/display"
+	"github.com/aouyang1/digitalphotoframe/slideshow"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+const defaultDisplayTransform = "normal"
+
+// days of display usage returned by default and at most
+const (
+	defaultUsageDays = 30
+	maxUsageDays     = 366
+)
+
+// range of output scales accepted, beyond which the slideshow is unusable
+const (
+	minDisplayScale = 0.5
+	maxDisplayScale = 4
+)
+
+// applyDisplayDefaults fills in the rotation for settings saved before it could be changed
+func applyDisplayDefaults(s *store.AppSettings) {
+	if s.DisplayTransform == "" {
+		s.DisplayTransform = defaultDisplayTransform
+	}
+	if s.DisplayScale == 0 {
+		s.DisplayScale = 1
+	}
+}
+
+// setDisplayEnabled turns the display on or off, putting the slideshow to sleep while it's off so
+// imv isn't decoding photos no one can see
+func setDisplayEnabled(controller *slideshow.Controller, enabled bool) error {
+	if err := display.UpdateEnabled(enabled); err != nil {
+		return err
+	}
+	if err := controller.SetAsleep(!enabled); err != nil {
+		slog.Warn("unable to update slideshow for display", "enabled", enabled, "error", err)
+	}
+	return nil
+}
+
+// sleepIfDisplayOff starts the slideshow asleep when the display was left off before the server
+// started, such as by quiet hours
+func (ws *WebServer) sleepIfDisplayOff() {
+	enabled, err := display.GetEnabled()
+	if err != nil {
+		slog.Warn("unable to get display state to start slideshow", "error", err)
+		return
+	}
+	if err := ws.controller.SetAsleep(!enabled); err != nil {
+		slog.Warn("unable to update slideshow for display", "enabled", enabled, "error", err)
+	}
+}
+
+func validDisplayTransform(transform string) bool {
+	return slices.Contains(display.Transforms, transform)
+}
+
+// applyDisplaySettings rotates the screen and sets its mode to what was saved since wlr-randr
+// changes don't survive a restart of the compositor
+func (ws *WebServer) applyDisplaySettings() {
+	settings, err := ws.db.GetAppSettings()
+	if err != nil {
+		slog.Warn("unable to get settings to configure display", "error", err)
+		return
+	}
+	applyDisplayDefaults(settings)
+	if settings.DisplayTransform != defaultDisplayTransform {
+		if err := display.UpdateTransform(settings.DisplayTransform); err != nil {
+			slog.Warn("unable to rotate display", "transform", settings.DisplayTransform, "error", err)
+		}
+	}
+	if settings.DisplayMode != "" || settings.DisplayScale != 1 {
+		if err := display.UpdateMode(settings.DisplayMode, settings.DisplayScale); err != nil {
+			slog.Warn("unable to set display mode", "mode", settings.DisplayMode, "scale", settings.DisplayScale, "error", err)
+		}
+	}
+}
+
+// matchDisplayMode finds the supported mode with the requested resolution, using the highest
+// refresh rate unless one was asked for
+func matchDisplayMode(modes []display.Mode, req models.DisplayModeRequest) (display.Mode, bool) {
+	var match display.Mode
+	var found bool
+	for _, mode := range modes {
+		if mode.Width != req.Width || mode.Height != req.Height {
+			continue
+		}
+		if req.Refresh > 0 && math.Abs(mode.Refresh-req.Refresh) >= 0.5 {
+			continue
+		}
+		if !found || mode.Refresh > match.Refresh {
+			match, found = mode, true
+		}
+	}
+	return match, found
+}
+
+// handleGetDisplayInfo returns what wlr-randr knows about the attached panel such as its model,
+// size, and supported modes
+func (ws *WebServer) handleGetDisplayInfo(c *gin.Context) {
+	output, err := display.GetOutput()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get display state: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.DisplayInfoResponse{Output: *output, CurrentMode: output.CurrentMode()})
+}
+
+// energyKWh estimates the energy used by a display drawing watts for seconds
+func energyKWh(seconds int64, watts float64) float64 {
+	return float64(seconds) / 3600 * watts / 1000
+}
+
+// handleGetDisplayUsage returns how long the display was on each day over the last days, 30 by
+// default, to check the schedule is working and estimate power use
+func (ws *WebServer) handleGetDisplayUsage(c *gin.Context) {
+	days := defaultUsageDays
+	if daysStr := c.Query("days"); daysStr != "" {
+		parsed, err := strconv.Atoi(daysStr)
+		if err != nil || parsed < 1 || parsed > maxUsageDays {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "days must be between 1 and %d", maxUsageDays)})
+			return
+		}
+		days = parsed
+	}
+
+	since := time.Now().AddDate(0, 0, -(days - 1)).Format(dateLayout)
+	usage, err := ws.db.GetDisplayUsage(since)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get display usage: %v", err)})
+		return
+	}
+
+	watts := ws.usageManager.Watts()
+	resp := models.DisplayUsageResponse{
+		Watts: watts,
+		On:    ws.usageManager.On(),
+		Days:  make([]models.DisplayUsageDay, 0, len(usage)),
+	}
+	for _, u := range usage {
+		resp.Days = append(resp.Days, models.DisplayUsageDay{
+			Day:       u.Day,
+			OnSeconds: u.OnSeconds,
+			EnergyKWh: energyKWh(u.OnSeconds, watts),
+		})
+		resp.TotalOnSeconds += u.OnSeconds
+	}
+	resp.TotalEnergyKWh = energyKWh(resp.TotalOnSeconds, watts)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// handleUpdateDisplayMode switches the screen to one of the modes the panel supports and
+// remembers it for the next boot
+func (ws *WebServer) handleUpdateDisplayMode(c *gin.Context) {
+	var req models.DisplayModeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
+		return
+	}
+	if req.Scale == 0 {
+		req.Scale = 1
+	}
+	if req.Scale < minDisplayScale || req.Scale > maxDisplayScale {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "scale must be between %v and %v", minDisplayScale, maxDisplayScale)})
+		return
+	}
+
+	output, err := display.GetOutput()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get display state: %v", err)})
+		return
+	}
+	mode, ok := matchDisplayMode(output.Modes, req)
+	if !ok {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "The display does not support %dx%d", req.Width, req.Height)})
+		return
+	}
+
+	if err := display.UpdateMode(mode.String(), req.Scale); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to update display mode: %v", err)})
+		return
+	}
+
+	settings, err := ws.db.GetAppSettings()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get settings: %v", err)})
+		return
+	}
+	previous := *settings
+	settings.DisplayMode = mode.String()
+	settings.DisplayScale = req.Scale
+	if err := ws.db.UpsertAppSettings(settings); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to update settings: %v", err)})
+		return
+	}
+	recordSettingsVersion(ws.db, store.HistorySettings, &previous, settings, changedBy(c))
+
+	mode.Current = true
+	c.JSON(http.StatusOK, models.DisplayModeResponse{Mode: mode, Scale: req.Scale})
+}
+
+// handleUpdateDisplayTransform rotates the screen and remembers the rotation for the next boot
+func (ws *WebServer) handleUpdateDisplayTransform(c *gin.Context) {
+	var req models.DisplayTransformRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
+		return
+	}
+	if !validDisplayTransform(req.Transform) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "transform must be one of %s", strings.Join(display.Transforms, ", "))})
+		return
+	}
+
+	if err := display.UpdateTransform(req.Transform); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to update display transform: %v", err)})
+		return
+	}
+
+	settings, err := ws.db.GetAppSettings()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get settings: %v", err)})
+		return
+	}
+	previous := *settings
+	settings.DisplayTransform = req.Transform
+	if err := ws.db.UpsertAppSettings(settings); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to update settings: %v", err)})
+		return
+	}
+	recordSettingsVersion(ws.db, store.HistorySettings, &previous, settings, changedBy(c))
+
+	c.JSON(http.StatusOK, models.DisplayTransformRequest{Transform: req.Transform})
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+const exportDateLayout = "2006-01-02"
+
+// exportDirs maps a category to the folder its photos are placed under in the export archive
+var exportDirs = map[int]string{
+	0: "surprise",
+	1: "my_photos",
+}
+
+// handleExportPhotos streams a zip of the original photos, optionally filtered by category, by tag,
+// and by the date each photo was taken, or added when that's unknown, with the since and until
+// query parameters.
+func (ws *WebServer) handleExportPhotos(c *gin.Context) {
+	categories := []int{0, 1}
+	if categoryStr := c.Query("category"); categoryStr != "" {
+		category, err := strconv.Atoi(categoryStr)
+		if err != nil || (category != 0 && category != 1) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "category must be 0 (surprise) or 1 (original)")})
+			return
+		}
+		categories = []int{category}
+	}
+
+	tag := strings.TrimSpace(c.Query("tag"))
+
+	var since, until time.Time
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		t, err := time.ParseInLocation(exportDateLayout, sinceStr, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid since date format: need 2006-01-02, got %s", sinceStr)})
+			return
+		}
+		since = t
+	}
+	if untilStr := c.Query("until"); untilStr != "" {
+		t, err := time.ParseInLocation(exportDateLayout, untilStr, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid until date format: need 2006-01-02, got %s", untilStr)})
+			return
+		}
+		// until is inclusive of the whole day
+		until = t.AddDate(0, 0, 1)
+	}
+
+	type exportFile struct {
+		path    string
+		name    string
+		modTime time.Time
+	}
+	var files []exportFile
+	for _, category := range categories {
+		for photo, err := range ws.db.AllPhotos(store.PhotoFilter{Category: category, Tag: tag}) {
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
+				return
+			}
+			if !inExportRange(photo, since, until) {
+				continue
+			}
+			filePath := ws.paths.Original(photo.Category, photo.PhotoName)
+			info, err := os.Stat(filePath)
+			if err != nil {
+				requestLogger(c).Warn("skipping photo missing from disk for export", "name", photo.PhotoName, "category", photo.Category, "error", err)
+				continue
+			}
+			files = append(files, exportFile{
+				path:    filePath,
+				name:    path.Join(exportDirs[photo.Category], photo.PhotoName),
+				modTime: info.ModTime(),
+			})
+		}
+	}
+
+	filename := fmt.Sprintf("photos-%s.zip", time.Now().Format(exportDateLayout))
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	zw := zip.NewWriter(c.Writer)
+	for _, file := range files {
+		if err := writeZipFile(zw, file.path, file.name, file.modTime); err != nil {
+			// headers are already sent so the best we can do is stop and log
+			requestLogger(c).Error("failed to write photo to export", "name", file.name, "error", err)
+			return
+		}
+	}
+	if err := zw.Close(); err != nil {
+		requestLogger(c).Error("failed to finish export archive", "error", err)
+	}
+}
+
+// inExportRange reports whether the photo was taken, or added when that's unknown, within since and
+// until, either of which is zero for no limit. Photos with neither date are left out of a range.
+func inExportRange(photo store.Photo, since, until time.Time) bool {
+	if since.IsZero() && until.IsZero() {
+		return true
+	}
+	date := photo.TakenAt
+	if date.IsZero() {
+		date = photo.AddedAt
+	}
+	if date.IsZero() {
+		return false
+	}
+	return (since.IsZero() || !date.Before(since)) && (until.IsZero() || date.Before(until))
+}
+
+func writeZipFile(zw *zip.Writer, filePath, name string, modTime time.Time) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// photos are already compressed so store them as is to save cpu on the pi
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Store,
+		Modified: modTime,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
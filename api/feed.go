@@ -0,0 +1,138 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Author  *atomAuthor `xml:"author,omitempty"`
+	Links   []atomLink  `xml:"link"`
+	Content atomContent `xml:"content"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// requireFeedToken only allows requests carrying the DPF_FEED_TOKEN in the token query parameter,
+// since feed readers can't set headers. The feed is disabled entirely when no token is configured.
+func (ws *WebServer) requireFeedToken(c *gin.Context) {
+	if ws.feedToken == "" {
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponse{Error: tr(c, "Endpoint disabled, set DPF_FEED_TOKEN to enable")})
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(c.Query("token")), []byte(ws.feedToken)) != 1 {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponse{Error: tr(c, "Invalid or missing feed token")})
+		return
+	}
+	c.Next()
+}
+
+// handlePhotoFeed publishes the recently added photos as an Atom feed so they can be followed in a
+// feed reader or automation service
+func (ws *WebServer) handlePhotoFeed(c *gin.Context) {
+	_, photos, status, msg := ws.recentPhotos(c)
+	if msg != "" {
+		c.JSON(status, models.ErrorResponse{Error: msg})
+		return
+	}
+
+	baseURL := ws.requestBaseURL(c)
+	feed := atomFeed{
+		Title:   tr(c, "New photos on the frame"),
+		ID:      baseURL + "/photos/recent.atom",
+		Updated: time.Now().UTC().Format(time.RFC3339),
+		// atom requires an author for entries without an uploader
+		Author: atomAuthor{Name: "Digital Photo Frame"},
+		Links: []atomLink{
+			{Rel: "self", Type: "application/atom+xml", Href: baseURL + "/photos/recent.atom"},
+			{Rel: "alternate", Type: "text/html", Href: baseURL + "/"},
+		},
+		Entries: make([]atomEntry, 0, len(photos)),
+	}
+	// the feed only changes when a photo is added, so the newest photo is when it was last updated
+	if len(photos) > 0 {
+		feed.Updated = photos[0].AddedAt.UTC().Format(time.RFC3339)
+	}
+
+	for _, photo := range photos {
+		feed.Entries = append(feed.Entries, photoFeedEntry(c, baseURL, photo))
+	}
+
+	out, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to build feed: %v", err)})
+		return
+	}
+	c.Data(http.StatusOK, "application/atom+xml; charset=utf-8", append([]byte(xml.Header), out...))
+}
+
+// photoFeedEntry describes a photo with its caption as the title, linking to the full image with
+// a thumbnail as the content
+func photoFeedEntry(c *gin.Context, baseURL string, photo store.Photo) atomEntry {
+	imageURL := fmt.Sprintf("%s/photos/id/%s/image", baseURL, url.PathEscape(photo.ID))
+	thumbnailURL := imageURL + "?w=400&h=400&fit=cover"
+
+	title := photo.Caption
+	if title == "" {
+		title = photo.PhotoName
+	}
+
+	var content strings.Builder
+	fmt.Fprintf(&content, `<p><a href="%s"><img src="%s" alt="%s"/></a></p>`, html.EscapeString(imageURL), html.EscapeString(thumbnailURL), html.EscapeString(photo.PhotoName))
+	if photo.UploadedBy != "" {
+		fmt.Fprintf(&content, "<p>%s</p>", html.EscapeString(tr(c, "from %s", photo.UploadedBy)))
+	}
+
+	entry := atomEntry{
+		Title: title,
+		// a photo uploaded again under the same name gets a new id, so it's a new entry
+		ID:      imageURL,
+		Updated: photo.AddedAt.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Rel: "alternate", Href: imageURL},
+			{Rel: "enclosure", Type: "image/jpeg", Href: imageURL},
+		},
+		Content: atomContent{Type: "html", Body: content.String()},
+	}
+	if photo.UploadedBy != "" {
+		entry.Author = &atomAuthor{Name: photo.UploadedBy}
+	}
+	return entry
+}
@@ -0,0 +1,326 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const (
+	fleetPublishInterval   = time.Minute
+	fleetSubscribeInterval = 5 * time.Minute
+	fleetRequestTimeout    = 30 * time.Second
+
+	defaultFleetPrefix = "fleet/"
+	fleetConfigName    = "config.json"
+
+	// fleetAuthor is who changes applied from the fleet config are recorded as in the history
+	fleetAuthor = "fleet"
+)
+
+type FleetMode string
+
+const (
+	FleetModeOff       FleetMode = ""
+	FleetModePublish   FleetMode = "publish"
+	FleetModeSubscribe FleetMode = "subscribe"
+)
+
+// FleetConfig is the document shared between frames through the fleet prefix of the s3 bucket
+type FleetConfig struct {
+	PublishedAt time.Time         `json:"published_at"`
+	Settings    store.AppSettings `json:"settings"`
+	Schedule    store.Schedule    `json:"schedule"`
+	Playlist    []store.Photo     `json:"playlist"`
+}
+
+// FleetManager publishes this frame's settings, schedule, and playlist order to a shared s3 prefix,
+// or subscribes to another frame's published configuration and applies it locally
+type FleetManager struct {
+	db *store.Database
+
+	client   *s3.Client
+	s3Bucket string
+	key      string
+	mode     FleetMode
+
+	// last published content digest or last applied etag
+	lastVersion string
+
+	Updated chan bool
+}
+
+func NewFleetManager(db *store.Database) (*FleetManager, error) {
+	f := &FleetManager{
+		db:      db,
+		mode:    FleetMode(os.Getenv("DPF_FLEET_MODE")),
+		Updated: make(chan bool),
+	}
+
+	switch f.mode {
+	case FleetModeOff:
+		return f, nil
+	case FleetModePublish, FleetModeSubscribe:
+	default:
+		return nil, fmt.Errorf("invalid DPF_FLEET_MODE %q, must be publish or subscribe", f.mode)
+	}
+
+	f.s3Bucket = os.Getenv("DPF_S3_BUCKET")
+	if f.s3Bucket == "" {
+		return nil, fmt.Errorf("no s3 bucket provided in environment variable DPF_S3_BUCKET for fleet %s", f.mode)
+	}
+
+	prefix := os.Getenv("DPF_FLEET_PREFIX")
+	if prefix == "" {
+		prefix = defaultFleetPrefix
+	}
+	f.key = path.Join(prefix, fleetConfigName)
+
+	s3Client, err := newS3Client()
+	if err != nil {
+		return nil, err
+	}
+	f.client = s3Client
+
+	return f, nil
+}
+
+func (f *FleetManager) Run() {
+	var interval time.Duration
+	var check func(ctx context.Context) error
+	switch f.mode {
+	case FleetModePublish:
+		interval, check = fleetPublishInterval, f.publish
+	case FleetModeSubscribe:
+		interval, check = fleetSubscribeInterval, f.subscribe
+	default:
+		return
+	}
+
+	slog.Info("starting fleet sync", "mode", f.mode, "bucket", f.s3Bucket, "key", f.key)
+	ticker := time.NewTicker(interval)
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), fleetRequestTimeout)
+		if err := check(ctx); err != nil {
+			slog.Warn("error while syncing fleet config", "mode", f.mode, "error", err)
+		}
+		cancel()
+		<-ticker.C
+	}
+}
+
+func (f *FleetManager) currentConfig() (*FleetConfig, error) {
+	settings, err := f.db.GetAppSettings()
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := f.db.GetSchedule()
+	if err != nil {
+		return nil, err
+	}
+
+	var playlist []store.Photo
+	for _, category := range []int{0, 1} {
+		photos, err := f.db.GetAllPhotos(category)
+		if err != nil {
+			return nil, err
+		}
+		playlist = append(playlist, photos...)
+	}
+
+	return &FleetConfig{
+		Settings: *settings,
+		Schedule: *schedule,
+		Playlist: playlist,
+	}, nil
+}
+
+// publish uploads the current configuration if it changed since the last publish
+func (f *FleetManager) publish(ctx context.Context) error {
+	cfg, err := f.currentConfig()
+	if err != nil {
+		return fmt.Errorf("unable to read current config, %w", err)
+	}
+
+	content, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(content)
+	digest := hex.EncodeToString(sum[:])
+	if digest == f.lastVersion {
+		return nil
+	}
+
+	cfg.PublishedAt = time.Now()
+	body, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(f.s3Bucket),
+		Key:         aws.String(f.key),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String("application/json"),
+	}); err != nil {
+		return fmt.Errorf("unable to publish fleet config, %w", err)
+	}
+
+	f.lastVersion = digest
+	slog.Info("published fleet config", "key", f.key)
+	return nil
+}
+
+// subscribe downloads the published configuration if it changed and applies it to this frame
+func (f *FleetManager) subscribe(ctx context.Context) error {
+	head, err := f.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(f.s3Bucket),
+		Key:    aws.String(f.key),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to check fleet config, %w", err)
+	}
+	etag := aws.ToString(head.ETag)
+	if etag == f.lastVersion {
+		return nil
+	}
+
+	output, err := f.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(f.s3Bucket),
+		Key:    aws.String(f.key),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to download fleet config, %w", err)
+	}
+	defer output.Body.Close()
+
+	body, err := io.ReadAll(output.Body)
+	if err != nil {
+		return fmt.Errorf("unable to read fleet config, %w", err)
+	}
+
+	var cfg FleetConfig
+	if err := json.Unmarshal(body, &cfg); err != nil {
+		return fmt.Errorf("unable to parse fleet config, %w", err)
+	}
+
+	if err := f.apply(&cfg); err != nil {
+		return err
+	}
+
+	f.lastVersion = etag
+	slog.Info("applied fleet config", "key", f.key, "published_at", cfg.PublishedAt)
+
+	select {
+	case f.Updated <- true:
+	default:
+	}
+	return nil
+}
+
+func (f *FleetManager) apply(cfg *FleetConfig) error {
+	if cfg.Settings.SlideshowIntervalSeconds <= 0 {
+		return fmt.Errorf("fleet config has invalid slideshow interval %d", cfg.Settings.SlideshowIntervalSeconds)
+	}
+	if cfg.Settings.IntervalJitterSeconds < 0 || cfg.Settings.IntervalJitterSeconds >= cfg.Settings.SlideshowIntervalSeconds {
+		return fmt.Errorf("fleet config has invalid interval jitter %d", cfg.Settings.IntervalJitterSeconds)
+	}
+	applyOverlayDefaults(&cfg.Settings)
+	if !validOverlay(&cfg.Settings) {
+		return fmt.Errorf("fleet config has invalid overlay position %s or size %s", cfg.Settings.OverlayPosition, cfg.Settings.OverlaySize)
+	}
+	applyWatermarkDefaults(&cfg.Settings)
+	if !validWatermark(&cfg.Settings) {
+		return fmt.Errorf("fleet config has invalid watermark %q", cfg.Settings.WatermarkText)
+	}
+	applyOrganizeDefaults(&cfg.Settings)
+	if !validOrganizeMode(cfg.Settings.AutoOrganize) {
+		return fmt.Errorf("fleet config has invalid auto organize mode %s", cfg.Settings.AutoOrganize)
+	}
+	applyPlaylistDefaults(&cfg.Settings)
+	if !validPlaylistOrder(cfg.Settings.PlaylistOrder) {
+		return fmt.Errorf("fleet config has invalid playlist order %s", cfg.Settings.PlaylistOrder)
+	}
+	if !validAlbumWeights(cfg.Settings.AlbumWeights) {
+		return fmt.Errorf("fleet config has invalid album weights %v", cfg.Settings.AlbumWeights)
+	}
+	applyPhotoOfDayDefaults(&cfg.Settings)
+	if !validScheduleTime.MatchString(cfg.Settings.PhotoOfDayTime) {
+		return fmt.Errorf("fleet config has invalid photo of the day time %s", cfg.Settings.PhotoOfDayTime)
+	}
+	applyThemeDefaults(&cfg.Settings)
+	if !validTheme(cfg.Settings.Theme) || !validAccentColor.MatchString(cfg.Settings.AccentColor) {
+		return fmt.Errorf("fleet config has invalid theme %s with accent color %s", cfg.Settings.Theme, cfg.Settings.AccentColor)
+	}
+	applyGuestbookDefaults(&cfg.Settings)
+	if !validGuestbookDays(&cfg.Settings) {
+		return fmt.Errorf("fleet config has invalid guestbook days %d", cfg.Settings.GuestbookDays)
+	}
+	applyAudioDefaults(&cfg.Settings)
+	if !validAudioVolume(cfg.Settings.AudioVolume) || !validAudioSource(cfg.Settings.AudioSource) {
+		return fmt.Errorf("fleet config has invalid audio source %q with volume %d", cfg.Settings.AudioSource, cfg.Settings.AudioVolume)
+	}
+	if !validScheduleTime.MatchString(cfg.Schedule.Start) || !validScheduleTime.MatchString(cfg.Schedule.End) {
+		return fmt.Errorf("fleet config has invalid schedule %s-%s", cfg.Schedule.Start, cfg.Schedule.End)
+	}
+	applyQuietHoursDefaults(&cfg.Schedule.QuietHours)
+	if q := cfg.Schedule.QuietHours; !validQuietAction(q.Action) || !validDimPercent(q.DimPercent) || q.SlowIntervalSeconds < 0 {
+		return fmt.Errorf("fleet config has invalid quiet hours %+v", q)
+	}
+
+	// the language, screen setup, EXIF stripping, and audio source are per frame preferences so
+	// keep whatever this frame already uses, which also leaves its slideshow copies matching how
+	// they were made. Audio is only turned on by the fleet for frames that have a source.
+	current, err := f.db.GetAppSettings()
+	if err != nil {
+		return err
+	}
+	cfg.Settings.Language = current.Language
+	cfg.Settings.DisplayTransform = current.DisplayTransform
+	cfg.Settings.DisplayMode = current.DisplayMode
+	cfg.Settings.DisplayScale = current.DisplayScale
+	cfg.Settings.StripExif = current.StripExif
+	cfg.Settings.AudioSource = current.AudioSource
+	if cfg.Settings.AudioSource == "" {
+		cfg.Settings.AudioEnabled = false
+	}
+
+	if err := f.db.UpsertAppSettings(&cfg.Settings); err != nil {
+		return err
+	}
+	recordSettingsVersion(f.db, store.HistorySettings, current, &cfg.Settings, fleetAuthor)
+	// schedule profiles are set up on each frame so keep the ones this frame has picked
+	localSchedule, err := f.db.GetSchedule()
+	if err != nil {
+		return err
+	}
+	cfg.Schedule.ActiveProfile = localSchedule.ActiveProfile
+	cfg.Schedule.TodayProfile = localSchedule.TodayProfile
+	cfg.Schedule.TodayDate = localSchedule.TodayDate
+	if err := f.db.UpsertSchedule(&cfg.Schedule); err != nil {
+		return err
+	}
+	recordSettingsVersion(f.db, store.HistorySchedule, scheduleVersion(localSchedule), scheduleVersion(&cfg.Schedule), fleetAuthor)
+
+	// only photos that also exist on this frame can be reordered. They're matched by name since
+	// photo ids are generated per frame and never line up across the fleet.
+	for _, photo := range cfg.Playlist {
+		if err := f.db.UpdatePhotoOrder(photo.PhotoName, photo.Category, photo.Order); err != nil {
+			slog.Warn("unable to apply fleet playlist order", "name", photo.PhotoName, "error", err)
+		}
+	}
+	return nil
+}
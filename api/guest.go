@@ -0,0 +1,196 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/api/web/templates"
+	"github.com/aouyang1/digitalphotoframe/paths"
+	"github.com/aouyang1/digitalphotoframe/service"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/aouyang1/digitalphotoframe/util"
+	"github.com/gin-gonic/gin"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	defaultGuestLinkExpiry = 24 * time.Hour
+	maxGuestLinkExpiry     = 14 * 24 * time.Hour
+	guestQRCodeSize        = 256
+)
+
+// handleCreateGuestLink generates a time limited link that only allows uploading photos
+func (ws *WebServer) handleCreateGuestLink(c *gin.Context) {
+	var req models.GuestLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
+		return
+	}
+
+	expiry := defaultGuestLinkExpiry
+	if req.ExpiresInHours < 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "expires_in_hours must be positive")})
+		return
+	}
+	if req.ExpiresInHours > 0 {
+		expiry = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+	if expiry > maxGuestLinkExpiry {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "expires_in_hours must be at most %d", int(maxGuestLinkExpiry.Hours()))})
+		return
+	}
+
+	token, err := util.NewToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to generate upload token: %v", err)})
+		return
+	}
+
+	uploadToken := &store.UploadToken{
+		Token:     token,
+		Label:     req.Label,
+		ExpiresAt: time.Now().Add(expiry),
+	}
+	if err := ws.db.InsertUploadToken(uploadToken); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to create guest link: %v", err)})
+		return
+	}
+
+	if err := ws.db.DeleteExpiredUploadTokens(); err != nil {
+		requestLogger(c).Warn("failed to clean up expired upload tokens", "error", err)
+	}
+
+	baseURL := ws.requestBaseURL(c)
+	c.JSON(http.StatusCreated, models.GuestLinkResponse{
+		URL:       fmt.Sprintf("%s/guest/%s", baseURL, token),
+		QRCodeURL: fmt.Sprintf("%s/guest-links/%s/qr.png", baseURL, token),
+		Token:     token,
+		ExpiresAt: uploadToken.ExpiresAt,
+	})
+}
+
+// handleGuestLinkQRCode renders the guest upload url as a QR code for guests to scan
+func (ws *WebServer) handleGuestLinkQRCode(c *gin.Context) {
+	uploadToken, ok := ws.lookupUploadToken(c)
+	if !ok {
+		return
+	}
+
+	png, err := qrcode.Encode(fmt.Sprintf("%s/guest/%s", ws.requestBaseURL(c), uploadToken.Token), qrcode.Medium, guestQRCodeSize)
+	if err != nil {
+		c.String(http.StatusInternalServerError, tr(c, "Failed to generate QR code"))
+		return
+	}
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+func (ws *WebServer) handleGuestUploadPage(c *gin.Context) {
+	uploadToken, ok := ws.lookupUploadToken(c)
+	if !ok {
+		return
+	}
+
+	component := templates.GuestUploadPage(uploadToken.Token, uploadToken.Label, "", false)
+	component.Render(c.Request.Context(), c.Writer)
+}
+
+// handleGuestUpload accepts one or more photos from a guest holding a valid upload token
+func (ws *WebServer) handleGuestUpload(c *gin.Context) {
+	uploadToken, ok := ws.lookupUploadToken(c)
+	if !ok {
+		return
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["file"]) == 0 {
+		c.Status(http.StatusBadRequest)
+		templates.GuestUploadPage(uploadToken.Token, uploadToken.Label, tr(c, "No photos were selected"), true).Render(c.Request.Context(), c.Writer)
+		return
+	}
+
+	// fall back to the link label so guest photos are still attributed to the event
+	uploadedBy := strings.TrimSpace(c.PostForm("from"))
+	if uploadedBy == "" {
+		uploadedBy = uploadToken.Label
+	}
+
+	photos, videos := pairLivePhotos(form.File["file"])
+	if len(photos) == 0 {
+		c.Status(http.StatusBadRequest)
+		templates.GuestUploadPage(uploadToken.Token, uploadToken.Label, tr(c, "No photos were selected"), true).Render(c.Request.Context(), c.Writer)
+		return
+	}
+
+	var uploaded int
+	var lastErr *ServerError
+	for _, file := range photos {
+		if srvErr := ws.saveUploadedPhoto(c, file, nil, videos[file], paths.CategoryOriginal, "", uploadedBy, true); srvErr != nil {
+			requestLogger(c).Warn("guest upload failed", "name", file.Filename, "error", srvErr.Error)
+			lastErr = srvErr
+			continue
+		}
+		uploaded++
+	}
+
+	if uploaded > 0 {
+		// trigger slideshow restart
+		ws.requestRestart()
+	}
+
+	if lastErr != nil {
+		c.Status(lastErr.StatusCode)
+		message := tr(c, "Uploaded %d of %d photos: %v", uploaded, len(photos), lastErr.Error)
+		templates.GuestUploadPage(uploadToken.Token, uploadToken.Label, message, true).Render(c.Request.Context(), c.Writer)
+		return
+	}
+
+	message := tr(c, "Thank you! Uploaded %d photos.", uploaded)
+	templates.GuestUploadPage(uploadToken.Token, uploadToken.Label, message, false).Render(c.Request.Context(), c.Writer)
+}
+
+// pairLivePhotos separates the videos picked along with photos, matching each to the photo of
+// the same name as the video of a live photo. Videos without a photo are left out.
+func pairLivePhotos(files []*multipart.FileHeader) ([]*multipart.FileHeader, map[*multipart.FileHeader]*multipart.FileHeader) {
+	stem := func(f *multipart.FileHeader) string {
+		return strings.ToLower(strings.TrimSuffix(f.Filename, filepath.Ext(f.Filename)))
+	}
+
+	byStem := make(map[string]*multipart.FileHeader)
+	var photos []*multipart.FileHeader
+	for _, file := range files {
+		if service.IsMotionVideo(file.Filename) {
+			byStem[stem(file)] = file
+			continue
+		}
+		photos = append(photos, file)
+	}
+
+	videos := make(map[*multipart.FileHeader]*multipart.FileHeader)
+	for _, photo := range photos {
+		if video, ok := byStem[stem(photo)]; ok {
+			videos[photo] = video
+		}
+	}
+	return photos, videos
+}
+
+// lookupUploadToken loads the upload token from the path, writing a not found response if it is
+// unknown or expired
+func (ws *WebServer) lookupUploadToken(c *gin.Context) (*store.UploadToken, bool) {
+	uploadToken, err := ws.db.GetUploadToken(c.Param("token"))
+	if err != nil {
+		c.String(http.StatusInternalServerError, tr(c, "Failed to look up upload link"))
+		return nil, false
+	}
+	if uploadToken == nil {
+		c.String(http.StatusNotFound, tr(c, "This upload link has expired or does not exist"))
+		return nil, false
+	}
+	return uploadToken, true
+}
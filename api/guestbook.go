@@ -0,0 +1,294 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/api/web/templates"
+	"github.com/aouyang1/digitalphotoframe/imaging"
+	"github.com/aouyang1/digitalphotoframe/slideshow"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/aouyang1/digitalphotoframe/util"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxGuestbookMessageLength = 200
+	maxGuestbookNameLength    = 64
+
+	// guestbookLineLength wraps messages, which are typed on a phone without line breaks, so the
+	// slide doesn't shrink them to a single unreadable line
+	guestbookLineLength = 32
+
+	defaultGuestbookDays = 7
+	maxGuestbookDays     = 365
+
+	// selfieMaxDim is as large as a selfie is kept, since it only fills half a slide
+	selfieMaxDim = 1024
+)
+
+// applyGuestbookDefaults shows guestbook messages for the default number of days for settings
+// saved before it was configurable
+func applyGuestbookDefaults(s *store.AppSettings) {
+	if s.GuestbookDays == 0 {
+		s.GuestbookDays = defaultGuestbookDays
+	}
+}
+
+func validGuestbookDays(s *store.AppSettings) bool {
+	return s.GuestbookDays >= 1 && s.GuestbookDays <= maxGuestbookDays
+}
+
+// guestbookShowing reports whether the message is still mixed into the slideshow as of now
+func guestbookShowing(e store.GuestbookEntry, days int, now time.Time) bool {
+	return now.Before(e.CreatedAt.AddDate(0, 0, days))
+}
+
+// showingGuestbookIDs lists the guestbook messages showing as of now, to tell when that changes
+func showingGuestbookIDs(entries []store.GuestbookEntry, days int, now time.Time) []int64 {
+	var ids []int64
+	for _, e := range entries {
+		if guestbookShowing(e, days, now) {
+			ids = append(ids, e.ID)
+		}
+	}
+	return ids
+}
+
+// wrapText breaks text into lines of at most width characters at spaces, leaving longer words
+// on lines of their own
+func wrapText(text string, width int) []string {
+	var lines []string
+	var line string
+	for _, word := range strings.Fields(text) {
+		if line != "" && len([]rune(line))+1+len([]rune(word)) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// guestbookText is the text of a message's slide, signed with the name it was left under
+func guestbookText(e store.GuestbookEntry) string {
+	lines := wrapText(e.Message, guestbookLineLength)
+	if e.Name != "" {
+		lines = append(lines, "", "— "+e.Name)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderGuestbookSlide renders the slide of a message, beside the selfie left with it
+func (ws *WebServer) renderGuestbookSlide(e store.GuestbookEntry) error {
+	var selfie string
+	if e.Selfie {
+		selfie = ws.paths.Selfie(e.ID)
+	}
+	return slideshow.RenderPhotoSlide(ws.paths.GuestbookSlide(e.ID), guestbookText(e), selfie)
+}
+
+// guestbookSlides returns the slides of the guestbook messages showing as of now, rendering any
+// that are missing, such as after the cache was cleared
+func (ws *WebServer) guestbookSlides(days int, now time.Time) ([]string, error) {
+	entries, err := ws.db.GetGuestbookEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	var slides []string
+	for _, e := range entries {
+		if !guestbookShowing(e, days, now) {
+			continue
+		}
+		slide := ws.paths.GuestbookSlide(e.ID)
+		if _, err := os.Stat(slide); errors.Is(err, os.ErrNotExist) {
+			if err := ws.renderGuestbookSlide(e); err != nil {
+				slog.Warn("unable to render guestbook message, leaving it out", "id", e.ID, "error", err)
+				continue
+			}
+		}
+		slides = append(slides, slide)
+	}
+	return slides, nil
+}
+
+// handleGuestbookMessage signs the guestbook for a guest holding a valid upload token, with an
+// optional selfie
+func (ws *WebServer) handleGuestbookMessage(c *gin.Context) {
+	uploadToken, ok := ws.lookupUploadToken(c)
+	if !ok {
+		return
+	}
+	fail := func(status int, message string) {
+		c.Status(status)
+		templates.GuestUploadPage(uploadToken.Token, uploadToken.Label, message, true).Render(c.Request.Context(), c.Writer)
+	}
+
+	entry := &store.GuestbookEntry{
+		Name:      strings.TrimSpace(c.PostForm("from")),
+		Message:   strings.Join(strings.Fields(c.PostForm("message")), " "),
+		CreatedAt: time.Now(),
+	}
+	if entry.Message == "" {
+		fail(http.StatusBadRequest, tr(c, "message is required"))
+		return
+	}
+	if len([]rune(entry.Message)) > maxGuestbookMessageLength {
+		fail(http.StatusBadRequest, tr(c, "message must be at most %d characters", maxGuestbookMessageLength))
+		return
+	}
+	if len([]rune(entry.Name)) > maxGuestbookNameLength {
+		fail(http.StatusBadRequest, tr(c, "name must be at most %d characters", maxGuestbookNameLength))
+		return
+	}
+
+	selfie, err := ws.readSelfie(c)
+	if err != nil {
+		requestLogger(c).Warn("unable to read guestbook selfie", "error", err)
+		fail(http.StatusBadRequest, tr(c, "Unable to read your photo, try a JPEG or PNG"))
+		return
+	}
+	entry.Selfie = selfie != nil
+
+	if err := ws.db.InsertGuestbookEntry(entry); err != nil {
+		fail(http.StatusInternalServerError, tr(c, "Failed to sign the guestbook: %v", err))
+		return
+	}
+	if err := ws.writeGuestbookFiles(*entry, selfie); err != nil {
+		if _, delErr := ws.removeGuestbookEntry(c, entry.ID); delErr != nil {
+			requestLogger(c).Warn("unable to remove guestbook message that failed to render", "id", entry.ID, "error", delErr)
+		}
+		fail(http.StatusInternalServerError, tr(c, "Failed to sign the guestbook: %v", err))
+		return
+	}
+
+	templates.GuestUploadPage(uploadToken.Token, uploadToken.Label, tr(c, "Thank you! Your message will be on the frame shortly."), false).Render(c.Request.Context(), c.Writer)
+
+	ws.requestRestart()
+}
+
+// writeGuestbookFiles keeps the selfie left with a message, if any, and renders its slide
+func (ws *WebServer) writeGuestbookFiles(e store.GuestbookEntry, selfie image.Image) error {
+	if selfie != nil {
+		resized := imaging.Resize(selfie, selfieMaxDim, selfieMaxDim, imaging.FitContain)
+		if err := imaging.WriteFile(ws.paths.Selfie(e.ID), resized); err != nil {
+			return err
+		}
+	}
+	return ws.renderGuestbookSlide(e)
+}
+
+// readSelfie decodes the selfie uploaded with a guestbook message turned upright, or returns nil
+// when none was left
+func (ws *WebServer) readSelfie(c *gin.Context) (image.Image, error) {
+	file, err := c.FormFile("selfie")
+	if errors.Is(err, http.ErrMissingFile) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	if !util.SupportedExt.Contains(ext) {
+		return nil, fmt.Errorf("unsupported selfie extension, %s", ext)
+	}
+
+	tmp, err := os.CreateTemp("", "selfie-*"+ext)
+	if err != nil {
+		return nil, err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := c.SaveUploadedFile(file, tmp.Name()); err != nil {
+		return nil, err
+	}
+	return imaging.DecodeUpright(tmp.Name())
+}
+
+// removeGuestbookEntry deletes a guestbook message along with its selfie and slide
+func (ws *WebServer) removeGuestbookEntry(c *gin.Context, id int64) (bool, error) {
+	deleted, err := ws.db.DeleteGuestbookEntry(id)
+	if err != nil {
+		return false, err
+	}
+	for _, path := range []string{ws.paths.Selfie(id), ws.paths.GuestbookSlide(id)} {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			requestLogger(c).Warn("unable to remove guestbook file", "id", id, "path", path, "error", err)
+		}
+	}
+	return deleted, nil
+}
+
+func (ws *WebServer) handleListGuestbook(c *gin.Context) {
+	entries, err := ws.db.GetGuestbookEntries()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get guestbook: %v", err)})
+		return
+	}
+	if entries == nil {
+		entries = []store.GuestbookEntry{}
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// handleGuestbookImage serves the slide rendered for a guestbook message so it can be previewed
+func (ws *WebServer) handleGuestbookImage(c *gin.Context) {
+	id, ok := parseGuestbookID(c)
+	if !ok {
+		return
+	}
+
+	slide := ws.paths.GuestbookSlide(id)
+	if _, err := os.Stat(slide); err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Guestbook message %d not found", id)})
+		return
+	}
+	c.File(slide)
+}
+
+func (ws *WebServer) handleDeleteGuestbookEntry(c *gin.Context) {
+	id, ok := parseGuestbookID(c)
+	if !ok {
+		return
+	}
+
+	deleted, err := ws.removeGuestbookEntry(c, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to delete guestbook message: %v", err)})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Guestbook message %d not found", id)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "Guestbook message %d deleted successfully", id)})
+
+	ws.requestRestart()
+}
+
+func parseGuestbookID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid guestbook message id")})
+		return 0, false
+	}
+	return id, true
+}
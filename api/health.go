@@ -0,0 +1,39 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// imv quitting this many times in a row without recovering marks the frame as degraded
+const slideshowFailureThreshold = 3
+
+// handleHealth reports whether the slideshow is staying up, responding with 503 while imv keeps
+// quitting so monitoring can alert on a black frame
+func (ws *WebServer) handleHealth(c *gin.Context) {
+	status := ws.controller.Status()
+
+	resp := models.HealthResponse{
+		Status: "ok",
+		Slideshow: models.SlideshowHealth{
+			Running:  status.Running,
+			Restarts: status.Restarts,
+			Failures: status.Failures,
+		},
+	}
+	if !status.LastFailure.IsZero() {
+		resp.Slideshow.LastFailureAt = &status.LastFailure
+	}
+	if status.LastError != nil {
+		resp.Slideshow.LastError = status.LastError.Error()
+	}
+
+	code := http.StatusOK
+	if status.Failures >= slideshowFailureThreshold {
+		resp.Status = "degraded"
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, resp)
+}
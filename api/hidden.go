@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+// isPlayable reports whether a photo can be put in a playlist. Hidden photos and photos waiting
+// for approval stay in the library but are never played, and photos limited to certain days are
+// only played on them.
+func isPlayable(photo store.Photo) bool {
+	return !photo.Hidden && !photo.Pending && shownOn(photo, time.Now())
+}
+
+// playablePhotos leaves out the photos that can't be played
+func playablePhotos(photos []store.Photo) []store.Photo {
+	return slices.DeleteFunc(photos, func(photo store.Photo) bool {
+		return !isPlayable(photo)
+	})
+}
+
+// handleUpdatePhotoHidden hides a photo from every playlist without deleting it, or shows it again
+func (ws *WebServer) handleUpdatePhotoHidden(c *gin.Context) {
+	category, name, ok := parsePhotoFileParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.PhotoHiddenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
+		return
+	}
+
+	exists, err := ws.db.PhotoExists(name, category)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Photo '%s' in category %d not found", name, category)})
+		return
+	}
+
+	if err := ws.db.UpdatePhotoHidden(name, category, req.Hidden); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to update photo: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, req)
+
+	// trigger slideshow restart
+	ws.requestRestart()
+}
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// versions of the settings and of the schedule kept in the history
+	maxSettingsHistory = 50
+
+	defaultHistoryLimit = 20
+
+	// header naming who made a change, falling back to the client's address
+	changedByHeader    = "X-Changed-By"
+	maxChangedByLength = 64
+)
+
+// changedBy names who made the change in the request
+func changedBy(c *gin.Context) string {
+	name := strings.TrimSpace(c.GetHeader(changedByHeader))
+	if name == "" {
+		return c.ClientIP()
+	}
+	if runes := []rune(name); len(runes) > maxChangedByLength {
+		name = string(runes[:maxChangedByLength])
+	}
+	return name
+}
+
+// scheduleVersion is the part of the schedule kept in the history, leaving out the profile picked
+// for today since that only lasts the day
+func scheduleVersion(s *store.Schedule) *store.Schedule {
+	version := *s
+	version.TodayProfile, version.TodayDate = "", ""
+	return &version
+}
+
+// recordSettingsVersion adds the current settings or schedule to the history unless nothing
+// changed since the last version. The first change also records how things were before it so it
+// can be undone. Failing to record is logged rather than failing the change.
+func recordSettingsVersion(db *store.Database, kind string, previous, current any, author string) {
+	data, err := json.Marshal(current)
+	if err != nil {
+		slog.Warn("unable to encode settings version", "kind", kind, "error", err)
+		return
+	}
+
+	latest, err := db.GetSettingsHistory(kind, 1)
+	if err != nil {
+		slog.Warn("unable to get settings history", "kind", kind, "error", err)
+		return
+	}
+	if len(latest) > 0 && bytes.Equal(latest[0].Data, data) {
+		return
+	}
+
+	now := time.Now()
+	if len(latest) == 0 {
+		previousData, err := json.Marshal(previous)
+		if err != nil {
+			slog.Warn("unable to encode settings version", "kind", kind, "error", err)
+			return
+		}
+		if !bytes.Equal(previousData, data) {
+			baseline := &store.SettingsVersion{Kind: kind, ChangedAt: now, Data: previousData}
+			if err := db.InsertSettingsVersion(baseline, maxSettingsHistory); err != nil {
+				slog.Warn("unable to record settings version", "kind", kind, "error", err)
+				return
+			}
+		}
+	}
+
+	version := &store.SettingsVersion{Kind: kind, ChangedAt: now, ChangedBy: author, Data: data}
+	if err := db.InsertSettingsVersion(version, maxSettingsHistory); err != nil {
+		slog.Warn("unable to record settings version", "kind", kind, "error", err)
+	}
+
+	event := store.EventSettings
+	if kind == store.HistorySchedule {
+		event = store.EventSchedule
+	}
+	recordEvent(db, event, author, 0, "")
+}
+
+// handleSettingsHistory lists the versions of the settings and schedule, newest first
+func (ws *WebServer) handleSettingsHistory(c *gin.Context) {
+	kind := c.Query("kind")
+	if kind != "" && kind != store.HistorySettings && kind != store.HistorySchedule {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "kind must be %s or %s", store.HistorySettings, store.HistorySchedule)})
+		return
+	}
+	limit := defaultHistoryLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > 2*maxSettingsHistory {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "limit must be between 1 and %d", 2*maxSettingsHistory)})
+			return
+		}
+		limit = parsed
+	}
+
+	versions, err := ws.db.GetSettingsHistory(kind, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get settings history: %v", err)})
+		return
+	}
+	if versions == nil {
+		versions = []store.SettingsVersion{}
+	}
+	c.JSON(http.StatusOK, versions)
+}
+
+// handleRollbackSettings restores the settings or schedule of a version, which is recorded as a
+// new version so the rollback can be undone too
+func (ws *WebServer) handleRollbackSettings(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid version id %s", c.Param("id"))})
+		return
+	}
+
+	version, err := ws.db.GetSettingsVersion(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get settings history: %v", err)})
+		return
+	}
+	if version == nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Settings version %d not found", id)})
+		return
+	}
+
+	switch version.Kind {
+	case store.HistorySettings:
+		var settings store.AppSettings
+		if err := json.Unmarshal(version.Data, &settings); err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to read settings version: %v", err)})
+			return
+		}
+		ws.saveSettings(c, &settings)
+	case store.HistorySchedule:
+		var schedule store.Schedule
+		if err := json.Unmarshal(version.Data, &schedule); err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to read settings version: %v", err)})
+			return
+		}
+		// a profile deleted since can't be picked again
+		exists, err := ws.scheduleProfileExists(schedule.ActiveProfile)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get schedule profiles: %v", err)})
+			return
+		}
+		if !exists {
+			schedule.ActiveProfile = ""
+		}
+		ws.saveSchedule(c, &schedule, true)
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Unknown settings version kind %s", version.Kind)})
+	}
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"github.com/aouyang1/digitalphotoframe/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// localize renders the request in the language from the app settings
+func (ws *WebServer) localize(c *gin.Context) {
+	settings, err := ws.db.GetAppSettings()
+	if err != nil {
+		requestLogger(c).Warn("unable to get language setting, using default", "error", err)
+		c.Next()
+		return
+	}
+	c.Request = c.Request.WithContext(i18n.WithLanguage(c.Request.Context(), settings.Language))
+	c.Next()
+}
+
+// tr translates msg into the language of the request
+func tr(c *gin.Context, msg string, args ...any) string {
+	return i18n.T(c.Request.Context(), msg, args...)
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"archive/zip"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/service"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+// handleImportTakeout adds the photos in an uploaded Google Takeout zip archive to My Photos,
+// responding with what was done with the files in it, or what would be done in a dry run
+func (ws *WebServer) handleImportTakeout(c *gin.Context) {
+	opts, ok := importOptions(c)
+	if !ok {
+		return
+	}
+	f, archive, ok := openUploadedArchive(c)
+	if !ok {
+		return
+	}
+	defer f.Close()
+
+	report, err := ws.photoService.ImportTakeout(c.Request.Context(), archive, opts)
+	// photos added before an import is cut short are kept
+	if report != nil && !report.DryRun && report.Added > 0 {
+		recordEvent(ws.db, store.EventUpload, opts.UploadedBy, report.Added, "")
+		defer ws.requestRestart()
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to import archive: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// handleImportApplePhotos adds the photos in an uploaded zip archive of an Apple Photos export to
+// My Photos, responding with what was done with the files in it, or what would be done in a dry run
+func (ws *WebServer) handleImportApplePhotos(c *gin.Context) {
+	opts, ok := importOptions(c)
+	if !ok {
+		return
+	}
+	f, archive, ok := openUploadedArchive(c)
+	if !ok {
+		return
+	}
+	defer f.Close()
+
+	report, err := ws.photoService.ImportApplePhotos(c.Request.Context(), archive, opts)
+	if report != nil && !report.DryRun && report.Added > 0 {
+		recordEvent(ws.db, store.EventUpload, opts.UploadedBy, report.Added, "")
+		defer ws.requestRestart()
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to import archive: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// importOptions reads who an import is from and whether it's a dry run from the form or query,
+// writing the error response when they aren't valid
+func importOptions(c *gin.Context) (service.ImportOptions, bool) {
+	opts := service.ImportOptions{UploadedBy: formOrQuery(c, "from")}
+	if dryRun := formOrQuery(c, "dry_run"); dryRun != "" {
+		var err error
+		if opts.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "dry_run must be true or false")})
+			return opts, false
+		}
+	}
+	return opts, true
+}
+
+// openUploadedArchive opens the zip archive in the file form field, writing the error response
+// when there isn't one
+func openUploadedArchive(c *gin.Context) (multipart.File, *zip.Reader, bool) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "no file provided")})
+		return nil, nil, false
+	}
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Failed to read archive: %v", err)})
+		return nil, nil, false
+	}
+
+	archive, err := zip.NewReader(f, file.Size)
+	if err != nil {
+		f.Close()
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "not a zip archive: %v", err)})
+		return nil, nil, false
+	}
+	return f, archive, true
+}
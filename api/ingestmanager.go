@@ -0,0 +1,202 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/paths"
+	"github.com/aouyang1/digitalphotoframe/service"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/aouyang1/digitalphotoframe/thermal"
+	"github.com/aouyang1/digitalphotoframe/util"
+)
+
+const (
+	ingestInterval = 15 * time.Second
+
+	// files changed more recently than this may still be being copied in
+	ingestSettleTime = 10 * time.Second
+)
+
+// IngestManager adds files dropped into the ingest directories by scp, sftp, ftp, or any other
+// means. Files are checked to be images, moved into their category, and registered, while copies
+// of photos already on the frame are dropped and name clashes are renamed. A json or xmp sidecar
+// dropped off with a photo is moved along with it so its metadata is imported, as is the video of
+// a live photo.
+type IngestManager struct {
+	db           *store.Database
+	photoService *service.PhotoService
+	paths        paths.Layout
+
+	// surprise photos mirror s3 when syncing, so dropped off surprise photos would be removed
+	surpriseSynced bool
+
+	Updated chan bool
+}
+
+func NewIngestManager(db *store.Database, photoService *service.PhotoService, layout paths.Layout, surpriseSynced bool) (*IngestManager, error) {
+	if db == nil {
+		return nil, errors.New("no database provided for ingest manager")
+	}
+	for _, dir := range []string{layout.IngestDir(paths.CategoryOriginal), layout.IngestDir(paths.CategorySurprise), layout.IngestRejectedDir()} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create ingest directory, %w", err)
+		}
+	}
+
+	return &IngestManager{
+		db:             db,
+		photoService:   photoService,
+		paths:          layout,
+		surpriseSynced: surpriseSynced,
+		Updated:        make(chan bool, 1),
+	}, nil
+}
+
+func (m *IngestManager) Run() {
+	ticker := time.NewTicker(ingestInterval)
+
+	m.scan()
+	for range ticker.C {
+		m.scan()
+	}
+}
+
+func (m *IngestManager) scan() {
+	var added int
+	for _, category := range paths.Categories {
+		dir := m.paths.IngestDir(category)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			slog.Warn("error reading ingest directory", "path", dir, "error", err)
+			continue
+		}
+
+		for _, entry := range entries {
+			// skip subdirectories and the temporary files transfer tools write to before renaming
+			if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil || time.Since(info.ModTime()) < ingestSettleTime {
+				continue
+			}
+
+			// sidecars are moved along with their photo, and only rejected when dropped off alone
+			if service.IsSidecar(entry.Name()) {
+				if _, ok := service.SidecarPhoto(filepath.Join(dir, entry.Name())); !ok {
+					slog.Warn("rejected dropped off sidecar without a photo", "name", entry.Name(), "category", category)
+					m.reject(filepath.Join(dir, entry.Name()))
+				}
+				continue
+			}
+			// as are the videos of live photos
+			if service.IsMotionVideo(entry.Name()) {
+				if _, ok := service.MotionVideoPhoto(filepath.Join(dir, entry.Name())); !ok {
+					slog.Warn("rejected dropped off video without a live photo", "name", entry.Name(), "category", category)
+					m.reject(filepath.Join(dir, entry.Name()))
+				}
+				continue
+			}
+
+			// a big drop off is added a photo at a time, waiting out a hot cpu
+			thermal.Wait(context.Background(), 0, 1)
+
+			ok, err := m.ingest(category, entry.Name())
+			if err != nil {
+				slog.Warn("rejected dropped off file", "name", entry.Name(), "category", category, "error", err)
+				m.reject(filepath.Join(dir, entry.Name()))
+				continue
+			}
+			if ok {
+				added++
+			}
+		}
+	}
+
+	if added > 0 {
+		select {
+		case m.Updated <- true:
+		default:
+		}
+	}
+}
+
+// ingest adds a dropped off file to the category, returning false if it was a copy of a photo
+// already on the frame and was dropped
+func (m *IngestManager) ingest(category int, name string) (bool, error) {
+	src := filepath.Join(m.paths.IngestDir(category), name)
+	if !util.SupportedExt.Contains(filepath.Ext(name)) {
+		return false, fmt.Errorf("unsupported file extension %s", filepath.Ext(name))
+	}
+	if category == paths.CategorySurprise && m.surpriseSynced {
+		return false, errors.New("surprise photos are synced from s3")
+	}
+	if err := service.CheckImage(src); err != nil {
+		return false, err
+	}
+
+	duplicate, err := m.photoService.FindDuplicate(category, src)
+	if err != nil {
+		return false, err
+	}
+	sidecar, hasSidecar := service.FindSidecar(src)
+	motion, hasMotion := service.FindMotionVideo(src)
+	if duplicate != "" {
+		slog.Info("dropping duplicate dropped off file", "name", name, "category", category, "duplicate_of", duplicate)
+		if hasSidecar {
+			if err := os.Remove(sidecar); err != nil {
+				return false, err
+			}
+		}
+		if hasMotion {
+			if err := os.Remove(motion); err != nil {
+				return false, err
+			}
+		}
+		return false, os.Remove(src)
+	}
+
+	dstName, err := m.photoService.FreeName(category, name)
+	if err != nil {
+		return false, err
+	}
+	if err := os.MkdirAll(m.paths.OriginalDir(category), 0o755); err != nil {
+		return false, fmt.Errorf("failed to create directory, %w", err)
+	}
+	dst := m.paths.Original(category, dstName)
+	if hasSidecar {
+		if err := os.Rename(sidecar, dst+strings.ToLower(filepath.Ext(sidecar))); err != nil {
+			return false, fmt.Errorf("failed to move dropped off sidecar, %w", err)
+		}
+	}
+	if hasMotion {
+		stem := strings.TrimSuffix(dst, filepath.Ext(dst))
+		if err := os.Rename(motion, stem+strings.ToLower(filepath.Ext(motion))); err != nil {
+			return false, fmt.Errorf("failed to move dropped off live photo video, %w", err)
+		}
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return false, fmt.Errorf("failed to move dropped off file, %w", err)
+	}
+	if err := m.photoService.Add(dstName, category, "", false); err != nil {
+		return false, err
+	}
+	slog.Info("added dropped off photo", "name", dstName, "category", category)
+	return true, nil
+}
+
+// reject moves a file that could not be added aside so it isn't retried, replacing any earlier
+// rejected file with the same name
+func (m *IngestManager) reject(path string) {
+	dst := filepath.Join(m.paths.IngestRejectedDir(), filepath.Base(path))
+	if err := os.Rename(path, dst); err != nil && !os.IsNotExist(err) {
+		slog.Warn("unable to move rejected file", "path", path, "error", err)
+	}
+}
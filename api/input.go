@@ -0,0 +1,72 @@
+package api
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/aouyang1/digitalphotoframe/display"
+	"github.com/aouyang1/digitalphotoframe/input"
+	"github.com/aouyang1/digitalphotoframe/slideshow"
+)
+
+// startInputs starts listening to any configured hardware input sources
+func (ws *WebServer) startInputs() {
+	ws.startLIRC()
+	ws.startTouch()
+}
+
+func (ws *WebServer) startLIRC() {
+	socketPath := os.Getenv("DPF_LIRC_SOCKET")
+	if socketPath == "" {
+		socketPath = input.DefaultLIRCSocket
+	}
+	if _, err := os.Stat(socketPath); err != nil {
+		slog.Info("lirc socket not found, ir remote input disabled", "socket", socketPath)
+		return
+	}
+
+	keymap := input.DefaultLIRCKeymap
+	if keymapStr := os.Getenv("DPF_LIRC_KEYMAP"); keymapStr != "" {
+		custom, err := input.ParseKeymap(keymapStr)
+		if err != nil {
+			slog.Warn("unable to parse DPF_LIRC_KEYMAP, using default", "DPF_LIRC_KEYMAP", keymapStr, "error", err)
+		} else {
+			keymap = custom
+		}
+	}
+
+	go input.NewLIRC(socketPath, keymap, ws.handleInputAction).Run()
+}
+
+func (ws *WebServer) startTouch() {
+	device := os.Getenv("DPF_TOUCH_DEVICE")
+	if device == "" {
+		slog.Info("DPF_TOUCH_DEVICE not set, touch input disabled")
+		return
+	}
+	go input.NewTouch(device, slideshow.RotateDegrees, ws.handleInputAction).Run()
+}
+
+// handleInputAction performs an input action through the same slideshow and display control
+// paths as the api
+func (ws *WebServer) handleInputAction(action input.Action) {
+	var err error
+	switch action {
+	case input.ActionNext:
+		err = ws.controller.Next()
+	case input.ActionPrev:
+		err = ws.controller.Prev()
+	case input.ActionTogglePause:
+		_, err = ws.controller.TogglePause()
+	case input.ActionToggleDisplay:
+		var enabled bool
+		enabled, err = display.GetEnabled()
+		if err == nil {
+			err = setDisplayEnabled(ws.controller, !enabled)
+		}
+		ws.audioManager.Update()
+	}
+	if err != nil {
+		slog.Warn("failed to perform input action", "action", action, "error", err)
+	}
+}
@@ -7,7 +7,8 @@ import (
 	"sort"
 	"time"
 
-	"github.com/aouyang1/digitalphotoframe/api/client"
+	"github.com/aouyang1/digitalphotoframe/paths"
+	"github.com/aouyang1/digitalphotoframe/service"
 	"github.com/aouyang1/digitalphotoframe/util"
 	mapset "github.com/deckarep/golang-set/v2"
 )
@@ -20,28 +21,25 @@ const (
 type LocalManager struct {
 	path string
 
-	photoClient  *client.PhotoClient
+	photoService *service.PhotoService
 	trackedFiles mapset.Set[string]
 
 	Updated chan bool
 }
 
-func NewLocalManager() (*LocalManager, error) {
+func NewLocalManager(photoService *service.PhotoService) (*LocalManager, error) {
 	// Use DPF_ROOT_PATH/original if set
 	rootPath := os.Getenv("DPF_ROOT_PATH")
 	var path string
 	if rootPath != "" {
-		path = filepath.Join(rootPath, "original")
+		path = paths.New(rootPath).OriginalDir(paths.CategoryOriginal)
 	} else {
 		path = "."
 	}
 
-	// Initialize photo client
-	photoClient := client.NewPhotoClient(webServerURL)
-
 	l := &LocalManager{
 		path:         path,
-		photoClient:  photoClient,
+		photoService: photoService,
 		trackedFiles: mapset.NewSet[string](),
 		Updated:      make(chan bool),
 	}
@@ -139,47 +137,9 @@ func (l *LocalManager) scanAndRegister() {
 	// Update tracked files
 	l.trackedFiles = currentFiles
 
-	// Ensure all local files are registered
-	for _, name := range currentFiles.ToSlice() {
-		// Find the file info
-		var fileInfo *fileInfo
-		for i := range fileInfos {
-			if fileInfos[i].name == name {
-				fileInfo = &fileInfos[i]
-				break
-			}
-		}
-
-		if fileInfo == nil {
-			continue
-		}
-
-		if err := l.photoClient.RegisterPhotoIfNotExists(fileInfo.path, 1); err != nil {
-			slog.Warn("error while registering local photo", "name", name, "error", err)
-		}
-	}
-
-	// Get all registered category 1 photos from DB and compare with local files
-	registeredPhotos, err := l.photoClient.GetPhotos(1)
-	if err != nil {
-		slog.Warn("error getting registered photos from DB", "error", err)
-	} else {
-		// Create set of registered photo names
-		registeredNames := mapset.NewSet[string]()
-		for _, photo := range registeredPhotos {
-			registeredNames.Add(photo.PhotoName)
-		}
-
-		// Find photos registered in DB but not present locally
-		toDeregister := registeredNames.Difference(currentFiles).ToSlice()
-		if len(toDeregister) > 0 {
-			slog.Info("deregistering category 1 photos not present locally", "count", len(toDeregister), "names", toDeregister)
-			for _, name := range toDeregister {
-				if err := l.photoClient.DeletePhoto(name, 1); err != nil {
-					slog.Warn("error while deregistering photo", "name", name, "error", err)
-				}
-			}
-		}
+	// Ensure all local files are registered and photos no longer present are deregistered
+	if _, _, err := l.photoService.Reconcile(paths.CategoryOriginal, currentFiles, false); err != nil {
+		slog.Warn("error while reconciling local photos", "error", err)
 	}
 
 	// Check if we need to enforce limit
@@ -193,7 +153,7 @@ func (l *LocalManager) scanAndRegister() {
 		toRemove := currentFiles.Cardinality() - localPhotoLimit
 		for i := 0; i < toRemove && i < len(fileInfos); i++ {
 			oldest := fileInfos[i]
-			if err := os.Remove(oldest.path); err != nil {
+			if err := l.photoService.Delete(oldest.name, paths.CategoryOriginal); err != nil {
 				slog.Warn("unable to remove old file", "name", oldest.name, "error", err)
 			} else {
 				slog.Info("removed old file to enforce limit", "name", oldest.name)
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/thermal"
+	"github.com/gin-gonic/gin"
+)
+
+// writeMetric writes a single sample in the Prometheus text exposition format
+func writeMetric(w io.Writer, name, kind, help string, value float64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %g\n", name, help, name, kind, name, value)
+}
+
+// handleMetrics exports the frame's metrics for Prometheus to scrape
+func (ws *WebServer) handleMetrics(c *gin.Context) {
+	total, err := ws.db.GetTotalDisplayOnTime()
+	if err != nil {
+		requestLogger(c).Warn("unable to get display on time for metrics", "error", err)
+	}
+
+	var today int64
+	usage, err := ws.db.GetDisplayUsage(time.Now().Format(dateLayout))
+	if err != nil {
+		requestLogger(c).Warn("unable to get today's display usage for metrics", "error", err)
+	}
+	if len(usage) > 0 {
+		today = usage[0].OnSeconds
+	}
+
+	var on float64
+	if ws.usageManager.On() {
+		on = 1
+	}
+
+	slideshowStatus := ws.controller.Status()
+	var running float64
+	if slideshowStatus.Running {
+		running = 1
+	}
+
+	c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.Status(http.StatusOK)
+	writeMetric(c.Writer, "dpf_display_on", "gauge", "Whether the display is on.", on)
+	writeMetric(c.Writer, "dpf_display_on_seconds_total", "counter", "Seconds the display has been on since usage was first tracked.", float64(total))
+	writeMetric(c.Writer, "dpf_display_on_seconds_today", "gauge", "Seconds the display has been on today.", float64(today))
+	writeMetric(c.Writer, "dpf_display_watts", "gauge", "Power the display is assumed to draw while on.", ws.usageManager.Watts())
+	writeMetric(c.Writer, "dpf_display_energy_kwh_total", "counter", "Estimated energy used by the display since usage was first tracked.", energyKWh(total, ws.usageManager.Watts()))
+	writeMetric(c.Writer, "dpf_slideshow_running", "gauge", "Whether the imv slideshow is running.", running)
+	writeMetric(c.Writer, "dpf_slideshow_restarts_total", "counter", "Times imv quit on its own and was restarted.", float64(slideshowStatus.Restarts))
+	writeMetric(c.Writer, "dpf_slideshow_failures", "gauge", "Times in a row imv quit or failed to start without recovering.", float64(slideshowStatus.Failures))
+	if temp, err := thermal.Temperature(); err == nil {
+		writeMetric(c.Writer, "dpf_cpu_temperature_celsius", "gauge", "Temperature of the CPU.", temp)
+	}
+}
@@ -1,7 +1,12 @@
 // Package models tracks all api models for request and responses
 package models
 
-import "github.com/aouyang1/digitalphotoframe/store"
+import (
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/display"
+	"github.com/aouyang1/digitalphotoframe/store"
+)
 
 type PhotoListResponse struct {
 	Photos []store.Photo `json:"photos"`
@@ -10,9 +15,30 @@ type PhotoListResponse struct {
 	Limit  int           `json:"limit"`
 }
 
+// FeedResponse is a page of the activity feed, newest first
+type FeedResponse struct {
+	Items []FeedItem `json:"items"`
+	Total int        `json:"total"`
+	Page  int        `json:"page"`
+	Limit int        `json:"limit"`
+}
+
+// FeedItem is an event along with a description of it in the request's language
+type FeedItem struct {
+	store.Event
+	Message string `json:"message"`
+}
+
+// RecentPhotosResponse lists the photos added since Since across categories, newest first
+type RecentPhotosResponse struct {
+	Since  time.Time     `json:"since"`
+	Photos []store.Photo `json:"photos"`
+}
+
 type RegisterPhotoRequest struct {
-	PhotoName string `json:"photo_name"`
-	Category  int    `json:"category"`
+	PhotoName  string `json:"photo_name"`
+	Category   int    `json:"category"`
+	UploadedBy string `json:"uploaded_by,omitempty"`
 }
 
 type RegisterPhotoResponse struct {
@@ -27,5 +53,302 @@ type ErrorResponse struct {
 }
 
 type DisplayStateResponse struct {
-	Enabled bool `json:"enabled"`
+	Enabled   bool   `json:"enabled"`
+	Transform string `json:"transform,omitempty"`
+}
+
+// DisplayInfoResponse describes the panel attached to the frame as reported by wlr-randr
+type DisplayInfoResponse struct {
+	display.Output
+	CurrentMode *display.Mode `json:"current_mode,omitempty"`
+}
+
+// DisplayModeRequest picks one of the resolutions the panel supports. Refresh is matched to the
+// nearest whole rate and the highest rate is used when it's left out. Scale defaults to 1.
+type DisplayModeRequest struct {
+	Width   int     `json:"width"`
+	Height  int     `json:"height"`
+	Refresh float64 `json:"refresh,omitempty"`
+	Scale   float64 `json:"scale,omitempty"`
+}
+
+type DisplayModeResponse struct {
+	Mode  display.Mode `json:"mode"`
+	Scale float64      `json:"scale"`
+}
+
+// DisplayUsageResponse is how long the display was on each day and the energy it is estimated
+// to have used at Watts while on
+type DisplayUsageResponse struct {
+	Watts          float64           `json:"watts"`
+	On             bool              `json:"on"`
+	Days           []DisplayUsageDay `json:"days"`
+	TotalOnSeconds int64             `json:"total_on_seconds"`
+	TotalEnergyKWh float64           `json:"total_energy_kwh"`
+}
+
+type DisplayUsageDay struct {
+	Day       string  `json:"day"`
+	OnSeconds int64   `json:"on_seconds"`
+	EnergyKWh float64 `json:"energy_kwh"`
+}
+
+type DisplayTransformRequest struct {
+	Transform string `json:"transform"`
+}
+
+// AudioResponse is the background audio's settings and whether it's playing right now, which it
+// isn't during quiet hours or while the display is off
+type AudioResponse struct {
+	Enabled bool   `json:"enabled"`
+	Source  string `json:"source"`
+	Volume  int    `json:"volume"`
+	Playing bool   `json:"playing"`
+}
+
+// AudioVolumeRequest sets the background audio's volume as a percent
+type AudioVolumeRequest struct {
+	Volume int `json:"volume"`
+}
+
+// ScheduleResponse is the saved schedule along with the times in effect right now and the profile
+// they came from
+type ScheduleResponse struct {
+	store.Schedule
+	CurrentProfile string `json:"current_profile"`
+	CurrentStart   string `json:"current_start"`
+	CurrentEnd     string `json:"current_end"`
+}
+
+// ScheduleProfileSelection picks a schedule profile by name, or none when empty
+type ScheduleProfileSelection struct {
+	Profile string `json:"profile"`
+}
+
+type ShareLinkRequest struct {
+	ExpiresInHours int `json:"expires_in_hours"`
+}
+
+type ShareLinkResponse struct {
+	URL       string    `json:"url"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type GuestLinkRequest struct {
+	Label          string `json:"label"`
+	ExpiresInHours int    `json:"expires_in_hours"`
+}
+
+type GuestLinkResponse struct {
+	URL       string    `json:"url"`
+	QRCodeURL string    `json:"qr_code_url"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type VoiceIntentRequest struct {
+	Intent string `json:"intent"`
+	Text   string `json:"text"`
+}
+
+type VoiceIntentResponse struct {
+	Intent string `json:"intent"`
+	Speech string `json:"speech"`
+}
+
+type NetworkStatusResponse struct {
+	Interface    string   `json:"interface"`
+	SSID         string   `json:"ssid"`
+	Signal       int      `json:"signal"`
+	IPAddresses  []string `json:"ip_addresses"`
+	Connectivity string   `json:"connectivity"`
+	Internet     bool     `json:"internet_reachable"`
+	SetupMode    bool     `json:"setup_mode"`
+
+	S3Enabled         bool       `json:"s3_enabled"`
+	S3LastCheckedAt   *time.Time `json:"s3_last_checked_at,omitempty"`
+	S3LastReachableAt *time.Time `json:"s3_last_reachable_at,omitempty"`
+	S3LastError       string     `json:"s3_last_error,omitempty"`
+
+	// S3FailedDownloads lists objects that could not be downloaded after retrying
+	S3FailedDownloads []SyncFailure `json:"s3_failed_downloads"`
+}
+
+// SyncFailure is an s3 object that failed to download and the number of syncs it has failed in
+type SyncFailure struct {
+	Name         string    `json:"name"`
+	Failures     int       `json:"failures"`
+	LastError    string    `json:"last_error"`
+	LastFailedAt time.Time `json:"last_failed_at"`
+}
+
+type PhotoCaptionRequest struct {
+	Caption string `json:"caption"`
+}
+
+type PhotoAlbumRequest struct {
+	Album string `json:"album"`
+}
+
+type PhotoHiddenRequest struct {
+	Hidden bool `json:"hidden"`
+}
+
+// ReactionRequest leaves a heart, a short comment, or both on a photo, under Name if given
+type ReactionRequest struct {
+	Name    string `json:"name"`
+	Heart   bool   `json:"heart"`
+	Comment string `json:"comment"`
+}
+
+// PersonResponse is someone who appears in photos with how many photos they are in
+type PersonResponse struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	Exclusive  bool   `json:"exclusive"`
+	PhotoCount int    `json:"photo_count"`
+}
+
+// PhotoPeopleRequest sets the ids of the people tagged in a photo
+type PhotoPeopleRequest struct {
+	People []int64 `json:"people"`
+}
+
+type PhotoPeopleResponse struct {
+	People []store.Person `json:"people"`
+}
+
+type PhotoPinnedRequest struct {
+	Pinned bool `json:"pinned"`
+}
+
+// PhotoVisibilityRequest sets the days a photo is played, formatted as 2006-01-02 and empty for no
+// limit
+type PhotoVisibilityRequest struct {
+	ShowFrom  string `json:"show_from"`
+	ShowUntil string `json:"show_until"`
+}
+
+type SlideshowHoldResponse struct {
+	Held bool `json:"held"`
+}
+
+// SlideshowStateResponse is what the slideshow is playing. Current is the image at the 1-based
+// Index in the playlist of Total images. While imv isn't running it's the state last saved.
+type SlideshowStateResponse struct {
+	Running    bool      `json:"running"`
+	PlaylistID string    `json:"playlist_id"`
+	Index      int       `json:"index"`
+	Total      int       `json:"total"`
+	Current    string    `json:"current"`
+	Paused     bool      `json:"paused"`
+	Held       bool      `json:"held"`
+	Asleep     bool      `json:"asleep"`
+	StartedAt  time.Time `json:"started_at,omitzero"`
+	SavedAt    time.Time `json:"saved_at,omitzero"`
+}
+
+type SlideshowShowResponse struct {
+	PhotoName string    `json:"photo_name"`
+	Category  int       `json:"category"`
+	Until     time.Time `json:"until"`
+}
+
+// Category describes a photo category along with how many photos it has and the space their
+// originals and slideshow copies take up
+type Category struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	PhotoCount int    `json:"photo_count"`
+	SizeBytes  int64  `json:"size_bytes"`
+
+	// Uploadable is false while the category is synced from a remote, which would remove uploads
+	Uploadable bool `json:"uploadable"`
+}
+
+// SyncResponse reports which remotes were asked to sync, which happens in the background
+type SyncResponse struct {
+	S3     bool `json:"s3"`
+	Rclone bool `json:"rclone"`
+}
+
+type OrganizeResponse struct {
+	Organized int `json:"organized"`
+}
+
+// WebSlideshowResponse is the playlist played by the browser slideshow
+type WebSlideshowResponse struct {
+	IntervalSeconds       int        `json:"interval_seconds"`
+	IntervalJitterSeconds int        `json:"interval_jitter_seconds"`
+	OverlayPosition       string     `json:"overlay_position"`
+	OverlaySize           string     `json:"overlay_size"`
+	Slides                []WebSlide `json:"slides"`
+}
+
+type WebSlide struct {
+	ID        string `json:"id"`
+	PhotoName string `json:"photo_name"`
+	Category  int    `json:"category"`
+	ImageURL  string `json:"image_url"`
+
+	// Overlay is the text shown over the photo with lines separated by newlines, empty when nothing
+	// is shown
+	Overlay string `json:"overlay"`
+
+	// MotionURL is the video of a motion or live photo to play before its still, with MotionType
+	// its content type, both empty unless the photo has one and motion is played
+	MotionURL  string `json:"motion_url,omitempty"`
+	MotionType string `json:"motion_type,omitempty"`
+}
+
+// HealthResponse reports whether the frame is working, which is degraded while the slideshow
+// keeps quitting
+type HealthResponse struct {
+	Status    string          `json:"status"`
+	Slideshow SlideshowHealth `json:"slideshow"`
+}
+
+// SlideshowHealth describes the imv process and how often it has had to be restarted
+type SlideshowHealth struct {
+	Running       bool       `json:"running"`
+	Restarts      int        `json:"restarts"`
+	Failures      int        `json:"failures"`
+	LastFailureAt *time.Time `json:"last_failure_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+}
+
+// SharedAlbumResponse is an album this frame shares with the link other frames pair with it by
+type SharedAlbumResponse struct {
+	ID        int64     `json:"id"`
+	Album     string    `json:"album"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SharedAlbumRequest struct {
+	Album string `json:"album"`
+}
+
+// AlbumPairingRequest pairs with the album another frame shares at URL, copying its photos into
+// Album, or an album of the same name when empty
+type AlbumPairingRequest struct {
+	URL   string `json:"url"`
+	Album string `json:"album"`
+}
+
+// PairedAlbumResponse lists the photos in an album a frame shares to the frames paired with it
+type PairedAlbumResponse struct {
+	Album  string        `json:"album"`
+	Photos []PairedPhoto `json:"photos"`
+}
+
+// PairedPhoto is a photo in a shared album with what's kept when paired frames copy it
+type PairedPhoto struct {
+	ID         string    `json:"id"`
+	PhotoName  string    `json:"photo_name"`
+	UploadedBy string    `json:"uploaded_by"`
+	Caption    string    `json:"caption"`
+	Tags       []string  `json:"tags,omitempty"`
+	TakenAt    time.Time `json:"taken_at,omitzero"`
 }
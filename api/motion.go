@@ -0,0 +1,53 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/service"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+// motionURL is where the video of a motion or live photo is served, or empty for a still photo
+func (ws *WebServer) motionURL(photo store.Photo) string {
+	if photo.Motion == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/photos/id/%s/motion", ws.basePath, url.PathEscape(photo.ID))
+}
+
+// handlePhotoMotion serves the video of a motion or live photo
+func (ws *WebServer) handlePhotoMotion(c *gin.Context) {
+	category, name, ok := parsePhotoFileParams(c)
+	if !ok {
+		return
+	}
+
+	photo, err := ws.db.GetPhoto(name, category)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
+		return
+	}
+	if photo == nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Photo '%s' in category %d not found", name, category)})
+		return
+	}
+	if photo.Motion == "" {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Photo '%s' has no motion video", name)})
+		return
+	}
+
+	path := ws.paths.Motion(category, name, photo.Motion)
+	if _, err := os.Stat(path); err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Photo '%s' has no motion video", name)})
+		return
+	}
+	if mimeType, ok := service.MotionMimeTypes[photo.Motion]; ok {
+		c.Header("Content-Type", mimeType)
+	}
+	c.File(path)
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/network"
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetNetwork reports the wifi connection, addresses, and internet and s3 reachability.
+// Failures to read any one piece are logged and left empty so the rest can still be shown.
+func (ws *WebServer) handleGetNetwork(c *gin.Context) {
+	resp := models.NetworkStatusResponse{
+		Interface: ws.setupManager.ifname,
+		SetupMode: ws.setupManager.Active(),
+	}
+
+	wifi, err := network.ActiveWifi(resp.Interface)
+	if err != nil {
+		requestLogger(c).Warn("unable to get active wifi network", "error", err)
+	} else if wifi != nil {
+		resp.SSID = wifi.SSID
+		resp.Signal = wifi.Signal
+	}
+
+	resp.IPAddresses, err = network.IPAddresses()
+	if err != nil {
+		requestLogger(c).Warn("unable to get ip addresses", "error", err)
+	}
+
+	resp.Connectivity, err = network.Connectivity()
+	if err != nil {
+		requestLogger(c).Warn("unable to check internet connectivity", "error", err)
+	}
+	resp.Internet = resp.Connectivity == "full"
+
+	resp.S3Enabled = ws.remoteManager.Enabled()
+	checkedAt, reachableAt, s3Err := ws.remoteManager.S3Status()
+	if !checkedAt.IsZero() {
+		resp.S3LastCheckedAt = &checkedAt
+	}
+	if !reachableAt.IsZero() {
+		resp.S3LastReachableAt = &reachableAt
+	}
+	if s3Err != nil {
+		resp.S3LastError = s3Err.Error()
+	}
+	resp.S3FailedDownloads = ws.remoteManager.FailedDownloads()
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,220 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/i18n"
+	"github.com/aouyang1/digitalphotoframe/notify"
+	"github.com/aouyang1/digitalphotoframe/paths"
+	"github.com/aouyang1/digitalphotoframe/store"
+)
+
+// uploads are made one photo per request, so they are counted until none arrive for this long
+// and announced together
+const uploadBatchDelay = time.Minute
+
+// PhotoNotifier tells the frame owner when a sync added photos, linking to where they can be seen
+// or approved in the web ui. Chat webhooks are also told about uploads and failed syncs. Each of
+// these is recorded in the activity feed whether or not anyone is notified.
+type PhotoNotifier struct {
+	db        *store.Database
+	notifiers notify.Notifiers
+	webhooks  notify.Notifiers
+
+	// address the web ui is reached at from the owner's phone
+	baseURL string
+
+	mu sync.Mutex
+	// photos uploaded by each uploader since the last announcement
+	uploads     map[string]int
+	uploadTimer *time.Timer
+	// sources whose last sync failed, so a sync failing every interval is only announced once
+	failing map[string]bool
+}
+
+func NewPhotoNotifier(db *store.Database) *PhotoNotifier {
+	return &PhotoNotifier{
+		db:        db,
+		notifiers: notify.FromEnv(),
+		webhooks:  notify.WebhooksFromEnv(),
+		baseURL:   publicURL(),
+		uploads:   make(map[string]int),
+		failing:   make(map[string]bool),
+	}
+}
+
+// publicURL is DPF_PUBLIC_URL, defaulting to the frame's mDNS name
+func publicURL() string {
+	if publicURL := os.Getenv("DPF_PUBLIC_URL"); publicURL != "" {
+		return strings.TrimSuffix(publicURL, "/")
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		slog.Warn("unable to get hostname for notification links", "error", err)
+		return ""
+	}
+	publicURL := "http://" + hostname + ".local"
+	if port := os.Getenv("DPF_PORT"); port != "" && port != "80" {
+		publicURL += ":" + port
+	}
+	return publicURL
+}
+
+// NewPhotos notifies that count photos were added to the category from source, such as the
+// shared bucket, noting when they are waiting for approval
+func (n *PhotoNotifier) NewPhotos(ctx context.Context, count int, category int, source string) {
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	delete(n.failing, source)
+	n.mu.Unlock()
+
+	if count == 0 {
+		return
+	}
+	recordEvent(n.db, store.EventSync, source, count, "")
+	if len(n.notifiers)+len(n.webhooks) == 0 {
+		return
+	}
+
+	settings, err := n.db.GetAppSettings()
+	if err != nil {
+		slog.Warn("unable to get settings for notification", "error", err)
+		return
+	}
+	lang := settings.Language
+	// sources without a translation, such as an rclone remote, are shown as they are
+	source = i18n.Translate(lang, source)
+
+	body := i18n.Translate(lang, "%d new photos from %s", count, source)
+	if count == 1 {
+		body = i18n.Translate(lang, "1 new photo from %s", source)
+	}
+	// recently added photos are listed first, while photos waiting for approval are with the rest
+	// of the surprise photos
+	anchor := "recent-photos"
+	if category == paths.CategorySurprise && settings.ApproveSurprise {
+		body += ", " + i18n.Translate(lang, "waiting for approval")
+		anchor = "surprise-photos"
+	}
+
+	n.send(ctx, slices.Concat(n.notifiers, n.webhooks), notify.Message{
+		Title: i18n.Translate(lang, "New photos on the frame"),
+		Body:  body,
+		URL:   n.link(anchor),
+	})
+}
+
+// Uploaded counts a photo uploaded through the web ui, a guest link, or webdav, recording and
+// announcing the photos from each uploader to the chat webhooks once the uploads stop
+func (n *PhotoNotifier) Uploaded(uploadedBy string) {
+	if n == nil {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.uploads[uploadedBy]++
+	if n.uploadTimer != nil {
+		n.uploadTimer.Stop()
+	}
+	n.uploadTimer = time.AfterFunc(uploadBatchDelay, n.announceUploads)
+}
+
+func (n *PhotoNotifier) announceUploads() {
+	n.mu.Lock()
+	uploads := n.uploads
+	n.uploads = make(map[string]int)
+	n.mu.Unlock()
+
+	for uploadedBy, count := range uploads {
+		recordEvent(n.db, store.EventUpload, uploadedBy, count, "")
+	}
+	if len(n.webhooks) == 0 {
+		return
+	}
+
+	settings, err := n.db.GetAppSettings()
+	if err != nil {
+		slog.Warn("unable to get settings for notification", "error", err)
+		return
+	}
+	lang := settings.Language
+
+	for uploadedBy, count := range uploads {
+		var body string
+		switch {
+		case uploadedBy == "" && count == 1:
+			body = i18n.Translate(lang, "1 photo was added to the frame")
+		case uploadedBy == "":
+			body = i18n.Translate(lang, "%d photos were added to the frame", count)
+		case count == 1:
+			body = i18n.Translate(lang, "%s added 1 photo to the frame", uploadedBy)
+		default:
+			body = i18n.Translate(lang, "%s added %d photos to the frame", uploadedBy, count)
+		}
+		n.send(context.Background(), n.webhooks, notify.Message{
+			Title: i18n.Translate(lang, "New photos on the frame"),
+			Body:  body,
+			URL:   n.link("recent-photos"),
+		})
+	}
+}
+
+// SyncFailed records and tells the chat webhooks that syncing from source failed, once until a sync
+// from it succeeds again
+func (n *PhotoNotifier) SyncFailed(ctx context.Context, source string, syncErr error) {
+	if n == nil {
+		return
+	}
+
+	n.mu.Lock()
+	announced := n.failing[source]
+	n.failing[source] = true
+	n.mu.Unlock()
+	if announced {
+		return
+	}
+	recordEvent(n.db, store.EventSyncFailed, source, 0, syncErr.Error())
+	if len(n.webhooks) == 0 {
+		return
+	}
+
+	settings, err := n.db.GetAppSettings()
+	if err != nil {
+		slog.Warn("unable to get settings for notification", "error", err)
+		return
+	}
+	lang := settings.Language
+
+	n.send(ctx, n.webhooks, notify.Message{
+		Title: i18n.Translate(lang, "Frame sync failed"),
+		Body:  i18n.Translate(lang, "Syncing photos from %s failed: %v", i18n.Translate(lang, source), syncErr),
+	})
+}
+
+// link returns the address of a section of the web ui, which is empty when the frame's address
+// isn't known
+func (n *PhotoNotifier) link(anchor string) string {
+	if n.baseURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/#%s", n.baseURL, anchor)
+}
+
+func (n *PhotoNotifier) send(ctx context.Context, notifiers notify.Notifiers, msg notify.Message) {
+	if err := notifiers.Send(ctx, msg); err != nil {
+		slog.Warn("unable to send notification", "body", msg.Body, "error", err)
+		return
+	}
+	slog.Info("sent notification", "body", msg.Body)
+}
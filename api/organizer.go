@@ -0,0 +1,213 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/imaging"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	organizeOff   = "off"
+	organizeMonth = "month"
+	organizeTrip  = "trip"
+
+	// defaultTripGap is how long between photos starts a new trip
+	defaultTripGap = 48 * time.Hour
+
+	// undatedAlbum holds photos without an exif date so they aren't reread on every import
+	undatedAlbum = "Undated"
+)
+
+var validOrganizeModes = []string{organizeOff, organizeMonth, organizeTrip}
+
+func applyOrganizeDefaults(settings *store.AppSettings) {
+	if settings.AutoOrganize == "" {
+		settings.AutoOrganize = organizeOff
+	}
+}
+
+func validOrganizeMode(mode string) bool {
+	return slices.Contains(validOrganizeModes, mode)
+}
+
+type photoKey struct {
+	name     string
+	category int
+}
+
+// organize assigns albums to new photos according to the auto organize setting
+func (ws *WebServer) organize() {
+	settings, err := ws.db.GetAppSettings()
+	if err != nil {
+		slog.Error("unable to get settings for organizing photos", "error", err)
+		return
+	}
+	if settings.AutoOrganize == "" || settings.AutoOrganize == organizeOff {
+		return
+	}
+
+	organized, err := ws.organizePhotos(settings.AutoOrganize)
+	if err != nil {
+		slog.Error("unable to organize photos", "mode", settings.AutoOrganize, "error", err)
+		return
+	}
+	if organized > 0 {
+		slog.Info("organized photos into albums", "mode", settings.AutoOrganize, "photos", organized)
+	}
+}
+
+// organizePhotos reads the exif date of every photo without an album or a date and groups them
+// into albums by month or by trip, returning how many photos were assigned an album
+func (ws *WebServer) organizePhotos(mode string) (int, error) {
+	photos, err := ws.getAllImages()
+	if err != nil {
+		return 0, err
+	}
+
+	albums := make(map[photoKey]string)
+	for i, photo := range photos {
+		// photos dated by a sidecar keep that date
+		if photo.Album != "" || !photo.TakenAt.IsZero() {
+			continue
+		}
+		taken, err := imaging.DateTaken(ws.paths.Original(photo.Category, photo.PhotoName))
+		if err != nil {
+			slog.Debug("photo has no exif date", "name", photo.PhotoName, "error", err)
+			albums[photoKey{photo.PhotoName, photo.Category}] = undatedAlbum
+			continue
+		}
+		photos[i].TakenAt = taken
+	}
+
+	switch mode {
+	case organizeMonth:
+		maps.Copy(albums, monthAlbums(photos))
+	case organizeTrip:
+		maps.Copy(albums, tripAlbums(photos, ws.tripGap))
+	default:
+		return 0, fmt.Errorf("invalid organize mode %s", mode)
+	}
+
+	var organized int
+	for _, photo := range photos {
+		album, ok := albums[photoKey{photo.PhotoName, photo.Category}]
+		if !ok {
+			continue
+		}
+		if err := ws.db.UpdatePhotoAlbum(photo.PhotoName, photo.Category, album, photo.TakenAt); err != nil {
+			return organized, err
+		}
+		organized++
+	}
+	return organized, nil
+}
+
+// monthAlbums names an album after the month each unorganized photo was taken in
+func monthAlbums(photos []store.Photo) map[photoKey]string {
+	albums := make(map[photoKey]string)
+	for _, photo := range photos {
+		if photo.Album != "" || photo.TakenAt.IsZero() {
+			continue
+		}
+		albums[photoKey{photo.PhotoName, photo.Category}] = photo.TakenAt.Format("January 2006")
+	}
+	return albums
+}
+
+// tripAlbums splits photos into trips wherever more than gap passes between consecutive photos.
+// Unorganized photos join the album already used by most of their trip, otherwise a new album is
+// named after the trip's dates.
+func tripAlbums(photos []store.Photo, gap time.Duration) map[photoKey]string {
+	var dated []store.Photo
+	for _, photo := range photos {
+		if !photo.TakenAt.IsZero() && photo.Album != undatedAlbum {
+			dated = append(dated, photo)
+		}
+	}
+	slices.SortFunc(dated, func(a, b store.Photo) int {
+		return a.TakenAt.Compare(b.TakenAt)
+	})
+
+	albums := make(map[photoKey]string)
+	for start := 0; start < len(dated); {
+		end := start + 1
+		for end < len(dated) && dated[end].TakenAt.Sub(dated[end-1].TakenAt) <= gap {
+			end++
+		}
+		trip := dated[start:end]
+		start = end
+
+		album := mostCommonAlbum(trip)
+		if album == "" {
+			album = tripName(trip[0].TakenAt, trip[len(trip)-1].TakenAt)
+		}
+		for _, photo := range trip {
+			if photo.Album == "" {
+				albums[photoKey{photo.PhotoName, photo.Category}] = album
+			}
+		}
+	}
+	return albums
+}
+
+func mostCommonAlbum(photos []store.Photo) string {
+	counts := make(map[string]int)
+	var album string
+	for _, photo := range photos {
+		if photo.Album == "" {
+			continue
+		}
+		counts[photo.Album]++
+		if counts[photo.Album] > counts[album] {
+			album = photo.Album
+		}
+	}
+	return album
+}
+
+// tripName describes the dates of a trip as compactly as possible, e.g. "Jul 3 - 9, 2024"
+func tripName(first, last time.Time) string {
+	switch {
+	case first.Year() != last.Year():
+		return first.Format("Jan 2, 2006") + " - " + last.Format("Jan 2, 2006")
+	case first.Month() != last.Month():
+		return first.Format("Jan 2") + " - " + last.Format("Jan 2, 2006")
+	case first.Day() != last.Day():
+		return first.Format("Jan 2") + " - " + last.Format("2, 2006")
+	default:
+		return first.Format("Jan 2, 2006")
+	}
+}
+
+// handleOrganizePhotos organizes photos without an album now, using the mode query parameter or
+// the auto organize setting
+func (ws *WebServer) handleOrganizePhotos(c *gin.Context) {
+	mode := c.Query("mode")
+	if mode == "" {
+		settings, err := ws.db.GetAppSettings()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get settings: %v", err)})
+			return
+		}
+		mode = settings.AutoOrganize
+	}
+	if mode != organizeMonth && mode != organizeTrip {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "mode must be %s or %s", organizeMonth, organizeTrip)})
+		return
+	}
+
+	organized, err := ws.organizePhotos(mode)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to organize photos: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, models.OrganizeResponse{Organized: organized})
+}
@@ -0,0 +1,472 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/client"
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/paths"
+	"github.com/aouyang1/digitalphotoframe/service"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/aouyang1/digitalphotoframe/util"
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	pairingInterval     = 2 * time.Minute
+	pairingSyncTimeout  = 10 * time.Minute
+	pairingCheckTimeout = 15 * time.Second
+
+	// pairPath is where a frame serves the albums it shares, followed by the token
+	pairPath = "/pair/"
+)
+
+// sharedPhotos lists the photos a shared album offers to paired frames, which are those in the
+// album that are shown in the slideshow. Photos this frame copied from other frames are left out
+// so two frames sharing an album with each other don't send the same photos back and forth.
+func (ws *WebServer) sharedPhotos(album string) ([]store.Photo, error) {
+	copiedIDs, err := ws.db.GetCopiedPhotoIDs()
+	if err != nil {
+		return nil, err
+	}
+	copied := mapset.NewThreadUnsafeSet(copiedIDs...)
+
+	var photos []store.Photo
+	for photo, err := range ws.db.AllPhotos(store.PhotoFilter{Category: paths.CategoryOriginal}) {
+		if err != nil {
+			return nil, err
+		}
+		if sharedPhoto(photo, album) && !copied.Contains(photo.ID) {
+			photos = append(photos, photo)
+		}
+	}
+	return photos, nil
+}
+
+// sharedPhoto reports whether the photo is in the album and shown in the slideshow, before
+// leaving out those copied from other frames
+func sharedPhoto(photo store.Photo, album string) bool {
+	return photo.Category == paths.CategoryOriginal && photo.Album == album && !photo.Hidden && !photo.Pending
+}
+
+// parsePairingURL splits the link a frame shares an album with, such as
+// http://frame.local:8080/pair/abc123, into the frame's base url and the token
+func parsePairingURL(link string) (string, string, error) {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return "", "", err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", "", errors.New("need an http or https url")
+	}
+	i := strings.LastIndex(u.Path, pairPath)
+	if i < 0 {
+		return "", "", fmt.Errorf("need a path ending in %s followed by the token", pairPath)
+	}
+	token := strings.TrimSuffix(u.Path[i+len(pairPath):], "/")
+	if token == "" || strings.Contains(token, "/") {
+		return "", "", fmt.Errorf("need a path ending in %s followed by the token", pairPath)
+	}
+
+	u.Path, u.RawPath, u.RawQuery, u.Fragment = u.Path[:i], "", "", ""
+	return u.String(), token, nil
+}
+
+// PairingManager copies the photos added to albums other frames share with this one into My
+// Photos, checking each of them every couple of minutes
+type PairingManager struct {
+	db           *store.Database
+	photoService *service.PhotoService
+	notifier     *PhotoNotifier
+
+	Updated chan bool
+}
+
+func NewPairingManager(db *store.Database, photoService *service.PhotoService, notifier *PhotoNotifier) (*PairingManager, error) {
+	if db == nil {
+		return nil, errors.New("no database provided for pairing manager")
+	}
+	if photoService == nil {
+		return nil, errors.New("no photo service provided for pairing manager")
+	}
+
+	return &PairingManager{
+		db:           db,
+		photoService: photoService,
+		notifier:     notifier,
+		Updated:      make(chan bool),
+	}, nil
+}
+
+func (p *PairingManager) syncAll() {
+	pairings, err := p.db.GetAlbumPairings()
+	if err != nil {
+		slog.Error("unable to get album pairings", "error", err)
+		return
+	}
+
+	var added int
+	for _, pairing := range pairings {
+		n, err := p.sync(pairing)
+		if err != nil {
+			slog.Warn("unable to sync paired album", "url", pairing.URL, "album", pairing.Album, "error", err)
+			p.notifier.SyncFailed(context.Background(), pairing.URL, err)
+		} else {
+			p.notifier.NewPhotos(context.Background(), n, paths.CategoryOriginal, pairing.URL)
+		}
+		added += n
+	}
+	if added > 0 {
+		p.Updated <- true
+	}
+}
+
+// sync copies the photos in the other frame's album that the pairing hasn't copied yet, returning
+// how many were added. Photos deleted here after being copied aren't copied again.
+func (p *PairingManager) sync(pairing store.AlbumPairing) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pairingSyncTimeout)
+	defer cancel()
+
+	pc := client.NewPhotoClient(pairing.URL)
+	remote, err := pc.GetPairedAlbum(ctx, pairing.Token)
+	if err != nil {
+		return 0, err
+	}
+	copiedIDs, err := p.db.GetPairedPhotoIDs(pairing.ID)
+	if err != nil {
+		return 0, err
+	}
+	copied := mapset.NewThreadUnsafeSet(copiedIDs...)
+
+	var photos []service.PairedPhoto
+	for _, photo := range remote.Photos {
+		if copied.Contains(photo.ID) {
+			continue
+		}
+		photos = append(photos, service.PairedPhoto{
+			Name: photo.PhotoName,
+			Open: func() (io.ReadCloser, error) {
+				data, err := pc.DownloadPairedPhoto(ctx, pairing.Token, photo.ID)
+				if err != nil {
+					return nil, err
+				}
+				return io.NopCloser(bytes.NewReader(data)), nil
+			},
+			Meta: &service.Sidecar{
+				Caption: photo.Caption,
+				Tags:    photo.Tags,
+				TakenAt: photo.TakenAt,
+			},
+			UploadedBy: photo.UploadedBy,
+			Copied: func(name string) {
+				var localID string
+				if name != "" {
+					local, err := p.db.GetPhoto(name, paths.CategoryOriginal)
+					if err != nil {
+						slog.Warn("unable to find copied paired photo", "album", pairing.Album, "name", name, "error", err)
+					} else if local != nil {
+						localID = local.ID
+					}
+				}
+				if err := p.db.InsertPairedPhoto(pairing.ID, photo.ID, localID); err != nil {
+					slog.Warn("unable to record copied paired photo", "album", pairing.Album, "id", photo.ID, "error", err)
+				}
+			},
+		})
+	}
+
+	var added int
+	if len(photos) > 0 {
+		report, err := p.photoService.ImportPaired(ctx, pairing.Album, photos, service.ImportOptions{})
+		if report != nil {
+			added = report.Added
+		}
+		if err != nil {
+			return added, err
+		}
+	}
+
+	if err := p.db.UpdateAlbumPairingSynced(pairing.ID, time.Now()); err != nil {
+		slog.Warn("unable to record album pairing sync", "album", pairing.Album, "error", err)
+	}
+	return added, nil
+}
+
+func (p *PairingManager) Run() {
+	ticker := time.NewTicker(pairingInterval)
+
+	p.syncAll()
+	for range ticker.C {
+		p.syncAll()
+	}
+}
+
+func (ws *WebServer) sharedAlbumResponse(c *gin.Context, sa store.SharedAlbum) models.SharedAlbumResponse {
+	return models.SharedAlbumResponse{
+		ID:        sa.ID,
+		Album:     sa.Album,
+		URL:       ws.requestBaseURL(c) + pairPath + sa.Token,
+		CreatedAt: sa.CreatedAt,
+	}
+}
+
+func (ws *WebServer) handleListSharedAlbums(c *gin.Context) {
+	albums, err := ws.db.GetSharedAlbums()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get shared albums: %v", err)})
+		return
+	}
+
+	resp := make([]models.SharedAlbumResponse, 0, len(albums))
+	for _, sa := range albums {
+		resp = append(resp, ws.sharedAlbumResponse(c, sa))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// handleCreateSharedAlbum opts an album in to being copied by other frames, responding with the
+// link they pair with it by
+func (ws *WebServer) handleCreateSharedAlbum(c *gin.Context) {
+	var req models.SharedAlbumRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
+		return
+	}
+
+	album := strings.TrimSpace(req.Album)
+	if album == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "album is required")})
+		return
+	}
+	if len([]rune(album)) > maxAlbumLength {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "album must be at most %d characters", maxAlbumLength)})
+		return
+	}
+
+	token, err := util.NewToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to generate pairing token: %v", err)})
+		return
+	}
+
+	sa := &store.SharedAlbum{
+		Album:     album,
+		Token:     token,
+		CreatedAt: time.Now(),
+	}
+	if err := ws.db.InsertSharedAlbum(sa); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to share album: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusCreated, ws.sharedAlbumResponse(c, *sa))
+}
+
+// handleDeleteSharedAlbum stops sharing an album, so the frames paired with it stop copying its
+// photos, while keeping the ones they already have
+func (ws *WebServer) handleDeleteSharedAlbum(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid shared album id")})
+		return
+	}
+
+	deleted, err := ws.db.DeleteSharedAlbum(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to stop sharing album: %v", err)})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Shared album %d not found", id)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "Stopped sharing album %d", id)})
+}
+
+// lookupSharedAlbum loads the album shared with the token in the path, writing a not found
+// response if it isn't shared
+func (ws *WebServer) lookupSharedAlbum(c *gin.Context) (*store.SharedAlbum, bool) {
+	sa, err := ws.db.GetSharedAlbum(c.Param("token"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to look up shared album: %v", err)})
+		return nil, false
+	}
+	if sa == nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "This album is no longer shared")})
+		return nil, false
+	}
+	return sa, true
+}
+
+// handlePairedAlbum lists the photos in a shared album for the frames paired with it to copy
+func (ws *WebServer) handlePairedAlbum(c *gin.Context) {
+	sa, ok := ws.lookupSharedAlbum(c)
+	if !ok {
+		return
+	}
+
+	photos, err := ws.sharedPhotos(sa.Album)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
+		return
+	}
+
+	resp := models.PairedAlbumResponse{
+		Album:  sa.Album,
+		Photos: make([]models.PairedPhoto, 0, len(photos)),
+	}
+	for _, photo := range photos {
+		resp.Photos = append(resp.Photos, models.PairedPhoto{
+			ID:         photo.ID,
+			PhotoName:  photo.PhotoName,
+			UploadedBy: photo.UploadedBy,
+			Caption:    photo.Caption,
+			Tags:       photo.Tags,
+			TakenAt:    photo.TakenAt,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// handlePairedPhoto serves a photo in a shared album to the frames paired with it
+func (ws *WebServer) handlePairedPhoto(c *gin.Context) {
+	sa, ok := ws.lookupSharedAlbum(c)
+	if !ok {
+		return
+	}
+
+	photo, err := ws.db.GetPhotoByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
+		return
+	}
+	if photo == nil || !sharedPhoto(*photo, sa.Album) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Photo with id '%s' not found", c.Param("id"))})
+		return
+	}
+	// photos copied from other frames are theirs to share
+	copied, err := ws.db.IsCopiedPhoto(photo.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
+		return
+	}
+	if copied {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Photo with id '%s' not found", photo.ID)})
+		return
+	}
+
+	filePath := ws.paths.Original(photo.Category, photo.PhotoName)
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Photo with id '%s' not found", photo.ID)})
+		return
+	}
+
+	settings, err := ws.db.GetAppSettings()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get settings")})
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	if !settings.StripExif {
+		c.File(filePath)
+		return
+	}
+	// photos copied to paired frames leave this one, so they're sent like share links are
+	if err := serveStripped(c, filePath); err != nil {
+		requestLogger(c).Warn("unable to strip metadata from paired photo", "name", photo.PhotoName, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to prepare photo")})
+	}
+}
+
+func (ws *WebServer) handleListAlbumPairings(c *gin.Context) {
+	pairings, err := ws.db.GetAlbumPairings()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get album pairings: %v", err)})
+		return
+	}
+	if pairings == nil {
+		pairings = []store.AlbumPairing{}
+	}
+	c.JSON(http.StatusOK, pairings)
+}
+
+// handleCreateAlbumPairing pairs with an album another frame shares, checking the link works
+// before saving it. Its photos are copied on the next check.
+func (ws *WebServer) handleCreateAlbumPairing(c *gin.Context) {
+	var req models.AlbumPairingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
+		return
+	}
+
+	baseURL, token, err := parsePairingURL(req.URL)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid pairing url: %v", err)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), pairingCheckTimeout)
+	defer cancel()
+	remote, err := client.NewPhotoClient(baseURL, client.WithRetries(1, 0)).GetPairedAlbum(ctx, token)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, models.ErrorResponse{Error: tr(c, "Unable to reach the shared album: %v", err)})
+		return
+	}
+
+	album := strings.TrimSpace(req.Album)
+	if album == "" {
+		album = remote.Album
+	}
+	if len([]rune(album)) > maxAlbumLength {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "album must be at most %d characters", maxAlbumLength)})
+		return
+	}
+
+	pairing := &store.AlbumPairing{
+		URL:   baseURL,
+		Token: token,
+		Album: album,
+	}
+	if err := ws.db.InsertAlbumPairing(pairing); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to create album pairing: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusCreated, pairing)
+}
+
+// handleDeleteAlbumPairing stops copying from another frame's album, keeping the photos already
+// copied
+func (ws *WebServer) handleDeleteAlbumPairing(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid album pairing id")})
+		return
+	}
+
+	deleted, err := ws.db.DeleteAlbumPairing(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to delete album pairing: %v", err)})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Album pairing %d not found", id)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "Album pairing %d deleted successfully", id)})
+}
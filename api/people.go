@@ -0,0 +1,358 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+// maxPersonNameLength keeps names short enough to fit on a tag in the ui
+const maxPersonNameLength = 64
+
+// peopleInPhotos maps each photo, by its id, to the ids of the people in it, whether they were
+// tagged on the frame or the photo was imported with a tag of their name
+type peopleInPhotos struct {
+	tagged map[string][]int64
+	byName map[string]int64
+}
+
+func newPeopleInPhotos(people []store.Person, tagged []store.PhotoPerson) *peopleInPhotos {
+	p := &peopleInPhotos{
+		tagged: make(map[string][]int64),
+		byName: make(map[string]int64, len(people)),
+	}
+	for _, person := range people {
+		p.byName[strings.ToLower(person.Name)] = person.ID
+	}
+	for _, pp := range tagged {
+		p.tagged[pp.PhotoID] = append(p.tagged[pp.PhotoID], pp.PersonID)
+	}
+	return p
+}
+
+// of returns the ids of the people in the photo
+func (p *peopleInPhotos) of(photo store.Photo) []int64 {
+	ids := slices.Clone(p.tagged[photo.ID])
+	for _, tag := range photo.Tags {
+		if id, ok := p.byName[strings.ToLower(tag)]; ok && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// loadPeopleInPhotos reads the people and who is tagged in which photos
+func (ws *WebServer) loadPeopleInPhotos() ([]store.Person, *peopleInPhotos, error) {
+	people, err := ws.db.GetPeople()
+	if err != nil {
+		return nil, nil, err
+	}
+	tagged, err := ws.db.GetPhotoPeople()
+	if err != nil {
+		return nil, nil, err
+	}
+	return people, newPeopleInPhotos(people, tagged), nil
+}
+
+// applyPeople keeps only the photos of people shown exclusively in each group. When none of them
+// are in any photo left to show, the groups are left as they are so the slideshow isn't left
+// empty.
+func applyPeople(groups [][]store.Photo, people []store.Person, in *peopleInPhotos) [][]store.Photo {
+	var exclusive []int64
+	for _, person := range people {
+		if person.Exclusive {
+			exclusive = append(exclusive, person.ID)
+		}
+	}
+	if len(exclusive) == 0 {
+		return groups
+	}
+
+	filtered := make([][]store.Photo, len(groups))
+	var n int
+	for i, group := range groups {
+		filtered[i] = slices.DeleteFunc(slices.Clone(group), func(photo store.Photo) bool {
+			return !slices.ContainsFunc(in.of(photo), func(id int64) bool {
+				return slices.Contains(exclusive, id)
+			})
+		})
+		n += len(filtered[i])
+	}
+	if n == 0 {
+		return groups
+	}
+	return filtered
+}
+
+// photosOf returns every photo the person is in, newest first within each category
+func (ws *WebServer) photosOf(id int64, in *peopleInPhotos) ([]store.Photo, error) {
+	photos := []store.Photo{}
+	for _, category := range []int{1, 0} {
+		for photo, err := range ws.db.AllPhotos(store.PhotoFilter{Category: category}) {
+			if err != nil {
+				return nil, err
+			}
+			if slices.Contains(in.of(photo), id) {
+				photos = append(photos, photo)
+			}
+		}
+	}
+	return photos, nil
+}
+
+func (ws *WebServer) handleListPeople(c *gin.Context) {
+	people, in, err := ws.loadPeopleInPhotos()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get people: %v", err)})
+		return
+	}
+
+	counts := make(map[int64]int)
+	for _, category := range []int{0, 1} {
+		for photo, err := range ws.db.AllPhotos(store.PhotoFilter{Category: category}) {
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
+				return
+			}
+			for _, id := range in.of(photo) {
+				counts[id]++
+			}
+		}
+	}
+
+	resp := make([]models.PersonResponse, 0, len(people))
+	for _, person := range people {
+		resp = append(resp, models.PersonResponse{
+			ID:         person.ID,
+			Name:       person.Name,
+			Exclusive:  person.Exclusive,
+			PhotoCount: counts[person.ID],
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// bindPerson reads and validates a person from the request body, writing the error response when
+// it isn't valid or their name is already taken by someone else
+func (ws *WebServer) bindPerson(c *gin.Context, id int64) (*store.Person, bool) {
+	var req store.Person
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
+		return nil, false
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "name is required")})
+		return nil, false
+	}
+	if len([]rune(req.Name)) > maxPersonNameLength {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "name must be at most %d characters", maxPersonNameLength)})
+		return nil, false
+	}
+
+	people, err := ws.db.GetPeople()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get people: %v", err)})
+		return nil, false
+	}
+	if slices.ContainsFunc(people, func(p store.Person) bool {
+		return p.ID != id && strings.EqualFold(p.Name, req.Name)
+	}) {
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: tr(c, "%s is already someone's name", req.Name)})
+		return nil, false
+	}
+
+	return &store.Person{
+		ID:        id,
+		Name:      req.Name,
+		Exclusive: req.Exclusive,
+	}, true
+}
+
+func (ws *WebServer) handleCreatePerson(c *gin.Context) {
+	person, ok := ws.bindPerson(c, 0)
+	if !ok {
+		return
+	}
+
+	if err := ws.db.InsertPerson(person); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to create person: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusCreated, person)
+
+	// photos imported with a tag of their name are theirs straight away
+	if person.Exclusive {
+		ws.requestRestart()
+	}
+}
+
+func (ws *WebServer) handleUpdatePerson(c *gin.Context) {
+	id, ok := parsePersonID(c)
+	if !ok {
+		return
+	}
+	person, ok := ws.bindPerson(c, id)
+	if !ok {
+		return
+	}
+
+	updated, err := ws.db.UpdatePerson(person)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to update person: %v", err)})
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Person %d not found", id)})
+		return
+	}
+
+	c.JSON(http.StatusOK, person)
+
+	ws.requestRestart()
+}
+
+func (ws *WebServer) handleDeletePerson(c *gin.Context) {
+	id, ok := parsePersonID(c)
+	if !ok {
+		return
+	}
+
+	deleted, err := ws.db.DeletePerson(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to delete person: %v", err)})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Person %d not found", id)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "Person %d deleted successfully", id)})
+
+	ws.requestRestart()
+}
+
+// handlePersonPhotos lists the photos a person is in
+func (ws *WebServer) handlePersonPhotos(c *gin.Context) {
+	id, ok := parsePersonID(c)
+	if !ok {
+		return
+	}
+
+	people, in, err := ws.loadPeopleInPhotos()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get people: %v", err)})
+		return
+	}
+	if !slices.ContainsFunc(people, func(p store.Person) bool { return p.ID == id }) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Person %d not found", id)})
+		return
+	}
+
+	photos, err := ws.photosOf(id, in)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, photos)
+}
+
+// handlePhotoPeople lists the people in a photo
+func (ws *WebServer) handlePhotoPeople(c *gin.Context) {
+	category, name, ok := parsePhotoFileParams(c)
+	if !ok {
+		return
+	}
+
+	photo, err := ws.db.GetPhoto(name, category)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
+		return
+	}
+	if photo == nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Photo '%s' in category %d not found", name, category)})
+		return
+	}
+
+	people, in, err := ws.loadPeopleInPhotos()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get people: %v", err)})
+		return
+	}
+
+	ids := in.of(*photo)
+	resp := models.PhotoPeopleResponse{People: []store.Person{}}
+	for _, person := range people {
+		if slices.Contains(ids, person.ID) {
+			resp.People = append(resp.People, person)
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// handleUpdatePhotoPeople replaces the people tagged in a photo on the frame. People named in the
+// tags it was imported with stay in it.
+func (ws *WebServer) handleUpdatePhotoPeople(c *gin.Context) {
+	category, name, ok := parsePhotoFileParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.PhotoPeopleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
+		return
+	}
+
+	photo, err := ws.db.GetPhoto(name, category)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
+		return
+	}
+	if photo == nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Photo '%s' in category %d not found", name, category)})
+		return
+	}
+
+	people, err := ws.db.GetPeople()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get people: %v", err)})
+		return
+	}
+	for _, id := range req.People {
+		if !slices.ContainsFunc(people, func(p store.Person) bool { return p.ID == id }) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Person %d not found", id)})
+			return
+		}
+	}
+
+	if err := ws.db.SetPhotoPeople(photo.ID, req.People); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to update photo: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, req)
+
+	// only the people shown exclusively change which photos are played
+	if slices.ContainsFunc(people, func(p store.Person) bool { return p.Exclusive }) {
+		ws.requestRestart()
+	}
+}
+
+func parsePersonID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid person id")})
+		return 0, false
+	}
+	return id, true
+}
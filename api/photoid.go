@@ -0,0 +1,34 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// photoByID looks up the photo named by the :id in the path and fills in its category and name,
+// so the handlers of the name based routes serve the same requests by id
+func (ws *WebServer) photoByID(c *gin.Context) {
+	photo, err := ws.db.GetPhotoByID(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
+		return
+	}
+	if photo == nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Photo with id '%s' not found", c.Param("id"))})
+		return
+	}
+
+	c.Set("photo", photo)
+	c.Params = append(c.Params,
+		gin.Param{Key: "category", Value: strconv.Itoa(photo.Category)},
+		gin.Param{Key: "name", Value: photo.PhotoName},
+	)
+	c.Next()
+}
+
+func (ws *WebServer) handleGetPhoto(c *gin.Context) {
+	c.JSON(http.StatusOK, c.MustGet("photo"))
+}
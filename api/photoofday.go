@@ -0,0 +1,121 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/store"
+)
+
+const (
+	photoOfDayInterval    = time.Minute
+	defaultPhotoOfDayTime = "06:00"
+)
+
+// PhotoOfDayManager periodically checks whether the photo of the day should change over so the
+// slideshow can be restarted with the next photo
+type PhotoOfDayManager struct {
+	db *store.Database
+
+	// start of the photo of the day period last seen
+	lastDay time.Time
+
+	Updated chan bool
+}
+
+func NewPhotoOfDayManager(db *store.Database) (*PhotoOfDayManager, error) {
+	if db == nil {
+		return nil, errors.New("no database provided for photo of the day")
+	}
+
+	return &PhotoOfDayManager{
+		db:      db,
+		Updated: make(chan bool),
+	}, nil
+}
+
+func (p *PhotoOfDayManager) checkPhotoOfDay() {
+	settings, err := p.db.GetAppSettings()
+	if err != nil {
+		slog.Error("unable to get settings for photo of the day", "error", err)
+		return
+	}
+	if !settings.PhotoOfDayEnabled {
+		p.lastDay = time.Time{}
+		return
+	}
+
+	day, err := photoOfDayStart(settings.PhotoOfDayTime, time.Now())
+	if err != nil {
+		slog.Warn("photo of the day time with invalid format", "time", settings.PhotoOfDayTime, "error", err)
+		return
+	}
+
+	// the slideshow is already showing the current day's photo when first enabled or started
+	if !p.lastDay.IsZero() && !day.Equal(p.lastDay) {
+		slog.Info("changing photo of the day", "day", day)
+		p.Updated <- true
+	}
+	p.lastDay = day
+}
+
+func (p *PhotoOfDayManager) Run() {
+	ticker := time.NewTicker(photoOfDayInterval)
+
+	p.checkPhotoOfDay()
+	for range ticker.C {
+		p.checkPhotoOfDay()
+	}
+}
+
+// photoOfDayStart returns when the photo of the day on display at now started showing, given the
+// HH:MM time photos change at each day
+func photoOfDayStart(changeTime string, now time.Time) (time.Time, error) {
+	t, err := time.Parse("15:04", changeTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	start := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+	if now.Before(start) {
+		start = start.AddDate(0, 0, -1)
+	}
+	return start, nil
+}
+
+func applyPhotoOfDayDefaults(settings *store.AppSettings) {
+	if settings.PhotoOfDayTime == "" {
+		settings.PhotoOfDayTime = defaultPhotoOfDayTime
+	}
+}
+
+// photoOfDay picks the photo to show all day. The pinned photo is used if it still exists and
+// can be played, otherwise one photo from the playlist is chosen per day, rotating through them in
+// order.
+func (ws *WebServer) photoOfDay(settings *store.AppSettings, playlist []store.Photo, now time.Time) ([]store.Photo, error) {
+	if settings.PhotoOfDayID != "" {
+		photo, err := ws.db.GetPhotoByID(settings.PhotoOfDayID)
+		if err != nil {
+			return nil, err
+		}
+		if photo != nil && isPlayable(*photo) {
+			return []store.Photo{*photo}, nil
+		}
+		slog.Warn("pinned photo of the day not found or not playable, rotating daily instead", "id", settings.PhotoOfDayID)
+	}
+
+	if len(playlist) == 0 {
+		return nil, nil
+	}
+
+	start, err := photoOfDayStart(settings.PhotoOfDayTime, now)
+	if err != nil {
+		return nil, fmt.Errorf("invalid photo of the day time %s, %w", settings.PhotoOfDayTime, err)
+	}
+
+	// count days independent of time zone changes so each photo gets exactly one day
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC).Unix() / int64(24*time.Hour/time.Second)
+	return []store.Photo{playlist[day%int64(len(playlist))]}, nil
+}
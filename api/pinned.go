@@ -0,0 +1,67 @@
+package api
+
+import (
+	"math/rand"
+	"net/http"
+	"slices"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/store"
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/gin-gonic/gin"
+)
+
+// includePinned adds the pinned photos a playlist left out, such as those in a category that isn't
+// included or is weighted 0, at random places when it is shuffled and at the end otherwise.
+// Pinned photos that are hidden or waiting for approval stay out.
+func includePinned(playlist, pinned []store.Photo, shuffled bool) []store.Photo {
+	ids := mapset.NewThreadUnsafeSet[string]()
+	for _, photo := range playlist {
+		ids.Add(photo.ID)
+	}
+	for _, photo := range pinned {
+		if !isPlayable(photo) || ids.Contains(photo.ID) {
+			continue
+		}
+		if shuffled {
+			playlist = slices.Insert(playlist, rand.Intn(len(playlist)+1), photo)
+		} else {
+			playlist = append(playlist, photo)
+		}
+	}
+	return playlist
+}
+
+// handleUpdatePhotoPinned pins a photo into every playlist, or unpins it
+func (ws *WebServer) handleUpdatePhotoPinned(c *gin.Context) {
+	category, name, ok := parsePhotoFileParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.PhotoPinnedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
+		return
+	}
+
+	exists, err := ws.db.PhotoExists(name, category)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Photo '%s' in category %d not found", name, category)})
+		return
+	}
+
+	if err := ws.db.UpdatePhotoPinned(name, category, req.Pinned); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to update photo: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, req)
+
+	// trigger slideshow restart
+	ws.requestRestart()
+}
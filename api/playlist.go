@@ -0,0 +1,102 @@
+package api
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+
+	"github.com/aouyang1/digitalphotoframe/store"
+)
+
+const (
+	// playlistOrderSequential plays each category in full before the next
+	playlistOrderSequential = "sequential"
+
+	// playlistOrderRoundRobin alternates between albums so a large one doesn't drown out a small
+	// one
+	playlistOrderRoundRobin = "round_robin"
+)
+
+var validPlaylistOrders = []string{playlistOrderSequential, playlistOrderRoundRobin}
+
+// maxAlbumWeight bounds how many times a photo can repeat in the playlist
+const maxAlbumWeight = 10
+
+func applyPlaylistDefaults(settings *store.AppSettings) {
+	if settings.PlaylistOrder == "" {
+		settings.PlaylistOrder = playlistOrderSequential
+	}
+}
+
+func validPlaylistOrder(order string) bool {
+	return slices.Contains(validPlaylistOrders, order)
+}
+
+func validAlbumWeights(weights map[string]int) bool {
+	for album, weight := range weights {
+		if album == "" || len([]rune(album)) > maxAlbumLength || weight < 0 || weight > maxAlbumWeight {
+			return false
+		}
+	}
+	return true
+}
+
+func shufflePhotos(photos []store.Photo) {
+	rand.Shuffle(len(photos), func(i, j int) {
+		photos[i], photos[j] = photos[j], photos[i]
+	})
+}
+
+// interleave takes one photo from each group in turn until the largest group has been fully
+// played. Smaller groups start over from their first photo when they run out so every group
+// gets an equal share of the slideshow.
+func interleave(groups [][]store.Photo) []store.Photo {
+	var longest int
+	for _, group := range groups {
+		longest = max(longest, len(group))
+	}
+
+	var photos []store.Photo
+	for i := range longest {
+		for _, group := range groups {
+			if len(group) == 0 {
+				continue
+			}
+			photos = append(photos, group[i%len(group)])
+		}
+	}
+	return photos
+}
+
+// weightedShuffle returns photos in random order with each photo repeated by its album's weight.
+// Repeats of the same photo are spread evenly through the playlist rather than bunched together.
+func weightedShuffle(photos []store.Photo, weights map[string]int) []store.Photo {
+	type slot struct {
+		photo store.Photo
+		key   float64
+	}
+
+	var slots []slot
+	for _, photo := range photos {
+		weight, ok := weights[photo.Album]
+		if !ok || photo.Album == "" {
+			weight = 1
+		}
+		// place the k-th copy at a random point within the k-th 1/weight of the playlist
+		for k := range weight {
+			slots = append(slots, slot{
+				photo: photo,
+				key:   (float64(k) + rand.Float64()) / float64(weight),
+			})
+		}
+	}
+	slices.SortFunc(slots, func(a, b slot) int {
+		return cmp.Compare(a.key, b.key)
+	})
+
+	shuffled := make([]store.Photo, len(slots))
+	for i, s := range slots {
+		shuffled[i] = s.photo
+	}
+	return shuffled
+}
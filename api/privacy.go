@@ -0,0 +1,60 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/aouyang1/digitalphotoframe/imaging"
+	"github.com/aouyang1/digitalphotoframe/paths"
+	"github.com/gin-gonic/gin"
+)
+
+// removeDerivatives deletes the slideshow copies of every photo so they are made again from the
+// originals on the next restart, such as after EXIF stripping is turned on or off
+func (ws *WebServer) removeDerivatives() error {
+	for _, category := range paths.Categories {
+		dir := ws.paths.DerivativeDir(category)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("unable to read directory, %s, %w", dir, err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !paths.IsDerivative(entry.Name()) {
+				continue
+			}
+			if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("unable to remove slideshow copy, %s, %w", entry.Name(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// serveStripped serves a copy of the photo without its EXIF and other identifying metadata,
+// leaving the file on disk untouched
+func serveStripped(c *gin.Context, filePath string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	if err := imaging.StripMetadata(&buf, f, filePath); err != nil {
+		return err
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(filePath))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+	return nil
+}
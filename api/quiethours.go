@@ -0,0 +1,76 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+// what the frame does between a schedule's end and its next start
+const (
+	quietOff  = "off"
+	quietDim  = "dim"
+	quietSlow = "slow"
+)
+
+var validQuietActions = []string{quietOff, quietDim, quietSlow}
+
+// applyQuietHoursDefaults fills in quiet hours for schedules saved before they could be changed
+func applyQuietHoursDefaults(q *store.QuietHours) {
+	if q.Action == "" {
+		q.Action = store.DefaultQuietHours.Action
+	}
+	if q.DimPercent == 0 {
+		q.DimPercent = store.DefaultQuietHours.DimPercent
+	}
+	if q.SlowIntervalSeconds == 0 {
+		q.SlowIntervalSeconds = store.DefaultQuietHours.SlowIntervalSeconds
+	}
+}
+
+func validQuietAction(action string) bool {
+	return slices.Contains(validQuietActions, action)
+}
+
+func validDimPercent(percent int) bool {
+	return percent >= 1 && percent <= 100
+}
+
+// validQuietHours writes a bad request response and returns false if the quiet hours are invalid
+func (ws *WebServer) validQuietHours(c *gin.Context, q store.QuietHours) bool {
+	switch {
+	case !validQuietAction(q.Action):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "action must be one of %s", strings.Join(validQuietActions, ", "))})
+	case !validDimPercent(q.DimPercent):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "dim_percent must be between 1 and 100")})
+	case q.SlowIntervalSeconds < 0:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "slow_interval_seconds must be positive")})
+	default:
+		return true
+	}
+	return false
+}
+
+// quietInterval returns the slideshow interval while the schedule is slowing the slideshow down
+// for quiet hours
+func quietInterval(db *store.Database, now time.Time) (int, bool) {
+	schedule, _, err := currentSchedule(db, now)
+	if err != nil {
+		slog.Warn("unable to get schedule for slideshow interval", "error", err)
+		return 0, false
+	}
+	applyQuietHoursDefaults(&schedule.QuietHours)
+	if !schedule.Enabled || schedule.Action != quietSlow {
+		return 0, false
+	}
+	if _, quiet := displayOffUntil(schedule, now); !quiet {
+		return 0, false
+	}
+	return schedule.SlowIntervalSeconds, true
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/paths"
+	"github.com/aouyang1/digitalphotoframe/runner"
+	"github.com/aouyang1/digitalphotoframe/service"
+	"github.com/aouyang1/digitalphotoframe/thermal"
+	"github.com/aouyang1/digitalphotoframe/util"
+	mapset "github.com/deckarep/golang-set/v2"
+)
+
+// rcloneMaxDuration stops a sync that runs too long, the rest is copied on the next sync
+const rcloneMaxDuration = "1h"
+
+// RcloneManager copies photos from any storage rclone supports, such as Google Drive, Dropbox, or
+// OneDrive, into a category. Surprise photos mirror the remote the way they do s3, while My Photos
+// are only added to so photos uploaded to the frame are kept.
+type RcloneManager struct {
+	// rclone sync is skipped when no remote is configured
+	enabled bool
+
+	// remote path in rclone's remote:path form
+	remote     string
+	configPath string
+	category   int
+	outputPath string
+
+	photoService *service.PhotoService
+	notifier     *PhotoNotifier
+
+	// requests to sync right away rather than waiting for the next check
+	trigger chan struct{}
+
+	Updated chan bool
+}
+
+func NewRcloneManager(photoService *service.PhotoService, notifier *PhotoNotifier, layout paths.Layout, s3Enabled bool) (*RcloneManager, error) {
+	disabled := &RcloneManager{trigger: make(chan struct{}, 1), Updated: make(chan bool, 1)}
+
+	remote := os.Getenv("DPF_RCLONE_REMOTE")
+	if remote == "" {
+		slog.Info("no rclone remote provided in environment variable DPF_RCLONE_REMOTE, rclone sync disabled")
+		return disabled, nil
+	}
+
+	category := paths.CategorySurprise
+	if categoryStr := os.Getenv("DPF_RCLONE_CATEGORY"); categoryStr != "" {
+		parsed, err := strconv.Atoi(categoryStr)
+		if err != nil || (parsed != paths.CategorySurprise && parsed != paths.CategoryOriginal) {
+			slog.Warn("unable to parse DPF_RCLONE_CATEGORY, using default", "DPF_RCLONE_CATEGORY", categoryStr, "default", category)
+		} else {
+			category = parsed
+		}
+	}
+	if category == paths.CategorySurprise && s3Enabled {
+		slog.Warn("surprise photos are already synced from s3, rclone sync disabled")
+		return disabled, nil
+	}
+
+	outputPath := layout.OriginalDir(category)
+	if err := os.MkdirAll(outputPath, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create rclone output directory, %w", err)
+	}
+
+	return &RcloneManager{
+		enabled:      true,
+		remote:       remote,
+		configPath:   os.Getenv("DPF_RCLONE_CONFIG"),
+		category:     category,
+		outputPath:   outputPath,
+		photoService: photoService,
+		notifier:     notifier,
+		trigger:      make(chan struct{}, 1),
+		Updated:      make(chan bool, 1),
+	}, nil
+}
+
+// Mirrors returns the remote when the category is kept identical to it, which removes photos added
+// to the category any other way, or empty otherwise
+func (r *RcloneManager) Mirrors(category int) string {
+	if !r.enabled || category != r.category || category != paths.CategorySurprise {
+		return ""
+	}
+	return r.remote
+}
+
+// args are the rclone arguments to bring the category up to date with the remote
+func (r *RcloneManager) args() []string {
+	mode := "copy"
+	if r.category == paths.CategorySurprise {
+		mode = "sync"
+	}
+
+	// sidecars are copied along with photos so their metadata is imported
+	var include []string
+	for ext := range util.SupportedExt.Union(util.SidecarExt).Iter() {
+		include = append(include, strings.TrimPrefix(ext, "."))
+	}
+	slices.Sort(include)
+
+	args := []string{
+		mode, r.remote, r.outputPath,
+		// photos are downsized in place, so files already copied are left alone rather than
+		// copied again for differing from the remote
+		"--ignore-existing",
+		"--max-depth", "1",
+		"--include", "*.{" + strings.Join(include, ",") + "}",
+		"--max-duration", rcloneMaxDuration,
+	}
+	if r.configPath != "" {
+		args = append(args, "--config", r.configPath)
+	}
+	return args
+}
+
+// Sync runs rclone and registers the photos it copied, deregistering those it removed
+func (r *RcloneManager) Sync() error {
+	before, err := r.getLocalFiles()
+	if err != nil {
+		return err
+	}
+
+	slog.Info("syncing with rclone remote", "remote", r.remote, "category", r.category)
+	if out, err := runner.Default().Run("rclone", r.args()...); err != nil {
+		return fmt.Errorf("rclone failed, %s, %w", out, err)
+	}
+
+	after, err := r.getLocalFiles()
+	if err != nil {
+		return err
+	}
+	added, removed, err := r.photoService.Reconcile(r.category, after, true)
+	if err != nil {
+		return fmt.Errorf("unable to reconcile rclone photos, %w", err)
+	}
+	slog.Info("rclone sync finished", "added", added, "removed", removed)
+	r.notifier.NewPhotos(context.Background(), added, r.category, r.remote)
+
+	if !before.Equal(after) || added > 0 || removed > 0 {
+		select {
+		case r.Updated <- true:
+		default:
+		}
+	}
+	return nil
+}
+
+func (r *RcloneManager) getLocalFiles() (mapset.Set[string], error) {
+	entries, err := os.ReadDir(r.outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read directory, %s, %w", r.outputPath, err)
+	}
+
+	localFiles := mapset.NewSet[string]()
+	for _, entry := range entries {
+		if !entry.IsDir() && util.SupportedExt.Contains(filepath.Ext(entry.Name())) {
+			localFiles.Add(entry.Name())
+		}
+	}
+	return localFiles, nil
+}
+
+func (r *RcloneManager) Run() {
+	if !r.enabled {
+		return
+	}
+	ticker := time.NewTicker(remoteCheckInterval)
+
+	r.syncAndReport()
+	for {
+		select {
+		case <-ticker.C:
+		case <-r.trigger:
+		}
+		r.syncAndReport()
+	}
+}
+
+// TriggerSync asks for a sync with the rclone remote right away, returning false when rclone sync
+// is disabled. A sync already waiting to run isn't queued twice.
+func (r *RcloneManager) TriggerSync() bool {
+	if !r.enabled {
+		return false
+	}
+	select {
+	case r.trigger <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+func (r *RcloneManager) syncAndReport() {
+	// the photos rclone copies are processed along with it, so syncing waits out a hot cpu
+	thermal.Wait(context.Background(), 0, 1)
+
+	if err := r.Sync(); err != nil {
+		slog.Warn("error while syncing with rclone remote", "error", err)
+		r.notifier.SyncFailed(context.Background(), r.remote, err)
+	}
+}
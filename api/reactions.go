@@ -0,0 +1,217 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/overlay"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// comments are kept short so they read as a reaction rather than a conversation
+	maxCommentLength = 200
+
+	maxReactionNameLength = 64
+
+	// reactionRedrawDelay is how long after the last heart the hearts on screen are redrawn, so a
+	// burst of them restarts imv once
+	reactionRedrawDelay = 10 * time.Second
+)
+
+// withReactions fills in how many hearts and comments each photo has. Photos are listed without
+// them if the reactions can't be read.
+func (ws *WebServer) withReactions(photos []store.Photo) []store.Photo {
+	counts, err := ws.db.GetReactionCounts()
+	if err != nil {
+		slog.Warn("unable to get reaction counts, listing photos without them", "error", err)
+		return photos
+	}
+	if len(counts) == 0 {
+		return photos
+	}
+
+	byPhoto := make(map[string]store.ReactionCount, len(counts))
+	for _, rc := range counts {
+		byPhoto[rc.PhotoID] = rc
+	}
+	for i := range photos {
+		rc := byPhoto[photos[i].ID]
+		photos[i].Hearts = rc.Hearts
+		photos[i].Comments = rc.Comments
+	}
+	return photos
+}
+
+// reactionText is the line shown over a photo with the hearts it has, or empty if it has none
+func reactionText(photo store.Photo) string {
+	if photo.Hearts == 0 {
+		return ""
+	}
+	// the caption font has no emoji, so the heart is the card suit
+	return fmt.Sprintf("♥ %d", photo.Hearts)
+}
+
+// handlePhotoReactions lists the hearts and comments left on a photo, newest first
+func (ws *WebServer) handlePhotoReactions(c *gin.Context) {
+	category, name, ok := parsePhotoFileParams(c)
+	if !ok {
+		return
+	}
+
+	photo, err := ws.db.GetPhoto(name, category)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
+		return
+	}
+	if photo == nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Photo '%s' in category %d not found", name, category)})
+		return
+	}
+
+	reactions, err := ws.db.GetReactions(photo.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get reactions: %v", err)})
+		return
+	}
+	if reactions == nil {
+		reactions = []store.Reaction{}
+	}
+	c.JSON(http.StatusOK, reactions)
+}
+
+// handleCreateReaction leaves a heart, a short comment, or both on a photo
+func (ws *WebServer) handleCreateReaction(c *gin.Context) {
+	category, name, ok := parsePhotoFileParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.ReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	req.Comment = strings.TrimSpace(req.Comment)
+	if !req.Heart && req.Comment == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "a heart or comment is required")})
+		return
+	}
+	if len([]rune(req.Comment)) > maxCommentLength {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "comment must be at most %d characters", maxCommentLength)})
+		return
+	}
+	if len([]rune(req.Name)) > maxReactionNameLength {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "name must be at most %d characters", maxReactionNameLength)})
+		return
+	}
+
+	photo, err := ws.db.GetPhoto(name, category)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
+		return
+	}
+	if photo == nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Photo '%s' in category %d not found", name, category)})
+		return
+	}
+
+	reaction := &store.Reaction{
+		PhotoID:   photo.ID,
+		PhotoName: name,
+		Category:  category,
+		Name:      req.Name,
+		Heart:     req.Heart,
+		Comment:   req.Comment,
+		CreatedAt: time.Now(),
+	}
+	if err := ws.db.InsertReaction(reaction); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to add reaction: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusCreated, reaction)
+
+	if req.Heart {
+		ws.reactionsChanged(photo.ID)
+	}
+}
+
+func (ws *WebServer) handleDeleteReaction(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid reaction id")})
+		return
+	}
+
+	photoID, err := ws.db.DeleteReaction(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to delete reaction: %v", err)})
+		return
+	}
+	if photoID == "" {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Reaction %d not found", id)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "Reaction %d deleted successfully", id)})
+
+	ws.reactionsChanged(photoID)
+}
+
+// reactionsChanged redraws the hearts shown over the photo once reactions stop coming in for a
+// moment, keeping the slideshow where it is rather than restarting the playlist
+func (ws *WebServer) reactionsChanged(photoID string) {
+	ws.reactionsMu.Lock()
+	defer ws.reactionsMu.Unlock()
+	ws.reacted.Add(photoID)
+	if ws.reactionsTimer != nil {
+		ws.reactionsTimer.Stop()
+	}
+	ws.reactionsTimer = time.AfterFunc(reactionRedrawDelay, ws.redrawReactions)
+}
+
+// redrawReactions redraws the captions of the photos whose reactions changed, which is only
+// needed when hearts are shown
+func (ws *WebServer) redrawReactions() {
+	ws.reactionsMu.Lock()
+	ids := ws.reacted.ToSlice()
+	ws.reacted.Clear()
+	ws.reactionsMu.Unlock()
+
+	settings, err := ws.db.GetAppSettings()
+	if err != nil {
+		slog.Warn("unable to get settings, not updating reactions on screen", "error", err)
+		return
+	}
+	if !settings.ShowReactions {
+		return
+	}
+
+	var photos []store.Photo
+	for _, id := range ids {
+		photo, err := ws.db.GetPhotoByID(id)
+		if err != nil {
+			slog.Warn("unable to get photo, not updating its reactions on screen", "id", id, "error", err)
+			continue
+		}
+		if photo != nil {
+			photos = append(photos, *photo)
+		}
+	}
+
+	changed := make(map[string]overlay.Text, len(photos))
+	for _, photo := range ws.withReactions(photos) {
+		changed[ws.paths.Derivative(photo.Category, photo.PhotoName)] = ws.photoCaption(photo, settings)
+	}
+	if err := ws.controller.Recaption(changed); err != nil {
+		slog.Warn("unable to update reactions on screen", "error", err)
+	}
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/api/web/templates"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+// how far back and how many photos the recent feed returns by default and at most
+const (
+	defaultRecentDays  = 7
+	maxRecentDays      = 365
+	defaultRecentLimit = 20
+	maxRecentLimit     = 100
+)
+
+// recentPhotos returns the newest photos added within the days and limit query parameters. If
+// they are invalid an error message is returned.
+func (ws *WebServer) recentPhotos(c *gin.Context) (time.Time, []store.Photo, int, string) {
+	days := defaultRecentDays
+	if daysStr := c.Query("days"); daysStr != "" {
+		parsed, err := strconv.Atoi(daysStr)
+		if err != nil || parsed < 1 || parsed > maxRecentDays {
+			return time.Time{}, nil, http.StatusBadRequest, tr(c, "days must be between 1 and %d", maxRecentDays)
+		}
+		days = parsed
+	}
+	limit := defaultRecentLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > maxRecentLimit {
+			return time.Time{}, nil, http.StatusBadRequest, tr(c, "limit must be between 1 and %d", maxRecentLimit)
+		}
+		limit = parsed
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	photos, err := ws.db.GetRecentPhotos(since, limit)
+	if err != nil {
+		return time.Time{}, nil, http.StatusInternalServerError, tr(c, "Error fetching photos: %v", err)
+	}
+	return since, photos, http.StatusOK, ""
+}
+
+// handleRecentPhotos returns the newest photos across categories, a week's worth by default
+func (ws *WebServer) handleRecentPhotos(c *gin.Context) {
+	since, photos, status, msg := ws.recentPhotos(c)
+	if msg != "" {
+		c.JSON(status, models.ErrorResponse{Error: msg})
+		return
+	}
+	if photos == nil {
+		photos = []store.Photo{}
+	}
+	c.JSON(http.StatusOK, models.RecentPhotosResponse{Since: since, Photos: photos})
+}
+
+// handleUIRecentPhotos renders the new this week strip of the photos page
+func (ws *WebServer) handleUIRecentPhotos(c *gin.Context) {
+	_, photos, status, msg := ws.recentPhotos(c)
+	if msg != "" {
+		c.String(status, msg)
+		return
+	}
+
+	component := templates.RecentPhotoRow(photos)
+	component.Render(c.Request.Context(), c.Writer)
+}
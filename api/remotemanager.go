@@ -2,15 +2,31 @@ package api
 
 import (
 	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"hash"
+	"io"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/aouyang1/digitalphotoframe/api/client"
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/paths"
+	"github.com/aouyang1/digitalphotoframe/service"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/aouyang1/digitalphotoframe/thermal"
 	"github.com/aouyang1/digitalphotoframe/util"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -20,56 +36,226 @@ import (
 	mapset "github.com/deckarep/golang-set/v2"
 )
 
-const remoteCheckInterval = time.Duration(24 * time.Hour)
+const (
+	remoteCheckInterval = time.Duration(24 * time.Hour)
+
+	// each object is attempted this many times per sync, doubling the wait between attempts
+	downloadAttempts   = 4
+	downloadMinBackoff = time.Duration(2 * time.Second)
+
+	// objects downloaded at once and how long each attempt at one may take by default
+	defaultDownloadConcurrency = 4
+	defaultDownloadTimeout     = time.Duration(5 * time.Minute)
+
+	// how often to check for the display off window when only syncing while the display is off
+	syncWindowCheckInterval = time.Duration(15 * time.Minute)
+
+	syncTimeout = time.Duration(30 * time.Minute)
+
+	// downloads are written to hidden files matching this until they are verified, which the sync
+	// ignores since they don't have a photo's extension
+	partialDownloadPattern = ".s3-download-*.part"
+
+	// quarantined photos are kept this long before they are deleted for good
+	quarantineRetention = time.Duration(30 * 24 * time.Hour)
+)
+
+// What happens to local photos removed from the bucket
+const (
+	// deletePolicyMirror deletes them so the frame matches the bucket
+	deletePolicyMirror = "mirror"
+
+	// deletePolicyAdditive keeps them, so photos are only ever added
+	deletePolicyAdditive = "additive"
+
+	// deletePolicyQuarantine moves them to the quarantine directory, deleting them after
+	// quarantineRetention
+	deletePolicyQuarantine = "quarantine"
+)
 
 type RemoteManager struct {
+	// s3 sync is skipped when no bucket is configured so the frame can run with only local photos
+	enabled bool
+
 	client *s3.Client
 
 	s3Bucket string
 
 	outputPath string
 
-	photoClient *client.PhotoClient
+	db           *store.Database
+	photoService *service.PhotoService
+	notifier     *PhotoNotifier
+
+	// results of the most recent attempt to reach s3
+	statusMu        sync.Mutex
+	lastCheckedAt   time.Time
+	lastReachableAt time.Time
+	lastErr         error
+
+	// objects that failed to download, persisted to failuresPath so they survive restarts
+	failuresPath string
+	failures     map[string]models.SyncFailure
+
+	// limits download bandwidth when set
+	limiter *byteRateLimiter
+
+	// how many objects are downloaded at once and how long each attempt at one may take
+	concurrency     int
+	downloadTimeout time.Duration
+
+	// deletePolicy is what happens to local photos removed from the bucket, and maxDeletions the
+	// most that may be removed in one sync when positive
+	deletePolicy  string
+	maxDeletions  int
+	quarantineDir string
+
+	// conflictPolicy is what happens to photos changed locally or in the bucket since they were
+	// synced, going by the versions in synced, which is persisted to syncedPath
+	conflictPolicy string
+	synced         map[string]syncedObject
+	syncedPath     string
+
+	// only sync while the schedule has the display turned off, picking up where the last sync
+	// stopped until a full sync completes
+	offHoursOnly bool
+	lastSyncedAt time.Time
+
+	// requests to sync right away rather than waiting for the next check
+	trigger chan struct{}
 
 	Updated chan bool
 }
 
-func NewRemoteManager() (*RemoteManager, error) {
+func NewRemoteManager(db *store.Database, photoService *service.PhotoService, notifier *PhotoNotifier) (*RemoteManager, error) {
 	// if empty then defaults to current directory
 	rootPath := os.Getenv("DPF_ROOT_PATH")
 	if rootPath == "" {
 		rootPath = "."
 	}
-	outputPath := filepath.Join(rootPath, "original/surprise")
+	layout := paths.New(rootPath)
+	outputPath := layout.OriginalDir(paths.CategorySurprise)
+
+	disabled := &RemoteManager{trigger: make(chan struct{}, 1), Updated: make(chan bool)}
 
 	s3Bucket := os.Getenv("DPF_S3_BUCKET")
 	if s3Bucket == "" {
-		return nil, errors.New("no s3 bucket provided in environment variable DPF_S3_BUCKET")
+		slog.Info("no s3 bucket provided in environment variable DPF_S3_BUCKET, s3 sync disabled")
+		return disabled, nil
+	}
+
+	s3Client, err := newS3Client()
+	if err != nil {
+		slog.Warn("unable to create s3 client, s3 sync disabled", "error", err)
+		return disabled, nil
+	}
+
+	var limiter *byteRateLimiter
+	if rateLimitStr := os.Getenv("DPF_S3_RATE_LIMIT_KBPS"); rateLimitStr != "" {
+		rateLimitKBps, err := strconv.Atoi(rateLimitStr)
+		if err != nil || rateLimitKBps <= 0 {
+			slog.Warn("unable to parse DPF_S3_RATE_LIMIT_KBPS, downloading without a limit", "DPF_S3_RATE_LIMIT_KBPS", rateLimitStr)
+		} else {
+			limiter = newByteRateLimiter(rateLimitKBps * 1024)
+		}
+	}
+
+	concurrency := defaultDownloadConcurrency
+	if concurrencyStr := os.Getenv("DPF_S3_CONCURRENCY"); concurrencyStr != "" {
+		if concurrency, err = strconv.Atoi(concurrencyStr); err != nil || concurrency <= 0 {
+			slog.Warn("unable to parse DPF_S3_CONCURRENCY, using default", "DPF_S3_CONCURRENCY", concurrencyStr, "default", defaultDownloadConcurrency)
+			concurrency = defaultDownloadConcurrency
+		}
+	}
+
+	downloadTimeout := defaultDownloadTimeout
+	if timeoutStr := os.Getenv("DPF_S3_DOWNLOAD_TIMEOUT_SECONDS"); timeoutStr != "" {
+		timeoutSeconds, err := strconv.Atoi(timeoutStr)
+		if err != nil || timeoutSeconds <= 0 {
+			slog.Warn("unable to parse DPF_S3_DOWNLOAD_TIMEOUT_SECONDS, using default", "DPF_S3_DOWNLOAD_TIMEOUT_SECONDS", timeoutStr, "default", defaultDownloadTimeout)
+		} else {
+			downloadTimeout = time.Duration(timeoutSeconds) * time.Second
+		}
+	}
+
+	deletePolicy := deletePolicyMirror
+	if policy := os.Getenv("DPF_S3_DELETE_POLICY"); policy != "" {
+		switch policy {
+		case deletePolicyMirror, deletePolicyAdditive, deletePolicyQuarantine:
+			deletePolicy = policy
+		default:
+			slog.Warn("unable to parse DPF_S3_DELETE_POLICY, using default", "DPF_S3_DELETE_POLICY", policy, "default", deletePolicyMirror)
+		}
+	}
+
+	var maxDeletions int
+	if maxDeletionsStr := os.Getenv("DPF_S3_MAX_DELETIONS"); maxDeletionsStr != "" {
+		if maxDeletions, err = strconv.Atoi(maxDeletionsStr); err != nil || maxDeletions <= 0 {
+			slog.Warn("unable to parse DPF_S3_MAX_DELETIONS, deleting without a limit", "DPF_S3_MAX_DELETIONS", maxDeletionsStr)
+			maxDeletions = 0
+		}
+	}
+
+	conflictPolicy := conflictPolicyRemote
+	if policy := os.Getenv("DPF_S3_CONFLICT_POLICY"); policy != "" {
+		switch policy {
+		case conflictPolicyRemote, conflictPolicyLocal, conflictPolicyBoth:
+			conflictPolicy = policy
+		default:
+			slog.Warn("unable to parse DPF_S3_CONFLICT_POLICY, using default", "DPF_S3_CONFLICT_POLICY", policy, "default", conflictPolicyRemote)
+		}
+	}
+
+	r := &RemoteManager{
+		enabled:         true,
+		client:          s3Client,
+		s3Bucket:        s3Bucket,
+		outputPath:      outputPath,
+		db:              db,
+		photoService:    photoService,
+		notifier:        notifier,
+		failuresPath:    layout.SyncFailures(),
+		failures:        make(map[string]models.SyncFailure),
+		limiter:         limiter,
+		concurrency:     concurrency,
+		downloadTimeout: downloadTimeout,
+		deletePolicy:    deletePolicy,
+		maxDeletions:    maxDeletions,
+		quarantineDir:   layout.QuarantineDir(),
+		conflictPolicy:  conflictPolicy,
+		synced:          make(map[string]syncedObject),
+		syncedPath:      layout.SyncedObjects(),
+		offHoursOnly:    os.Getenv("DPF_S3_SYNC_OFF_HOURS") == "1",
+		trigger:         make(chan struct{}, 1),
+		Updated:         make(chan bool),
+	}
+	if err := r.loadFailures(); err != nil {
+		slog.Warn("unable to load failed s3 downloads", "error", err)
+	}
+	if err := r.loadSynced(); err != nil {
+		slog.Warn("unable to load synced s3 objects", "error", err)
+	}
+	return r, nil
+}
+
+// newS3Client creates an Amazon S3 service client from the shared AWS configuration (~/.aws/config)
+// using the DPF_AWS_PROFILE profile if set
+func newS3Client() (*s3.Client, error) {
+	var opts []func(*config.LoadOptions) error
+	if profile := os.Getenv("DPF_AWS_PROFILE"); profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(profile))
 	}
 
-	// Load the Shared AWS Configuration (~/.aws/config)
 	ctxCfg, cancelCfg := context.WithTimeout(context.Background(), time.Duration(3*time.Second))
 	cfg, err := config.LoadDefaultConfig(
 		ctxCfg,
+		opts...,
 	)
 	cancelCfg()
 	if err != nil {
 		return nil, err
 	}
-
-	// Create an Amazon S3 service client
-	s3Client := s3.NewFromConfig(cfg)
-
-	// Initialize photo client if web server URL is available
-	photoClient := client.NewPhotoClient(webServerURL)
-
-	return &RemoteManager{
-		client:      s3Client,
-		s3Bucket:    s3Bucket,
-		outputPath:  outputPath,
-		photoClient: photoClient,
-		Updated:     make(chan bool),
-	}, nil
+	return s3.NewFromConfig(cfg), nil
 }
 
 func (r *RemoteManager) GetS3Objects(ctx context.Context) ([]s3types.Object, error) {
@@ -87,24 +273,220 @@ func (r *RemoteManager) GetS3Objects(ctx context.Context) ([]s3types.Object, err
 	return output.Contents, nil
 }
 
+// DownloadObject downloads an object to a temporary file in the output directory and moves it into
+// place once its size and checksum match what s3 reports, so an interrupted or corrupted download
+// never leaves a truncated photo behind
 func (r *RemoteManager) DownloadObject(ctx context.Context, name string) error {
-	downloader := manager.NewDownloader(r.client)
+	head, err := r.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:       aws.String(r.s3Bucket),
+		Key:          aws.String(name),
+		ChecksumMode: s3types.ChecksumModeEnabled,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to get s3 object details, %s, %w", name, err)
+	}
 
-	f, err := os.Create(filepath.Join(r.outputPath, name))
+	f, err := os.CreateTemp(r.outputPath, partialDownloadPattern)
 	if err != nil {
 		return fmt.Errorf("unable to create file for s3 download, %s, %w", name, err)
 	}
-	defer f.Close()
+	// remove the partial file unless it was moved into place
+	defer func() {
+		f.Close()
+		if rmErr := os.Remove(f.Name()); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
+			slog.Warn("unable to remove partial s3 download", "name", name, "error", rmErr)
+		}
+	}()
 
-	if _, err := downloader.Download(ctx, f, &s3.GetObjectInput{
+	var w io.WriterAt = f
+	if r.limiter != nil {
+		w = &rateLimitedWriterAt{ctx: ctx, w: f, limiter: r.limiter}
+	}
+
+	downloader := manager.NewDownloader(r.client)
+	n, err := downloader.Download(ctx, w, &s3.GetObjectInput{
 		Bucket: aws.String(r.s3Bucket),
 		Key:    aws.String(name),
-	}); err != nil {
+		// fail rather than mix parts of two versions if the object is replaced mid download
+		IfMatch: head.ETag,
+	})
+	if err != nil {
 		return fmt.Errorf("unable to download object from s3, %s, %w", name, err)
 	}
+	if err := verifyDownload(f, n, head); err != nil {
+		return fmt.Errorf("downloaded object from s3 is corrupt, %s, %w", name, err)
+	}
+
+	// flush before the rename so a power cut can't leave a complete name with partial content
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("unable to write s3 download, %s, %w", name, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("unable to write s3 download, %s, %w", name, err)
+	}
+	if err := os.Rename(f.Name(), filepath.Join(r.outputPath, name)); err != nil {
+		return fmt.Errorf("unable to move s3 download into place, %s, %w", name, err)
+	}
+	r.recordSynced(name, syncedObject{ETag: aws.ToString(head.ETag), Size: n})
+	return nil
+}
+
+// verifyDownload checks a downloaded file against the size and checksum s3 reports for the object.
+// The sha256 checksum is used when the object was uploaded with one, otherwise the etag, which is
+// the md5 of the content unless the object was uploaded in parts or encrypted with kms. Objects
+// with neither are only checked by size.
+func verifyDownload(f *os.File, n int64, head *s3.HeadObjectOutput) error {
+	if size := aws.ToInt64(head.ContentLength); n != size {
+		return fmt.Errorf("got %d bytes, expected %d", n, size)
+	}
+
+	// s3 reports sha256 checksums in base64 and etags in hex
+	var h hash.Hash
+	var expected string
+	var encode func([]byte) string
+	etag := strings.ToLower(strings.Trim(aws.ToString(head.ETag), `"`))
+	switch {
+	case head.ChecksumSHA256 != nil && head.ChecksumType != s3types.ChecksumTypeComposite:
+		h, expected, encode = sha256.New(), *head.ChecksumSHA256, base64.StdEncoding.EncodeToString
+	case isMD5ETag(etag, head):
+		h, expected, encode = md5.New(), etag, hex.EncodeToString
+	default:
+		return nil
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+
+	if actual := encode(h.Sum(nil)); actual != expected {
+		return fmt.Errorf("checksum %s doesn't match %s", actual, expected)
+	}
+	return nil
+}
+
+// isMD5ETag reports whether an object's etag is the md5 of its content, which it isn't for objects
+// uploaded in parts, whose etags end in the part count, or encrypted with a kms or customer key
+func isMD5ETag(etag string, head *s3.HeadObjectOutput) bool {
+	if len(etag) != md5.Size*2 || strings.Contains(etag, "-") || head.SSECustomerAlgorithm != nil {
+		return false
+	}
+	switch head.ServerSideEncryption {
+	case s3types.ServerSideEncryptionAwsKms, s3types.ServerSideEncryptionAwsKmsDsse:
+		return false
+	}
+	return true
+}
+
+// removePartialDownloads removes temporary files left behind by downloads cut off when the frame
+// lost power or restarted
+func (r *RemoteManager) removePartialDownloads() {
+	partials, err := filepath.Glob(filepath.Join(r.outputPath, partialDownloadPattern))
+	if err != nil {
+		return
+	}
+	for _, partial := range partials {
+		if err := os.Remove(partial); err != nil {
+			slog.Warn("unable to remove partial s3 download", "path", partial, "error", err)
+		}
+	}
+}
+
+// downloadWithRetry attempts to download an object up to downloadAttempts times with exponential
+// backoff, giving up early if the context is done. Each attempt is cut off after downloadTimeout so
+// a stalled connection is retried rather than holding up the sync.
+func (r *RemoteManager) downloadWithRetry(ctx context.Context, name string) error {
+	backoff := downloadMinBackoff
+	var err error
+	for attempt := 1; attempt <= downloadAttempts; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, r.downloadTimeout)
+		err = r.DownloadObject(attemptCtx, name)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		if attempt == downloadAttempts {
+			break
+		}
+		slog.Warn("retrying s3 download", "name", name, "attempt", attempt, "backoff", backoff, "error", err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w, %w", err, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return err
+}
+
+// FailedDownloads lists the objects that failed to download, sorted by name
+func (r *RemoteManager) FailedDownloads() []models.SyncFailure {
+	r.statusMu.Lock()
+	defer r.statusMu.Unlock()
+
+	failures := make([]models.SyncFailure, 0, len(r.failures))
+	for _, name := range slices.Sorted(maps.Keys(r.failures)) {
+		failures = append(failures, r.failures[name])
+	}
+	return failures
+}
+
+func (r *RemoteManager) recordFailure(name string, err error) {
+	r.statusMu.Lock()
+	defer r.statusMu.Unlock()
+
+	failure := r.failures[name]
+	failure.Name = name
+	failure.Failures++
+	failure.LastError = err.Error()
+	failure.LastFailedAt = time.Now()
+	r.failures[name] = failure
+}
+
+// clearFailures forgets failed objects that have since downloaded or no longer exist in s3
+func (r *RemoteManager) clearFailures(remoteFiles, localFiles mapset.Set[string]) {
+	r.statusMu.Lock()
+	defer r.statusMu.Unlock()
+
+	for name := range r.failures {
+		if !remoteFiles.Contains(name) || localFiles.Contains(name) {
+			delete(r.failures, name)
+		}
+	}
+}
+
+func (r *RemoteManager) loadFailures() error {
+	content, err := os.ReadFile(r.failuresPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read %s, %w", r.failuresPath, err)
+	}
+
+	var failures []models.SyncFailure
+	if err := json.Unmarshal(content, &failures); err != nil {
+		return fmt.Errorf("unable to parse %s, %w", r.failuresPath, err)
+	}
+	for _, failure := range failures {
+		r.failures[failure.Name] = failure
+	}
 	return nil
 }
 
+func (r *RemoteManager) saveFailures() error {
+	content, err := json.Marshal(r.FailedDownloads())
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(r.failuresPath, content)
+}
+
 func (r *RemoteManager) getLocalFiles() (mapset.Set[string], error) {
 	dirs, err := os.ReadDir(r.outputPath)
 	if err != nil {
@@ -126,64 +508,98 @@ func (r *RemoteManager) getLocalFiles() (mapset.Set[string], error) {
 	return localFiles, nil
 }
 
-func (r *RemoteManager) getRemoteFiles(ctx context.Context) (mapset.Set[string], error) {
-	remoteFiles := mapset.NewSet[string]()
+// Enabled reports whether photos are synced from s3
+func (r *RemoteManager) Enabled() bool {
+	return r.enabled
+}
+
+// S3Status reports when s3 was last checked and reached, and the error from the last check
+func (r *RemoteManager) S3Status() (time.Time, time.Time, error) {
+	r.statusMu.Lock()
+	defer r.statusMu.Unlock()
+	return r.lastCheckedAt, r.lastReachableAt, r.lastErr
+}
+
+func (r *RemoteManager) recordS3Status(err error) {
+	r.statusMu.Lock()
+	defer r.statusMu.Unlock()
+
+	r.lastCheckedAt = time.Now()
+	r.lastErr = err
+	if err == nil {
+		r.lastReachableAt = r.lastCheckedAt
+	}
+}
+
+// getRemoteFiles returns the photos in the bucket keyed by name, and the sidecar kept alongside
+// each photo that has one
+func (r *RemoteManager) getRemoteFiles(ctx context.Context) (map[string]s3types.Object, map[string]string, error) {
+	remoteFiles := make(map[string]s3types.Object)
 	objects, err := r.GetS3Objects(ctx)
+	r.recordS3Status(err)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	sidecarKeys := mapset.NewThreadUnsafeSet[string]()
 	for object := range slices.Values(objects) {
 		name := aws.ToString(object.Key)
-		if !util.SupportedExt.Contains(filepath.Ext(name)) {
-			continue
+		switch {
+		case util.SupportedExt.Contains(filepath.Ext(name)):
+			remoteFiles[name] = object
+		case service.IsSidecar(name):
+			sidecarKeys.Add(name)
+		}
+	}
+
+	sidecars := make(map[string]string)
+	for name := range remoteFiles {
+		for _, candidate := range service.SidecarCandidates(name) {
+			if sidecarKeys.Contains(candidate) {
+				sidecars[name] = candidate
+				break
+			}
 		}
-		remoteFiles.Add(name)
 	}
 
-	if remoteFiles.Cardinality() == 0 {
+	if len(remoteFiles) == 0 {
 		slog.Info("no remote files found")
 	}
-	return remoteFiles, nil
+	return remoteFiles, sidecars, nil
 }
 
 func (r *RemoteManager) SyncFolder(ctx context.Context) error {
+	r.removePartialDownloads()
+
 	localFiles, err := r.getLocalFiles()
 	if err != nil {
 		return err
 	}
 
-	remoteFiles, err := r.getRemoteFiles(ctx)
+	remoteObjects, sidecars, err := r.getRemoteFiles(ctx)
 	if err != nil {
 		return err
 	}
+	remoteFiles := mapset.NewSetFromMapKeys(remoteObjects)
 
-	toDelete := localFiles.Difference(remoteFiles).ToSlice()
+	// local copies kept from conflicts aren't in the bucket but mustn't be removed
+	toDelete := slices.DeleteFunc(localFiles.Difference(remoteFiles).ToSlice(), r.kept)
 	toDownload := remoteFiles.Difference(localFiles).ToSlice()
+	toDownload = append(toDownload, r.resolveConflicts(localFiles.Intersect(remoteFiles), remoteObjects)...)
+	var removed int
+	var removeErr error
 	if len(toDelete) > 0 {
-		slog.Info("deleting local files", "count", len(toDelete), "names", toDelete)
-		for name := range slices.Values(toDelete) {
-			filePath := filepath.Join(r.outputPath, name)
-			if err := os.Remove(filePath); err != nil {
-				slog.Warn("unable to remove local file", "error", err)
-			}
+		removed, removeErr = r.removeMissing(toDelete)
+		if removeErr != nil {
+			slog.Warn("not removing local files missing from s3", "count", len(toDelete), "error", removeErr)
 		}
 	}
+	if r.deletePolicy == deletePolicyQuarantine {
+		r.purgeQuarantine()
+	}
+	var downloaded int
 	if len(toDownload) > 0 {
-		slog.Info("adding files", "count", len(toDownload), "names", toDownload)
-		for name := range slices.Values(toDownload) {
-			err := r.DownloadObject(ctx, name)
-			if err != nil {
-				slog.Warn("error while downloading s3 object", "name", name, "error", err)
-				continue
-			}
-
-			// Register photo in database via web server
-			photoPath := filepath.Join(r.outputPath, name)
-			if err := r.photoClient.RegisterPhotoIfNotExists(photoPath, 0); err != nil {
-				slog.Warn("error while registering photo", "name", name, "error", err)
-				// Continue even if registration fails - file is downloaded
-			}
-		}
+		slog.Info("adding files", "count", len(toDownload), "names", toDownload, "concurrency", r.concurrency)
+		downloaded = r.downloadAll(ctx, toDownload, sidecars)
 	}
 
 	// After syncing with S3, ensure DB is in sync with local files for category 0
@@ -192,60 +608,271 @@ func (r *RemoteManager) SyncFolder(ctx context.Context) error {
 	if err != nil {
 		slog.Warn("error getting local files for DB sync", "error", err)
 	} else {
-		// Ensure all local files are registered
-		for _, name := range localFiles.ToSlice() {
-			photoPath := filepath.Join(r.outputPath, name)
-			if err := r.photoClient.RegisterPhotoIfNotExists(photoPath, 0); err != nil {
-				slog.Warn("error while registering local photo", "name", name, "error", err)
-			}
+		r.clearFailures(remoteFiles, localFiles)
+		r.pruneSynced(localFiles)
+		// Ensure all local files are registered and photos no longer present are deregistered
+		if _, _, err := r.photoService.Reconcile(paths.CategorySurprise, localFiles, true); err != nil {
+			slog.Warn("error while reconciling synced photos", "error", err)
 		}
+	}
 
-		// Get all registered category 0 photos from DB
-		registeredPhotos, err := r.photoClient.GetPhotos(0)
-		if err != nil {
-			slog.Warn("error getting registered photos from DB", "error", err)
+	if failed := len(r.FailedDownloads()); failed > 0 {
+		slog.Warn("s3 sync finished with failed downloads", "downloaded", downloaded, "failed", failed)
+	}
+	if err := r.saveFailures(); err != nil {
+		slog.Warn("unable to save failed s3 downloads", "error", err)
+	}
+	if err := r.saveSynced(); err != nil {
+		slog.Warn("unable to save synced s3 objects", "error", err)
+	}
+
+	// Only signal update if there were actual changes
+	if removed > 0 || downloaded > 0 {
+		r.Updated <- true
+	}
+	r.notifier.NewPhotos(context.WithoutCancel(ctx), downloaded, paths.CategorySurprise, "the shared bucket")
+	// the sync itself went fine, but someone should check the bucket wasn't emptied by mistake
+	if removeErr != nil {
+		r.notifier.SyncFailed(context.WithoutCancel(ctx), "the shared bucket", removeErr)
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("s3 sync stopped after downloading %d of %d files, %w", downloaded, len(toDownload), err)
+	}
+	return nil
+}
+
+// removeMissing applies the delete policy to local photos no longer in the bucket, returning how
+// many were removed. None are removed when there are more than maxDeletions, since a bucket emptied
+// by mistake shouldn't wipe the frame.
+func (r *RemoteManager) removeMissing(names []string) (int, error) {
+	switch {
+	case r.deletePolicy == deletePolicyAdditive:
+		slog.Info("keeping local files removed from s3", "count", len(names))
+		return 0, nil
+	case r.maxDeletions > 0 && len(names) > r.maxDeletions:
+		return 0, fmt.Errorf("%d photos are missing from s3, more than the limit of %d that may be removed at once", len(names), r.maxDeletions)
+	}
+
+	slog.Info("removing local files", "count", len(names), "names", names, "policy", r.deletePolicy)
+	var removed int
+	for _, name := range names {
+		filePath := filepath.Join(r.outputPath, name)
+		var err error
+		if r.deletePolicy == deletePolicyQuarantine {
+			err = r.quarantine(filePath)
 		} else {
-			// Create set of registered photo names
-			registeredNames := mapset.NewSet[string]()
-			for _, photo := range registeredPhotos {
-				registeredNames.Add(photo.PhotoName)
+			err = os.Remove(filePath)
+		}
+		if err != nil {
+			slog.Warn("unable to remove local file", "error", err)
+			continue
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// quarantine moves a photo to the quarantine directory, marking it with the time it was moved so
+// it is kept for quarantineRetention from then
+func (r *RemoteManager) quarantine(filePath string) error {
+	if err := os.MkdirAll(r.quarantineDir, 0o755); err != nil {
+		return fmt.Errorf("unable to create quarantine directory, %w", err)
+	}
+	dest := filepath.Join(r.quarantineDir, filepath.Base(filePath))
+	if err := os.Rename(filePath, dest); err != nil {
+		return fmt.Errorf("unable to quarantine %s, %w", filePath, err)
+	}
+	now := time.Now()
+	if err := os.Chtimes(dest, now, now); err != nil {
+		slog.Warn("unable to mark quarantine time", "path", dest, "error", err)
+	}
+	return nil
+}
+
+// purgeQuarantine deletes photos quarantined longer than quarantineRetention
+func (r *RemoteManager) purgeQuarantine() {
+	entries, err := os.ReadDir(r.quarantineDir)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("unable to read quarantine directory", "error", err)
+		}
+		return
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() || time.Since(info.ModTime()) < quarantineRetention {
+			continue
+		}
+		filePath := filepath.Join(r.quarantineDir, entry.Name())
+		if err := os.Remove(filePath); err != nil {
+			slog.Warn("unable to delete quarantined file", "path", filePath, "error", err)
+			continue
+		}
+		slog.Info("deleted quarantined file", "name", entry.Name())
+	}
+}
+
+// Mirrors reports whether local surprise photos are removed when they are removed from the bucket,
+// in which case photos can't be added to surprise any other way
+func (r *RemoteManager) Mirrors() bool {
+	return r.enabled && r.deletePolicy != deletePolicyAdditive
+}
+
+// downloadAll downloads and registers the named objects, up to concurrency at a time depending on
+// the cpu temperature, returning how many were downloaded. A photo's sidecar, named in sidecars,
+// is downloaded before it's registered so its metadata is imported. Downloads stop when the
+// context is done and the remaining files are picked up next sync.
+func (r *RemoteManager) downloadAll(ctx context.Context, names []string, sidecars map[string]string) int {
+	queue := make(chan string)
+	go func() {
+		defer close(queue)
+		for _, name := range names {
+			select {
+			case queue <- name:
+			case <-ctx.Done():
+				return
 			}
+		}
+	}()
+
+	var downloaded atomic.Int64
+	var wg sync.WaitGroup
+	for slot := range min(r.concurrency, len(names)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				// workers step back as the cpu heats up so the slideshow doesn't stutter
+				if thermal.Wait(ctx, slot, r.concurrency) != nil {
+					return
+				}
+				name, ok := <-queue
+				if !ok {
+					return
+				}
 
-			// Find photos registered in DB but not present locally
-			toDeregister := registeredNames.Difference(localFiles).ToSlice()
-			if len(toDeregister) > 0 {
-				slog.Info("deregistering photos not present locally", "count", len(toDeregister), "names", toDeregister)
-				for _, name := range toDeregister {
-					if err := r.photoClient.DeletePhoto(name, 0); err != nil {
-						slog.Warn("error while deregistering photo", "name", name, "error", err)
+				err := r.downloadWithRetry(ctx, name)
+				if ctx.Err() != nil {
+					return
+				}
+				if err != nil {
+					slog.Warn("error while downloading s3 object", "name", name, "error", err)
+					r.recordFailure(name, err)
+					continue
+				}
+				downloaded.Add(1)
+
+				if sidecar, ok := sidecars[name]; ok {
+					if err := r.downloadWithRetry(ctx, sidecar); err != nil {
+						slog.Warn("error while downloading sidecar, registering photo without it", "name", name, "sidecar", sidecar, "error", err)
 					}
 				}
+
+				// Register the photo, or regenerate its copies when it replaced a conflicting one
+				err = r.photoService.Register(name, paths.CategorySurprise, "", true)
+				if errors.Is(err, service.ErrExists) {
+					err = r.photoService.Replaced(name, paths.CategorySurprise)
+				}
+				if err != nil {
+					slog.Warn("error while registering photo", "name", name, "error", err)
+					// Continue even if registration fails - file is downloaded
+				}
 			}
+		}()
+	}
+	wg.Wait()
+	return int(downloaded.Load())
+}
+
+func (r *RemoteManager) Run() {
+	if !r.enabled {
+		return
+	}
+
+	interval := remoteCheckInterval
+	if r.offHoursOnly {
+		interval = syncWindowCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+
+	// Initial sync
+	r.syncIfDue()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.syncIfDue()
+		case <-r.trigger:
+			ctx, cancel := context.WithTimeout(context.Background(), syncTimeout)
+			r.sync(ctx)
+			cancel()
 		}
 	}
+}
 
-	// Only signal update if there were actual changes
-	if len(toDelete) > 0 || len(toDownload) > 0 {
-		r.Updated <- true
+// TriggerSync asks for a sync with s3 right away, even outside the display off window, returning
+// false when s3 sync is disabled. A sync already waiting to run isn't queued twice.
+func (r *RemoteManager) TriggerSync() bool {
+	if !r.enabled {
+		return false
 	}
-	return nil
+	select {
+	case r.trigger <- struct{}{}:
+	default:
+	}
+	return true
 }
 
-func (r *RemoteManager) Run() {
-	ticker := time.NewTicker(remoteCheckInterval)
+// syncIfDue syncs with s3, and when only syncing while the display is off waits for the off
+// window and stops the sync once the display turns back on
+func (r *RemoteManager) syncIfDue() {
+	ctx, cancel := context.WithTimeout(context.Background(), syncTimeout)
+	defer cancel()
 
-	// Initial sync
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(30*time.Minute))
-	if err := r.SyncFolder(ctx); err != nil {
-		slog.Warn("error while syncing with remote", "error", err)
+	if !r.offHoursOnly && thermal.Hot() {
+		// a hot cpu leaves syncing for while the display is off, when a stutter isn't seen
+		schedule, _, err := currentSchedule(r.db, time.Now())
+		if err != nil {
+			slog.Warn("unable to get schedule for s3 sync", "error", err)
+			return
+		}
+		if _, off := displayOffUntil(schedule, time.Now()); schedule.Enabled && !off {
+			slog.Info("deferring s3 sync while the cpu is hot")
+			return
+		}
 	}
-	cancel()
 
-	for range ticker.C {
-		ctx, cancel = context.WithTimeout(context.Background(), time.Duration(30*time.Minute))
-		if err := r.SyncFolder(ctx); err != nil {
-			slog.Warn("error while syncing with remote", "error", err)
+	if r.offHoursOnly {
+		if !r.lastSyncedAt.IsZero() && time.Since(r.lastSyncedAt) < remoteCheckInterval {
+			return
 		}
-		cancel()
+
+		schedule, _, err := currentSchedule(r.db, time.Now())
+		if err != nil {
+			slog.Warn("unable to get schedule for s3 sync", "error", err)
+			return
+		}
+		if schedule.Enabled {
+			until, off := displayOffUntil(schedule, time.Now())
+			if !off {
+				return
+			}
+			ctx, cancel = context.WithDeadline(ctx, until)
+			defer cancel()
+		}
+	}
+	r.sync(ctx)
+}
+
+// sync syncs with s3, reporting failures other than running out of time
+func (r *RemoteManager) sync(ctx context.Context) {
+	if err := r.SyncFolder(ctx); err != nil {
+		slog.Warn("error while syncing with remote", "error", err)
+		// a sync cut short by its window is picked up next time rather than having failed
+		if ctx.Err() == nil {
+			r.notifier.SyncFailed(context.Background(), "the shared bucket", err)
+		}
+		return
 	}
+	r.lastSyncedAt = time.Now()
 }
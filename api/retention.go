@@ -0,0 +1,233 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/paths"
+	"github.com/aouyang1/digitalphotoframe/service"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	retentionInterval = time.Hour
+
+	retentionDelete  = "delete"
+	retentionArchive = "archive"
+
+	maxRetentionDays = 3650
+)
+
+// photoExpiry returns when the first retention rule for the photo's album removes or archives it,
+// and the rule. Photos already in the album get the rule's full days from when it was created, and
+// photos added before upload times were recorded never expire.
+func photoExpiry(photo store.Photo, rules []store.RetentionRule) (time.Time, store.RetentionRule, bool) {
+	var (
+		expiresAt time.Time
+		rule      store.RetentionRule
+	)
+	if photo.AddedAt.IsZero() || photo.Album == "" {
+		return expiresAt, rule, false
+	}
+	for _, r := range rules {
+		if r.Album != photo.Album {
+			continue
+		}
+		start := photo.AddedAt
+		if r.CreatedAt.After(start) {
+			start = r.CreatedAt
+		}
+		at := start.AddDate(0, 0, r.Days)
+		if expiresAt.IsZero() || at.Before(expiresAt) {
+			expiresAt, rule = at, r
+		}
+	}
+	return expiresAt, rule, !expiresAt.IsZero()
+}
+
+// withExpiry fills in when each photo expires so the ui can warn about it. Photos are listed
+// without it if the rules can't be read.
+func (ws *WebServer) withExpiry(photos []store.Photo) []store.Photo {
+	rules, err := ws.db.GetRetentionRules()
+	if err != nil {
+		slog.Warn("unable to get retention rules, listing photos without expiry", "error", err)
+		return photos
+	}
+	if len(rules) == 0 {
+		return photos
+	}
+	for i := range photos {
+		if photos[i].Category != paths.CategoryOriginal {
+			continue
+		}
+		if expiresAt, _, ok := photoExpiry(photos[i], rules); ok {
+			photos[i].ExpiresAt = expiresAt
+		}
+	}
+	return photos
+}
+
+// RetentionManager removes or archives the photos in My Photos that retention rules have expired.
+// Archived photos are hidden and taken out of the album, so showing one again keeps it.
+type RetentionManager struct {
+	db           *store.Database
+	photoService *service.PhotoService
+
+	Updated chan bool
+}
+
+func NewRetentionManager(db *store.Database, photoService *service.PhotoService) (*RetentionManager, error) {
+	if db == nil {
+		return nil, errors.New("no database provided for retention manager")
+	}
+	if photoService == nil {
+		return nil, errors.New("no photo service provided for retention manager")
+	}
+
+	return &RetentionManager{
+		db:           db,
+		photoService: photoService,
+		Updated:      make(chan bool),
+	}, nil
+}
+
+func (r *RetentionManager) checkRetention() {
+	rules, err := r.db.GetRetentionRules()
+	if err != nil {
+		slog.Error("unable to get retention rules", "error", err)
+		return
+	}
+	if len(rules) == 0 {
+		return
+	}
+
+	type expiredPhoto struct {
+		photo store.Photo
+		rule  store.RetentionRule
+	}
+	var expired []expiredPhoto
+	now := time.Now()
+	for photo, err := range r.db.AllPhotos(store.PhotoFilter{Category: paths.CategoryOriginal}) {
+		if err != nil {
+			slog.Error("unable to get photos for retention", "error", err)
+			return
+		}
+		if expiresAt, rule, ok := photoExpiry(photo, rules); ok && !now.Before(expiresAt) {
+			expired = append(expired, expiredPhoto{photo: photo, rule: rule})
+		}
+	}
+
+	var changed bool
+	for _, e := range expired {
+		name, category := e.photo.PhotoName, e.photo.Category
+		switch e.rule.Action {
+		case retentionDelete:
+			if err := r.photoService.Delete(name, category); err != nil {
+				slog.Error("unable to delete expired photo", "name", name, "album", e.rule.Album, "error", err)
+				continue
+			}
+		case retentionArchive:
+			if err := r.db.UpdatePhotoHidden(name, category, true); err != nil {
+				slog.Error("unable to archive expired photo", "name", name, "album", e.rule.Album, "error", err)
+				continue
+			}
+			if err := r.db.UpdatePhotoAlbum(name, category, "", e.photo.TakenAt); err != nil {
+				slog.Error("unable to take archived photo out of its album", "name", name, "album", e.rule.Album, "error", err)
+			}
+		}
+		slog.Info("expired photo", "name", name, "album", e.rule.Album, "action", e.rule.Action, "days", e.rule.Days)
+		changed = true
+	}
+	if changed {
+		r.Updated <- true
+	}
+}
+
+func (r *RetentionManager) Run() {
+	ticker := time.NewTicker(retentionInterval)
+
+	r.checkRetention()
+	for range ticker.C {
+		r.checkRetention()
+	}
+}
+
+func (ws *WebServer) handleListRetentionRules(c *gin.Context) {
+	rules, err := ws.db.GetRetentionRules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get retention rules: %v", err)})
+		return
+	}
+	if rules == nil {
+		rules = []store.RetentionRule{}
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+func (ws *WebServer) handleCreateRetentionRule(c *gin.Context) {
+	var req store.RetentionRule
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
+		return
+	}
+
+	req.Album = strings.TrimSpace(req.Album)
+	if req.Album == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "album is required")})
+		return
+	}
+	if len([]rune(req.Album)) > maxAlbumLength {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "album must be at most %d characters", maxAlbumLength)})
+		return
+	}
+	if req.Days < 1 || req.Days > maxRetentionDays {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "days must be between 1 and %d", maxRetentionDays)})
+		return
+	}
+	if req.Action == "" {
+		req.Action = retentionArchive
+	}
+	if req.Action != retentionDelete && req.Action != retentionArchive {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "action must be %s or %s", retentionArchive, retentionDelete)})
+		return
+	}
+
+	rule := &store.RetentionRule{
+		Album:     req.Album,
+		Days:      req.Days,
+		Action:    req.Action,
+		CreatedAt: time.Now(),
+	}
+	if err := ws.db.InsertRetentionRule(rule); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to create retention rule: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+func (ws *WebServer) handleDeleteRetentionRule(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid retention rule id")})
+		return
+	}
+
+	deleted, err := ws.db.DeleteRetentionRule(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to delete retention rule: %v", err)})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Retention rule %d not found", id)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "Retention rule %d deleted successfully", id)})
+}
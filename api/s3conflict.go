@@ -0,0 +1,169 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	mapset "github.com/deckarep/golang-set/v2"
+)
+
+// What happens when a photo has changed either locally or in the bucket since it was synced
+const (
+	// conflictPolicyRemote downloads the bucket's copy over the local one
+	conflictPolicyRemote = "remote"
+
+	// conflictPolicyLocal keeps the local copy until the bucket's changes again
+	conflictPolicyLocal = "local"
+
+	// conflictPolicyBoth renames the local copy with conflictSuffix, keeping it even when the
+	// delete policy mirrors the bucket, and downloads the bucket's copy under the original name
+	conflictPolicyBoth = "both"
+)
+
+const conflictSuffix = "-local"
+
+// syncedObject is the version of an object last synced from s3, which tells a photo that changed
+// since apart from one that's still the same
+type syncedObject struct {
+	ETag string `json:"etag"`
+	Size int64  `json:"size"`
+
+	// Kept marks a local copy renamed to keep both sides of a conflict, which isn't in the bucket
+	Kept bool `json:"kept,omitempty"`
+}
+
+// recordSynced remembers the version of an object that the local photo matches
+func (r *RemoteManager) recordSynced(name string, synced syncedObject) {
+	r.statusMu.Lock()
+	defer r.statusMu.Unlock()
+	r.synced[name] = synced
+}
+
+// kept reports whether a local photo was kept from a conflict rather than synced from the bucket
+func (r *RemoteManager) kept(name string) bool {
+	r.statusMu.Lock()
+	defer r.statusMu.Unlock()
+	return r.synced[name].Kept
+}
+
+// resolveConflicts compares the photos both on the frame and in the bucket with the versions last
+// synced, applying the conflict policy to those whose content differs, and returns the photos to
+// download again. Photos synced before versions were recorded are assumed to match when their size
+// does.
+func (r *RemoteManager) resolveConflicts(names mapset.Set[string], objects map[string]s3types.Object) []string {
+	var toDownload []string
+	for name := range names.Iter() {
+		info, err := os.Stat(filepath.Join(r.outputPath, name))
+		if err != nil {
+			continue
+		}
+		object := objects[name]
+		remote := syncedObject{ETag: aws.ToString(object.ETag), Size: aws.ToInt64(object.Size)}
+
+		r.statusMu.Lock()
+		synced, ok := r.synced[name]
+		r.statusMu.Unlock()
+		switch {
+		case !ok && info.Size() == remote.Size:
+			r.recordSynced(name, remote)
+			continue
+		case ok && synced.ETag == remote.ETag && synced.Size == info.Size():
+			continue
+		}
+
+		slog.Info("photo differs between the frame and s3", "name", name, "policy", r.conflictPolicy,
+			"local_size", info.Size(), "remote_size", remote.Size, "remote_changed", ok && synced.ETag != remote.ETag)
+		switch r.conflictPolicy {
+		case conflictPolicyLocal:
+			// the local copy stands in for this version of the object until it changes again
+			r.recordSynced(name, syncedObject{ETag: remote.ETag, Size: info.Size()})
+		case conflictPolicyBoth:
+			keptName, err := r.keepLocalCopy(name)
+			if err != nil {
+				slog.Warn("unable to keep local copy of conflicting photo", "name", name, "error", err)
+				continue
+			}
+			slog.Info("kept local copy of conflicting photo", "name", name, "kept", keptName)
+			toDownload = append(toDownload, name)
+		default:
+			toDownload = append(toDownload, name)
+		}
+	}
+	return toDownload
+}
+
+// keepLocalCopy renames a local photo with conflictSuffix, numbering it when an earlier conflict
+// already took the name, so the bucket's copy can be downloaded in its place
+func (r *RemoteManager) keepLocalCopy(name string) (string, error) {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	keptName := base + conflictSuffix + ext
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(r.outputPath, keptName)); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		keptName = fmt.Sprintf("%s%s-%d%s", base, conflictSuffix, i, ext)
+	}
+
+	if err := os.Rename(filepath.Join(r.outputPath, name), filepath.Join(r.outputPath, keptName)); err != nil {
+		return "", err
+	}
+	r.recordSynced(keptName, syncedObject{Kept: true})
+	return keptName, nil
+}
+
+// pruneSynced forgets the versions of photos no longer on the frame
+func (r *RemoteManager) pruneSynced(localFiles mapset.Set[string]) {
+	r.statusMu.Lock()
+	defer r.statusMu.Unlock()
+
+	for name := range r.synced {
+		if !localFiles.Contains(name) {
+			delete(r.synced, name)
+		}
+	}
+}
+
+func (r *RemoteManager) loadSynced() error {
+	content, err := os.ReadFile(r.syncedPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read %s, %w", r.syncedPath, err)
+	}
+	if err := json.Unmarshal(content, &r.synced); err != nil {
+		return fmt.Errorf("unable to parse %s, %w", r.syncedPath, err)
+	}
+	return nil
+}
+
+func (r *RemoteManager) saveSynced() error {
+	r.statusMu.Lock()
+	content, err := json.Marshal(r.synced)
+	r.statusMu.Unlock()
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(r.syncedPath, content)
+}
+
+// writeFileAtomic writes to a temporary file and renames it over path so a crash never leaves a
+// truncated file behind
+func writeFileAtomic(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("unable to create directory for %s, %w", path, err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, content, 0o644); err != nil {
+		return fmt.Errorf("unable to write %s, %w", tmpPath, err)
+	}
+	return os.Rename(tmpPath, path)
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/store"
+)
+
+// byteRateLimiter spaces out writes shared across concurrent downloads so they average at most
+// bytesPerSec
+type byteRateLimiter struct {
+	bytesPerSec int
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func newByteRateLimiter(bytesPerSec int) *byteRateLimiter {
+	return &byteRateLimiter{bytesPerSec: bytesPerSec}
+}
+
+// wait blocks until n more bytes fit within the rate limit or the context is done
+func (l *byteRateLimiter) wait(ctx context.Context, n int) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(time.Duration(n) * time.Second / time.Duration(l.bytesPerSec))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimitedWriterAt throttles the writes of an s3 download, which in turn slows reading the
+// response body down to the limiter's rate
+type rateLimitedWriterAt struct {
+	ctx     context.Context
+	w       io.WriterAt
+	limiter *byteRateLimiter
+}
+
+func (r *rateLimitedWriterAt) WriteAt(p []byte, off int64) (int, error) {
+	if err := r.limiter.wait(r.ctx, len(p)); err != nil {
+		return 0, err
+	}
+	return r.w.WriteAt(p, off)
+}
+
+// displayOffUntil reports whether the schedule has the display turned off at now, and if so when
+// it turns back on. The display is off from the schedule's end until its next start.
+func displayOffUntil(schedule *store.Schedule, now time.Time) (time.Time, bool) {
+	start, err := time.Parse("15:04", schedule.Start)
+	if err != nil {
+		return time.Time{}, false
+	}
+	end, err := time.Parse("15:04", schedule.End)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	minuteOfDay := func(t time.Time) int { return t.Hour()*60 + t.Minute() }
+	nowMin, onMin, offMin := minuteOfDay(now), minuteOfDay(start), minuteOfDay(end)
+
+	var off bool
+	if offMin <= onMin {
+		off = nowMin >= offMin && nowMin < onMin
+	} else {
+		// the off window wraps around midnight
+		off = nowMin >= offMin || nowMin < onMin
+	}
+	if !off {
+		return time.Time{}, false
+	}
+
+	until := time.Date(now.Year(), now.Month(), now.Day(), start.Hour(), start.Minute(), 0, 0, now.Location())
+	if !until.After(now) {
+		until = until.AddDate(0, 0, 1)
+	}
+	return until, true
+}
@@ -2,34 +2,72 @@ package api
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
 	"time"
 
 	"github.com/aouyang1/digitalphotoframe/display"
+	"github.com/aouyang1/digitalphotoframe/slideshow"
 	"github.com/aouyang1/digitalphotoframe/store"
 )
 
 const scheduleInterval = time.Minute
 
-// ScheduleManager will periodically check the time to decide if we need to turn off or on the display
+// displayWakeDelay gives the compositor time to bring the display back before the slideshow is
+// checked
+const displayWakeDelay = 2 * time.Second
+
+// ScheduleManager will periodically check the time to decide if we need to turn off or on the
+// display and whether seasonal rules have switched albums in or out of the slideshow, or
+// announcements and special dates have started or ended
 type ScheduleManager struct {
-	db *store.Database
+	db         *store.Database
+	controller *slideshow.Controller
 
 	lastCheck time.Time
+
+	// schedule profile in effect as of the last check
+	lastProfile string
+
+	// albums left out by seasonal rules as of the last check
+	lastInactive string
+
+	// announcements showing as of the last check
+	lastAnnouncements string
+
+	// guestbook messages showing as of the last check
+	lastGuestbook string
+
+	// special dates falling on the day of the last check
+	lastSpecialDates string
+
+	// albums left out and shown exclusively by their schedules as of the last check
+	lastAlbumThemes string
+
+	// photos outside the days they're shown as of the last check
+	lastUnshown string
+
+	Updated chan bool
 }
 
-func NewScheduleManager(db *store.Database) (*ScheduleManager, error) {
+func NewScheduleManager(db *store.Database, controller *slideshow.Controller) (*ScheduleManager, error) {
 	if db == nil {
 		return nil, errors.New("no database provided for scheduler")
 	}
+	if controller == nil {
+		return nil, errors.New("no slideshow controller provided for scheduler")
+	}
 
 	return &ScheduleManager{
-		db: db,
+		db:         db,
+		controller: controller,
+		Updated:    make(chan bool),
 	}, nil
 }
 
 func (s *ScheduleManager) checkSchedule() {
-	schedule, err := s.db.GetSchedule()
+	now := time.Now()
+	schedule, profile, err := currentSchedule(s.db, now)
 	if err != nil {
 		slog.Error("unable to get schedule", "error", err)
 		return
@@ -39,8 +77,19 @@ func (s *ScheduleManager) checkSchedule() {
 		return
 	}
 
-	now := time.Now()
-	defer func() { s.lastCheck = now }()
+	defer func() { s.lastCheck, s.lastProfile = now, profile }()
+
+	// switching profiles, such as staying home for the day, takes effect right away instead of
+	// waiting for the new profile's next start or end
+	if !s.lastCheck.IsZero() && profile != s.lastProfile {
+		_, quiet := displayOffUntil(schedule, now)
+		if err := s.setQuietHours(schedule, quiet); err != nil {
+			slog.Warn("issue while switching schedule profile", "profile", profile, "error", err)
+		} else {
+			slog.Info("switched schedule profile", "profile", profile, "quiet", quiet)
+		}
+		return
+	}
 
 	startTime, err := time.Parse("15:04", schedule.Start)
 	if err != nil {
@@ -60,34 +109,195 @@ func (s *ScheduleManager) checkSchedule() {
 		endDate = endDate.Add(24 * time.Hour)
 	}
 
-	// crossed into end of schedule - turn off display
+	// crossed into end of schedule - start quiet hours
 	if s.lastCheck.Before(endDate) && now.After(endDate) {
-		if err := display.UpdateEnabled(false); err != nil {
-			slog.Warn("issue while turning off display for schedule", "error", err)
+		if err := s.setQuietHours(schedule, true); err != nil {
+			slog.Warn("issue while starting quiet hours for schedule", "action", schedule.Action, "error", err)
 		} else {
-			slog.Info("turning display off for schedule", "time", now)
+			slog.Info("starting quiet hours for schedule", "action", schedule.Action, "time", now)
 		}
 		return
 	}
 
-	// crossed into start of schedule - turn on display
+	// crossed into start of schedule - end quiet hours
 	if now.After(startDate) && s.lastCheck.Before(startDate) {
-		if err := display.UpdateEnabled(true); err != nil {
-			slog.Warn("issue while turning on display for schedule", "error", err)
+		if err := s.setQuietHours(schedule, false); err != nil {
+			slog.Warn("issue while ending quiet hours for schedule", "action", schedule.Action, "error", err)
 		} else {
-			slog.Info("turning display on for schedule", "time", now)
+			slog.Info("ending quiet hours for schedule", "action", schedule.Action, "time", now)
 		}
 		return
 	}
 }
 
+// setQuietHours starts or ends the schedule's quiet hours by turning the display off, dimming
+// it, or restarting the slideshow to change its interval
+func (s *ScheduleManager) setQuietHours(schedule *store.Schedule, quiet bool) error {
+	applyQuietHoursDefaults(&schedule.QuietHours)
+
+	// the display comes back on at the start of the schedule whatever the action so switching
+	// away from turning it off doesn't leave it off
+	if !quiet || schedule.Action == quietOff {
+		if err := setDisplayEnabled(s.controller, !quiet); err != nil {
+			return err
+		}
+	}
+	// imv can be left stale or blank once the display is back on
+	if !quiet {
+		time.Sleep(displayWakeDelay)
+		if err := s.controller.EnsurePlaying(); err != nil {
+			slog.Warn("unable to resume slideshow after quiet hours", "error", err)
+		}
+	}
+
+	switch schedule.Action {
+	case quietDim:
+		brightness := 100
+		if quiet {
+			brightness = schedule.DimPercent
+		}
+		return display.SetBrightness(brightness)
+	case quietSlow:
+		s.Updated <- true
+	}
+	return nil
+}
+
+// checkSeasonalRules restarts the slideshow when the albums enabled by seasonal rules change
+func (s *ScheduleManager) checkSeasonalRules() {
+	rules, err := s.db.GetSeasonalRules()
+	if err != nil {
+		slog.Error("unable to get seasonal rules", "error", err)
+		return
+	}
+
+	inactive := fmt.Sprint(inactiveAlbums(rules, time.Now()))
+	// the slideshow already reflects the rules when it first starts
+	if s.lastInactive != "" && inactive != s.lastInactive {
+		slog.Info("seasonal rules changed the slideshow albums", "inactive", inactive)
+		s.Updated <- true
+	}
+	s.lastInactive = inactive
+}
+
+// checkAnnouncements restarts the slideshow when announcements start or end
+func (s *ScheduleManager) checkAnnouncements() {
+	announcements, err := s.db.GetAnnouncements()
+	if err != nil {
+		slog.Error("unable to get announcements", "error", err)
+		return
+	}
+
+	active := fmt.Sprint(activeAnnouncementIDs(announcements, time.Now()))
+	// the slideshow already shows the announcements when it first starts
+	if s.lastAnnouncements != "" && active != s.lastAnnouncements {
+		slog.Info("announcements showing in the slideshow changed", "active", active)
+		s.Updated <- true
+	}
+	s.lastAnnouncements = active
+}
+
+// checkGuestbook restarts the slideshow when guestbook messages have been shown for long enough
+func (s *ScheduleManager) checkGuestbook() {
+	settings, err := s.db.GetAppSettings()
+	if err != nil {
+		slog.Error("unable to get settings", "error", err)
+		return
+	}
+	entries, err := s.db.GetGuestbookEntries()
+	if err != nil {
+		slog.Error("unable to get guestbook", "error", err)
+		return
+	}
+
+	applyGuestbookDefaults(settings)
+	showing := fmt.Sprint(showingGuestbookIDs(entries, settings.GuestbookDays, time.Now()))
+	// the slideshow already shows the messages when it first starts
+	if s.lastGuestbook != "" && showing != s.lastGuestbook {
+		slog.Info("guestbook messages showing in the slideshow changed", "showing", showing)
+		s.Updated <- true
+	}
+	s.lastGuestbook = showing
+}
+
+// checkSpecialDates restarts the slideshow when a birthday or anniversary starts or ends, to show
+// or take down its greeting
+func (s *ScheduleManager) checkSpecialDates() {
+	dates, err := s.db.GetSpecialDates()
+	if err != nil {
+		slog.Error("unable to get special dates", "error", err)
+		return
+	}
+
+	var ids []int64
+	for _, sd := range todaysSpecialDates(dates, time.Now()) {
+		ids = append(ids, sd.ID)
+	}
+	today := fmt.Sprint(ids)
+	// the slideshow already shows the greetings when it first starts
+	if s.lastSpecialDates != "" && today != s.lastSpecialDates {
+		slog.Info("special dates showing in the slideshow changed", "today", today)
+		s.Updated <- true
+	}
+	s.lastSpecialDates = today
+}
+
+// checkAlbumSchedules restarts the slideshow when scheduled albums come on or go off, re-theming it
+// on the days their schedules start and end
+func (s *ScheduleManager) checkAlbumSchedules() {
+	schedules, err := s.db.GetAlbumSchedules()
+	if err != nil {
+		slog.Error("unable to get album schedules", "error", err)
+		return
+	}
+
+	off, exclusive := albumThemes(schedules, time.Now())
+	themes := fmt.Sprint(off, exclusive)
+	// the slideshow already follows the schedules when it first starts
+	if s.lastAlbumThemes != "" && themes != s.lastAlbumThemes {
+		slog.Info("album schedules changed the slideshow albums", "off", off, "exclusive", exclusive)
+		s.Updated <- true
+	}
+	s.lastAlbumThemes = themes
+}
+
+// checkPhotoVisibility restarts the slideshow when photos limited to certain days enter or leave
+// rotation
+func (s *ScheduleManager) checkPhotoVisibility() {
+	photos, err := s.db.GetScheduledPhotos()
+	if err != nil {
+		slog.Error("unable to get scheduled photos", "error", err)
+		return
+	}
+
+	unshown := fmt.Sprint(unshownPhotoIDs(photos, time.Now()))
+	// the slideshow already leaves the photos out when it first starts
+	if s.lastUnshown != "" && unshown != s.lastUnshown {
+		slog.Info("photos shown in the slideshow changed with their dates", "unshown", unshown)
+		s.Updated <- true
+	}
+	s.lastUnshown = unshown
+}
+
 func (s *ScheduleManager) Run() {
 	ticker := time.NewTicker(scheduleInterval)
 
 	s.checkSchedule()
+	s.checkSeasonalRules()
+	s.checkAnnouncements()
+	s.checkGuestbook()
+	s.checkSpecialDates()
+	s.checkAlbumSchedules()
+	s.checkPhotoVisibility()
 
 	// Initial sync
 	for range ticker.C {
 		s.checkSchedule()
+		s.checkSeasonalRules()
+		s.checkAnnouncements()
+		s.checkGuestbook()
+		s.checkSpecialDates()
+		s.checkAlbumSchedules()
+		s.checkPhotoVisibility()
 	}
 }
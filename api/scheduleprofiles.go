@@ -0,0 +1,240 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+const maxScheduleProfileNameLength = 32
+
+// scheduleDays names the days of the week in the order of time.Weekday
+var scheduleDays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// dateLayout formats the day a one off profile applies to
+const dateLayout = "2006-01-02"
+
+// resolveSchedule returns the schedule with the start and end times and quiet hours in effect at
+// now along with the name of the profile they came from, if any. A profile picked for today wins
+// over the active profile, which wins over a profile for the day of the week. Profiles that no
+// longer exist are ignored.
+func resolveSchedule(schedule *store.Schedule, profiles []store.ScheduleProfile, now time.Time) (*store.Schedule, string) {
+	find := func(name string) (store.ScheduleProfile, bool) {
+		i := slices.IndexFunc(profiles, func(p store.ScheduleProfile) bool { return p.Name == name })
+		if i < 0 {
+			return store.ScheduleProfile{}, false
+		}
+		return profiles[i], true
+	}
+
+	profile, ok := store.ScheduleProfile{}, false
+	if schedule.TodayProfile != "" && schedule.TodayDate == now.Format(dateLayout) {
+		profile, ok = find(schedule.TodayProfile)
+	}
+	if !ok && schedule.ActiveProfile != "" {
+		profile, ok = find(schedule.ActiveProfile)
+	}
+	if !ok {
+		day := scheduleDays[now.Weekday()]
+		for _, p := range profiles {
+			if slices.Contains(p.Days, day) {
+				profile, ok = p, true
+				break
+			}
+		}
+	}
+
+	resolved := *schedule
+	if !ok {
+		return &resolved, ""
+	}
+	resolved.Start, resolved.End, resolved.QuietHours = profile.Start, profile.End, profile.QuietHours
+	return &resolved, profile.Name
+}
+
+// currentSchedule loads the schedule and resolves which times are in effect at now
+func currentSchedule(db *store.Database, now time.Time) (*store.Schedule, string, error) {
+	schedule, err := db.GetSchedule()
+	if err != nil {
+		return nil, "", err
+	}
+	profiles, err := db.GetScheduleProfiles()
+	if err != nil {
+		return nil, "", err
+	}
+	resolved, profile := resolveSchedule(schedule, profiles, now)
+	return resolved, profile, nil
+}
+
+// scheduleProfileExists reports whether the profile is defined, treating an empty name as no
+// profile which always exists
+func (ws *WebServer) scheduleProfileExists(name string) (bool, error) {
+	if name == "" {
+		return true, nil
+	}
+	profiles, err := ws.db.GetScheduleProfiles()
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(profiles, func(p store.ScheduleProfile) bool { return p.Name == name }), nil
+}
+
+func (ws *WebServer) handleListScheduleProfiles(c *gin.Context) {
+	profiles, err := ws.db.GetScheduleProfiles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get schedule profiles: %v", err)})
+		return
+	}
+	if profiles == nil {
+		profiles = []store.ScheduleProfile{}
+	}
+	c.JSON(http.StatusOK, profiles)
+}
+
+func (ws *WebServer) handleUpdateScheduleProfile(c *gin.Context) {
+	var req store.ScheduleProfile
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
+		return
+	}
+
+	name := strings.TrimSpace(c.Param("name"))
+	if name == "" || len([]rune(name)) > maxScheduleProfileNameLength {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "profile name must be 1 to %d characters", maxScheduleProfileNameLength)})
+		return
+	}
+	if !validScheduleTime.MatchString(req.Start) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid start time format: need 23:15, got %s", req.Start)})
+		return
+	}
+	if !validScheduleTime.MatchString(req.End) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid end time format: need 23:15, got %s", req.End)})
+		return
+	}
+
+	applyQuietHoursDefaults(&req.QuietHours)
+	if !ws.validQuietHours(c, req.QuietHours) {
+		return
+	}
+
+	days := []string{}
+	for _, day := range req.Days {
+		day = strings.ToLower(day)
+		if !slices.Contains(scheduleDays, day) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "days must be among %s", strings.Join(scheduleDays, ", "))})
+			return
+		}
+		if !slices.Contains(days, day) {
+			days = append(days, day)
+		}
+	}
+
+	profile := &store.ScheduleProfile{
+		Name:  name,
+		Start: req.Start,
+		End:   req.End,
+		Days:  days,
+
+		QuietHours: req.QuietHours,
+	}
+	if err := ws.db.UpsertScheduleProfile(profile); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to save schedule profile: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+func (ws *WebServer) handleDeleteScheduleProfile(c *gin.Context) {
+	name := strings.TrimSpace(c.Param("name"))
+	deleted, err := ws.db.DeleteScheduleProfile(name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to delete schedule profile: %v", err)})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Schedule profile %s not found", name)})
+		return
+	}
+
+	// stop picking the deleted profile so a new one with the same name isn't picked by surprise
+	schedule, err := ws.db.GetSchedule()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get settings: %v", err)})
+		return
+	}
+	if schedule.ActiveProfile == name || schedule.TodayProfile == name {
+		previous := *schedule
+		if schedule.ActiveProfile == name {
+			schedule.ActiveProfile = ""
+		}
+		if schedule.TodayProfile == name {
+			schedule.TodayProfile, schedule.TodayDate = "", ""
+		}
+		if err := ws.db.UpsertSchedule(schedule); err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to update schedule: %v", err)})
+			return
+		}
+		recordSettingsVersion(ws.db, store.HistorySchedule, scheduleVersion(&previous), scheduleVersion(schedule), changedBy(c))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "Schedule profile %s deleted successfully", name)})
+}
+
+// handleSelectScheduleProfile picks the profile used every day, or goes back to picking by the
+// day of the week when the profile is empty
+func (ws *WebServer) handleSelectScheduleProfile(c *gin.Context) {
+	ws.selectScheduleProfile(c, func(schedule *store.Schedule, profile string) {
+		schedule.ActiveProfile = profile
+	})
+}
+
+// handleSelectTodayScheduleProfile picks a profile for the rest of today, or clears today's pick
+// when the profile is empty
+func (ws *WebServer) handleSelectTodayScheduleProfile(c *gin.Context) {
+	ws.selectScheduleProfile(c, func(schedule *store.Schedule, profile string) {
+		schedule.TodayProfile, schedule.TodayDate = profile, ""
+		if profile != "" {
+			schedule.TodayDate = time.Now().Format(dateLayout)
+		}
+	})
+}
+
+func (ws *WebServer) selectScheduleProfile(c *gin.Context, apply func(*store.Schedule, string)) {
+	var req models.ScheduleProfileSelection
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
+		return
+	}
+
+	profile := strings.TrimSpace(req.Profile)
+	exists, err := ws.scheduleProfileExists(profile)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get schedule profiles: %v", err)})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Schedule profile %s not found", profile)})
+		return
+	}
+
+	schedule, err := ws.db.GetSchedule()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get settings: %v", err)})
+		return
+	}
+	previous := *schedule
+	apply(schedule, profile)
+	if err := ws.db.UpsertSchedule(schedule); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to update schedule: %v", err)})
+		return
+	}
+	recordSettingsVersion(ws.db, store.HistorySchedule, scheduleVersion(&previous), scheduleVersion(schedule), changedBy(c))
+
+	ws.respondSchedule(c)
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+const maxSeasonalLabelLength = 64
+
+var validSeasonalDate = regexp.MustCompile(`^(?:0[1-9]|1[0-2])-(?:0[1-9]|[12]\d|3[01])$`)
+
+// seasonalRuleActive reports whether now falls within the rule's yearly date range
+func seasonalRuleActive(rule store.SeasonalRule, now time.Time) bool {
+	return yearlyRangeActive(rule.Start, rule.End, now)
+}
+
+// yearlyRangeActive reports whether now falls between the MM-DD dates start and end, inclusive
+func yearlyRangeActive(start, end string, now time.Time) bool {
+	today := now.Format("01-02")
+	if start <= end {
+		return start <= today && today <= end
+	}
+	// the range wraps around the new year, e.g. 12-15 to 01-05
+	return today >= start || today <= end
+}
+
+// inactiveAlbums returns the albums with seasonal rules where none of the rules cover now.
+// Albums without rules are always active.
+func inactiveAlbums(rules []store.SeasonalRule, now time.Time) []string {
+	active := make(map[string]bool)
+	for _, rule := range rules {
+		active[rule.Album] = active[rule.Album] || seasonalRuleActive(rule, now)
+	}
+
+	var inactive []string
+	for album, isActive := range active {
+		if !isActive {
+			inactive = append(inactive, album)
+		}
+	}
+	slices.Sort(inactive)
+	return inactive
+}
+
+func (ws *WebServer) handleListSeasonalRules(c *gin.Context) {
+	rules, err := ws.db.GetSeasonalRules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get seasonal rules: %v", err)})
+		return
+	}
+	if rules == nil {
+		rules = []store.SeasonalRule{}
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+func (ws *WebServer) handleCreateSeasonalRule(c *gin.Context) {
+	var req store.SeasonalRule
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
+		return
+	}
+
+	req.Album = strings.TrimSpace(req.Album)
+	if req.Album == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "album is required")})
+		return
+	}
+	if len([]rune(req.Album)) > maxAlbumLength {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "album must be at most %d characters", maxAlbumLength)})
+		return
+	}
+	if !validSeasonalDate.MatchString(req.Start) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid start date format: need 12-01, got %s", req.Start)})
+		return
+	}
+	if !validSeasonalDate.MatchString(req.End) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid end date format: need 12-31, got %s", req.End)})
+		return
+	}
+	req.Label = strings.TrimSpace(req.Label)
+	if len([]rune(req.Label)) > maxSeasonalLabelLength {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "label must be at most %d characters", maxSeasonalLabelLength)})
+		return
+	}
+
+	rule := &store.SeasonalRule{
+		Album: req.Album,
+		Label: req.Label,
+		Start: req.Start,
+		End:   req.End,
+	}
+	if err := ws.db.InsertSeasonalRule(rule); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to create seasonal rule: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+
+	// trigger slideshow restart
+	ws.requestRestart()
+}
+
+func (ws *WebServer) handleDeleteSeasonalRule(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid seasonal rule id")})
+		return
+	}
+
+	deleted, err := ws.db.DeleteSeasonalRule(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to delete seasonal rule: %v", err)})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Seasonal rule %d not found", id)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "Seasonal rule %d deleted successfully", id)})
+
+	// trigger slideshow restart
+	ws.requestRestart()
+}
@@ -5,33 +5,51 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	"html/template"
 	"io/fs"
 	"log"
 	"log/slog"
-	"math/rand"
+	"mime"
+	"mime/multipart"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/aouyang1/digitalphotoframe/api/models"
 	"github.com/aouyang1/digitalphotoframe/api/web/templates"
+	"github.com/aouyang1/digitalphotoframe/cache"
 	"github.com/aouyang1/digitalphotoframe/display"
+	"github.com/aouyang1/digitalphotoframe/i18n"
+	"github.com/aouyang1/digitalphotoframe/imaging"
+	"github.com/aouyang1/digitalphotoframe/overlay"
+	"github.com/aouyang1/digitalphotoframe/paths"
+	"github.com/aouyang1/digitalphotoframe/service"
 	"github.com/aouyang1/digitalphotoframe/slideshow"
 	"github.com/aouyang1/digitalphotoframe/store"
 	"github.com/aouyang1/digitalphotoframe/util"
+	mapset "github.com/deckarep/golang-set/v2"
 	"github.com/gin-gonic/gin"
+	"golang.org/x/net/webdav"
 )
 
 //go:embed web/templates/* web/static/**
 var webFiles embed.FS
 
-const webServerURL = "http://localhost:80"
+const defaultImageCacheMB = 64
+
+const (
+	// photos shown in a category before scrolling loads the next page
+	defaultUIPhotosLimit = 60
+	maxUIPhotosLimit     = 200
+)
 
 type ServerError struct {
 	StatusCode int
@@ -42,42 +60,179 @@ type WebServer struct {
 	router   *gin.Engine
 	db       *store.Database
 	rootPath string
+	paths    paths.Layout
 
-	localManager    *LocalManager
-	remoteManager   *RemoteManager
-	scheduleManager *ScheduleManager
+	// registers and removes photos for the handlers and managers alike
+	photoService *service.PhotoService
 
-	Updated chan bool
+	localManager      *LocalManager
+	remoteManager     *RemoteManager
+	scheduleManager   *ScheduleManager
+	fleetManager      *FleetManager
+	setupManager      *SetupManager
+	photoOfDayManager *PhotoOfDayManager
+	usageManager      *UsageManager
+	ingestManager     *IngestManager
+	rcloneManager     *RcloneManager
+	retentionManager  *RetentionManager
+	pairingManager    *PairingManager
+	audioManager      *AudioManager
+
+	// announces new photos and failed syncs to phones and chat webhooks
+	notifier *PhotoNotifier
+
+	// hot resized images kept in memory to avoid rereading from the sd card
+	imageCache *cache.LRU
+
+	controller *slideshow.Controller
+
+	// sends the image on screen to the clients of the mjpeg stream
+	stream *streamBroadcaster
+
+	// bearer token guarding system endpoints, which are disabled when empty
+	adminToken string
+
+	// path a reverse proxy serves the frame under, which is added to every generated url
+	basePath string
+
+	// embedded static files, served with their content hash in the name
+	assets *staticAssets
+
+	// webdav share of My Photos for adding photos from a file manager
+	webdav *webdav.Handler
+
+	// token required in the query of the photo feed, which is disabled when empty
+	feedToken string
 
-	// this ensures only one go routine can restart the slideshow at a time
-	imvMutex sync.Mutex
+	// time between photos that starts a new trip album when auto organizing
+	tripGap time.Duration
+
+	// photos whose reactions changed, redrawn on screen together once reactions stop coming in
+	reactionsMu    sync.Mutex
+	reacted        mapset.Set[string]
+	reactionsTimer *time.Timer
+
+	Updated chan bool
 }
 
 func NewWebServer(db *store.Database, rootPath string) *WebServer {
-	router := gin.Default()
+	router := gin.New()
+	router.Use(requestID)
+	if accessLog := accessLogFromEnv(); accessLog != nil {
+		router.Use(accessLog)
+	}
+	router.Use(gin.Recovery(), compress)
+
+	imageCacheMBStr := os.Getenv("DPF_IMAGE_CACHE_MB")
+	imageCacheMB, err := strconv.Atoi(imageCacheMBStr)
+	if err != nil || imageCacheMB < 0 {
+		slog.Warn("unable to parse DPF_IMAGE_CACHE_MB, using default", "DPF_IMAGE_CACHE_MB", imageCacheMBStr, "default", defaultImageCacheMB)
+		imageCacheMB = defaultImageCacheMB
+	}
+
+	tripGap := defaultTripGap
+	if tripGapStr := os.Getenv("DPF_TRIP_GAP_HOURS"); tripGapStr != "" {
+		tripGapHours, err := strconv.Atoi(tripGapStr)
+		if err != nil || tripGapHours <= 0 {
+			slog.Warn("unable to parse DPF_TRIP_GAP_HOURS, using default", "DPF_TRIP_GAP_HOURS", tripGapStr, "default", defaultTripGap)
+		} else {
+			tripGap = time.Duration(tripGapHours) * time.Hour
+		}
+	}
+
+	// frames upgraded from when the slideshow's state was kept in a file resume where they were
+	layout := paths.New(rootPath)
+	if err := slideshow.ImportStateFile(db, layout.LegacySlideshowState()); err != nil {
+		slog.Warn("unable to import saved slideshow state", "error", err)
+	}
 
 	ws := &WebServer{
-		router:   router,
-		db:       db,
-		rootPath: rootPath,
-		Updated:  make(chan bool),
+		router:     router,
+		db:         db,
+		rootPath:   rootPath,
+		paths:      layout,
+		imageCache: cache.NewLRU(imageCacheMB * 1024 * 1024),
+		controller: slideshow.NewController(db),
+		adminToken: os.Getenv("DPF_ADMIN_TOKEN"),
+		basePath:   basePathFromEnv(),
+		feedToken:  os.Getenv("DPF_FEED_TOKEN"),
+		tripGap:    tripGap,
+		reacted:    mapset.NewThreadUnsafeSet[string](),
+		// buffered so requestRestart can queue a restart while one is in progress
+		Updated: make(chan bool, 1),
+	}
+	ws.stream = newStreamBroadcaster(ws.controller)
+
+	photoService, err := service.NewPhotoService(db, ws.paths, ws.imageCache)
+	if err != nil {
+		log.Fatalf("Failed to initialize photo service: %v", err)
 	}
+	ws.photoService = photoService
 
-	localManager, err := NewLocalManager()
+	localManager, err := NewLocalManager(photoService)
 	if err != nil {
 		log.Fatalf("Failed to initialize local manager: %v", err)
 	}
-	remoteManager, err := NewRemoteManager()
+	notifier := NewPhotoNotifier(db)
+	remoteManager, err := NewRemoteManager(db, photoService, notifier)
 	if err != nil {
 		log.Fatalf("Failed to initialize remote manager: %v", err)
 	}
-	scheduleManager, err := NewScheduleManager(db)
+	scheduleManager, err := NewScheduleManager(db, ws.controller)
 	if err != nil {
 		log.Fatalf("Failed to initialize schedule manager: %v", err)
 	}
+	fleetManager, err := NewFleetManager(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize fleet manager: %v", err)
+	}
+	photoOfDayManager, err := NewPhotoOfDayManager(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize photo of the day manager: %v", err)
+	}
+	setupManager, err := NewSetupManager()
+	if err != nil {
+		log.Fatalf("Failed to initialize setup manager: %v", err)
+	}
+	usageManager, err := NewUsageManager(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize usage manager: %v", err)
+	}
+	ingestManager, err := NewIngestManager(db, photoService, ws.paths, remoteManager.Mirrors())
+	if err != nil {
+		log.Fatalf("Failed to initialize ingest manager: %v", err)
+	}
+	rcloneManager, err := NewRcloneManager(photoService, notifier, ws.paths, remoteManager.Enabled())
+	if err != nil {
+		log.Fatalf("Failed to initialize rclone manager: %v", err)
+	}
+	retentionManager, err := NewRetentionManager(db, photoService)
+	if err != nil {
+		log.Fatalf("Failed to initialize retention manager: %v", err)
+	}
+	pairingManager, err := NewPairingManager(db, photoService, notifier)
+	if err != nil {
+		log.Fatalf("Failed to initialize pairing manager: %v", err)
+	}
+	audioManager, err := NewAudioManager(db, ws.controller, ws.paths.AudioSocket())
+	if err != nil {
+		log.Fatalf("Failed to initialize audio manager: %v", err)
+	}
 	ws.localManager = localManager
 	ws.remoteManager = remoteManager
 	ws.scheduleManager = scheduleManager
+	ws.fleetManager = fleetManager
+	ws.setupManager = setupManager
+	ws.photoOfDayManager = photoOfDayManager
+	ws.usageManager = usageManager
+	ws.ingestManager = ingestManager
+	ws.rcloneManager = rcloneManager
+	ws.retentionManager = retentionManager
+	ws.pairingManager = pairingManager
+	ws.audioManager = audioManager
+	ws.notifier = notifier
+
+	templates.SetBasePath(ws.basePath)
 
 	// Setup routes
 	ws.setupRoutes()
@@ -86,6 +241,10 @@ func NewWebServer(db *store.Database, rootPath string) *WebServer {
 }
 
 func (ws *WebServer) setupRoutes() {
+	// render messages in the configured language and redirect everything to the wifi setup
+	// page while the setup hotspot is up
+	ws.router.Use(ws.localize, ws.captivePortal)
+
 	// Create filesystem for static files (strip "web/" prefix)
 	staticFS, err := fs.Sub(webFiles, "web/static")
 	if err != nil {
@@ -98,8 +257,15 @@ func (ws *WebServer) setupRoutes() {
 		log.Fatalf("Failed to create templates filesystem: %v", err)
 	}
 
-	// Serve static files from embedded filesystem
-	ws.router.StaticFS("static", http.FS(staticFS))
+	// Serve static files from embedded filesystem under names with their content hash, so they can
+	// be cached until they change
+	ws.assets, err = newStaticAssets(staticFS)
+	if err != nil {
+		log.Fatalf("Failed to load static files: %v", err)
+	}
+	templates.SetAssetPaths(ws.assets.hashed)
+	ws.router.GET("/static/*filepath", ws.assets.serve)
+	ws.router.HEAD("/static/*filepath", ws.assets.serve)
 
 	// Serve favicon
 	ws.router.GET("/favicon.ico", func(c *gin.Context) {
@@ -122,58 +288,205 @@ func (ws *WebServer) setupRoutes() {
 	})
 
 	// Serve index.html from embedded filesystem
+	indexTmpl, err := template.New("index.html").
+		Funcs(template.FuncMap{"static": ws.staticURL}).
+		ParseFS(templatesFS, "index.html")
+	if err != nil {
+		log.Fatalf("Failed to parse index.html: %v", err)
+	}
 	ws.router.GET("/", func(c *gin.Context) {
-		data, err := fs.ReadFile(templatesFS, "index.html")
+		settings, err := ws.db.GetAppSettings()
 		if err != nil {
-			slog.Error("failed to read index.html", "error", err)
+			requestLogger(c).Warn("unable to get settings for index.html, using defaults", "error", err)
+			settings = &store.AppSettings{}
+		}
+
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		if err := indexTmpl.Execute(c.Writer, newIndexPage(settings, ws.basePath)); err != nil {
+			requestLogger(c).Error("failed to render index.html", "error", err)
 			c.String(http.StatusInternalServerError, "Failed to load index.html")
-			return
 		}
-		c.Data(http.StatusOK, "text/html; charset=utf-8", data)
 	})
+	ws.router.GET("/ui/photos/recent", ws.handleUIRecentPhotos)
 	ws.router.GET("/ui/photos/:category", ws.handleUIPhotos)
 
 	// API routes
 	ws.router.POST("/upload", ws.handleUpload)
 	ws.router.POST("/photos/register", ws.handleRegisterPhoto)
+	ws.router.POST("/import/takeout", ws.handleImportTakeout)
+	ws.router.POST("/import/apple-photos", ws.handleImportApplePhotos)
 	ws.router.GET("/photos", ws.handleListPhotos)
+	ws.router.GET("/albums", ws.handleListAlbums)
+	ws.router.GET("/categories", ws.handleListCategories)
+	ws.router.GET("/photos/recent", ws.handleRecentPhotos)
+	ws.router.GET("/photos/pending", ws.handlePendingPhotos)
+	ws.router.GET("/photos/recent.atom", ws.requireFeedToken, ws.handlePhotoFeed)
+	ws.router.GET("/photos/export.zip", ws.handleExportPhotos)
+	ws.router.POST("/photos/organize", ws.handleOrganizePhotos)
 	ws.router.GET("/photos/:category/:name/image", ws.handlePhotoImage)
+	ws.router.GET("/photos/:category/:name/download", ws.handlePhotoDownload)
+	ws.router.GET("/photos/:category/:name/motion", ws.handlePhotoMotion)
+	ws.router.PUT("/photos/:category/:name/caption", ws.handleUpdatePhotoCaption)
+	ws.router.PUT("/photos/:category/:name/album", ws.handleUpdatePhotoAlbum)
+	ws.router.PUT("/photos/:category/:name/hidden", ws.handleUpdatePhotoHidden)
+	ws.router.PUT("/photos/:category/:name/pinned", ws.handleUpdatePhotoPinned)
+	ws.router.PUT("/photos/:category/:name/visibility", ws.handleUpdatePhotoVisibility)
+	ws.router.GET("/photos/:category/:name/people", ws.handlePhotoPeople)
+	ws.router.PUT("/photos/:category/:name/people", ws.handleUpdatePhotoPeople)
+	ws.router.GET("/photos/:category/:name/reactions", ws.handlePhotoReactions)
+	ws.router.POST("/photos/:category/:name/reactions", ws.handleCreateReaction)
+	ws.router.POST("/photos/:category/:name/approve", ws.handleApprovePhoto)
+	ws.router.POST("/photos/:category/:name/reject", ws.handleRejectPhoto)
+	ws.router.POST("/photos/:category/:name/share", ws.handleCreateShareLink)
+	ws.router.GET("/share/:token", ws.handleSharedPhoto)
+	ws.router.GET("/pair/:token", ws.handlePairedAlbum)
+	ws.router.GET("/pair/:token/photos/:id", ws.handlePairedPhoto)
+	ws.router.POST("/guest-links", ws.handleCreateGuestLink)
+	ws.router.GET("/guest-links/:token/qr.png", ws.handleGuestLinkQRCode)
+	ws.router.GET("/guest/:token", ws.handleGuestUploadPage)
+	ws.router.POST("/guest/:token", ws.handleGuestUpload)
+	ws.router.POST("/guest/:token/guestbook", ws.handleGuestbookMessage)
 	ws.router.DELETE("/photos/:name/category/:category", ws.handleDeletePhoto)
+
+	// the same photo routes by id, which keep working when a photo's name doesn't
+	byID := ws.router.Group("/photos/id/:id", ws.photoByID)
+	byID.GET("", ws.handleGetPhoto)
+	byID.DELETE("", ws.handleDeletePhoto)
+	byID.GET("/image", ws.handlePhotoImage)
+	byID.GET("/download", ws.handlePhotoDownload)
+	byID.GET("/motion", ws.handlePhotoMotion)
+	byID.PUT("/caption", ws.handleUpdatePhotoCaption)
+	byID.PUT("/album", ws.handleUpdatePhotoAlbum)
+	byID.PUT("/hidden", ws.handleUpdatePhotoHidden)
+	byID.PUT("/pinned", ws.handleUpdatePhotoPinned)
+	byID.PUT("/visibility", ws.handleUpdatePhotoVisibility)
+	byID.GET("/people", ws.handlePhotoPeople)
+	byID.PUT("/people", ws.handleUpdatePhotoPeople)
+	byID.GET("/reactions", ws.handlePhotoReactions)
+	byID.POST("/reactions", ws.handleCreateReaction)
+	byID.POST("/approve", ws.handleApprovePhoto)
+	byID.POST("/reject", ws.handleRejectPhoto)
+	byID.POST("/share", ws.handleCreateShareLink)
+	byID.POST("/play", ws.handlePlayFromPhoto)
+	byID.POST("/show", ws.handleShowPhoto)
+
 	ws.router.POST("/slideshow/play/:name/category/:category", ws.handlePlayFromPhoto)
+	ws.router.GET("/slideshow/state", ws.handleSlideshowState)
+	ws.router.POST("/slideshow/hold", ws.handleHoldSlideshow)
+	ws.router.POST("/slideshow/release", ws.handleReleaseSlideshow)
+	ws.router.POST("/slideshow/show/:category/:name", ws.handleShowPhoto)
+	ws.router.GET("/slideshow/stream", ws.handleSlideshowStream)
+	ws.router.GET("/slideshow/playlist", ws.handleWebSlideshowPlaylist)
+	ws.router.GET("/slideshow/web", ws.handleWebSlideshowPage)
 	ws.router.GET("/settings", ws.handleGetSettings)
 	ws.router.PUT("/settings", ws.handleUpdateSettings)
 	ws.router.GET("/schedule", ws.handleGetSchedule)
 	ws.router.PUT("/schedule", ws.handleUpdateSchedule)
+	ws.router.GET("/settings/history", ws.handleSettingsHistory)
+	ws.router.POST("/settings/history/:id/rollback", ws.handleRollbackSettings)
+	ws.router.GET("/feed", ws.handleFeed)
+	ws.router.GET("/schedule/profiles", ws.handleListScheduleProfiles)
+	ws.router.PUT("/schedule/profiles/:name", ws.handleUpdateScheduleProfile)
+	ws.router.DELETE("/schedule/profiles/:name", ws.handleDeleteScheduleProfile)
+	ws.router.PUT("/schedule/profile", ws.handleSelectScheduleProfile)
+	ws.router.PUT("/schedule/today", ws.handleSelectTodayScheduleProfile)
+	ws.router.GET("/seasonal-rules", ws.handleListSeasonalRules)
+	ws.router.POST("/seasonal-rules", ws.handleCreateSeasonalRule)
+	ws.router.DELETE("/seasonal-rules/:id", ws.handleDeleteSeasonalRule)
+	ws.router.GET("/album-schedules", ws.handleListAlbumSchedules)
+	ws.router.POST("/album-schedules", ws.handleCreateAlbumSchedule)
+	ws.router.PUT("/album-schedules/:id", ws.handleUpdateAlbumSchedule)
+	ws.router.DELETE("/album-schedules/:id", ws.handleDeleteAlbumSchedule)
+	ws.router.GET("/people", ws.handleListPeople)
+	ws.router.POST("/people", ws.handleCreatePerson)
+	ws.router.PUT("/people/:id", ws.handleUpdatePerson)
+	ws.router.DELETE("/people/:id", ws.handleDeletePerson)
+	ws.router.GET("/people/:id/photos", ws.handlePersonPhotos)
+	ws.router.DELETE("/reactions/:id", ws.handleDeleteReaction)
+	ws.router.GET("/retention-rules", ws.handleListRetentionRules)
+	ws.router.POST("/retention-rules", ws.handleCreateRetentionRule)
+	ws.router.DELETE("/retention-rules/:id", ws.handleDeleteRetentionRule)
+	ws.router.GET("/shared-albums", ws.handleListSharedAlbums)
+	ws.router.POST("/shared-albums", ws.handleCreateSharedAlbum)
+	ws.router.DELETE("/shared-albums/:id", ws.handleDeleteSharedAlbum)
+	ws.router.GET("/album-pairings", ws.handleListAlbumPairings)
+	ws.router.POST("/album-pairings", ws.handleCreateAlbumPairing)
+	ws.router.DELETE("/album-pairings/:id", ws.handleDeleteAlbumPairing)
+	ws.router.GET("/announcements", ws.handleListAnnouncements)
+	ws.router.POST("/announcements", ws.handleCreateAnnouncement)
+	ws.router.GET("/announcements/:id/image", ws.handleAnnouncementImage)
+	ws.router.DELETE("/announcements/:id", ws.handleDeleteAnnouncement)
+	ws.router.GET("/guestbook", ws.handleListGuestbook)
+	ws.router.GET("/guestbook/:id/image", ws.handleGuestbookImage)
+	ws.router.DELETE("/guestbook/:id", ws.handleDeleteGuestbookEntry)
+	ws.router.GET("/special-dates", ws.handleListSpecialDates)
+	ws.router.POST("/special-dates", ws.handleCreateSpecialDate)
+	ws.router.PUT("/special-dates/:id", ws.handleUpdateSpecialDate)
+	ws.router.DELETE("/special-dates/:id", ws.handleDeleteSpecialDate)
 	ws.router.GET("/display", ws.handleGetDisplay)
 	ws.router.PUT("/display/:state", ws.handleUpdateDisplay)
+	ws.router.GET("/display/info", ws.handleGetDisplayInfo)
+	ws.router.GET("/display/usage", ws.handleGetDisplayUsage)
+	ws.router.GET("/display/screenshot", ws.handleDisplayScreenshot)
+	ws.router.PUT("/display/transform", ws.handleUpdateDisplayTransform)
+	ws.router.PUT("/display/mode", ws.handleUpdateDisplayMode)
+	ws.router.GET("/audio", ws.handleGetAudio)
+	ws.router.PUT("/audio/volume", ws.handleUpdateAudioVolume)
+	ws.router.GET("/network", ws.handleGetNetwork)
+	ws.router.POST("/sync", ws.handleTriggerSync)
+	ws.router.GET("/metrics", ws.handleMetrics)
+	ws.router.GET("/health", ws.handleHealth)
+	ws.router.POST("/voice/intent", ws.handleVoiceIntent)
+
+	ws.router.GET(wifiSetupPath, ws.handleWifiSetupPage)
+	ws.router.POST(wifiSetupPath, ws.handleWifiSetup)
+
+	davHandler, err := ws.newWebDAVHandler()
+	if err != nil {
+		log.Fatalf("Failed to initialize webdav share: %v", err)
+	}
+	ws.webdav = davHandler
+	for _, method := range webdavMethods {
+		ws.router.Handle(method, webdavPrefix, ws.handleWebDAV)
+		ws.router.Handle(method, webdavPrefix+"/*path", ws.handleWebDAV)
+	}
+
+	system := ws.router.Group("/system", ws.requireAdminToken)
+	system.POST("/reboot", ws.handleReboot)
+	system.POST("/shutdown", ws.handleShutdown)
 }
 
 func (ws *WebServer) Start(port string) {
-	// listen for updates and restart the slideshow
+	ws.applyDisplaySettings()
+	ws.sleepIfDisplayOff()
+
+	// listen for updates, organize any new photos, and restart the slideshow
 	go func() {
+		if dated, err := ws.photoService.DateUndated(); err != nil {
+			slog.Error("unable to date photos", "error", err)
+		} else if dated > 0 {
+			slog.Info("dated photos from their exif", "photos", dated)
+		}
+		ws.organize()
 		for {
 			select {
 			case <-ws.Updated:
 			case <-ws.remoteManager.Updated:
 			case <-ws.localManager.Updated:
-				slog.Info("found new updates, restarting slideshow")
-				ws.imvMutex.Lock()
-				imgPaths, err := ws.GetImgPaths()
-				if err != nil {
-					slog.Error("error while getting image paths", "error", err)
-					ws.imvMutex.Unlock()
-					continue
-				}
-				settings, err := ws.db.GetAppSettings()
-				if err != nil {
-					slog.Error("error while getting settings", "error", err)
-					ws.imvMutex.Unlock()
-					continue
-				}
-				if err := slideshow.RestartSlideshow(imgPaths, settings.SlideshowIntervalSeconds); err != nil {
-					slog.Error("error while restarting slideshow from update", "error", err)
-				}
-				ws.imvMutex.Unlock()
+			case <-ws.fleetManager.Updated:
+				// the fleet may have changed the audio settings
+				ws.audioManager.Update()
+			case <-ws.photoOfDayManager.Updated:
+			case <-ws.scheduleManager.Updated:
+			case <-ws.ingestManager.Updated:
+			case <-ws.rcloneManager.Updated:
+			case <-ws.retentionManager.Updated:
+			case <-ws.pairingManager.Updated:
+			}
+			slog.Info("found new updates, restarting slideshow")
+			ws.organize()
+			if err := ws.RestartSlideshow(); err != nil {
+				slog.Error("error while restarting slideshow from update", "error", err)
 			}
 		}
 	}()
@@ -181,13 +494,53 @@ func (ws *WebServer) Start(port string) {
 	go ws.localManager.Run()
 	go ws.remoteManager.Run()
 	go ws.scheduleManager.Run()
+	go ws.fleetManager.Run()
+	go ws.setupManager.Run()
+	go ws.photoOfDayManager.Run()
+	go ws.usageManager.Run()
+	go ws.ingestManager.Run()
+	go ws.rcloneManager.Run()
+	go ws.retentionManager.Run()
+	go ws.pairingManager.Run()
+	go ws.audioManager.Run()
+	go ws.controller.Run()
+	ws.startInputs()
+
+	if socketPath := os.Getenv("DPF_UNIX_SOCKET"); socketPath != "" {
+		go func() {
+			if err := ws.serveUnix(socketPath); err != nil {
+				slog.Error("unable to serve on unix socket", "path", socketPath, "error", err)
+			}
+		}()
+	}
 
 	log.Printf("Starting web server on port %s", port)
-	if err := ws.router.Run(port); err != nil {
+	if err := http.ListenAndServe(port, ws.handler()); err != nil {
 		log.Fatalf("Failed to start web server: %v", err)
 	}
 }
 
+// serveUnix serves the api on a unix socket alongside tcp so a local reverse proxy or companion
+// process can reach the frame without a network port. A socket left behind by an earlier run is
+// replaced, and only the owner and group may connect.
+func (ws *WebServer) serveUnix(socketPath string) error {
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to remove stale socket, %w", err)
+	}
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("unable to listen, %w", err)
+	}
+	defer listener.Close()
+
+	if err := os.Chmod(socketPath, 0o660); err != nil {
+		return fmt.Errorf("unable to set socket permissions, %w", err)
+	}
+
+	log.Printf("Starting web server on unix socket %s", socketPath)
+	return http.Serve(listener, ws.handler())
+}
+
 func (ws *WebServer) getAllImages() ([]store.Photo, error) {
 	allPhotos, err := ws.db.GetAllPhotos(0)
 	if err != nil {
@@ -202,44 +555,159 @@ func (ws *WebServer) getAllImages() ([]store.Photo, error) {
 	return allPhotos, nil
 }
 
-func (ws *WebServer) GetAppSettings() (*store.AppSettings, error) {
-	return ws.db.GetAppSettings()
+// buildPlaylist returns the photos to show in slideshow order according to settings
+func (ws *WebServer) buildPlaylist(settings *store.AppSettings) ([]store.Photo, error) {
+	categories := []int{1}
+	if settings.IncludeSurprise {
+		categories = []int{0, 1}
+	}
+
+	groups := make([][]store.Photo, 0, len(categories))
+	for _, category := range categories {
+		var group []store.Photo
+		for photo, err := range ws.db.AllPhotos(store.PhotoFilter{Category: category}) {
+			if err != nil {
+				return nil, fmt.Errorf("failed to get all photos for category %d: %v", category, err)
+			}
+			if isPlayable(photo) {
+				group = append(group, photo)
+			}
+		}
+		groups = append(groups, group)
+	}
+
+	rules, err := ws.db.GetSeasonalRules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seasonal rules: %v", err)
+	}
+	inactive := inactiveAlbums(rules, time.Now())
+	for i, group := range groups {
+		groups[i] = slices.DeleteFunc(group, func(photo store.Photo) bool {
+			return slices.Contains(inactive, photo.Album)
+		})
+	}
+
+	schedules, err := ws.db.GetAlbumSchedules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get album schedules: %v", err)
+	}
+	groups = applyAlbumSchedules(groups, schedules, time.Now())
+
+	people, in, err := ws.loadPeopleInPhotos()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get people: %v", err)
+	}
+	groups = applyPeople(groups, people, in)
+
+	var photos []store.Photo
+	for _, group := range groups {
+		photos = append(photos, group...)
+	}
+
+	if settings.PhotoOfDayEnabled {
+		return ws.photoOfDay(settings, photos, time.Now())
+	}
+
+	pinned, err := ws.db.GetPinnedPhotos()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pinned photos: %v", err)
+	}
+
+	var playlist []store.Photo
+	switch {
+	case settings.PlaylistOrder == playlistOrderRoundRobin:
+		albums := albumGroups(photos)
+		if settings.ShuffleEnabled {
+			for _, album := range albums {
+				shufflePhotos(album)
+			}
+		}
+		playlist = includePinned(interleave(albums), pinned, settings.ShuffleEnabled)
+	case settings.ShuffleEnabled:
+		playlist = includePinned(weightedShuffle(photos, settings.AlbumWeights), pinned, true)
+	default:
+		playlist = includePinned(photos, pinned, false)
+	}
+	return ws.boostSpecialDates(playlist, time.Now()), nil
 }
 
-func (ws *WebServer) GetImgPaths() ([]string, error) {
-	allPhotos, err := ws.getAllImages()
+// restartSlideshow restarts imv showing photos in the given order
+func (ws *WebServer) restartSlideshow(photos []store.Photo, settings *store.AppSettings) error {
+	if settings.ShowReactions {
+		photos = ws.withReactions(photos)
+	}
+	imgPaths := make([]string, len(photos))
+	captions := make(map[string]overlay.Text)
+	for i, photo := range photos {
+		imgPaths[i] = ws.paths.Derivative(photo.Category, photo.PhotoName)
+		if text := ws.photoCaption(photo, settings); !text.IsZero() {
+			captions[imgPaths[i]] = text
+		}
+	}
+
+	interval := settings.SlideshowIntervalSeconds
+	if slow, ok := quietInterval(ws.db, time.Now()); ok {
+		interval = max(interval, slow)
+	}
+
+	applyOverlayDefaults(settings)
+	var collages map[string][]string
+	if settings.Collages {
+		imgPaths, collages = ws.mixCollages(imgPaths, photos, settings.ShuffleEnabled, time.Now())
+	}
+	greetings, err := ws.greetingSlides(settings.Language, time.Now())
+	if err != nil {
+		slog.Warn("unable to get special dates, leaving out greetings", "error", err)
+	}
+	announcements, err := ws.announcementSlides(time.Now())
+	if err != nil {
+		slog.Warn("unable to get announcements, leaving them out", "error", err)
+	}
+	applyGuestbookDefaults(settings)
+	guestbook, err := ws.guestbookSlides(settings.GuestbookDays, time.Now())
 	if err != nil {
-		return nil, fmt.Errorf("failed to get all images: %v", err)
+		slog.Warn("unable to get guestbook, leaving out its messages", "error", err)
 	}
-	imgPaths := make([]string, len(allPhotos))
-	for i, photo := range allPhotos {
-		imgPaths[i] = ws.buildImgPathFromPhoto(photo)
+	slides := append(append(greetings, announcements...), guestbook...)
+	imgPaths = mixSlides(imgPaths, slides, announcementEvery)
+
+	pacing := slideshow.Pacing{
+		Interval:     interval,
+		Jitter:       settings.IntervalJitterSeconds,
+		Crossfade:    settings.Crossfade,
+		PanPanoramas: settings.PanPanoramas,
 	}
-	return imgPaths, nil
+	return ws.controller.Restart(imgPaths, collages, pacing, captions, overlayOptions(settings), settings.StripExif)
 }
 
-// buildImgPathFromPhoto constructs the filesystem path to the rotated (_IMGP) image
-// corresponding to a Photo record, based on its category and the web server rootPath.
-func (ws *WebServer) buildImgPathFromPhoto(photo store.Photo) string {
-	baseName := strings.TrimSuffix(photo.PhotoName, filepath.Ext(photo.PhotoName))
-	rotatedName := baseName + "_IMGP" + filepath.Ext(photo.PhotoName)
+// photoCaption is the caption and watermark drawn over the photo on screen
+func (ws *WebServer) photoCaption(photo store.Photo, settings *store.AppSettings) overlay.Text {
+	return overlay.Text{
+		Caption:   overlayText(photo, settings),
+		Watermark: watermarkText(photo, settings),
+	}
+}
 
-	switch photo.Category {
-	case 0:
-		return filepath.Join(ws.rootPath, "photos", "surprise", rotatedName)
-	case 1:
-		return filepath.Join(ws.rootPath, "photos", rotatedName)
-	default:
-		// Fallback: treat as original photos directory
-		return filepath.Join(ws.rootPath, "photos", rotatedName)
+// RestartSlideshow rebuilds the playlist from the current settings and restarts the slideshow
+func (ws *WebServer) RestartSlideshow() error {
+	settings, err := ws.db.GetAppSettings()
+	if err != nil {
+		return fmt.Errorf("error while getting settings, %w", err)
 	}
+
+	photos, err := ws.buildPlaylist(settings)
+	if err != nil {
+		return fmt.Errorf("failed to build playlist, %w", err)
+	}
+	return ws.restartSlideshow(photos, settings)
 }
 
 func (ws *WebServer) handleUpload(c *gin.Context) {
 	// Check if this is an HTMX request
 	isHTMX := c.GetHeader("HX-Request") == "true"
 
-	if srvErr := ws.upload(c); srvErr != nil {
+	category, srvErr := ws.upload(c)
+	if srvErr != nil {
 		if isHTMX {
 			c.String(srvErr.StatusCode, srvErr.Error.Error())
 			return
@@ -249,16 +717,15 @@ func (ws *WebServer) handleUpload(c *gin.Context) {
 	}
 	// If HTMX request, return HTML fragment with updated photos
 	if isHTMX {
-		// Get all photos for category 1
-		photos, err := ws.db.GetAllPhotos(1)
-		if err != nil {
-			c.String(http.StatusInternalServerError, "failed to refresh photos")
+		// the form sits above My Photos, so photos uploaded elsewhere replace their own row
+		if category == paths.CategorySurprise {
+			c.Header("HX-Retarget", "#surprise-photos")
+		}
+		if err := ws.renderPhotoPage(c, category, defaultUIPhotosLimit, 0); err != nil {
+			c.String(http.StatusInternalServerError, tr(c, "failed to refresh photos"))
 			return
 		}
 
-		component := templates.PhotoRow(photos, 1)
-		component.Render(c.Request.Context(), c.Writer)
-
 		// trigger slideshow restart
 		ws.Updated <- true
 		return
@@ -270,30 +737,99 @@ func (ws *WebServer) handleUpload(c *gin.Context) {
 	ws.Updated <- true
 }
 
-func (ws *WebServer) upload(c *gin.Context) *ServerError {
+// upload saves the photo in the request to the category and album given in the form or query,
+// My Photos by default, returning the category it was saved to
+func (ws *WebServer) upload(c *gin.Context) (int, *ServerError) {
 	// Get the file from the form
 	file, err := c.FormFile("file")
 	if err != nil {
-		return &ServerError{http.StatusBadRequest, errors.New("no file provided")}
+		return 0, &ServerError{http.StatusBadRequest, errors.New(tr(c, "no file provided"))}
 	}
 
+	category := paths.CategoryOriginal
+	if categoryStr := formOrQuery(c, "category"); categoryStr != "" {
+		category, err = strconv.Atoi(categoryStr)
+		if err != nil || (category != paths.CategorySurprise && category != paths.CategoryOriginal) {
+			return 0, &ServerError{http.StatusBadRequest, errors.New(tr(c, "category must be 0 (surprise) or 1 (original)"))}
+		}
+	}
+	if source := ws.mirroredFrom(c, category); source != "" {
+		return 0, &ServerError{http.StatusConflict, errors.New(tr(c, "Surprise photos are synced from %s and would be removed by the next sync, upload them there instead", source))}
+	}
+
+	album := formOrQuery(c, "album")
+	if len([]rune(album)) > maxAlbumLength {
+		return 0, &ServerError{http.StatusBadRequest, errors.New(tr(c, "album must be at most %d characters", maxAlbumLength))}
+	}
+
+	// metadata curated elsewhere can come along in a json or xmp sidecar
+	sidecar, err := c.FormFile("sidecar")
+	if err != nil && !errors.Is(err, http.ErrMissingFile) {
+		return 0, &ServerError{http.StatusBadRequest, errors.New(tr(c, "invalid sidecar: %v", err))}
+	}
+
+	// a live photo's video comes along with its still
+	motion, err := c.FormFile("motion")
+	if err != nil && !errors.Is(err, http.ErrMissingFile) {
+		return 0, &ServerError{http.StatusBadRequest, errors.New(tr(c, "invalid live photo video: %v", err))}
+	}
+
+	if srvErr := ws.saveUploadedPhoto(c, file, sidecar, motion, category, album, formOrQuery(c, "from"), false); srvErr != nil {
+		return 0, srvErr
+	}
+	return category, nil
+}
+
+// formOrQuery returns a trimmed form field, falling back to the query parameter of the same name
+func formOrQuery(c *gin.Context, key string) string {
+	if value, ok := c.GetPostForm(key); ok {
+		return strings.TrimSpace(value)
+	}
+	return strings.TrimSpace(c.Query(key))
+}
+
+// mirroredFrom names the remote a category is kept identical to, which deletes photos added to it
+// any other way, or returns empty when photos can be added to the category
+func (ws *WebServer) mirroredFrom(c *gin.Context, category int) string {
+	if category != paths.CategorySurprise {
+		return ""
+	}
+	if ws.remoteManager.Mirrors() {
+		return tr(c, "the shared bucket")
+	}
+	return ws.rcloneManager.Mirrors(category)
+}
+
+// saveUploadedPhoto validates, stores, downsizes, and registers a single uploaded photo in a
+// category, recording who it was uploaded by and adding it to an album if given. The metadata in
+// the sidecar and the video of a live photo, if given, are imported with it. A guest's photo waits
+// for approval when it's required.
+func (ws *WebServer) saveUploadedPhoto(c *gin.Context, file, sidecar, motion *multipart.FileHeader, category int, album, uploadedBy string, guest bool) *ServerError {
 	// Validate file extension
 	ext := filepath.Ext(file.Filename)
 	if !util.SupportedExt.Contains(ext) {
-		return &ServerError{http.StatusBadRequest, fmt.Errorf("unsupported file extension: %s. Supported: .jpeg, .jpg, .png", ext)}
+		return &ServerError{http.StatusBadRequest, errors.New(tr(c, "unsupported file extension: %s. Supported: .jpeg, .jpg, .png", ext))}
+	}
+	if sidecar != nil {
+		if srvErr := checkSidecar(c, sidecar); srvErr != nil {
+			return srvErr
+		}
+	}
+	if motion != nil && !service.IsMotionVideo(motion.Filename) {
+		return &ServerError{http.StatusBadRequest, errors.New(tr(c, "unsupported live photo video extension: %s. Supported: .mov, .mp4", filepath.Ext(motion.Filename)))}
 	}
 
 	// Check for duplicates
-	exists, err := ws.db.PhotoExists(file.Filename, 1)
+	exists, err := ws.db.PhotoExists(file.Filename, category)
 	if err != nil {
 		return &ServerError{http.StatusInternalServerError, fmt.Errorf("database error, %w", err)}
 	}
 	if exists {
-		return &ServerError{http.StatusConflict, fmt.Errorf("photo with name '%s' already exists", file.Filename)}
+		return &ServerError{http.StatusConflict, errors.New(tr(c, "photo with name '%s' already exists", file.Filename))}
 	}
 
 	// Ensure the original directory exists
-	originalDir := filepath.Join(ws.rootPath, "original")
+	originalDir := ws.paths.OriginalDir(category)
 	if err := os.MkdirAll(originalDir, 0o755); err != nil {
 		return &ServerError{http.StatusInternalServerError, fmt.Errorf("failed to create directory: %w", err)}
 	}
@@ -304,43 +840,66 @@ func (ws *WebServer) upload(c *gin.Context) *ServerError {
 		return &ServerError{http.StatusInternalServerError, fmt.Errorf("failed to save file: %w", err)}
 	}
 
-	// auto resize so that viewing in ui is more reliable
-	targetMaxDimStr := os.Getenv("DPF_TARGET_MAX_DIM")
-	targetMaxDim, err := strconv.Atoi(targetMaxDimStr)
-	if err != nil {
-		slog.Warn("unable to parse DPF_TARGET_MAX_DIM, using default", "DPF_TARGET_MAX_DIM", targetMaxDimStr, "default", slideshow.DefaultTargetMaxDim)
-		targetMaxDim = slideshow.DefaultTargetMaxDim
+	// the sidecar and live photo video are kept alongside the original, where they're picked up
+	// when the photo is registered
+	var companions []string
+	saveCompanion := func(upload *multipart.FileHeader, path string) error {
+		if err := c.SaveUploadedFile(upload, path); err != nil {
+			return err
+		}
+		companions = append(companions, path)
+		return nil
 	}
-
-	rOpt, err := slideshow.GenerateRotateOptions(originalDir, file.Filename, targetMaxDim)
-	if err != nil {
-		slog.Warn("unable generate rotate options", "error", err)
-	} else {
-		args := append([]string{"-w", "-x", strconv.Itoa(rOpt.Scale) + "%"}, rOpt.Name)
-		cmd := exec.Command("imgp", args...)
-		if err := cmd.Run(); err != nil {
-			slog.Warn("failed to downsize image", "name", rOpt.Name, "error", err)
+	removeCompanions := func() {
+		for _, path := range companions {
+			if remErr := os.Remove(path); remErr != nil && !os.IsNotExist(remErr) {
+				requestLogger(c).Warn("unable to remove file uploaded with photo", "name", file.Filename, "path", filepath.Base(path), "error", remErr)
+			}
 		}
 	}
 
-	// Get max order for category 1 (original)
-	maxOrder, err := ws.db.GetMaxOrder(1)
-	if err != nil {
-		// Clean up file if DB insert fails
+	var saveErr error
+	if sidecar != nil {
+		saveErr = saveCompanion(sidecar, filePath+strings.ToLower(filepath.Ext(sidecar.Filename)))
+	}
+	if saveErr == nil && motion != nil {
+		saveErr = saveCompanion(motion, strings.TrimSuffix(filePath, ext)+strings.ToLower(filepath.Ext(motion.Filename)))
+	}
+	if saveErr != nil {
+		removeCompanions()
 		if remErr := os.Remove(filePath); remErr != nil {
-			return &ServerError{http.StatusInternalServerError, fmt.Errorf("database error, %w, with failed file removal, %w", err, remErr)}
+			requestLogger(c).Warn("unable to remove uploaded photo", "name", file.Filename, "error", remErr)
 		}
-		return &ServerError{http.StatusInternalServerError, fmt.Errorf("database error: %v", err)}
+		return &ServerError{http.StatusInternalServerError, fmt.Errorf("failed to save file uploaded with photo: %w", saveErr)}
 	}
 
-	// Insert into database
-	if err := ws.db.InsertPhoto(file.Filename, 1, maxOrder); err != nil {
-		// Clean up file if DB insert fails
-		if remErr := os.Remove(filePath); remErr != nil {
-			return &ServerError{http.StatusInternalServerError, fmt.Errorf("failed to insert photo into database, %w, with failed file removal, %w", err, remErr)}
+	if err := ws.photoService.Add(file.Filename, category, uploadedBy, guest); err != nil {
+		removeCompanions()
+		return &ServerError{http.StatusInternalServerError, err}
+	}
+	// auto organizing leaves photos already in an album alone
+	if album != "" {
+		if err := ws.db.UpdatePhotoAlbum(file.Filename, category, album, time.Time{}); err != nil {
+			requestLogger(c).Warn("unable to add uploaded photo to album", "name", file.Filename, "album", album, "error", err)
 		}
+	}
+	ws.notifier.Uploaded(uploadedBy)
+	return nil
+}
 
-		return &ServerError{http.StatusInternalServerError, fmt.Errorf("failed to insert photo into database: %w", err)}
+// checkSidecar makes sure an uploaded sidecar is json or xmp that can be read
+func checkSidecar(c *gin.Context, sidecar *multipart.FileHeader) *ServerError {
+	ext := filepath.Ext(sidecar.Filename)
+	if !service.IsSidecar(sidecar.Filename) {
+		return &ServerError{http.StatusBadRequest, errors.New(tr(c, "unsupported sidecar extension: %s. Supported: .json, .xmp", ext))}
+	}
+	f, err := sidecar.Open()
+	if err != nil {
+		return &ServerError{http.StatusBadRequest, errors.New(tr(c, "invalid sidecar: %v", err))}
+	}
+	defer f.Close()
+	if _, err := service.ParseSidecar(f, ext); err != nil {
+		return &ServerError{http.StatusBadRequest, errors.New(tr(c, "invalid sidecar: %v", err))}
 	}
 	return nil
 }
@@ -349,69 +908,37 @@ func (ws *WebServer) handleRegisterPhoto(c *gin.Context) {
 	// Parse request body
 	var req models.RegisterPhotoRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: fmt.Sprintf("Invalid request body: %v", err)})
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
 		return
 	}
 
 	if req.PhotoName == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "photo_name is required"})
-		return
-	}
-
-	if req.Category != 0 && req.Category != 1 {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "category must be 0 (surprise) or 1 (original)"})
-		return
-	}
-
-	// Validate file extension
-	ext := filepath.Ext(req.PhotoName)
-	if !util.SupportedExt.Contains(ext) {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error: fmt.Sprintf("Unsupported file extension: %s. Supported: .jpeg, .jpg, .png", ext),
-		})
-		return
-	}
-
-	// Check if file exists in the original directory
-	filePath := filepath.Join(ws.rootPath, "original", req.PhotoName)
-	if req.Category == 0 {
-		filePath = filepath.Join(ws.rootPath, "original/surprise", req.PhotoName)
-	}
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: fmt.Sprintf("Photo file does not exist: %s", req.PhotoName)})
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "photo_name is required")})
 		return
 	}
 
-	// Check for duplicates in database
-	exists, err := ws.db.PhotoExists(req.PhotoName, req.Category)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: fmt.Sprintf("Database error: %v", err)})
-		return
-	}
-	if exists {
+	err := ws.photoService.Register(req.PhotoName, req.Category, req.UploadedBy, false)
+	switch {
+	case err == nil:
+		c.Status(http.StatusCreated)
+	case errors.Is(err, service.ErrExists):
 		c.JSON(http.StatusOK, models.RegisterPhotoResponse{
 			PhotoName: req.PhotoName,
 			Category:  req.Category,
 			Order:     -1,
 			Message:   fmt.Sprintf("Photo with name '%s' already exists in database", req.PhotoName),
 		})
-		return
-	}
-
-	// Get max order for the category
-	maxOrder, err := ws.db.GetMaxOrder(req.Category)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: fmt.Sprintf("Database error: %v", err)})
-		return
-	}
-
-	// Insert into database
-	if err := ws.db.InsertPhoto(req.PhotoName, req.Category, maxOrder); err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: fmt.Sprintf("Failed to insert photo into database: %v", err)})
-		return
+	case errors.Is(err, service.ErrInvalidCategory):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "category must be 0 (surprise) or 1 (original)")})
+	case errors.Is(err, service.ErrUnsupportedExt):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: tr(c, "Unsupported file extension: %s. Supported: .jpeg, .jpg, .png", filepath.Ext(req.PhotoName)),
+		})
+	case errors.Is(err, service.ErrFileNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Photo file does not exist: %s", req.PhotoName)})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to insert photo into database: %v", err)})
 	}
-
-	c.Status(http.StatusCreated)
 }
 
 func (ws *WebServer) handleListPhotos(c *gin.Context) {
@@ -422,26 +949,36 @@ func (ws *WebServer) handleListPhotos(c *gin.Context) {
 
 	category, err := strconv.Atoi(categoryStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid category parameter"})
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid category parameter")})
 		return
 	}
 
 	page, err := strconv.Atoi(pageStr)
 	if err != nil || page < 1 {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid page parameter"})
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid page parameter")})
 		return
 	}
 
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit < 1 {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid limit parameter"})
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid limit parameter")})
+		return
+	}
+
+	filter := store.PhotoFilter{Category: category, UploadedBy: strings.TrimSpace(c.Query("uploader"))}
+	if filter.AddedAfter, err = parseUploadTime(c.Query("uploaded_after")); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "uploaded_after must be a date like 2024-06-01 or a time like 2024-06-01T15:04:05Z")})
+		return
+	}
+	if filter.AddedBefore, err = parseUploadTime(c.Query("uploaded_before")); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "uploaded_before must be a date like 2024-06-01 or a time like 2024-06-01T15:04:05Z")})
 		return
 	}
 
 	// Get total count
-	total, err := ws.db.GetPhotoCount(category)
+	total, err := ws.db.CountPhotos(filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: fmt.Sprintf("Database error: %v", err)})
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
 		return
 	}
 
@@ -449,70 +986,83 @@ func (ws *WebServer) handleListPhotos(c *gin.Context) {
 	offset := (page - 1) * limit
 
 	// Get photos
-	photos, err := ws.db.GetPhotos(category, limit, offset)
+	photos, err := ws.db.GetPhotos(filter, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: fmt.Sprintf("Database error: %v", err)})
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
 		return
 	}
+	if photos == nil {
+		photos = []store.Photo{}
+	}
 
 	c.JSON(http.StatusOK, models.PhotoListResponse{
-		Photos: photos,
+		Photos: ws.withReactions(ws.withExpiry(photos)),
 		Total:  total,
 		Page:   page,
 		Limit:  limit,
 	})
 }
 
+// parseUploadTime reads a filter on when photos were uploaded, either an RFC 3339 time or a date
+// meaning midnight at the start of that day in the frame's time zone. Empty is no filter.
+func parseUploadTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation(dateLayout, value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
 func (ws *WebServer) handleDeletePhoto(c *gin.Context) {
 	name := c.Param("name")
 	if name == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Photo name is required"})
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Photo name is required")})
 		return
 	}
 
 	category := c.Param("category")
 	if category == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Category is required"})
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Category is required")})
 		return
 	}
 
 	categoryInt, err := strconv.Atoi(category)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid category parameter"})
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid category parameter")})
 		return
 	}
 
-	// Check if photo exists in database
-	exists, err := ws.db.PhotoExists(name, categoryInt)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: fmt.Sprintf("Database error: %v", err)})
-		return
-	}
-	if !exists {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: fmt.Sprintf("Photo '%s' not found", name)})
+	// Delete the original, everything generated from it, and its database entry
+	if err := ws.photoService.Delete(name, categoryInt); err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Photo '%s' not found", name)})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to delete photo: %v", err)})
 		return
 	}
 
-	// Delete file from filesystem
-	filePath := filepath.Join(ws.rootPath, "original", name)
-	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: fmt.Sprintf("Failed to delete file: %v", err)})
-		return
-	}
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "Photo '%s' deleted successfully", name)})
 
-	// Delete from database
-	if err := ws.db.DeletePhoto(name, categoryInt); err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: fmt.Sprintf("Failed to delete photo from database: %v", err)})
-		return
-	}
+	// drop the photo from the running slideshow
+	ws.requestRestart()
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Photo '%s' deleted successfully", name)})
+// requestRestart asks for the slideshow to be restarted without waiting on it. Requests made
+// while a restart is already pending are combined into that restart.
+func (ws *WebServer) requestRestart() {
+	select {
+	case ws.Updated <- true:
+	default:
+	}
 }
 
 func (ws *WebServer) handleGetSettings(c *gin.Context) {
 	settings, err := ws.db.GetAppSettings()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: fmt.Sprintf("Failed to get settings: %v", err)})
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get settings: %v", err)})
 		return
 	}
 
@@ -522,68 +1072,182 @@ func (ws *WebServer) handleGetSettings(c *gin.Context) {
 func (ws *WebServer) handleUpdateSettings(c *gin.Context) {
 	var req store.AppSettings
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: fmt.Sprintf("Invalid request body: %v", err)})
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
 		return
 	}
 
 	if req.SlideshowIntervalSeconds <= 0 {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "slideshow_interval_seconds must be positive"})
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "slideshow_interval_seconds must be positive")})
+		return
+	}
+	if req.IntervalJitterSeconds < 0 || req.IntervalJitterSeconds >= req.SlideshowIntervalSeconds {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "interval_jitter_seconds must be at least 0 and less than slideshow_interval_seconds")})
 		return
 	}
 
-	newSettings := &req
+	applyThemeDefaults(&req)
+	if !validTheme(req.Theme) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "theme must be one of %s", strings.Join(validThemes, ", "))})
+		return
+	}
+	if !validAccentColor.MatchString(req.AccentColor) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "accent_color must be a hex color like %s", defaultAccentColor)})
+		return
+	}
 
-	if err := ws.db.UpsertAppSettings(newSettings); err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: fmt.Sprintf("Failed to update settings: %v", err)})
+	applyOrganizeDefaults(&req)
+	if !validOrganizeMode(req.AutoOrganize) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "auto_organize must be one of %s", strings.Join(validOrganizeModes, ", "))})
 		return
 	}
 
-	// After updating settings, restart the slideshow with the new configuration.
-	var imgPhotos []store.Photo
-	if newSettings.IncludeSurprise {
-		allPhotos, err := ws.getAllImages()
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: fmt.Sprintf("Failed to get photos for restart: %v", err)})
-			return
-		}
-		imgPhotos = allPhotos
-	} else {
-		photos, err := ws.db.GetAllPhotos(1)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: fmt.Sprintf("Failed to get photos for restart: %v", err)})
-			return
-		}
-		imgPhotos = photos
+	applyPlaylistDefaults(&req)
+	if !validPlaylistOrder(req.PlaylistOrder) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "playlist_order must be one of %s", strings.Join(validPlaylistOrders, ", "))})
+		return
 	}
 
-	imgPaths := make([]string, len(imgPhotos))
-	for i, p := range imgPhotos {
-		imgPaths[i] = ws.buildImgPathFromPhoto(p)
+	if !validAlbumWeights(req.AlbumWeights) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "album_weights must be between 0 and %d for albums named with at most %d characters", maxAlbumWeight, maxAlbumLength)})
+		return
 	}
 
-	if newSettings.ShuffleEnabled && len(imgPaths) > 1 {
-		rand.Shuffle(len(imgPaths), func(i, j int) {
-			imgPaths[i], imgPaths[j] = imgPaths[j], imgPaths[i]
-		})
+	applyPhotoOfDayDefaults(&req)
+	if !validScheduleTime.MatchString(req.PhotoOfDayTime) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid photo of the day time format: need 23:15, got %s", req.PhotoOfDayTime)})
+		return
 	}
 
-	ws.imvMutex.Lock()
-	defer ws.imvMutex.Unlock()
-	if err := slideshow.RestartSlideshow(imgPaths, newSettings.SlideshowIntervalSeconds); err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: fmt.Sprintf("Failed to restart slideshow: %v", err)})
+	applyOverlayDefaults(&req)
+	if !validOverlay(&req) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid overlay position %s or size %s", req.OverlayPosition, req.OverlaySize)})
+		return
+	}
+	applyWatermarkDefaults(&req)
+	if !validWatermark(&req) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "watermark_text must be at most %d characters", maxWatermarkLength)})
+		return
+	}
+	applyGuestbookDefaults(&req)
+	if !validGuestbookDays(&req) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "guestbook_days must be between 1 and %d", maxGuestbookDays)})
+		return
+	}
+	applyAudioDefaults(&req)
+	if !validAudioVolume(req.AudioVolume) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "audio_volume must be between 0 and %d", maxAudioVolume)})
+		return
+	}
+	if !validAudioSource(req.AudioSource) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "audio_source must be an http or https url or an absolute path on the frame")})
+		return
+	}
+	if req.AudioEnabled && req.AudioSource == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "audio_source is required to play audio")})
 		return
 	}
 
-	c.Status(http.StatusOK)
+	if req.Language == "" {
+		req.Language = i18n.DefaultLanguage
+	}
+	if !i18n.IsSupported(req.Language) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "language must be one of %s", strings.Join(i18n.Supported(), ", "))})
+		return
+	}
+
+	applyDisplayDefaults(&req)
+	if !validDisplayTransform(req.DisplayTransform) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "transform must be one of %s", strings.Join(display.Transforms, ", "))})
+		return
+	}
+
+	ws.saveSettings(c, &req)
+}
+
+// saveSettings stores the settings, recording them in the settings history, and restarts the
+// slideshow with them
+func (ws *WebServer) saveSettings(c *gin.Context, newSettings *store.AppSettings) {
+	if !ws.storeSettings(c, newSettings) {
+		return
+	}
+
+	// After updating settings, restart the slideshow with the new configuration.
+	imgPhotos, err := ws.buildPlaylist(newSettings)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get photos for restart: %v", err)})
+		return
+	}
+
+	if err := ws.restartSlideshow(imgPhotos, newSettings); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to restart slideshow: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, newSettings)
+}
+
+// storeSettings stores the settings and records them in the settings history without restarting
+// the slideshow, responding with an error and returning false if they couldn't be stored
+func (ws *WebServer) storeSettings(c *gin.Context, newSettings *store.AppSettings) bool {
+	previous, err := ws.db.GetAppSettings()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get settings: %v", err)})
+		return false
+	}
+	// the display mode is only changed through /display/mode since it has to match the panel
+	newSettings.DisplayMode = previous.DisplayMode
+	newSettings.DisplayScale = previous.DisplayScale
+	if previous.DisplayTransform != newSettings.DisplayTransform {
+		if err := display.UpdateTransform(newSettings.DisplayTransform); err != nil {
+			requestLogger(c).Warn("unable to rotate display", "transform", newSettings.DisplayTransform, "error", err)
+		}
+	}
+
+	if err := ws.db.UpsertAppSettings(newSettings); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to update settings: %v", err)})
+		return false
+	}
+	recordSettingsVersion(ws.db, store.HistorySettings, previous, newSettings, changedBy(c))
+	ws.audioManager.Update()
+
+	// slideshow copies are made again on restart with or without their EXIF metadata
+	if previous.StripExif != newSettings.StripExif {
+		if err := ws.removeDerivatives(); err != nil {
+			requestLogger(c).Warn("unable to remove slideshow copies", "error", err)
+		}
+	}
+	return true
 }
 
 func (ws *WebServer) handleGetSchedule(c *gin.Context) {
+	ws.respondSchedule(c)
+}
+
+// respondSchedule writes the schedule along with the times in effect right now
+func (ws *WebServer) respondSchedule(c *gin.Context) {
+	now := time.Now()
 	schedule, err := ws.db.GetSchedule()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: fmt.Sprintf("Failed to get settings: %v", err)})
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get settings: %v", err)})
 		return
 	}
-	c.JSON(http.StatusOK, schedule)
+	profiles, err := ws.db.GetScheduleProfiles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get schedule profiles: %v", err)})
+		return
+	}
+	current, profile := resolveSchedule(schedule, profiles, now)
+
+	// a profile picked for a day that has passed no longer applies
+	if schedule.TodayDate != now.Format(dateLayout) {
+		schedule.TodayProfile, schedule.TodayDate = "", ""
+	}
+	c.JSON(http.StatusOK, models.ScheduleResponse{
+		Schedule:       *schedule,
+		CurrentProfile: profile,
+		CurrentStart:   current.Start,
+		CurrentEnd:     current.End,
+	})
 }
 
 var validScheduleTime = regexp.MustCompile(`^(?:[01]\d|2[0-3]):[0-5]\d$`)
@@ -591,55 +1255,86 @@ var validScheduleTime = regexp.MustCompile(`^(?:[01]\d|2[0-3]):[0-5]\d$`)
 func (ws *WebServer) handleUpdateSchedule(c *gin.Context) {
 	var req store.Schedule
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: fmt.Sprintf("Invalid request body: %v", err)})
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
 		return
 	}
 
 	if !validScheduleTime.MatchString(req.Start) {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: fmt.Sprintf("Invalid start time format: need 23:15, got %s", req.Start)})
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid start time format: need 23:15, got %s", req.Start)})
 		return
 	}
 
 	if !validScheduleTime.MatchString(req.End) {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: fmt.Sprintf("Invalid end time format: need 23:15, got %s", req.End)})
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid end time format: need 23:15, got %s", req.End)})
+		return
+	}
+
+	applyQuietHoursDefaults(&req.QuietHours)
+	if !ws.validQuietHours(c, req.QuietHours) {
+		return
+	}
+
+	ws.saveSchedule(c, &req, false)
+}
+
+// saveSchedule stores the schedule's times and quiet hours, recording them in the settings history.
+// The profile picked for every day is kept as it is unless restoring it from the history, since
+// profiles are otherwise picked through their own endpoints.
+func (ws *WebServer) saveSchedule(c *gin.Context, req *store.Schedule, restoreProfile bool) {
+	previous, err := ws.db.GetSchedule()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get settings: %v", err)})
 		return
 	}
 
 	newSchedule := &store.Schedule{
-		Enabled: req.Enabled,
-		Start:   req.Start,
-		End:     req.End,
+		Enabled:       req.Enabled,
+		Start:         req.Start,
+		End:           req.End,
+		ActiveProfile: previous.ActiveProfile,
+		TodayProfile:  previous.TodayProfile,
+		TodayDate:     previous.TodayDate,
+		QuietHours:    req.QuietHours,
+	}
+	if restoreProfile {
+		newSchedule.ActiveProfile = req.ActiveProfile
 	}
 
 	if err := ws.db.UpsertSchedule(newSchedule); err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: fmt.Sprintf("Failed to update schedule: %v", err)})
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to update schedule: %v", err)})
 		return
 	}
+	recordSettingsVersion(ws.db, store.HistorySchedule, scheduleVersion(previous), scheduleVersion(newSchedule), changedBy(c))
 
 	c.JSON(http.StatusOK, newSchedule)
+
+	// pick up a new interval if the slideshow is slowed down for quiet hours
+	if previous.QuietHours != newSchedule.QuietHours {
+		ws.requestRestart()
+	}
 }
 
 func (ws *WebServer) handlePlayFromPhoto(c *gin.Context) {
 	photoName := c.Param("name")
 	if photoName == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Photo name is required"})
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Photo name is required")})
 		return
 	}
 
 	category := c.Param("category")
 	if category == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Category is required"})
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Category is required")})
 		return
 	}
 
 	photoCategory, err := strconv.Atoi(category)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: fmt.Sprintf("Category must be an integer, %v", err)})
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Category must be an integer, %v", err)})
 		return
 	}
 
 	if photoCategory != 0 && photoCategory != 1 {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "category must be 0 (surprise) or 1 (original)"})
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "category must be 0 (surprise) or 1 (original)")})
 		return
 	}
 
@@ -647,7 +1342,7 @@ func (ws *WebServer) handlePlayFromPhoto(c *gin.Context) {
 	ext := filepath.Ext(photoName)
 	if ext == "" || !util.SupportedExt.Contains(ext) {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error: fmt.Sprintf("Unsupported or missing file extension: %s. Supported: .jpeg, .jpg, .png", ext),
+			Error: tr(c, "Unsupported or missing file extension: %s. Supported: .jpeg, .jpg, .png", ext),
 		})
 		return
 	}
@@ -655,12 +1350,12 @@ func (ws *WebServer) handlePlayFromPhoto(c *gin.Context) {
 	// Ensure the photo exists in the database
 	exists, err := ws.db.PhotoExists(photoName, photoCategory)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: fmt.Sprintf("Database error: %v", err)})
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
 		return
 	}
 	if !exists {
 		c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Error: fmt.Sprintf("Photo '%s' in category %d not found", photoName, photoCategory),
+			Error: tr(c, "Photo '%s' in category %d not found", photoName, photoCategory),
 		})
 		return
 	}
@@ -668,19 +1363,17 @@ func (ws *WebServer) handlePlayFromPhoto(c *gin.Context) {
 	// Fetch all photos in the required order: category 0 then category 1
 	allPhotos, err := ws.getAllImages()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: fmt.Sprintf("Failed to get image paths: %v", err)})
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get image paths: %v", err)})
 		return
 	}
+	allPhotos = playablePhotos(allPhotos)
 	if len(allPhotos) == 0 {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "No photos available to start slideshow"})
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "No photos available to start slideshow")})
 		return
 	}
 
-	imgPaths := make([]string, len(allPhotos))
 	startIdx := -1
-
 	for i, p := range allPhotos {
-		imgPaths[i] = ws.buildImgPathFromPhoto(p)
 		if p.PhotoName == photoName && p.Category == photoCategory {
 			startIdx = i
 		}
@@ -689,33 +1382,31 @@ func (ws *WebServer) handlePlayFromPhoto(c *gin.Context) {
 	if startIdx == -1 {
 		// Defensive: DB changed between existence check and fetch
 		c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Error: fmt.Sprintf("Photo '%s' in category %d not found in current playlist", photoName, photoCategory),
+			Error: tr(c, "Photo '%s' in category %d not found in current playlist", photoName, photoCategory),
 		})
 		return
 	}
 
 	// Rotate the slice so the requested photo is first
-	var ordered []string
+	var ordered []store.Photo
 	if startIdx == 0 {
-		ordered = imgPaths
+		ordered = allPhotos
 	} else {
-		ordered = append(imgPaths[startIdx:], imgPaths[:startIdx]...)
+		ordered = append(allPhotos[startIdx:], allPhotos[:startIdx]...)
 	}
 
 	settings, err := ws.db.GetAppSettings()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error: fmt.Sprintf("Unable to fetch app settings, %v", err),
+			Error: tr(c, "Unable to fetch app settings, %v", err),
 		})
 		return
 	}
 
-	ws.imvMutex.Lock()
-	defer ws.imvMutex.Unlock()
-	// Let slideshow.RestartSlideshow handle defaulting when interval <= 0
-	if err := slideshow.RestartSlideshow(ordered, settings.SlideshowIntervalSeconds); err != nil {
+	// Let the slideshow controller handle defaulting when interval <= 0
+	if err := ws.restartSlideshow(ordered, settings); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error: fmt.Sprintf("Failed to restart slideshow: %v", err),
+			Error: tr(c, "Failed to restart slideshow: %v", err),
 		})
 		return
 	}
@@ -725,93 +1416,241 @@ func (ws *WebServer) handlePlayFromPhoto(c *gin.Context) {
 }
 
 func (ws *WebServer) handleGetDisplay(c *gin.Context) {
-	enabled, err := display.GetEnabled()
+	output, err := display.GetOutput()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: fmt.Sprintf("Failed to get display state: %v", err)})
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get display state: %v", err)})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.DisplayStateResponse{Enabled: enabled})
+	c.JSON(http.StatusOK, models.DisplayStateResponse{Enabled: output.Enabled, Transform: output.Transform})
 }
 
 func (ws *WebServer) handleUpdateDisplay(c *gin.Context) {
 	state := c.Param("state")
 	if state != "0" && state != "1" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "state must be 0 (off) or 1 (on)"})
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "state must be 0 (off) or 1 (on)")})
 		return
 	}
 
 	desiredEnabled := state == "1"
-	if err := display.UpdateEnabled(desiredEnabled); err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: fmt.Sprintf("Failed to update display state: %v", err)})
+	if err := setDisplayEnabled(ws.controller, desiredEnabled); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to update display state: %v", err)})
 		return
 	}
+	ws.audioManager.Update()
 
 	// Re-read state to reflect actual output if possible.
 	enabled, err := display.GetEnabled()
 	if err != nil {
-		slog.Warn("failed to re-read display state after update", "error", err)
+		requestLogger(c).Warn("failed to re-read display state after update", "error", err)
 		enabled = desiredEnabled
 	}
 
 	c.JSON(http.StatusOK, models.DisplayStateResponse{Enabled: enabled})
 }
 
-func (ws *WebServer) handlePhotoImage(c *gin.Context) {
+// handleDisplayScreenshot returns a PNG of what is on the frame's screen for remote debugging
+func (ws *WebServer) handleDisplayScreenshot(c *gin.Context) {
+	png, err := display.Screenshot()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to capture screenshot: %v", err)})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+// parsePhotoFileParams decodes the category and name path parameters of the photo file endpoints.
+// If they are invalid an error response is written and ok is false.
+func parsePhotoFileParams(c *gin.Context) (category int, name string, ok bool) {
 	categoryStr := c.Param("category")
 	encodedName := c.Param("name")
 
 	if encodedName == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Photo name is required"})
-		return
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Photo name is required")})
+		return 0, "", false
 	}
 
 	// Decode the photo name
 	name, err := url.PathUnescape(encodedName)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid photo name encoding"})
-		return
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid photo name encoding")})
+		return 0, "", false
 	}
 
-	category, err := strconv.Atoi(categoryStr)
+	// Reject anything that could escape the photo directories
+	if name != filepath.Base(name) || name == "." || name == ".." {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid photo name")})
+		return 0, "", false
+	}
+
+	category, err = strconv.Atoi(categoryStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid category parameter"})
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid category parameter")})
+		return 0, "", false
+	}
+
+	return category, name, true
+}
+
+func (ws *WebServer) handlePhotoImage(c *gin.Context) {
+	category, name, ok := parsePhotoFileParams(c)
+	if !ok {
 		return
 	}
 
 	// Determine file path based on category
-	var filePath string
-	if category == 0 {
-		filePath = filepath.Join(ws.rootPath, "original/surprise", name)
-	} else {
-		filePath = filepath.Join(ws.rootPath, "original", name)
-	}
+	filePath := ws.paths.Original(category, name)
 
 	// Check if file exists
+	info, err := os.Stat(filePath)
+	if os.IsNotExist(err) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Photo file not found: %s", name)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to stat photo file: %v", err)})
+		return
+	}
+
+	// Serve the original if no resize was requested
+	if c.Query("w") == "" && c.Query("h") == "" {
+		c.File(filePath)
+		return
+	}
+
+	width, err := parseDimension(c.Query("w"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid w parameter: %v", err)})
+		return
+	}
+	height, err := parseDimension(c.Query("h"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid h parameter: %v", err)})
+		return
+	}
+	fit, err := imaging.ParseFit(c.Query("fit"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	resizedPath := ws.buildResizedPath(category, name, width, height, fit)
+
+	// Serve hot resized images from memory if the original has not changed since caching
+	if entry, ok := ws.imageCache.Get(resizedPath); ok && !entry.ModTime.Before(info.ModTime()) {
+		c.Data(http.StatusOK, entry.ContentType, entry.Data)
+		return
+	}
+
+	if resizedInfo, err := os.Stat(resizedPath); err != nil || resizedInfo.ModTime().Before(info.ModTime()) {
+		if err := imaging.ResizeFile(filePath, resizedPath, width, height, fit); err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to resize photo: %v", err)})
+			return
+		}
+	}
+
+	data, err := os.ReadFile(resizedPath)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to read resized photo: %v", err)})
+		return
+	}
+	entry := cache.Entry{
+		Data:        data,
+		ContentType: mime.TypeByExtension(strings.ToLower(filepath.Ext(name))),
+		ModTime:     info.ModTime(),
+	}
+	ws.imageCache.Add(resizedPath, entry)
+
+	c.Data(http.StatusOK, entry.ContentType, entry.Data)
+}
+
+// handlePhotoDownload serves the untouched original as an attachment, unlike handlePhotoImage
+// which is meant for inline display
+func (ws *WebServer) handlePhotoDownload(c *gin.Context) {
+	category, name, ok := parsePhotoFileParams(c)
+	if !ok {
+		return
+	}
+
+	filePath := ws.paths.Original(category, name)
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: fmt.Sprintf("Photo file not found: %s", name)})
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Photo file not found: %s", name)})
 		return
 	}
 
-	// Serve the file
-	c.File(filePath)
+	if contentType := mime.TypeByExtension(strings.ToLower(filepath.Ext(name))); contentType != "" {
+		c.Header("Content-Type", contentType)
+	}
+	c.FileAttachment(filePath, name)
+}
+
+// parseDimension parses an optional resize dimension, where empty means unconstrained
+func parseDimension(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	dim, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	if dim < 1 || dim > imaging.MaxDim {
+		return 0, fmt.Errorf("must be between 1 and %d", imaging.MaxDim)
+	}
+	return dim, nil
+}
+
+// buildResizedPath constructs the on-disk cache path of a resized copy of a photo
+func (ws *WebServer) buildResizedPath(category int, name string, width, height int, fit imaging.Fit) string {
+	variant := fmt.Sprintf("%dx%d_%s", width, height, fit)
+	return ws.paths.Resized(category, variant, name)
 }
 
+// handleUIPhotos renders a page of the photos in a category, newest first. The first page renders
+// the whole row, while later pages are appended to it as the end of the row scrolls into view.
 func (ws *WebServer) handleUIPhotos(c *gin.Context) {
 	categoryStr := c.Param("category")
 	category, err := strconv.Atoi(categoryStr)
 	if err != nil {
-		c.String(http.StatusBadRequest, "Invalid category")
+		c.String(http.StatusBadRequest, tr(c, "Invalid category"))
 		return
 	}
 
-	// Get all photos for this category
-	photos, err := ws.db.GetAllPhotos(category)
-	if err != nil {
-		c.String(http.StatusInternalServerError, fmt.Sprintf("Error fetching photos: %v", err))
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultUIPhotosLimit)))
+	if err != nil || limit < 1 || limit > maxUIPhotosLimit {
+		c.String(http.StatusBadRequest, tr(c, "limit must be between 1 and %d", maxUIPhotosLimit))
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.String(http.StatusBadRequest, tr(c, "Invalid offset parameter"))
 		return
 	}
 
-	component := templates.PhotoRow(photos, category)
-	component.Render(c.Request.Context(), c.Writer)
+	if err := ws.renderPhotoPage(c, category, limit, offset); err != nil {
+		c.String(http.StatusInternalServerError, tr(c, "Error fetching photos: %v", err))
+	}
+}
+
+// renderPhotoPage renders up to limit photos in a category starting at offset, followed by a
+// loader for the next page when there are more
+func (ws *WebServer) renderPhotoPage(c *gin.Context, category, limit, offset int) error {
+	// one more than the page tells whether there's another after it
+	photos, err := ws.db.GetPhotosPage(store.PhotoFilter{Category: category}, limit+1, offset)
+	if err != nil {
+		return err
+	}
+	next := 0
+	if len(photos) > limit {
+		photos = photos[:limit]
+		next = offset + limit
+	}
+
+	photos = ws.withReactions(ws.withExpiry(photos))
+	component := templates.PhotoPage(photos, category, next, limit)
+	if offset == 0 {
+		component = templates.PhotoRow(photos, category, next, limit)
+	}
+	return component.Render(c.Request.Context(), c.Writer)
 }
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/api/web/templates"
+	"github.com/gin-gonic/gin"
+)
+
+const wifiSetupPath = "/setup/wifi"
+
+// portalExempt are the paths left alone by the captive portal, which are the setup page itself,
+// its assets, and those read by programs rather than people
+var portalExempt = []string{"/static/", "/favicon", "/api/", "/health", "/metrics"}
+
+// captivePortal sends page loads to the wifi setup page while the setup hotspot is up so phones
+// joining the hotspot open it automatically. Api requests pass through untouched.
+func (ws *WebServer) captivePortal(c *gin.Context) {
+	if !ws.setupManager.Active() || !wantsPage(c) {
+		c.Next()
+		return
+	}
+
+	path := c.Request.URL.Path
+	if path == wifiSetupPath || slices.ContainsFunc(portalExempt, func(prefix string) bool { return strings.HasPrefix(path, prefix) }) {
+		c.Next()
+		return
+	}
+	c.Redirect(http.StatusFound, ws.basePath+wifiSetupPath)
+	c.Abort()
+}
+
+// wantsPage reports whether the request loads a page in a browser rather than calling the api
+func wantsPage(c *gin.Context) bool {
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		return false
+	}
+	if c.GetHeader("HX-Request") == "true" {
+		return false
+	}
+	return !strings.Contains(c.GetHeader("Accept"), "application/json")
+}
+
+func (ws *WebServer) handleWifiSetupPage(c *gin.Context) {
+	if !ws.setupManager.Active() {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: tr(c, "Wifi setup is only available while the setup hotspot is running")})
+		return
+	}
+
+	networks, lastError := ws.setupManager.Status()
+	component := templates.WifiSetupPage(networks, lastError, false)
+	component.Render(c.Request.Context(), c.Writer)
+}
+
+func (ws *WebServer) handleWifiSetup(c *gin.Context) {
+	if !ws.setupManager.Active() {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: tr(c, "Wifi setup is only available while the setup hotspot is running")})
+		return
+	}
+
+	ssid := strings.TrimSpace(c.PostForm("ssid"))
+	if ssid == "" {
+		networks, _ := ws.setupManager.Status()
+		c.Status(http.StatusBadRequest)
+		component := templates.WifiSetupPage(networks, tr(c, "Pick a wifi network"), false)
+		component.Render(c.Request.Context(), c.Writer)
+		return
+	}
+
+	// the hotspot goes down while connecting so respond before switching networks
+	go ws.setupManager.Connect(ssid, c.PostForm("password"))
+
+	component := templates.WifiSetupPage(nil, tr(c, "Connecting to %s", ssid), true)
+	component.Render(c.Request.Context(), c.Writer)
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/network"
+)
+
+const (
+	setupCheckInterval = 30 * time.Second
+	// setupGracePeriod is how long the frame must be offline before starting the setup hotspot
+	setupGracePeriod = 2 * time.Minute
+
+	defaultWifiIfname = "wlan0"
+	defaultSetupSSID  = "PhotoFrame-Setup"
+)
+
+// SetupManager watches for the frame losing its network connection and starts a setup hotspot
+// with a captive portal so someone nearby can enter new wifi credentials
+type SetupManager struct {
+	enabled bool
+	ifname  string
+	ssid    string
+
+	// active is read on every request by the captive portal so it never waits on nmcli
+	active atomic.Bool
+
+	// switching is held while nmcli checks, scans, or changes networks, which can take tens of
+	// seconds, so only one runs at a time
+	switching sync.Mutex
+
+	mu            sync.Mutex
+	networks      []network.WifiNetwork
+	lastConnected time.Time
+	lastError     string
+}
+
+func NewSetupManager() (*SetupManager, error) {
+	ifname := os.Getenv("DPF_WIFI_IFNAME")
+	if ifname == "" {
+		ifname = defaultWifiIfname
+	}
+	ssid := os.Getenv("DPF_SETUP_SSID")
+	if ssid == "" {
+		ssid = defaultSetupSSID
+	}
+
+	return &SetupManager{
+		enabled:       os.Getenv("DPF_WIFI_SETUP") == "1",
+		ifname:        ifname,
+		ssid:          ssid,
+		lastConnected: time.Now(),
+	}, nil
+}
+
+func (s *SetupManager) Run() {
+	if !s.enabled {
+		slog.Info("DPF_WIFI_SETUP not enabled, skipping wifi setup mode")
+		return
+	}
+
+	ticker := time.NewTicker(setupCheckInterval)
+	for range ticker.C {
+		s.check()
+	}
+}
+
+// Active reports whether the setup hotspot is up
+func (s *SetupManager) Active() bool {
+	return s.active.Load()
+}
+
+// Status returns the networks found before the hotspot started and the last connection error
+func (s *SetupManager) Status() ([]network.WifiNetwork, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.networks, s.lastError
+}
+
+func (s *SetupManager) check() {
+	s.switching.Lock()
+	defer s.switching.Unlock()
+
+	if s.active.Load() {
+		return
+	}
+
+	connected, err := network.Connected()
+	if err != nil {
+		slog.Warn("unable to check network connection", "error", err)
+		return
+	}
+
+	s.mu.Lock()
+	if connected {
+		s.lastConnected = time.Now()
+	}
+	offlineSince := s.lastConnected
+	s.mu.Unlock()
+	if connected || time.Since(offlineSince) < setupGracePeriod {
+		return
+	}
+
+	slog.Info("network unreachable, starting wifi setup mode", "offline_since", offlineSince, "ssid", s.ssid)
+	s.startHotspot()
+}
+
+// startHotspot scans for networks while the interface is still free and then brings up the
+// setup hotspot. Callers must hold switching.
+func (s *SetupManager) startHotspot() {
+	networks, err := network.ScanWifi(s.ifname)
+	if err != nil {
+		slog.Warn("unable to scan wifi networks", "error", err)
+	} else {
+		s.mu.Lock()
+		s.networks = networks
+		s.mu.Unlock()
+	}
+
+	if err := network.StartHotspot(s.ifname, s.ssid); err != nil {
+		slog.Error("unable to start wifi setup hotspot", "error", err)
+		return
+	}
+	s.active.Store(true)
+}
+
+// Connect leaves setup mode and joins the given wifi network. If the frame can't join, the setup
+// hotspot is started again with the error shown on the setup page.
+func (s *SetupManager) Connect(ssid, password string) {
+	s.switching.Lock()
+	defer s.switching.Unlock()
+
+	if err := network.StopHotspot(); err != nil {
+		slog.Warn("unable to stop wifi setup hotspot", "error", err)
+	}
+	s.active.Store(false)
+
+	if err := network.ConnectWifi(s.ifname, ssid, password); err != nil {
+		slog.Warn("unable to connect to wifi from setup mode", "ssid", ssid, "error", err)
+		s.mu.Lock()
+		s.lastError = err.Error()
+		s.mu.Unlock()
+		s.startHotspot()
+		return
+	}
+
+	slog.Info("connected to wifi from setup mode", "ssid", ssid)
+	s.mu.Lock()
+	s.lastError = ""
+	s.lastConnected = time.Now()
+	s.mu.Unlock()
+}
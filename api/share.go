@@ -0,0 +1,130 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/aouyang1/digitalphotoframe/util"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultShareExpiry = 72 * time.Hour
+	maxShareExpiry     = 30 * 24 * time.Hour
+)
+
+// handleCreateShareLink generates a tokenized public url that serves only the requested photo
+// until it expires
+func (ws *WebServer) handleCreateShareLink(c *gin.Context) {
+	category, name, ok := parsePhotoFileParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.ShareLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
+		return
+	}
+
+	expiry := defaultShareExpiry
+	if req.ExpiresInHours < 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "expires_in_hours must be positive")})
+		return
+	}
+	if req.ExpiresInHours > 0 {
+		expiry = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+	if expiry > maxShareExpiry {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "expires_in_hours must be at most %d", int(maxShareExpiry.Hours()))})
+		return
+	}
+
+	photo, err := ws.db.GetPhoto(name, category)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
+		return
+	}
+	if photo == nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Photo '%s' in category %d not found", name, category)})
+		return
+	}
+
+	token, err := util.NewToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to generate share token: %v", err)})
+		return
+	}
+
+	link := &store.ShareLink{
+		Token:     token,
+		PhotoID:   photo.ID,
+		ExpiresAt: time.Now().Add(expiry),
+	}
+	if err := ws.db.InsertShareLink(link); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to create share link: %v", err)})
+		return
+	}
+
+	// opportunistically clean up old links
+	if err := ws.db.DeleteExpiredShareLinks(); err != nil {
+		requestLogger(c).Warn("failed to clean up expired share links", "error", err)
+	}
+
+	c.JSON(http.StatusCreated, models.ShareLinkResponse{
+		URL:       fmt.Sprintf("%s/share/%s", ws.requestBaseURL(c), token),
+		Token:     token,
+		ExpiresAt: link.ExpiresAt,
+	})
+}
+
+// handleSharedPhoto serves the single photo a share link points to
+func (ws *WebServer) handleSharedPhoto(c *gin.Context) {
+	link, err := ws.db.GetShareLink(c.Param("token"))
+	if err != nil {
+		c.String(http.StatusInternalServerError, tr(c, "Failed to look up share link"))
+		return
+	}
+	if link == nil {
+		c.String(http.StatusNotFound, tr(c, "This link has expired or does not exist"))
+		return
+	}
+
+	filePath := ws.paths.Original(link.Category, link.PhotoName)
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		c.String(http.StatusNotFound, tr(c, "This photo is no longer available"))
+		return
+	}
+
+	settings, err := ws.db.GetAppSettings()
+	if err != nil {
+		c.String(http.StatusInternalServerError, tr(c, "Failed to get settings"))
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	if !settings.StripExif {
+		c.File(filePath)
+		return
+	}
+	// share links leave the frame, so location and camera details are left out when asked to
+	if err := serveStripped(c, filePath); err != nil {
+		requestLogger(c).Warn("unable to strip metadata from shared photo", "name", link.PhotoName, "error", err)
+		c.String(http.StatusInternalServerError, tr(c, "Failed to prepare photo"))
+	}
+}
+
+// requestBaseURL returns the scheme and host the client used to reach the server followed by the
+// base path the frame is served under
+func (ws *WebServer) requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s%s", scheme, c.Request.Host, ws.basePath)
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/slideshow"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultShowMinutes = 10
+	maxShowMinutes     = 24 * 60
+)
+
+// handleHoldSlideshow freezes the slideshow on the image currently on screen until released.
+// Photo syncs and settings changes made while held are applied on release.
+func (ws *WebServer) handleHoldSlideshow(c *gin.Context) {
+	if err := ws.controller.Hold(); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to hold slideshow: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, models.SlideshowHoldResponse{Held: true})
+}
+
+func (ws *WebServer) handleReleaseSlideshow(c *gin.Context) {
+	if err := ws.controller.Release(); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to release slideshow: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, models.SlideshowHoldResponse{Held: false})
+}
+
+// handleSlideshowState reports what the slideshow is playing, falling back to the state saved last
+// when imv isn't running
+func (ws *WebServer) handleSlideshowState(c *gin.Context) {
+	state := ws.controller.State()
+	if state == nil {
+		var err error
+		if state, err = ws.db.GetSlideshowState(); err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get slideshow state: %v", err)})
+			return
+		}
+	}
+
+	resp := models.SlideshowStateResponse{
+		Running: ws.controller.Status().Running,
+		Held:    ws.controller.Held(),
+		Asleep:  ws.controller.Asleep(),
+	}
+	if state != nil {
+		resp.PlaylistID = state.PlaylistID
+		resp.Index = state.Index
+		resp.Total = len(state.Playlist)
+		resp.Paused = state.Paused
+		resp.StartedAt = state.StartedAt
+		resp.SavedAt = state.SavedAt
+		if state.Index >= 1 && state.Index <= len(state.Playlist) {
+			resp.Current = state.Playlist[state.Index-1]
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// handleShowPhoto interrupts the playlist to display one photo for the requested number of
+// minutes, after which the slideshow returns to where it was
+func (ws *WebServer) handleShowPhoto(c *gin.Context) {
+	category, name, ok := parsePhotoFileParams(c)
+	if !ok {
+		return
+	}
+
+	minutes := defaultShowMinutes
+	if minutesStr := c.Query("minutes"); minutesStr != "" {
+		parsed, err := strconv.Atoi(minutesStr)
+		if err != nil || parsed <= 0 || parsed > maxShowMinutes {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "minutes must be between 1 and %d", maxShowMinutes)})
+			return
+		}
+		minutes = parsed
+	}
+
+	exists, err := ws.db.PhotoExists(name, category)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Photo '%s' in category %d not found", name, category)})
+		return
+	}
+
+	duration := time.Duration(minutes) * time.Minute
+	imgPath := ws.paths.Derivative(category, name)
+	if err := ws.controller.Show(imgPath, duration); err != nil {
+		if errors.Is(err, slideshow.ErrHeld) {
+			c.JSON(http.StatusConflict, models.ErrorResponse{Error: tr(c, "Slideshow is held, release it before showing another photo")})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to show photo: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SlideshowShowResponse{
+		PhotoName: name,
+		Category:  category,
+		Until:     time.Now().Add(duration),
+	})
+}
@@ -0,0 +1,258 @@
+package api
+
+import (
+	"cmp"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/i18n"
+	"github.com/aouyang1/digitalphotoframe/slideshow"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	specialDateBirthday    = "birthday"
+	specialDateAnniversary = "anniversary"
+
+	maxSpecialDateNameLength = 64
+)
+
+// specialDateToday reports whether the special date falls on now. Dates on February 29 are
+// celebrated on the 28th outside leap years.
+func specialDateToday(sd store.SpecialDate, now time.Time) bool {
+	today := now.Format("01-02")
+	if sd.Date == "02-29" && today == "02-28" && now.AddDate(0, 0, 1).Month() == time.March {
+		return true
+	}
+	return sd.Date == today
+}
+
+// todaysSpecialDates returns the special dates falling on now
+func todaysSpecialDates(dates []store.SpecialDate, now time.Time) []store.SpecialDate {
+	return slices.DeleteFunc(slices.Clone(dates), func(sd store.SpecialDate) bool {
+		return !specialDateToday(sd, now)
+	})
+}
+
+// greeting is the text of the slide shown on a special date
+func greeting(sd store.SpecialDate, language string) string {
+	if sd.Type == specialDateAnniversary {
+		return i18n.Translate(language, "Happy Anniversary, %s!", sd.Name)
+	}
+	return i18n.Translate(language, "Happy Birthday, %s!", sd.Name)
+}
+
+// greetingSlides renders the greetings for the special dates falling on now and returns their
+// slides. They're rendered on every restart so they follow changes to the name or language.
+func (ws *WebServer) greetingSlides(language string, now time.Time) ([]string, error) {
+	dates, err := ws.db.GetSpecialDates()
+	if err != nil {
+		return nil, err
+	}
+
+	var slides []string
+	for _, sd := range todaysSpecialDates(dates, now) {
+		slide := ws.paths.Greeting(sd.ID)
+		if err := slideshow.RenderSlide(slide, greeting(sd, language)); err != nil {
+			slog.Warn("unable to render greeting, leaving it out", "id", sd.ID, "error", err)
+			continue
+		}
+		slides = append(slides, slide)
+	}
+	return slides, nil
+}
+
+// mentions reports whether the photo is tagged with the person or its caption names them as a
+// whole word, ignoring case
+func mentions(photo store.Photo, name string) bool {
+	if name == "" {
+		return false
+	}
+	if slices.ContainsFunc(photo.Tags, func(tag string) bool { return strings.EqualFold(tag, name) }) {
+		return true
+	}
+	re, err := regexp.Compile(`(?i)\b` + regexp.QuoteMeta(name) + `\b`)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(photo.Caption)
+}
+
+// boostSpecialDates plays the photos of people celebrating a boosted special date today twice,
+// the extra copy half a playlist after the first so the two are spread apart
+func (ws *WebServer) boostSpecialDates(playlist []store.Photo, now time.Time) []store.Photo {
+	dates, err := ws.db.GetSpecialDates()
+	if err != nil {
+		slog.Warn("unable to get special dates, not boosting photos", "error", err)
+		return playlist
+	}
+
+	var names []string
+	for _, sd := range todaysSpecialDates(dates, now) {
+		if sd.Boost {
+			names = append(names, sd.Name)
+		}
+	}
+	if len(names) == 0 || len(playlist) == 0 {
+		return playlist
+	}
+
+	type slot struct {
+		photo store.Photo
+		key   float64
+	}
+	n := len(playlist)
+	slots := make([]slot, 0, n)
+	for i, photo := range playlist {
+		slots = append(slots, slot{photo: photo, key: float64(i)})
+		if slices.ContainsFunc(names, func(name string) bool { return mentions(photo, name) }) {
+			slots = append(slots, slot{photo: photo, key: float64((i+n/2)%n) + 0.5})
+		}
+	}
+	slices.SortStableFunc(slots, func(a, b slot) int {
+		return cmp.Compare(a.key, b.key)
+	})
+
+	boosted := make([]store.Photo, len(slots))
+	for i, s := range slots {
+		boosted[i] = s.photo
+	}
+	return boosted
+}
+
+func (ws *WebServer) handleListSpecialDates(c *gin.Context) {
+	dates, err := ws.db.GetSpecialDates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get special dates: %v", err)})
+		return
+	}
+	if dates == nil {
+		dates = []store.SpecialDate{}
+	}
+	c.JSON(http.StatusOK, dates)
+}
+
+// bindSpecialDate reads and validates a special date from the request body, writing the error
+// response when it isn't valid
+func bindSpecialDate(c *gin.Context) (*store.SpecialDate, bool) {
+	var req store.SpecialDate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
+		return nil, false
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "name is required")})
+		return nil, false
+	}
+	if len([]rune(req.Name)) > maxSpecialDateNameLength {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "name must be at most %d characters", maxSpecialDateNameLength)})
+		return nil, false
+	}
+	if !validSeasonalDate.MatchString(req.Date) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid date format: need 12-31, got %s", req.Date)})
+		return nil, false
+	}
+	if req.Type == "" {
+		req.Type = specialDateBirthday
+	}
+	if req.Type != specialDateBirthday && req.Type != specialDateAnniversary {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "type must be %s or %s", specialDateBirthday, specialDateAnniversary)})
+		return nil, false
+	}
+
+	return &store.SpecialDate{
+		Name:  req.Name,
+		Date:  req.Date,
+		Type:  req.Type,
+		Boost: req.Boost,
+	}, true
+}
+
+func (ws *WebServer) handleCreateSpecialDate(c *gin.Context) {
+	sd, ok := bindSpecialDate(c)
+	if !ok {
+		return
+	}
+
+	if err := ws.db.InsertSpecialDate(sd); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to create special date: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusCreated, sd)
+
+	if specialDateToday(*sd, time.Now()) {
+		ws.requestRestart()
+	}
+}
+
+func (ws *WebServer) handleUpdateSpecialDate(c *gin.Context) {
+	id, ok := parseSpecialDateID(c)
+	if !ok {
+		return
+	}
+	sd, ok := bindSpecialDate(c)
+	if !ok {
+		return
+	}
+	sd.ID = id
+
+	updated, err := ws.db.UpdateSpecialDate(sd)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to update special date: %v", err)})
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Special date %d not found", id)})
+		return
+	}
+
+	c.JSON(http.StatusOK, sd)
+
+	// the date may have moved off of today as well as onto it
+	ws.requestRestart()
+}
+
+func (ws *WebServer) handleDeleteSpecialDate(c *gin.Context) {
+	id, ok := parseSpecialDateID(c)
+	if !ok {
+		return
+	}
+
+	deleted, err := ws.db.DeleteSpecialDate(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to delete special date: %v", err)})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Special date %d not found", id)})
+		return
+	}
+	if err := os.Remove(ws.paths.Greeting(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		requestLogger(c).Warn("unable to remove greeting slide", "id", id, "error", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": tr(c, "Special date %d deleted successfully", id)})
+
+	ws.requestRestart()
+}
+
+func parseSpecialDateID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid special date id")})
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"image/jpeg"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"sync"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/imaging"
+	"github.com/aouyang1/digitalphotoframe/slideshow"
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// how often the stream checks which image is on screen
+	streamInterval = 2 * time.Second
+
+	// longest side of the preview frames
+	streamMaxDim = 800
+)
+
+// streamBroadcaster checks which image is on screen on behalf of every stream client, rendering
+// each image once and sending it to all of them. It only runs while a client is watching.
+type streamBroadcaster struct {
+	controller *slideshow.Controller
+
+	mu      sync.Mutex
+	clients mapset.Set[chan []byte]
+	running bool
+}
+
+func newStreamBroadcaster(controller *slideshow.Controller) *streamBroadcaster {
+	return &streamBroadcaster{
+		controller: controller,
+		clients:    mapset.NewThreadUnsafeSet[chan []byte](),
+	}
+}
+
+// subscribe returns a channel receiving the frame on screen every interval, starting the
+// broadcast for the first client
+func (b *streamBroadcaster) subscribe() chan []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// holds one frame so a slow client skips frames rather than holding up the rest
+	frames := make(chan []byte, 1)
+	b.clients.Add(frames)
+	if !b.running {
+		b.running = true
+		go b.run()
+	}
+	return frames
+}
+
+func (b *streamBroadcaster) unsubscribe(frames chan []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.clients.Remove(frames)
+}
+
+// run sends the frame on screen to every client each interval until the last one leaves
+func (b *streamBroadcaster) run() {
+	ticker := time.NewTicker(streamInterval)
+	defer ticker.Stop()
+
+	var shownPath string
+	var frame []byte
+	for {
+		imgPath, err := b.controller.Current()
+		if err != nil {
+			slog.Debug("unable to get image on screen for stream", "error", err)
+		} else if imgPath != shownPath || frame == nil {
+			if frame, err = previewFrame(imgPath); err != nil {
+				slog.Warn("unable to render stream frame", "path", imgPath, "error", err)
+			}
+			shownPath = imgPath
+		}
+
+		b.mu.Lock()
+		if b.clients.IsEmpty() {
+			b.running = false
+			b.mu.Unlock()
+			return
+		}
+		if frame != nil {
+			for frames := range b.clients.Iter() {
+				select {
+				case frames <- frame:
+				default:
+				}
+			}
+		}
+		b.mu.Unlock()
+
+		<-ticker.C
+	}
+}
+
+// handleSlideshowStream serves an MJPEG stream of the image the slideshow has on screen so the
+// frame can be checked remotely. A frame is sent whenever the image changes and repeated every
+// interval to keep the connection alive.
+func (ws *WebServer) handleSlideshowStream(c *gin.Context) {
+	mw := multipart.NewWriter(c.Writer)
+	c.Header("Content-Type", "multipart/x-mixed-replace; boundary="+mw.Boundary())
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+
+	frames := ws.stream.subscribe()
+	defer ws.stream.unsubscribe(frames)
+
+	for {
+		var frame []byte
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case frame = <-frames:
+		}
+
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":   {"image/jpeg"},
+			"Content-Length": {fmt.Sprint(len(frame))},
+		})
+		if err != nil {
+			return
+		}
+		if _, err := part.Write(frame); err != nil {
+			return
+		}
+		c.Writer.Flush()
+	}
+}
+
+// previewFrame renders a slideshow image as a small upright jpeg, undoing the rotation applied
+// for the mounted frame
+func previewFrame(imgPath string) ([]byte, error) {
+	img, err := imaging.Decode(imgPath)
+	if err != nil {
+		return nil, err
+	}
+	img = imaging.Rotate(img, -slideshow.RotateDegrees)
+	img = imaging.Resize(img, streamMaxDim, streamMaxDim, imaging.FitContain)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
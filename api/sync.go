@@ -0,0 +1,22 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// handleTriggerSync starts syncing with s3 and the rclone remote without waiting for their next
+// daily check. The syncs run in the background, and photos they add show up once they finish.
+func (ws *WebServer) handleTriggerSync(c *gin.Context) {
+	resp := models.SyncResponse{
+		S3:     ws.remoteManager.TriggerSync(),
+		Rclone: ws.rcloneManager.TriggerSync(),
+	}
+	if !resp.S3 && !resp.Rclone {
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: tr(c, "No remote is configured to sync with")})
+		return
+	}
+	c.JSON(http.StatusAccepted, resp)
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/runner"
+	"github.com/gin-gonic/gin"
+)
+
+// powerActionDelay gives the response time to reach the client before the frame goes down
+const powerActionDelay = 2 * time.Second
+
+func (ws *WebServer) handleReboot(c *gin.Context) {
+	go ws.powerAction("reboot")
+	c.JSON(http.StatusAccepted, gin.H{"message": tr(c, "Rebooting")})
+}
+
+func (ws *WebServer) handleShutdown(c *gin.Context) {
+	go ws.powerAction("poweroff")
+	c.JSON(http.StatusAccepted, gin.H{"message": tr(c, "Shutting down")})
+}
+
+// powerAction flushes the database and filesystem, stops imv, and asks systemd to reboot or
+// power off. The slideshow is restarted if systemd refuses.
+func (ws *WebServer) powerAction(action string) {
+	time.Sleep(powerActionDelay)
+	slog.Info("preparing for system power action", "action", action)
+
+	if err := ws.db.Checkpoint(); err != nil {
+		slog.Warn("failed to flush database before power action", "error", err)
+	}
+	if err := ws.controller.Stop(); err != nil {
+		slog.Warn("failed to stop slideshow before power action", "error", err)
+	}
+	syscall.Sync()
+
+	if out, err := runner.Default().Run("systemctl", action); err != nil {
+		slog.Error("failed to perform system power action", "action", action, "error", fmt.Errorf("systemctl %s, %s, %w", action, out, err))
+		if err := ws.RestartSlideshow(); err != nil {
+			slog.Error("failed to restart slideshow after power action failure", "error", err)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"regexp"
+	"slices"
+
+	"github.com/aouyang1/digitalphotoframe/i18n"
+	"github.com/aouyang1/digitalphotoframe/store"
+)
+
+const (
+	defaultTheme       = "light"
+	defaultAccentColor = "#007AFF"
+)
+
+// validThemes are the ui themes, where auto follows the light or dark preference of the browser
+var validThemes = []string{"light", "dark", "auto"}
+
+var validAccentColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// applyThemeDefaults fills in the theme for settings saved before theming existed
+func applyThemeDefaults(s *store.AppSettings) {
+	if s.Theme == "" {
+		s.Theme = defaultTheme
+	}
+	if s.AccentColor == "" {
+		s.AccentColor = defaultAccentColor
+	}
+}
+
+func validTheme(theme string) bool {
+	return slices.Contains(validThemes, theme)
+}
+
+// indexPage is the data the control ui is rendered with
+type indexPage struct {
+	Language    string
+	Theme       string
+	DarkTheme   bool
+	AccentColor string
+
+	// BasePath prefixes the page's urls when served behind a reverse proxy
+	BasePath string
+}
+
+func newIndexPage(s *store.AppSettings, basePath string) indexPage {
+	applyThemeDefaults(s)
+	language := s.Language
+	if language == "" {
+		language = i18n.DefaultLanguage
+	}
+	return indexPage{
+		Language:    language,
+		Theme:       s.Theme,
+		DarkTheme:   s.Theme == "dark",
+		AccentColor: s.AccentColor,
+		BasePath:    basePath,
+	}
+}
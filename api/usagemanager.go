@@ -0,0 +1,115 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/display"
+	"github.com/aouyang1/digitalphotoframe/store"
+)
+
+const (
+	usageInterval = time.Minute
+
+	// defaultDisplayWatts is roughly what a 15 inch panel draws with the backlight on
+	defaultDisplayWatts = 10.0
+)
+
+// UsageManager samples whether the display is on to track how long it is on each day, whether
+// turned on by the schedule, a remote, or by hand
+type UsageManager struct {
+	db *store.Database
+
+	// watts the display draws while on, used to estimate energy use
+	watts float64
+
+	mu         sync.Mutex
+	on         bool
+	lastSample time.Time
+}
+
+func NewUsageManager(db *store.Database) (*UsageManager, error) {
+	if db == nil {
+		return nil, errors.New("no database provided for usage manager")
+	}
+
+	watts := defaultDisplayWatts
+	if wattsStr := os.Getenv("DPF_DISPLAY_WATTS"); wattsStr != "" {
+		parsed, err := strconv.ParseFloat(wattsStr, 64)
+		if err != nil || parsed < 0 {
+			slog.Warn("unable to parse DPF_DISPLAY_WATTS, using default", "DPF_DISPLAY_WATTS", wattsStr, "default", defaultDisplayWatts)
+		} else {
+			watts = parsed
+		}
+	}
+
+	return &UsageManager{
+		db:    db,
+		watts: watts,
+	}, nil
+}
+
+// Watts is how much power the display is assumed to draw while on
+func (u *UsageManager) Watts() float64 {
+	return u.watts
+}
+
+// On reports whether the display was on as of the last sample
+func (u *UsageManager) On() bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.on
+}
+
+func (u *UsageManager) sample() {
+	enabled, err := display.GetEnabled()
+	if err != nil {
+		slog.Warn("unable to get display state for usage", "error", err)
+		return
+	}
+	now := time.Now()
+
+	u.mu.Lock()
+	wasOn, last := u.on, u.lastSample
+	u.on, u.lastSample = enabled, now
+	u.mu.Unlock()
+
+	// only count time between samples that saw the display on, leaving out gaps such as the
+	// server being stopped
+	if !wasOn || last.IsZero() || now.Sub(last) > 2*usageInterval {
+		return
+	}
+	for day, seconds := range splitByDay(last, now) {
+		if err := u.db.AddDisplayOnTime(day, seconds); err != nil {
+			slog.Warn("unable to record display usage", "day", day, "error", err)
+		}
+	}
+}
+
+// splitByDay divides the time from start to end among the days it falls on
+func splitByDay(start, end time.Time) map[string]int64 {
+	seconds := make(map[string]int64)
+	for start.Before(end) {
+		midnight := time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, start.Location())
+		until := end
+		if midnight.Before(end) {
+			until = midnight
+		}
+		seconds[start.Format(dateLayout)] += int64(until.Sub(start).Round(time.Second) / time.Second)
+		start = until
+	}
+	return seconds
+}
+
+func (u *UsageManager) Run() {
+	ticker := time.NewTicker(usageInterval)
+
+	u.sample()
+	for range ticker.C {
+		u.sample()
+	}
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/gin-gonic/gin"
+)
+
+// shownOn reports whether now falls within the days the photo is shown, which is every day for a
+// photo without a show_from or show_until date
+func shownOn(photo store.Photo, now time.Time) bool {
+	today := now.Format(time.DateOnly)
+	return (photo.ShowFrom == "" || photo.ShowFrom <= today) && (photo.ShowUntil == "" || today <= photo.ShowUntil)
+}
+
+// unshownPhotoIDs lists the photos outside the days they're shown as of now, to tell when photos
+// enter or leave rotation
+func unshownPhotoIDs(photos []store.Photo, now time.Time) []string {
+	var ids []string
+	for _, photo := range photos {
+		if !shownOn(photo, now) {
+			ids = append(ids, photo.ID)
+		}
+	}
+	return ids
+}
+
+// handleUpdatePhotoVisibility limits the days a photo is played to those from show_from until
+// show_until, either of which may be left empty for no limit
+func (ws *WebServer) handleUpdatePhotoVisibility(c *gin.Context) {
+	category, name, ok := parsePhotoFileParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.PhotoVisibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
+		return
+	}
+	req.ShowFrom = strings.TrimSpace(req.ShowFrom)
+	req.ShowUntil = strings.TrimSpace(req.ShowUntil)
+	if _, err := time.Parse(time.DateOnly, req.ShowFrom); req.ShowFrom != "" && err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid show_from date format: need 2006-01-02, got %s", req.ShowFrom)})
+		return
+	}
+	if _, err := time.Parse(time.DateOnly, req.ShowUntil); req.ShowUntil != "" && err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid show_until date format: need 2006-01-02, got %s", req.ShowUntil)})
+		return
+	}
+	if req.ShowFrom != "" && req.ShowUntil != "" && req.ShowUntil < req.ShowFrom {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "show_until must not be before show_from")})
+		return
+	}
+
+	exists, err := ws.db.PhotoExists(name, category)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Database error: %v", err)})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: tr(c, "Photo '%s' in category %d not found", name, category)})
+		return
+	}
+
+	if err := ws.db.UpdatePhotoVisibility(name, category, req.ShowFrom, req.ShowUntil); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to update photo: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, req)
+
+	// trigger slideshow restart
+	ws.requestRestart()
+}
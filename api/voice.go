@@ -0,0 +1,114 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	intentNextPhoto      = "next_photo"
+	intentPreviousPhoto  = "previous_photo"
+	intentPause          = "pause_slideshow"
+	intentResume         = "resume_slideshow"
+	intentTurnOnDisplay  = "turn_on"
+	intentTurnOffDisplay = "turn_off"
+)
+
+// voicePhrases matches spoken commands to intents, checked in order so commands for the display
+// win over those for the slideshow
+var voicePhrases = []struct {
+	phrase string
+	intent string
+}{
+	{"turn off", intentTurnOffDisplay},
+	{"switch off", intentTurnOffDisplay},
+	{"turn on", intentTurnOnDisplay},
+	{"switch on", intentTurnOnDisplay},
+	{"wake", intentTurnOnDisplay},
+	{"previous", intentPreviousPhoto},
+	{"go back", intentPreviousPhoto},
+	{"last photo", intentPreviousPhoto},
+	{"next", intentNextPhoto},
+	{"skip", intentNextPhoto},
+	{"unpause", intentResume},
+	{"pause", intentPause},
+	{"stop", intentPause},
+	{"resume", intentResume},
+	{"play", intentResume},
+	{"continue", intentResume},
+}
+
+// matchVoiceIntent finds the intent for a transcribed command such as "turn off the photo frame".
+// Phrases match whole words, so "unpause" isn't taken for "pause" nor "display" for "play".
+func matchVoiceIntent(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+	for _, p := range voicePhrases {
+		phrase := strings.Fields(p.phrase)
+		for i := 0; i+len(phrase) <= len(words); i++ {
+			if slices.Equal(words[i:i+len(phrase)], phrase) {
+				return p.intent
+			}
+		}
+	}
+	return ""
+}
+
+// handleVoiceIntent performs a voice assistant command. Assistants that resolve intents send the
+// intent name directly, while simpler integrations can forward the transcribed text. The
+// response includes a sentence for the assistant to speak back.
+func (ws *WebServer) handleVoiceIntent(c *gin.Context) {
+	var req models.VoiceIntentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Invalid request body: %v", err)})
+		return
+	}
+
+	intent := strings.ToLower(strings.TrimSpace(req.Intent))
+	if intent == "" {
+		intent = matchVoiceIntent(req.Text)
+	}
+
+	var (
+		speech string
+		err    error
+	)
+	switch intent {
+	case intentNextPhoto:
+		speech = tr(c, "Showing the next photo")
+		err = ws.controller.Next()
+	case intentPreviousPhoto:
+		speech = tr(c, "Showing the previous photo")
+		err = ws.controller.Prev()
+	case intentPause:
+		speech = tr(c, "Pausing the slideshow")
+		err = ws.controller.SetPaused(true)
+	case intentResume:
+		speech = tr(c, "Resuming the slideshow")
+		err = ws.controller.SetPaused(false)
+	case intentTurnOnDisplay:
+		speech = tr(c, "Turning on the photo frame")
+		err = setDisplayEnabled(ws.controller, true)
+		ws.audioManager.Update()
+	case intentTurnOffDisplay:
+		speech = tr(c, "Turning off the photo frame")
+		err = setDisplayEnabled(ws.controller, false)
+		ws.audioManager.Update()
+	default:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: tr(c, "Unrecognized voice command, intent %q text %q", req.Intent, req.Text)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to perform %s: %v", intent, err)})
+		return
+	}
+
+	requestLogger(c).Info("performed voice intent", "intent", intent, "text", req.Text)
+	c.JSON(http.StatusOK, models.VoiceIntentResponse{Intent: intent, Speech: speech})
+}
@@ -1,3 +1,6 @@
+// path the frame is served under behind a reverse proxy, prefixed to every request
+const basePath = document.querySelector('meta[name="base-path"]').content;
+
 function openPhotoModal(imageUrl) {
     const modal = document.getElementById('photo-modal');
     const modalImg = document.getElementById('photo-modal-img');
@@ -72,6 +75,11 @@ function handleUploadResponse(event) {
         if (fileName) {
             fileName.textContent = '';
         }
+
+        loadCategories();
+
+        // Show the upload in the new this week strip
+        htmx.ajax('GET', basePath + '/ui/photos/recent', { target: '#recent-photos', swap: 'innerHTML' });
     }
 }
 
@@ -103,11 +111,55 @@ let currentSettings = null;
 // Display state for slideshow view
 let currentDisplayEnabled = null;
 
-// Dark mode state
-let darkModeEnabled = true;
+// albumWeight returns the shuffle weight for album, defaulting to 1 when unset
+function albumWeight(weights, album) {
+    if (!weights || weights[album] === undefined) {
+        return 1;
+    }
+    return weights[album];
+}
+
+// settingsFromResponse normalizes settings returned by the api so unchanged settings compare equal
+function settingsFromResponse(data) {
+    return {
+        slideshow_interval_seconds: data.slideshow_interval_seconds,
+        interval_jitter_seconds: data.interval_jitter_seconds || 0,
+        guestbook_days: data.guestbook_days || 7,
+        crossfade: data.crossfade,
+        collages: data.collages,
+        play_motion: data.play_motion,
+        pan_panoramas: data.pan_panoramas,
+        include_surprise: data.include_surprise,
+        shuffle_enabled: data.shuffle_enabled,
+        playlist_order: data.playlist_order || 'sequential',
+        auto_organize: data.auto_organize || 'off',
+        display_transform: data.display_transform || 'normal',
+        album_weights: { ...data.album_weights },
+        show_uploader: data.show_uploader,
+        strip_exif: data.strip_exif,
+        approve_surprise: data.approve_surprise,
+        language: data.language || 'en',
+        theme: data.theme || 'light',
+        accent_color: (data.accent_color || '#007AFF').toUpperCase(),
+        show_filename: data.show_filename,
+        show_caption: data.show_caption,
+        show_date_taken: data.show_date_taken,
+        show_reactions: data.show_reactions,
+        overlay_position: data.overlay_position || 'bottom-right',
+        overlay_size: data.overlay_size || 'medium',
+        watermark_text: data.watermark_text || '',
+        watermark_uploader: data.watermark_uploader,
+        audio_enabled: data.audio_enabled,
+        audio_source: data.audio_source || '',
+        audio_volume: data.audio_volume === undefined ? 50 : data.audio_volume,
+        photo_of_day_enabled: data.photo_of_day_enabled,
+        photo_of_day_time: data.photo_of_day_time || '06:00',
+        photo_of_day_id: data.photo_of_day_id || ''
+    };
+}
 
 function loadSettings() {
-    fetch('/settings')
+    fetch(basePath + '/settings')
         .then(response => {
             if (!response.ok) {
                 throw new Error('Failed to load settings');
@@ -115,11 +167,7 @@ function loadSettings() {
             return response.json();
         })
         .then(data => {
-            originalSettings = {
-                slideshow_interval_seconds: data.slideshow_interval_seconds,
-                include_surprise: data.include_surprise,
-                shuffle_enabled: data.shuffle_enabled
-            };
+            originalSettings = settingsFromResponse(data);
             currentSettings = { ...originalSettings };
             applySettingsToUI(currentSettings);
             updateSettingsSaveButton();
@@ -141,8 +189,9 @@ function applySettingsToUI(settings) {
     const intervalUnit = document.getElementById('interval-unit');
     const includeBtn = document.getElementById('toggle-include-surprise');
     const shuffleBtn = document.getElementById('toggle-shuffle');
+    const showUploaderBtn = document.getElementById('toggle-show-uploader');
 
-    if (!intervalInput || !intervalUnit || !includeBtn || !shuffleBtn) {
+    if (!intervalInput || !intervalUnit || !includeBtn || !shuffleBtn || !showUploaderBtn) {
         return;
     }
 
@@ -161,8 +210,87 @@ function applySettingsToUI(settings) {
     intervalInput.value = value;
     intervalUnit.value = unit;
 
+    const intervalJitter = document.getElementById('interval-jitter');
+    if (intervalJitter) {
+        intervalJitter.value = settings.interval_jitter_seconds || 0;
+    }
+    const guestbookDays = document.getElementById('guestbook-days');
+    if (guestbookDays) {
+        guestbookDays.value = settings.guestbook_days || 7;
+    }
+
     setToggleButton(includeBtn, settings.include_surprise);
     setToggleButton(shuffleBtn, settings.shuffle_enabled);
+    setToggleButton(showUploaderBtn, settings.show_uploader);
+    setToggleButton(document.getElementById('toggle-strip-exif'), settings.strip_exif);
+    setToggleButton(document.getElementById('toggle-crossfade'), settings.crossfade);
+    setToggleButton(document.getElementById('toggle-collages'), settings.collages);
+    setToggleButton(document.getElementById('toggle-play-motion'), settings.play_motion);
+    setToggleButton(document.getElementById('toggle-pan-panoramas'), settings.pan_panoramas);
+    setToggleButton(document.getElementById('toggle-approve-surprise'), settings.approve_surprise);
+    setToggleButton(document.getElementById('toggle-show-caption'), settings.show_caption);
+    setToggleButton(document.getElementById('toggle-show-date-taken'), settings.show_date_taken);
+    setToggleButton(document.getElementById('toggle-show-filename'), settings.show_filename);
+    setToggleButton(document.getElementById('toggle-show-reactions'), settings.show_reactions);
+
+    setToggleButton(document.getElementById('toggle-photo-of-day'), settings.photo_of_day_enabled);
+    const photoOfDayTime = document.getElementById('photo-of-day-time');
+    if (photoOfDayTime) {
+        photoOfDayTime.value = settings.photo_of_day_time || '06:00';
+    }
+
+    const overlayPosition = document.getElementById('overlay-position');
+    const overlaySize = document.getElementById('overlay-size');
+    if (overlayPosition && overlaySize) {
+        overlayPosition.value = settings.overlay_position || 'bottom-right';
+        overlaySize.value = settings.overlay_size || 'medium';
+    }
+
+    const watermarkText = document.getElementById('watermark-text');
+    if (watermarkText) {
+        watermarkText.value = settings.watermark_text || '';
+    }
+    setToggleButton(document.getElementById('toggle-watermark-uploader'), settings.watermark_uploader);
+
+    setToggleButton(document.getElementById('toggle-audio-enabled'), settings.audio_enabled);
+    const audioSource = document.getElementById('audio-source');
+    if (audioSource) {
+        audioSource.value = settings.audio_source || '';
+    }
+    const audioVolume = document.getElementById('audio-volume');
+    if (audioVolume) {
+        audioVolume.value = settings.audio_volume;
+    }
+
+    loadAlbumWeights(settings.album_weights);
+
+    const playlistOrder = document.getElementById('playlist-order');
+    if (playlistOrder) {
+        playlistOrder.value = settings.playlist_order || 'sequential';
+    }
+
+    const autoOrganize = document.getElementById('auto-organize');
+    if (autoOrganize) {
+        autoOrganize.value = settings.auto_organize || 'off';
+    }
+
+    const displayTransform = document.getElementById('display-transform');
+    if (displayTransform) {
+        displayTransform.value = settings.display_transform || 'normal';
+    }
+
+    const languageSelect = document.getElementById('language-select');
+    if (languageSelect) {
+        languageSelect.value = settings.language || 'en';
+    }
+
+    const themeSelect = document.getElementById('theme-select');
+    const accentInput = document.getElementById('accent-color');
+    if (themeSelect && accentInput) {
+        themeSelect.value = settings.theme || 'light';
+        accentInput.value = settings.accent_color || '#007AFF';
+    }
+    applyTheme(settings.theme || 'light', settings.accent_color);
 }
 
 function setToggleButton(btn, isOn) {
@@ -190,6 +318,34 @@ function toggleSettingButton(btn) {
         currentSettings.include_surprise = next;
     } else if (btn.id === 'toggle-shuffle') {
         currentSettings.shuffle_enabled = next;
+    } else if (btn.id === 'toggle-show-uploader') {
+        currentSettings.show_uploader = next;
+    } else if (btn.id === 'toggle-watermark-uploader') {
+        currentSettings.watermark_uploader = next;
+    } else if (btn.id === 'toggle-approve-surprise') {
+        currentSettings.approve_surprise = next;
+    } else if (btn.id === 'toggle-strip-exif') {
+        currentSettings.strip_exif = next;
+    } else if (btn.id === 'toggle-crossfade') {
+        currentSettings.crossfade = next;
+    } else if (btn.id === 'toggle-collages') {
+        currentSettings.collages = next;
+    } else if (btn.id === 'toggle-play-motion') {
+        currentSettings.play_motion = next;
+    } else if (btn.id === 'toggle-pan-panoramas') {
+        currentSettings.pan_panoramas = next;
+    } else if (btn.id === 'toggle-show-caption') {
+        currentSettings.show_caption = next;
+    } else if (btn.id === 'toggle-show-date-taken') {
+        currentSettings.show_date_taken = next;
+    } else if (btn.id === 'toggle-show-filename') {
+        currentSettings.show_filename = next;
+    } else if (btn.id === 'toggle-show-reactions') {
+        currentSettings.show_reactions = next;
+    } else if (btn.id === 'toggle-photo-of-day') {
+        currentSettings.photo_of_day_enabled = next;
+    } else if (btn.id === 'toggle-audio-enabled') {
+        currentSettings.audio_enabled = next;
     }
 
     updateSettingsSaveButton();
@@ -221,6 +377,212 @@ function onIntervalChanged() {
     updateSettingsSaveButton();
 }
 
+function onIntervalJitterChanged() {
+    const intervalJitter = document.getElementById('interval-jitter');
+    if (!intervalJitter) return;
+
+    let jitter = parseInt(intervalJitter.value, 10);
+    if (Number.isNaN(jitter) || jitter < 0) {
+        jitter = 0;
+        intervalJitter.value = jitter;
+    }
+
+    if (!currentSettings) {
+        currentSettings = { ...originalSettings };
+    }
+    currentSettings.interval_jitter_seconds = jitter;
+    updateSettingsSaveButton();
+}
+
+function onGuestbookDaysChanged() {
+    const guestbookDays = document.getElementById('guestbook-days');
+    if (!guestbookDays) return;
+
+    let days = parseInt(guestbookDays.value, 10);
+    if (Number.isNaN(days) || days < 1) {
+        days = 1;
+        guestbookDays.value = days;
+    } else if (days > 365) {
+        days = 365;
+        guestbookDays.value = days;
+    }
+
+    if (!currentSettings) {
+        currentSettings = { ...originalSettings };
+    }
+    currentSettings.guestbook_days = days;
+    updateSettingsSaveButton();
+}
+
+function onOverlayStyleChanged() {
+    const overlayPosition = document.getElementById('overlay-position');
+    const overlaySize = document.getElementById('overlay-size');
+    if (!overlayPosition || !overlaySize) return;
+
+    if (!currentSettings) {
+        currentSettings = { ...originalSettings };
+    }
+    currentSettings.overlay_position = overlayPosition.value;
+    currentSettings.overlay_size = overlaySize.value;
+    updateSettingsSaveButton();
+}
+
+function onWatermarkChanged() {
+    const watermarkText = document.getElementById('watermark-text');
+    if (!watermarkText) return;
+
+    if (!currentSettings) {
+        currentSettings = { ...originalSettings };
+    }
+    currentSettings.watermark_text = watermarkText.value;
+    updateSettingsSaveButton();
+}
+
+function onAudioSourceChanged() {
+    const audioSource = document.getElementById('audio-source');
+    if (!audioSource) return;
+
+    if (!currentSettings) {
+        currentSettings = { ...originalSettings };
+    }
+    currentSettings.audio_source = audioSource.value;
+    updateSettingsSaveButton();
+}
+
+function onAudioVolumeChanged() {
+    const audioVolume = document.getElementById('audio-volume');
+    if (!audioVolume) return;
+
+    let volume = parseInt(audioVolume.value, 10);
+    if (Number.isNaN(volume) || volume < 0) {
+        volume = 0;
+        audioVolume.value = volume;
+    } else if (volume > 100) {
+        volume = 100;
+        audioVolume.value = volume;
+    }
+
+    if (!currentSettings) {
+        currentSettings = { ...originalSettings };
+    }
+    currentSettings.audio_volume = volume;
+    updateSettingsSaveButton();
+}
+
+function onPhotoOfDayTimeChanged() {
+    const photoOfDayTime = document.getElementById('photo-of-day-time');
+    if (!photoOfDayTime || !photoOfDayTime.value) return;
+
+    if (!currentSettings) {
+        currentSettings = { ...originalSettings };
+    }
+    currentSettings.photo_of_day_time = photoOfDayTime.value;
+    updateSettingsSaveButton();
+}
+
+// loadAlbumWeights lists a shuffle weight input for each album
+function loadAlbumWeights(weights) {
+    fetch(basePath + '/albums')
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to load albums');
+            }
+            return response.json();
+        })
+        .then(albums => {
+            const list = document.getElementById('album-weights');
+            if (!list) return;
+
+            list.replaceChildren();
+            albums.forEach(album => {
+                const name = document.createElement('span');
+                name.textContent = album;
+
+                const weightInput = document.createElement('input');
+                weightInput.type = 'number';
+                weightInput.min = '0';
+                weightInput.max = '10';
+                weightInput.value = albumWeight(weights, album);
+                weightInput.addEventListener('change', function() {
+                    onAlbumWeightChanged(album, weightInput);
+                });
+
+                list.append(name, weightInput);
+            });
+        })
+        .catch(err => {
+            console.error(err);
+        });
+}
+
+function onAlbumWeightChanged(album, weightInput) {
+    let weight = parseInt(weightInput.value, 10);
+    if (Number.isNaN(weight) || weight < 0) {
+        weight = 0;
+    } else if (weight > 10) {
+        weight = 10;
+    }
+    weightInput.value = weight;
+
+    if (!currentSettings) {
+        currentSettings = { ...originalSettings };
+    }
+    const weights = { ...currentSettings.album_weights, [album]: weight };
+    // albums default to 1, so leave it unset to compare equal to an album never weighted
+    if (weight === 1) {
+        delete weights[album];
+    }
+    // keep the albums sorted as the api returns them so unchanged weights compare equal
+    currentSettings.album_weights = Object.fromEntries(
+        Object.entries(weights).sort(([a], [b]) => (a < b ? -1 : 1))
+    );
+    updateSettingsSaveButton();
+}
+
+function onAutoOrganizeChanged() {
+    const autoOrganize = document.getElementById('auto-organize');
+    if (!autoOrganize) return;
+
+    if (!currentSettings) {
+        currentSettings = { ...originalSettings };
+    }
+    currentSettings.auto_organize = autoOrganize.value;
+    updateSettingsSaveButton();
+}
+
+function onPlaylistOrderChanged() {
+    const playlistOrder = document.getElementById('playlist-order');
+    if (!playlistOrder) return;
+
+    if (!currentSettings) {
+        currentSettings = { ...originalSettings };
+    }
+    currentSettings.playlist_order = playlistOrder.value;
+    updateSettingsSaveButton();
+}
+
+function onDisplayTransformChanged() {
+    const displayTransform = document.getElementById('display-transform');
+    if (!displayTransform) return;
+
+    if (!currentSettings) {
+        currentSettings = { ...originalSettings };
+    }
+    currentSettings.display_transform = displayTransform.value;
+    updateSettingsSaveButton();
+}
+
+function onLanguageChanged() {
+    const languageSelect = document.getElementById('language-select');
+    if (!languageSelect) return;
+
+    if (!currentSettings) {
+        currentSettings = { ...originalSettings };
+    }
+    currentSettings.language = languageSelect.value;
+    updateSettingsSaveButton();
+}
+
 function updateSettingsSaveButton() {
     const saveBtn = document.getElementById('settings-save-btn');
     if (!saveBtn) return;
@@ -246,15 +608,48 @@ function saveSettings() {
 
     const payload = {
         slideshow_interval_seconds: currentSettings.slideshow_interval_seconds,
+        interval_jitter_seconds: currentSettings.interval_jitter_seconds || 0,
+        guestbook_days: currentSettings.guestbook_days || 7,
+        crossfade: !!currentSettings.crossfade,
+        collages: !!currentSettings.collages,
+        play_motion: !!currentSettings.play_motion,
+        pan_panoramas: !!currentSettings.pan_panoramas,
         include_surprise: !!currentSettings.include_surprise,
-        shuffle_enabled: !!currentSettings.shuffle_enabled
+        shuffle_enabled: !!currentSettings.shuffle_enabled,
+        playlist_order: currentSettings.playlist_order || 'sequential',
+        auto_organize: currentSettings.auto_organize || 'off',
+        display_transform: currentSettings.display_transform || 'normal',
+        album_weights: currentSettings.album_weights,
+        show_uploader: !!currentSettings.show_uploader,
+        strip_exif: !!currentSettings.strip_exif,
+        approve_surprise: !!currentSettings.approve_surprise,
+        language: currentSettings.language || 'en',
+        theme: currentSettings.theme || 'light',
+        accent_color: currentSettings.accent_color || '#007AFF',
+        show_filename: !!currentSettings.show_filename,
+        show_caption: !!currentSettings.show_caption,
+        show_date_taken: !!currentSettings.show_date_taken,
+        show_reactions: !!currentSettings.show_reactions,
+        overlay_position: currentSettings.overlay_position || 'bottom-right',
+        overlay_size: currentSettings.overlay_size || 'medium',
+        watermark_text: (currentSettings.watermark_text || '').trim(),
+        watermark_uploader: !!currentSettings.watermark_uploader,
+        audio_enabled: !!currentSettings.audio_enabled,
+        audio_source: (currentSettings.audio_source || '').trim(),
+        audio_volume: currentSettings.audio_volume === undefined ? 50 : currentSettings.audio_volume,
+        photo_of_day_enabled: !!currentSettings.photo_of_day_enabled,
+        photo_of_day_time: currentSettings.photo_of_day_time || '06:00',
+        photo_of_day_id: currentSettings.photo_of_day_id || ''
     };
 
     if (payload.slideshow_interval_seconds < 1) {
         payload.slideshow_interval_seconds = 1;
     }
+    if (payload.interval_jitter_seconds >= payload.slideshow_interval_seconds) {
+        payload.interval_jitter_seconds = payload.slideshow_interval_seconds - 1;
+    }
 
-    fetch('/settings', {
+    fetch(basePath + '/settings', {
         method: 'PUT',
         headers: {
             'Content-Type': 'application/json'
@@ -273,11 +668,7 @@ function saveSettings() {
             return response.json();
         })
         .then(data => {
-            originalSettings = {
-                slideshow_interval_seconds: data.slideshow_interval_seconds,
-                include_surprise: data.include_surprise,
-                shuffle_enabled: data.shuffle_enabled
-            };
+            originalSettings = settingsFromResponse(data);
             currentSettings = { ...originalSettings };
             applySettingsToUI(currentSettings);
             updateSettingsSaveButton();
@@ -308,7 +699,7 @@ function applyDisplayToUI(enabled) {
 }
 
 function loadDisplayState() {
-    fetch('/display')
+    fetch(basePath + '/display')
         .then(response => {
             if (!response.ok) {
                 throw new Error('Failed to load display state');
@@ -338,7 +729,7 @@ function toggleDisplay(btn) {
 
     const desired = next ? 1 : 0;
 
-    fetch('/display/' + desired, {
+    fetch(basePath + '/display/' + desired, {
         method: 'PUT'
     })
         .then(response => {
@@ -370,8 +761,27 @@ function toggleDisplay(btn) {
 let originalSchedule = null;
 let currentSchedule = null;
 
+// scheduleFromResponse normalizes the schedule returned by the api so unchanged schedules compare equal
+function scheduleFromResponse(data) {
+    return {
+        enabled: data.enabled,
+        start: data.start || '00:00',
+        end: data.end || '23:59',
+        action: data.action || 'off',
+        dim_percent: data.dim_percent || 20,
+        slow_interval_seconds: data.slow_interval_seconds || 300
+    };
+}
+
+// quietHoursDescription summarizes what a schedule does outside of its on times
+function quietHoursDescription(q) {
+    if (q.action === 'dim') return 'dim to ' + q.dim_percent + '%';
+    if (q.action === 'slow') return 'every ' + q.slow_interval_seconds + 's';
+    return 'off';
+}
+
 function loadSchedule() {
-    fetch('/schedule')
+    fetch(basePath + '/schedule')
         .then(response => {
             if (!response.ok) {
                 throw new Error('Failed to load schedule');
@@ -379,14 +789,11 @@ function loadSchedule() {
             return response.json();
         })
         .then(data => {
-            originalSchedule = {
-                enabled: data.enabled,
-                start: data.start || '00:00',
-                end: data.end || '23:59'
-            };
+            originalSchedule = scheduleFromResponse(data);
             currentSchedule = { ...originalSchedule };
             applyScheduleToUI(currentSchedule);
             updateScheduleSaveButton();
+            loadScheduleProfiles(data);
         })
         .catch(err => {
             console.error(err);
@@ -400,6 +807,158 @@ function loadSchedule() {
         });
 }
 
+const scheduleDayNames = { mon: 'Mon', tue: 'Tue', wed: 'Wed', thu: 'Thu', fri: 'Fri', sat: 'Sat', sun: 'Sun' };
+
+// loadScheduleProfiles lists the schedule profiles and shows which are picked and in effect
+function loadScheduleProfiles(schedule) {
+    const current = document.getElementById('schedule-current');
+    if (current) {
+        current.textContent = schedule.current_start + ' - ' + schedule.current_end +
+            (schedule.current_profile ? ' (' + schedule.current_profile + ')' : '') +
+            ', otherwise ' + quietHoursDescription(scheduleFromResponse(schedule));
+    }
+
+    fetch(basePath + '/schedule/profiles')
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to load schedule profiles');
+            }
+            return response.json();
+        })
+        .then(profiles => {
+            [['schedule-active-profile', schedule.active_profile], ['schedule-today-profile', schedule.today_profile]].forEach(([id, selected]) => {
+                const select = document.getElementById(id);
+                if (!select) return;
+                // keep the first option which picks no profile
+                select.replaceChildren(select.options[0]);
+                profiles.forEach(profile => {
+                    const option = document.createElement('option');
+                    option.value = profile.name;
+                    option.textContent = profile.name;
+                    select.append(option);
+                });
+                select.value = selected || '';
+            });
+
+            const list = document.getElementById('schedule-profile-list');
+            if (!list) return;
+
+            list.replaceChildren();
+            profiles.forEach(profile => {
+                const row = document.createElement('div');
+                row.className = 'settings-row';
+
+                const days = profile.days.map(day => scheduleDayNames[day] || day).join(', ');
+                const text = document.createElement('span');
+                text.textContent = profile.name + ': ' + profile.start + ' - ' + profile.end + (days ? ' on ' + days : '') +
+                    ', otherwise ' + quietHoursDescription(profile);
+
+                const remove = document.createElement('button');
+                remove.type = 'button';
+                remove.className = 'settings-save-btn';
+                remove.textContent = 'Remove';
+                remove.onclick = function() {
+                    deleteScheduleProfile(profile.name);
+                };
+
+                row.append(text, remove);
+                list.append(row);
+            });
+        })
+        .catch(err => {
+            console.error(err);
+        });
+}
+
+function saveScheduleProfile() {
+    const btn = document.getElementById('schedule-profile-save-btn');
+    const statusEl = document.getElementById('schedule-profile-status');
+    const name = document.getElementById('schedule-profile-name').value.trim();
+
+    const payload = {
+        start: document.getElementById('schedule-profile-start').value,
+        end: document.getElementById('schedule-profile-end').value,
+        days: Array.from(document.querySelectorAll('input[name="schedule-profile-day"]:checked')).map(el => el.value),
+        action: document.getElementById('schedule-profile-action').value,
+        dim_percent: parseInt(document.getElementById('schedule-profile-dim-percent').value, 10) || 0,
+        slow_interval_seconds: parseInt(document.getElementById('schedule-profile-slow-interval').value, 10) || 0
+    };
+
+    btn.disabled = true;
+    fetch(basePath + '/schedule/profiles/' + encodeURIComponent(name), {
+        method: 'PUT',
+        headers: {
+            'Content-Type': 'application/json'
+        },
+        body: JSON.stringify(payload)
+    })
+        .then(response => {
+            if (!response.ok) {
+                return response.json().then(data => {
+                    throw new Error(data && data.error ? data.error : 'Failed to save schedule profile');
+                });
+            }
+            return response.json();
+        })
+        .then(() => {
+            document.getElementById('schedule-profile-name').value = '';
+            document.getElementById('schedule-profile-start').value = '';
+            document.getElementById('schedule-profile-end').value = '';
+            document.querySelectorAll('input[name="schedule-profile-day"]').forEach(el => {
+                el.checked = false;
+            });
+            statusEl.style.display = 'none';
+            loadSchedule();
+        })
+        .catch(err => {
+            console.error(err);
+            statusEl.textContent = err.message || 'Failed to save schedule profile';
+            statusEl.classList.remove('success');
+            statusEl.classList.add('error');
+            statusEl.style.display = 'inline';
+        })
+        .finally(() => {
+            btn.disabled = false;
+        });
+}
+
+function deleteScheduleProfile(name) {
+    fetch(basePath + '/schedule/profiles/' + encodeURIComponent(name), { method: 'DELETE' })
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to remove schedule profile');
+            }
+            loadSchedule();
+        })
+        .catch(err => {
+            console.error(err);
+        });
+}
+
+// selectScheduleProfile picks the profile used every day, or just for today, where an empty
+// profile goes back to the default
+function selectScheduleProfile(which, profile) {
+    fetch(basePath + '/schedule/' + which, {
+        method: 'PUT',
+        headers: {
+            'Content-Type': 'application/json'
+        },
+        body: JSON.stringify({ profile: profile })
+    })
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to pick schedule profile');
+            }
+            return response.json();
+        })
+        .then(data => {
+            loadScheduleProfiles(data);
+        })
+        .catch(err => {
+            console.error(err);
+        });
+}
+
 function applyScheduleToUI(schedule) {
     const enabledBtn = document.getElementById('toggle-schedule-enabled');
     const startInput = document.getElementById('schedule-start');
@@ -414,6 +973,20 @@ function applyScheduleToUI(schedule) {
     // Format time as HH:MM
     startInput.value = formatTimeInput(schedule.start);
     endInput.value = formatTimeInput(schedule.end);
+
+    document.getElementById('schedule-action').value = schedule.action;
+    document.getElementById('schedule-dim-percent').value = schedule.dim_percent;
+    document.getElementById('schedule-slow-interval').value = schedule.slow_interval_seconds;
+}
+
+function onScheduleQuietHoursChanged() {
+    if (!currentSchedule) {
+        currentSchedule = { ...originalSchedule };
+    }
+    currentSchedule.action = document.getElementById('schedule-action').value;
+    currentSchedule.dim_percent = parseInt(document.getElementById('schedule-dim-percent').value, 10) || 0;
+    currentSchedule.slow_interval_seconds = parseInt(document.getElementById('schedule-slow-interval').value, 10) || 0;
+    updateScheduleSaveButton();
 }
 
 function formatTimeInput(time) {
@@ -558,10 +1131,13 @@ function saveSchedule() {
     const payload = {
         enabled: !!currentSchedule.enabled,
         start: currentSchedule.start,
-        end: currentSchedule.end
+        end: currentSchedule.end,
+        action: currentSchedule.action,
+        dim_percent: currentSchedule.dim_percent,
+        slow_interval_seconds: currentSchedule.slow_interval_seconds
     };
 
-    fetch('/schedule', {
+    fetch(basePath + '/schedule', {
         method: 'PUT',
         headers: {
             'Content-Type': 'application/json'
@@ -580,11 +1156,7 @@ function saveSchedule() {
             return response.json();
         })
         .then(data => {
-            originalSchedule = {
-                enabled: data.enabled,
-                start: data.start,
-                end: data.end
-            };
+            originalSchedule = scheduleFromResponse(data);
             currentSchedule = { ...originalSchedule };
             applyScheduleToUI(currentSchedule);
             updateScheduleSaveButton();
@@ -633,35 +1205,1155 @@ function enablePlayButtons() {
     });
 }
 
-function loadDarkMode() {
-    const saved = localStorage.getItem('darkMode');
-    if (saved !== null) {
-        darkModeEnabled = saved === 'true';
-        applyDarkMode(darkModeEnabled);
-        const btn = document.getElementById('toggle-dark-mode');
-        if (btn) {
-            setToggleButton(btn, darkModeEnabled);
-        }
-    }
+// hide a photo from the slideshow or show it again, keeping it in the library either way
+function togglePhotoHidden(btn) {
+    const hidden = btn.dataset.hidden !== 'true';
+    btn.disabled = true;
+    fetch(btn.dataset.hiddenUrl, {
+        method: 'PUT',
+        headers: {
+            'Content-Type': 'application/json'
+        },
+        body: JSON.stringify({ hidden: hidden })
+    })
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to update photo');
+            }
+            htmx.trigger(document.body, 'refreshPhotos');
+        })
+        .catch(err => {
+            console.error(err);
+            btn.disabled = false;
+        });
 }
 
-function applyDarkMode(enabled) {
-    if (enabled) {
-        document.body.setAttribute('data-theme', 'dark');
-    } else {
-        document.body.removeAttribute('data-theme');
-    }
-    darkModeEnabled = enabled;
-    localStorage.setItem('darkMode', enabled ? 'true' : 'false');
+// pin a photo into every slideshow whatever the order and category settings, or unpin it
+function togglePhotoPinned(btn) {
+    const pinned = btn.dataset.pinned !== 'true';
+    btn.disabled = true;
+    fetch(btn.dataset.pinnedUrl, {
+        method: 'PUT',
+        headers: {
+            'Content-Type': 'application/json'
+        },
+        body: JSON.stringify({ pinned: pinned })
+    })
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to update photo');
+            }
+            htmx.trigger(document.body, 'refreshPhotos');
+        })
+        .catch(err => {
+            console.error(err);
+            btn.disabled = false;
+        });
 }
 
-function toggleDarkMode(btn) {
-    const current = btn.dataset.value === 'true';
-    const next = !current;
-    setToggleButton(btn, next);
-    applyDarkMode(next);
+// the name reactions are left under, taken from the upload form so it only has to be typed once
+function reactionName() {
+    const from = document.querySelector('#upload-form input[name="from"]');
+    return from ? from.value.trim() : '';
 }
 
+function postReaction(btn, reaction) {
+    btn.disabled = true;
+    return fetch(btn.dataset.reactionsUrl, {
+        method: 'POST',
+        headers: {
+            'Content-Type': 'application/json'
+        },
+        body: JSON.stringify(Object.assign({ name: reactionName() }, reaction))
+    })
+        .then(response => {
+            if (!response.ok) {
+                return response.json().then(data => {
+                    throw new Error(data && data.error ? data.error : 'Failed to add reaction');
+                });
+            }
+            htmx.trigger(document.body, 'refreshPhotos');
+        })
+        .catch(err => {
+            console.error(err);
+            alert(err.message || 'Failed to add reaction');
+            btn.disabled = false;
+        });
+}
+
+function leaveHeart(btn) {
+    postReaction(btn, { heart: true });
+}
+
+// shows the latest comments on a photo and asks for a new one
+function leaveComment(btn) {
+    fetch(btn.dataset.reactionsUrl)
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to load comments');
+            }
+            return response.json();
+        })
+        .then(reactions => {
+            const latest = reactions
+                .filter(r => r.comment)
+                .slice(0, 5)
+                .map(r => (r.name ? r.name + ': ' : '') + r.comment);
+            const comment = prompt(latest.concat(['', 'Leave a comment']).join('\n').trim());
+            if (comment && comment.trim()) {
+                postReaction(btn, { comment: comment.trim() });
+            }
+        })
+        .catch(err => {
+            console.error(err);
+        });
+}
+
+// approve or reject a synced photo waiting for approval
+function updatePhotoApproval(btn) {
+    btn.disabled = true;
+    fetch(btn.dataset.approvalUrl, { method: 'POST' })
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to update photo');
+            }
+            htmx.trigger(document.body, 'refreshPhotos');
+        })
+        .catch(err => {
+            console.error(err);
+            btn.disabled = false;
+        });
+}
+
+function createGuestLink() {
+    const btn = document.getElementById('guest-link-btn');
+    const statusEl = document.getElementById('guest-link-status');
+    const result = document.getElementById('guest-link-result');
+    const link = document.getElementById('guest-link-url');
+    const qr = document.getElementById('guest-link-qr');
+
+    btn.disabled = true;
+    fetch(basePath + '/guest-links', {
+        method: 'POST',
+        headers: {
+            'Content-Type': 'application/json'
+        },
+        body: JSON.stringify({})
+    })
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to create guest link');
+            }
+            return response.json();
+        })
+        .then(data => {
+            link.href = data.url;
+            link.textContent = data.url;
+            qr.src = data.qr_code_url;
+            result.style.display = 'flex';
+            statusEl.textContent = 'Expires ' + new Date(data.expires_at).toLocaleString();
+            statusEl.classList.remove('error');
+            statusEl.classList.add('success');
+            statusEl.style.display = 'inline';
+        })
+        .catch(err => {
+            console.error(err);
+            statusEl.textContent = err.message || 'Failed to create guest link';
+            statusEl.classList.remove('success');
+            statusEl.classList.add('error');
+            statusEl.style.display = 'inline';
+        })
+        .finally(() => {
+            btn.disabled = false;
+        });
+}
+
+function loadNetworkStatus() {
+    const btn = document.getElementById('network-refresh-btn');
+    if (btn) btn.disabled = true;
+
+    fetch(basePath + '/network')
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to load network status');
+            }
+            return response.json();
+        })
+        .then(data => {
+            let wifi = 'Not connected';
+            if (data.setup_mode) {
+                wifi = 'Setup mode';
+            } else if (data.ssid) {
+                wifi = data.ssid + ' (' + data.signal + '%)';
+            }
+            document.getElementById('network-wifi').textContent = wifi;
+            document.getElementById('network-ip').textContent = (data.ip_addresses || []).join(', ') || '-';
+            document.getElementById('network-internet').textContent = data.internet_reachable ? 'Reachable' : 'Unreachable (' + data.connectivity + ')';
+
+            let s3 = 'Not checked yet';
+            if (!data.s3_enabled) {
+                s3 = 'Not configured';
+            } else if (data.s3_last_error) {
+                s3 = 'Error: ' + data.s3_last_error;
+            } else if (data.s3_last_reachable_at) {
+                s3 = 'Reached ' + new Date(data.s3_last_reachable_at).toLocaleString();
+            }
+            const failed = data.s3_failed_downloads || [];
+            if (failed.length > 0) {
+                s3 += ' (' + failed.length + ' failed downloads: ' + failed.map(f => f.name).join(', ') + ')';
+            }
+            document.getElementById('network-s3').textContent = s3;
+        })
+        .catch(err => {
+            console.error(err);
+        })
+        .finally(() => {
+            if (btn) btn.disabled = false;
+        });
+}
+
+// loadDisplayInfo shows which panel is attached to the frame and how it is driven
+function loadDisplayInfo() {
+    fetch(basePath + '/display/info')
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to load display info');
+            }
+            return response.json();
+        })
+        .then(data => {
+            const panel = [data.make, data.model].filter(Boolean).join(' ') || data.description || data.name;
+            document.getElementById('display-info-panel').textContent = panel || '-';
+
+            let mode = 'Off';
+            if (data.current_mode) {
+                mode = data.current_mode.width + 'x' + data.current_mode.height + ' @ ' + data.current_mode.refresh.toFixed(0) + 'Hz';
+            }
+            document.getElementById('display-info-mode').textContent = mode;
+
+            const size = data.physical_size || {};
+            let physical = '-';
+            if (size.width && size.height) {
+                const inches = Math.sqrt(size.width * size.width + size.height * size.height) / 25.4;
+                physical = size.width + 'x' + size.height + ' mm (' + inches.toFixed(1) + '")';
+            }
+            document.getElementById('display-info-size').textContent = physical;
+            document.getElementById('display-info-scale').textContent = data.scale ? data.scale + 'x' : '-';
+
+            const modeSelect = document.getElementById('display-mode-select');
+            modeSelect.innerHTML = '';
+            (data.modes || []).forEach(m => {
+                const option = document.createElement('option');
+                option.value = m.width + 'x' + m.height + '@' + m.refresh;
+                option.textContent = m.width + 'x' + m.height + ' @ ' + m.refresh.toFixed(0) + 'Hz' + (m.preferred ? ' (native)' : '');
+                option.selected = m.current;
+                modeSelect.appendChild(option);
+            });
+            if (data.scale) {
+                document.getElementById('display-scale-select').value = String(data.scale);
+            }
+        })
+        .catch(err => {
+            console.error(err);
+        });
+}
+
+// formatOnTime describes how long the display was on and the energy that used
+function formatOnTime(seconds, kwh) {
+    return (seconds / 3600).toFixed(1) + ' h (' + kwh.toFixed(2) + ' kWh)';
+}
+
+// loadDisplayUsage shows how long the display was on today and over the last 30 days
+function loadDisplayUsage() {
+    fetch(basePath + '/display/usage?days=30')
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to load display usage');
+            }
+            return response.json();
+        })
+        .then(data => {
+            const days = data.days || [];
+            const last = days[days.length - 1];
+            const today = new Date();
+            const todayStr = today.getFullYear() + '-' + String(today.getMonth() + 1).padStart(2, '0') + '-' + String(today.getDate()).padStart(2, '0');
+            const todayUsage = last && last.day === todayStr ? last : { on_seconds: 0, energy_kwh: 0 };
+
+            document.getElementById('display-usage-today').textContent = formatOnTime(todayUsage.on_seconds, todayUsage.energy_kwh);
+            document.getElementById('display-usage-month').textContent = formatOnTime(data.total_on_seconds, data.total_energy_kwh);
+        })
+        .catch(err => {
+            console.error(err);
+        });
+}
+
+// applyDisplayMode switches the screen to the chosen resolution and scale
+function applyDisplayMode() {
+    const btn = document.getElementById('display-mode-btn');
+    const statusEl = document.getElementById('display-mode-status');
+    const mode = document.getElementById('display-mode-select').value;
+    if (!mode) return;
+
+    const [size, refresh] = mode.split('@');
+    const [width, height] = size.split('x');
+    const payload = {
+        width: parseInt(width, 10),
+        height: parseInt(height, 10),
+        refresh: parseFloat(refresh),
+        scale: parseFloat(document.getElementById('display-scale-select').value)
+    };
+
+    btn.disabled = true;
+    fetch(basePath + '/display/mode', {
+        method: 'PUT',
+        headers: {
+            'Content-Type': 'application/json'
+        },
+        body: JSON.stringify(payload)
+    })
+        .then(response => {
+            if (!response.ok) {
+                return response.json().then(data => {
+                    throw new Error(data && data.error ? data.error : 'Failed to change display mode');
+                });
+            }
+            return response.json();
+        })
+        .then(() => {
+            statusEl.style.display = 'none';
+            loadDisplayInfo();
+        })
+        .catch(err => {
+            console.error(err);
+            statusEl.textContent = err.message || 'Failed to change display mode';
+            statusEl.classList.remove('success');
+            statusEl.classList.add('error');
+            statusEl.style.display = 'inline';
+        })
+        .finally(() => {
+            btn.disabled = false;
+        });
+}
+
+// togglePreview starts or stops streaming the image on the frame's screen
+function togglePreview(btn) {
+    const img = document.getElementById('preview-stream');
+    if (img.style.display === 'none') {
+        img.src = basePath + '/slideshow/stream';
+        img.style.display = 'block';
+        btn.textContent = 'Hide';
+    } else {
+        // clearing the source closes the stream
+        img.removeAttribute('src');
+        img.style.display = 'none';
+        btn.textContent = 'Show';
+    }
+}
+
+// photo categories by id as listed by the server, with their names, photo counts, and sizes
+let categories = {};
+
+function loadCategories() {
+    fetch(basePath + '/categories')
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to load categories');
+            }
+            return response.json();
+        })
+        .then(list => {
+            categories = {};
+            list.forEach(category => {
+                categories[category.id] = category;
+            });
+            applyCategories(list);
+        })
+        .catch(err => console.error(err));
+}
+
+// fill in category names, counts, and the options of category selects, keeping what was picked
+function applyCategories(list) {
+    document.querySelectorAll('[data-category-name]').forEach(el => {
+        const category = categories[el.dataset.categoryName];
+        if (category) {
+            el.textContent = category.name;
+        }
+    });
+    document.querySelectorAll('[data-category-stats]').forEach(el => {
+        const category = categories[el.dataset.categoryStats];
+        if (category) {
+            el.textContent = category.photo_count + (category.photo_count === 1 ? ' photo, ' : ' photos, ') +
+                formatBytes(category.size_bytes);
+        }
+    });
+    document.querySelectorAll('select[data-category-select]').forEach(select => {
+        const selected = select.value || select.dataset.categorySelect;
+        select.replaceChildren();
+        list.forEach(category => {
+            if (select.hasAttribute('data-uploadable-only') && !category.uploadable) {
+                return;
+            }
+            select.appendChild(new Option(category.name, category.id));
+        });
+        select.value = selected;
+        if (!select.value && select.options.length > 0) {
+            select.value = select.options[0].value;
+        }
+    });
+}
+
+function formatBytes(bytes) {
+    const units = ['B', 'KB', 'MB', 'GB'];
+    let unit = 0;
+    while (bytes >= 1024 && unit < units.length - 1) {
+        bytes /= 1024;
+        unit++;
+    }
+    return (unit === 0 ? bytes : bytes.toFixed(1)) + ' ' + units[unit];
+}
+
+function loadSeasonalRules() {
+    fetch(basePath + '/seasonal-rules')
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to load seasonal rules');
+            }
+            return response.json();
+        })
+        .then(rules => {
+            const list = document.getElementById('seasonal-rules');
+            if (!list) return;
+
+            list.replaceChildren();
+            rules.forEach(rule => {
+                const row = document.createElement('div');
+                row.className = 'settings-row';
+
+                const text = document.createElement('span');
+                text.textContent = rule.album + ': ' + rule.start + ' to ' + rule.end +
+                    (rule.label ? ' (' + rule.label + ')' : '');
+
+                const remove = document.createElement('button');
+                remove.type = 'button';
+                remove.className = 'settings-save-btn';
+                remove.textContent = 'Remove';
+                remove.onclick = function() {
+                    deleteSeasonalRule(rule.id);
+                };
+
+                row.append(text, remove);
+                list.append(row);
+            });
+        })
+        .catch(err => {
+            console.error(err);
+        });
+}
+
+function createSeasonalRule() {
+    const btn = document.getElementById('seasonal-add-btn');
+    const statusEl = document.getElementById('seasonal-status');
+
+    const payload = {
+        album: document.getElementById('seasonal-album').value,
+        label: document.getElementById('seasonal-label').value,
+        start: document.getElementById('seasonal-start').value,
+        end: document.getElementById('seasonal-end').value
+    };
+
+    btn.disabled = true;
+    fetch(basePath + '/seasonal-rules', {
+        method: 'POST',
+        headers: {
+            'Content-Type': 'application/json'
+        },
+        body: JSON.stringify(payload)
+    })
+        .then(response => {
+            if (!response.ok) {
+                return response.json().then(data => {
+                    throw new Error(data && data.error ? data.error : 'Failed to add seasonal rule');
+                });
+            }
+            return response.json();
+        })
+        .then(() => {
+            document.getElementById('seasonal-album').value = '';
+            document.getElementById('seasonal-label').value = '';
+            document.getElementById('seasonal-start').value = '';
+            document.getElementById('seasonal-end').value = '';
+            statusEl.style.display = 'none';
+            loadSeasonalRules();
+        })
+        .catch(err => {
+            console.error(err);
+            statusEl.textContent = err.message || 'Failed to add seasonal rule';
+            statusEl.classList.remove('success');
+            statusEl.classList.add('error');
+            statusEl.style.display = 'inline';
+        })
+        .finally(() => {
+            btn.disabled = false;
+        });
+}
+
+function deleteSeasonalRule(id) {
+    fetch(basePath + '/seasonal-rules/' + id, { method: 'DELETE' })
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to remove seasonal rule');
+            }
+            loadSeasonalRules();
+        })
+        .catch(err => {
+            console.error(err);
+        });
+}
+
+function loadAlbumSchedules() {
+    fetch(basePath + '/album-schedules')
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to load album schedules');
+            }
+            return response.json();
+        })
+        .then(schedules => {
+            const list = document.getElementById('album-schedules');
+            if (!list) return;
+
+            list.replaceChildren();
+            schedules.forEach(schedule => {
+                const row = document.createElement('div');
+                row.className = 'settings-row';
+
+                const text = document.createElement('span');
+                text.textContent = schedule.album + ': ' + schedule.start + ' to ' + schedule.end +
+                    (schedule.exclusive ? ' (only this album)' : '');
+
+                const remove = document.createElement('button');
+                remove.type = 'button';
+                remove.className = 'settings-save-btn';
+                remove.textContent = 'Remove';
+                remove.onclick = function() {
+                    deleteAlbumSchedule(schedule.id);
+                };
+
+                row.append(text, remove);
+                list.append(row);
+            });
+        })
+        .catch(err => {
+            console.error(err);
+        });
+}
+
+function createAlbumSchedule() {
+    const btn = document.getElementById('album-schedule-add-btn');
+    const statusEl = document.getElementById('album-schedule-status');
+
+    const payload = {
+        album: document.getElementById('album-schedule-album').value,
+        start: document.getElementById('album-schedule-start').value,
+        end: document.getElementById('album-schedule-end').value,
+        exclusive: document.getElementById('album-schedule-exclusive').checked
+    };
+
+    btn.disabled = true;
+    fetch(basePath + '/album-schedules', {
+        method: 'POST',
+        headers: {
+            'Content-Type': 'application/json'
+        },
+        body: JSON.stringify(payload)
+    })
+        .then(response => {
+            if (!response.ok) {
+                return response.json().then(data => {
+                    throw new Error(data && data.error ? data.error : 'Failed to add album schedule');
+                });
+            }
+            return response.json();
+        })
+        .then(() => {
+            document.getElementById('album-schedule-album').value = '';
+            document.getElementById('album-schedule-start').value = '';
+            document.getElementById('album-schedule-end').value = '';
+            document.getElementById('album-schedule-exclusive').checked = false;
+            statusEl.style.display = 'none';
+            loadAlbumSchedules();
+        })
+        .catch(err => {
+            console.error(err);
+            statusEl.textContent = err.message || 'Failed to add album schedule';
+            statusEl.classList.remove('success');
+            statusEl.classList.add('error');
+            statusEl.style.display = 'inline';
+        })
+        .finally(() => {
+            btn.disabled = false;
+        });
+}
+
+function deleteAlbumSchedule(id) {
+    fetch(basePath + '/album-schedules/' + id, { method: 'DELETE' })
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to remove album schedule');
+            }
+            loadAlbumSchedules();
+        })
+        .catch(err => {
+            console.error(err);
+        });
+}
+
+function loadPeople() {
+    fetch(basePath + '/people')
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to load people');
+            }
+            return response.json();
+        })
+        .then(people => {
+            const list = document.getElementById('people');
+            if (!list) return;
+
+            list.replaceChildren();
+            people.forEach(person => {
+                const row = document.createElement('div');
+                row.className = 'settings-row';
+
+                const text = document.createElement('span');
+                text.textContent = person.name + ': ' + person.photo_count +
+                    (person.photo_count === 1 ? ' photo' : ' photos');
+
+                const exclusive = document.createElement('label');
+                const toggle = document.createElement('input');
+                toggle.type = 'checkbox';
+                toggle.checked = person.exclusive;
+                toggle.onchange = function() {
+                    updatePerson(person, toggle.checked);
+                };
+                exclusive.append(toggle, ' Only their photos');
+
+                const remove = document.createElement('button');
+                remove.type = 'button';
+                remove.className = 'settings-save-btn';
+                remove.textContent = 'Remove';
+                remove.onclick = function() {
+                    deletePerson(person.id);
+                };
+
+                row.append(text, exclusive, remove);
+                list.append(row);
+            });
+        })
+        .catch(err => {
+            console.error(err);
+        });
+}
+
+function createPerson() {
+    const btn = document.getElementById('person-add-btn');
+    const statusEl = document.getElementById('person-status');
+
+    const payload = {
+        name: document.getElementById('person-name').value,
+        exclusive: document.getElementById('person-exclusive').checked
+    };
+
+    btn.disabled = true;
+    fetch(basePath + '/people', {
+        method: 'POST',
+        headers: {
+            'Content-Type': 'application/json'
+        },
+        body: JSON.stringify(payload)
+    })
+        .then(response => {
+            if (!response.ok) {
+                return response.json().then(data => {
+                    throw new Error(data && data.error ? data.error : 'Failed to add person');
+                });
+            }
+            return response.json();
+        })
+        .then(() => {
+            document.getElementById('person-name').value = '';
+            document.getElementById('person-exclusive').checked = false;
+            statusEl.style.display = 'none';
+            loadPeople();
+        })
+        .catch(err => {
+            console.error(err);
+            statusEl.textContent = err.message || 'Failed to add person';
+            statusEl.classList.remove('success');
+            statusEl.classList.add('error');
+            statusEl.style.display = 'inline';
+        })
+        .finally(() => {
+            btn.disabled = false;
+        });
+}
+
+function updatePerson(person, exclusive) {
+    fetch(basePath + '/people/' + person.id, {
+        method: 'PUT',
+        headers: {
+            'Content-Type': 'application/json'
+        },
+        body: JSON.stringify({ name: person.name, exclusive: exclusive })
+    })
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to update person');
+            }
+            loadPeople();
+        })
+        .catch(err => {
+            console.error(err);
+            loadPeople();
+        });
+}
+
+function deletePerson(id) {
+    fetch(basePath + '/people/' + id, { method: 'DELETE' })
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to remove person');
+            }
+            loadPeople();
+        })
+        .catch(err => {
+            console.error(err);
+        });
+}
+
+function loadRetentionRules() {
+    fetch(basePath + '/retention-rules')
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to load retention rules');
+            }
+            return response.json();
+        })
+        .then(rules => {
+            const list = document.getElementById('retention-rules');
+            if (!list) return;
+
+            list.replaceChildren();
+            rules.forEach(rule => {
+                const row = document.createElement('div');
+                row.className = 'settings-row';
+
+                const text = document.createElement('span');
+                text.textContent = rule.album + ': ' + rule.action + ' after ' + rule.days +
+                    (rule.days === 1 ? ' day' : ' days');
+
+                const remove = document.createElement('button');
+                remove.type = 'button';
+                remove.className = 'settings-save-btn';
+                remove.textContent = 'Remove';
+                remove.onclick = function() {
+                    deleteRetentionRule(rule.id);
+                };
+
+                row.append(text, remove);
+                list.append(row);
+            });
+        })
+        .catch(err => {
+            console.error(err);
+        });
+}
+
+function createRetentionRule() {
+    const btn = document.getElementById('retention-rule-add-btn');
+    const statusEl = document.getElementById('retention-rule-status');
+
+    const payload = {
+        album: document.getElementById('retention-rule-album').value,
+        days: parseInt(document.getElementById('retention-rule-days').value, 10) || 0,
+        action: document.getElementById('retention-rule-action').value
+    };
+
+    btn.disabled = true;
+    fetch(basePath + '/retention-rules', {
+        method: 'POST',
+        headers: {
+            'Content-Type': 'application/json'
+        },
+        body: JSON.stringify(payload)
+    })
+        .then(response => {
+            if (!response.ok) {
+                return response.json().then(data => {
+                    throw new Error(data && data.error ? data.error : 'Failed to add retention rule');
+                });
+            }
+            return response.json();
+        })
+        .then(() => {
+            document.getElementById('retention-rule-album').value = '';
+            document.getElementById('retention-rule-days').value = '';
+            statusEl.style.display = 'none';
+            loadRetentionRules();
+        })
+        .catch(err => {
+            console.error(err);
+            statusEl.textContent = err.message || 'Failed to add retention rule';
+            statusEl.classList.remove('success');
+            statusEl.classList.add('error');
+            statusEl.style.display = 'inline';
+        })
+        .finally(() => {
+            btn.disabled = false;
+        });
+}
+
+function deleteRetentionRule(id) {
+    fetch(basePath + '/retention-rules/' + id, { method: 'DELETE' })
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to remove retention rule');
+            }
+            loadRetentionRules();
+        })
+        .catch(err => {
+            console.error(err);
+        });
+}
+
+function loadSharedAlbums() {
+    fetch(basePath + '/shared-albums')
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to load shared albums');
+            }
+            return response.json();
+        })
+        .then(albums => {
+            const list = document.getElementById('shared-albums');
+            if (!list) return;
+
+            list.replaceChildren();
+            albums.forEach(album => {
+                const row = document.createElement('div');
+                row.className = 'settings-row';
+
+                const text = document.createElement('span');
+                text.textContent = album.album + ': ' + album.url;
+
+                const remove = document.createElement('button');
+                remove.type = 'button';
+                remove.className = 'settings-save-btn';
+                remove.textContent = 'Stop Sharing';
+                remove.onclick = function() {
+                    deleteSharedAlbum(album.id);
+                };
+
+                row.append(text, remove);
+                list.append(row);
+            });
+        })
+        .catch(err => {
+            console.error(err);
+        });
+}
+
+function createSharedAlbum() {
+    const btn = document.getElementById('shared-album-add-btn');
+    const statusEl = document.getElementById('shared-album-status');
+
+    const payload = {
+        album: document.getElementById('shared-album-album').value
+    };
+
+    btn.disabled = true;
+    fetch(basePath + '/shared-albums', {
+        method: 'POST',
+        headers: {
+            'Content-Type': 'application/json'
+        },
+        body: JSON.stringify(payload)
+    })
+        .then(response => {
+            if (!response.ok) {
+                return response.json().then(data => {
+                    throw new Error(data && data.error ? data.error : 'Failed to share album');
+                });
+            }
+            return response.json();
+        })
+        .then(() => {
+            document.getElementById('shared-album-album').value = '';
+            statusEl.style.display = 'none';
+            loadSharedAlbums();
+        })
+        .catch(err => {
+            console.error(err);
+            statusEl.textContent = err.message || 'Failed to share album';
+            statusEl.classList.remove('success');
+            statusEl.classList.add('error');
+            statusEl.style.display = 'inline';
+        })
+        .finally(() => {
+            btn.disabled = false;
+        });
+}
+
+function deleteSharedAlbum(id) {
+    fetch(basePath + '/shared-albums/' + id, { method: 'DELETE' })
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to stop sharing album');
+            }
+            loadSharedAlbums();
+        })
+        .catch(err => {
+            console.error(err);
+        });
+}
+
+function loadAlbumPairings() {
+    fetch(basePath + '/album-pairings')
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to load album pairings');
+            }
+            return response.json();
+        })
+        .then(pairings => {
+            const list = document.getElementById('album-pairings');
+            if (!list) return;
+
+            list.replaceChildren();
+            pairings.forEach(pairing => {
+                const row = document.createElement('div');
+                row.className = 'settings-row';
+
+                const text = document.createElement('span');
+                text.textContent = pairing.album + ' from ' + pairing.url +
+                    (pairing.last_synced_at ? ' (synced ' + new Date(pairing.last_synced_at).toLocaleString() + ')' : ' (not synced yet)');
+
+                const remove = document.createElement('button');
+                remove.type = 'button';
+                remove.className = 'settings-save-btn';
+                remove.textContent = 'Unpair';
+                remove.onclick = function() {
+                    deleteAlbumPairing(pairing.id);
+                };
+
+                row.append(text, remove);
+                list.append(row);
+            });
+        })
+        .catch(err => {
+            console.error(err);
+        });
+}
+
+function createAlbumPairing() {
+    const btn = document.getElementById('album-pairing-add-btn');
+    const statusEl = document.getElementById('album-pairing-status');
+
+    const payload = {
+        url: document.getElementById('album-pairing-url').value,
+        album: document.getElementById('album-pairing-album').value
+    };
+
+    btn.disabled = true;
+    fetch(basePath + '/album-pairings', {
+        method: 'POST',
+        headers: {
+            'Content-Type': 'application/json'
+        },
+        body: JSON.stringify(payload)
+    })
+        .then(response => {
+            if (!response.ok) {
+                return response.json().then(data => {
+                    throw new Error(data && data.error ? data.error : 'Failed to pair album');
+                });
+            }
+            return response.json();
+        })
+        .then(() => {
+            document.getElementById('album-pairing-url').value = '';
+            document.getElementById('album-pairing-album').value = '';
+            statusEl.style.display = 'none';
+            loadAlbumPairings();
+        })
+        .catch(err => {
+            console.error(err);
+            statusEl.textContent = err.message || 'Failed to pair album';
+            statusEl.classList.remove('success');
+            statusEl.classList.add('error');
+            statusEl.style.display = 'inline';
+        })
+        .finally(() => {
+            btn.disabled = false;
+        });
+}
+
+function deleteAlbumPairing(id) {
+    fetch(basePath + '/album-pairings/' + id, { method: 'DELETE' })
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to unpair album');
+            }
+            loadAlbumPairings();
+        })
+        .catch(err => {
+            console.error(err);
+        });
+}
+
+// last page of the activity feed shown
+let feedPage = 0;
+
+// loadFeed shows a page of the activity feed, replacing what's shown for the first page and
+// adding to it for later ones
+function loadFeed(page) {
+    fetch(basePath + '/feed?page=' + page)
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to load activity');
+            }
+            return response.json();
+        })
+        .then(feed => {
+            const list = document.getElementById('activity-feed');
+            if (!list) return;
+
+            if (page === 1) {
+                list.replaceChildren();
+            }
+            feed.items.forEach(item => {
+                const row = document.createElement('div');
+                row.className = 'activity-item activity-' + item.kind;
+
+                const when = document.createElement('span');
+                when.className = 'activity-time';
+                when.textContent = new Date(item.occurred_at).toLocaleString();
+
+                const text = document.createElement('span');
+                text.textContent = item.message;
+
+                row.append(when, text);
+                list.append(row);
+            });
+            if (page === 1 && feed.items.length === 0) {
+                const empty = document.createElement('div');
+                empty.className = 'photo-row-empty';
+                empty.textContent = 'Nothing has happened yet';
+                list.append(empty);
+            }
+
+            feedPage = page;
+            document.getElementById('activity-more-btn').style.display =
+                feed.page * feed.limit < feed.total ? 'inline-block' : 'none';
+        })
+        .catch(err => {
+            console.error(err);
+        });
+}
+
+function loadSettingsHistory() {
+    fetch(basePath + '/settings/history')
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to load settings history');
+            }
+            return response.json();
+        })
+        .then(versions => {
+            const list = document.getElementById('settings-history');
+            if (!list) return;
+
+            list.replaceChildren();
+            versions.forEach(version => {
+                const row = document.createElement('div');
+                row.className = 'settings-row';
+
+                const text = document.createElement('span');
+                text.textContent = new Date(version.changed_at).toLocaleString() + ': ' +
+                    (version.kind === 'schedule' ? 'Schedule' : 'Settings') +
+                    (version.changed_by ? ' by ' + version.changed_by : ' before history');
+
+                const restore = document.createElement('button');
+                restore.type = 'button';
+                restore.className = 'settings-save-btn';
+                restore.textContent = 'Restore';
+                restore.onclick = function() {
+                    rollbackSettings(version.id);
+                };
+
+                row.append(text, restore);
+                list.append(row);
+            });
+        })
+        .catch(err => {
+            console.error(err);
+        });
+}
+
+function rollbackSettings(id) {
+    fetch(basePath + '/settings/history/' + id + '/rollback', { method: 'POST' })
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to restore settings');
+            }
+            loadSettings();
+            loadSchedule();
+            loadSettingsHistory();
+        })
+        .catch(err => {
+            console.error(err);
+        });
+}
+
+function applyTheme(theme, accentColor) {
+    document.body.dataset.themeSetting = theme;
+
+    let dark = theme === 'dark';
+    if (theme === 'auto') {
+        dark = window.matchMedia('(prefers-color-scheme: dark)').matches;
+    }
+    if (dark) {
+        document.body.setAttribute('data-theme', 'dark');
+    } else {
+        document.body.removeAttribute('data-theme');
+    }
+
+    if (accentColor) {
+        document.body.style.setProperty('--accent-color', accentColor);
+    }
+}
+
+function onThemeChanged() {
+    const themeSelect = document.getElementById('theme-select');
+    const accentInput = document.getElementById('accent-color');
+    if (!themeSelect || !accentInput) return;
+
+    if (!currentSettings) {
+        currentSettings = { ...originalSettings };
+    }
+    currentSettings.theme = themeSelect.value;
+    currentSettings.accent_color = accentInput.value.toUpperCase();
+    applyTheme(currentSettings.theme, currentSettings.accent_color);
+    updateSettingsSaveButton();
+}
+
+// follow the system light and dark preference while the auto theme is selected
+window.matchMedia('(prefers-color-scheme: dark)').addEventListener('change', function() {
+    if (document.body.dataset.themeSetting === 'auto') {
+        applyTheme('auto');
+    }
+});
+
 document.addEventListener('DOMContentLoaded', function() {
     const intervalInput = document.getElementById('interval-value');
     const intervalUnit = document.getElementById('interval-unit');
@@ -672,11 +2364,73 @@ document.addEventListener('DOMContentLoaded', function() {
     if (intervalUnit) {
         intervalUnit.addEventListener('change', onIntervalChanged);
     }
+    const intervalJitter = document.getElementById('interval-jitter');
+    if (intervalJitter) {
+        intervalJitter.addEventListener('change', onIntervalJitterChanged);
+        intervalJitter.addEventListener('input', onIntervalJitterChanged);
+    }
+    const guestbookDays = document.getElementById('guestbook-days');
+    if (guestbookDays) {
+        guestbookDays.addEventListener('change', onGuestbookDaysChanged);
+    }
+    const languageSelect = document.getElementById('language-select');
+    if (languageSelect) {
+        languageSelect.addEventListener('change', onLanguageChanged);
+    }
+    const playlistOrder = document.getElementById('playlist-order');
+    if (playlistOrder) {
+        playlistOrder.addEventListener('change', onPlaylistOrderChanged);
+    }
+
+    const autoOrganize = document.getElementById('auto-organize');
+    if (autoOrganize) {
+        autoOrganize.addEventListener('change', onAutoOrganizeChanged);
+    }
 
+    const displayTransform = document.getElementById('display-transform');
+    if (displayTransform) {
+        displayTransform.addEventListener('change', onDisplayTransformChanged);
+    }
+
+    const photoOfDayTime = document.getElementById('photo-of-day-time');
+    if (photoOfDayTime) {
+        photoOfDayTime.addEventListener('change', onPhotoOfDayTimeChanged);
+    }
+
+    ['overlay-position', 'overlay-size'].forEach(function(id) {
+        const el = document.getElementById(id);
+        if (el) {
+            el.addEventListener('change', onOverlayStyleChanged);
+        }
+    });
+
+    const watermarkText = document.getElementById('watermark-text');
+    if (watermarkText) {
+        watermarkText.addEventListener('input', onWatermarkChanged);
+    }
+
+    const audioSource = document.getElementById('audio-source');
+    if (audioSource) {
+        audioSource.addEventListener('input', onAudioSourceChanged);
+    }
+    const audioVolume = document.getElementById('audio-volume');
+    if (audioVolume) {
+        audioVolume.addEventListener('change', onAudioVolumeChanged);
+    }
+
+    loadCategories();
+    document.body.addEventListener('refreshPhotos', loadCategories);
     loadSettings();
     loadDisplayState();
     loadSchedule();
-    loadDarkMode();
+    const themeSelect = document.getElementById('theme-select');
+    const accentInput = document.getElementById('accent-color');
+    if (themeSelect) {
+        themeSelect.addEventListener('change', onThemeChanged);
+    }
+    if (accentInput) {
+        accentInput.addEventListener('input', onThemeChanged);
+    }
 });
 
 // Load schedule when switching to slideshow view
@@ -688,5 +2442,20 @@ document.addEventListener('DOMContentLoaded', function() {
         if (viewName === 'slideshow') {
             loadSchedule();
         }
+        if (viewName === 'activity') {
+            loadFeed(1);
+        }
+        if (viewName === 'settings') {
+            loadNetworkStatus();
+            loadDisplayInfo();
+            loadDisplayUsage();
+            loadSeasonalRules();
+            loadAlbumSchedules();
+            loadPeople();
+            loadRetentionRules();
+            loadSharedAlbums();
+            loadAlbumPairings();
+            loadSettingsHistory();
+        }
     };
 })();
@@ -0,0 +1,153 @@
+// Plays the frame's playlist in the browser. The playlist is fetched again each time it has
+// been played through so new photos and settings changes are picked up.
+
+// path the frame is served under behind a reverse proxy, prefixed to every request
+const basePath = document.querySelector('meta[name="base-path"]').content;
+
+let slideImg = document.getElementById('web-slide');
+const slideMotion = document.getElementById('web-slide-motion');
+const slideOverlay = document.getElementById('web-slide-overlay');
+const slideEmpty = document.getElementById('web-slide-empty');
+
+// how long to wait before trying again when the playlist can't be loaded or is empty
+const retrySeconds = 60;
+
+// how many upcoming slides are decoded ahead of time so a large photo is ready by its turn
+const prefetchCount = 2;
+
+// decoded images of the upcoming slides by url, each a promise of an img element
+const prefetched = new Map();
+
+let playlist = null;
+let slideIndex = 0;
+
+function loadPlaylist() {
+    return fetch(basePath + '/slideshow/playlist')
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to load playlist');
+            }
+            return response.json();
+        })
+        .then(data => {
+            playlist = data;
+            slideIndex = 0;
+            slideOverlay.className = 'web-slide-overlay ' + data.overlay_position + ' ' + data.overlay_size;
+        });
+}
+
+function slideURL(slide) {
+    // request a copy sized to the screen instead of the full resolution original
+    const scale = window.devicePixelRatio || 1;
+    const w = Math.round(window.innerWidth * scale);
+    const h = Math.round(window.innerHeight * scale);
+    return slide.image_url + '?w=' + w + '&h=' + h + '&fit=contain';
+}
+
+// decodeSlide loads and decodes a photo off screen, reusing it when it was already prefetched
+function decodeSlide(url) {
+    let decoded = prefetched.get(url);
+    if (!decoded) {
+        const img = new Image();
+        img.src = url;
+        // a photo that can't be decoded is still shown, leaving the browser to show it as broken
+        decoded = img.decode().then(() => img, () => img);
+        prefetched.set(url, decoded);
+    }
+    return decoded;
+}
+
+// prefetch decodes the slides coming up after the one at index, letting go of any others
+function prefetch(index) {
+    const upcoming = playlist.slides.slice(index + 1, index + 1 + prefetchCount).map(slideURL);
+    for (const url of prefetched.keys()) {
+        if (!upcoming.includes(url)) {
+            prefetched.delete(url);
+        }
+    }
+    upcoming.forEach(decodeSlide);
+}
+
+function showSlide(index) {
+    const slide = playlist.slides[index];
+    return decodeSlide(slideURL(slide)).then(img => {
+        // the decoded image takes the slide's place, since pointing the slide at its url could
+        // decode it all over again
+        img.id = slideImg.id;
+        img.className = slideImg.className;
+        img.alt = '';
+        slideImg.replaceWith(img);
+        slideImg = img;
+
+        slideOverlay.textContent = slide.overlay;
+        slideOverlay.style.display = slide.overlay ? 'block' : 'none';
+        playMotion(slide);
+        prefetch(index);
+    });
+}
+
+// playMotion plays the video of a motion or live photo once over its still when the browser can
+// play it, leaving the still showing once it ends
+function playMotion(slide) {
+    slideMotion.pause();
+    slideMotion.style.display = 'none';
+    if (!slide.motion_url || !slideMotion.canPlayType(slide.motion_type)) {
+        slideMotion.removeAttribute('src');
+        return;
+    }
+    slideMotion.src = slide.motion_url;
+    slideMotion.play()
+        .then(() => {
+            slideMotion.style.display = 'block';
+        })
+        .catch(() => {
+            // autoplay was blocked or the video couldn't be decoded, so only the still is shown
+            slideMotion.style.display = 'none';
+        });
+}
+
+slideMotion.addEventListener('ended', () => {
+    slideMotion.style.display = 'none';
+});
+
+// slideSeconds is how long to show a slide, the interval varied randomly by up to the jitter either
+// way
+function slideSeconds() {
+    const jitter = playlist.interval_jitter_seconds || 0;
+    const offset = Math.floor(Math.random() * (2 * jitter + 1)) - jitter;
+    return Math.max(playlist.interval_seconds + offset, 1);
+}
+
+function advance() {
+    const load = (!playlist || slideIndex >= playlist.slides.length) ? loadPlaylist() : Promise.resolve();
+    load
+        .then(() => {
+            if (playlist.slides.length === 0) {
+                slideEmpty.style.display = 'block';
+                slideImg.removeAttribute('src');
+                slideOverlay.style.display = 'none';
+                slideMotion.pause();
+                slideMotion.style.display = 'none';
+                setTimeout(advance, retrySeconds * 1000);
+                return;
+            }
+            slideEmpty.style.display = 'none';
+            return showSlide(slideIndex++).then(() => {
+                setTimeout(advance, slideSeconds() * 1000);
+            });
+        })
+        .catch(err => {
+            console.error(err);
+            playlist = null;
+            setTimeout(advance, retrySeconds * 1000);
+        });
+}
+
+// tapping the screen asks the browser to go full screen
+document.addEventListener('click', () => {
+    if (!document.fullscreenElement && document.documentElement.requestFullscreen) {
+        document.documentElement.requestFullscreen().catch(() => {});
+    }
+});
+
+advance();
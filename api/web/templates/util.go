@@ -3,20 +3,109 @@ package templates
 import (
 	"fmt"
 	"net/url"
+	"time"
 
 	"github.com/aouyang1/digitalphotoframe/store"
 )
 
+// expiryWarning is how long before a retention rule expires a photo that it's marked in the ui
+const expiryWarning = 3 * 24 * time.Hour
+
+// basePath is the path a reverse proxy serves the frame under, which prefixes every url in the
+// rendered pages
+var basePath string
+
+// SetBasePath sets the path the frame is served under, such as /frame
+func SetBasePath(path string) {
+	basePath = path
+}
+
+// pathURL prefixes an absolute path within the frame with the base path
+func pathURL(path string) string {
+	return basePath + path
+}
+
+// assetPaths maps each static file to its name with its content hash
+var assetPaths map[string]string
+
+// SetAssetPaths sets the names static files are served under, keyed by their path within /static
+func SetAssetPaths(paths map[string]string) {
+	assetPaths = paths
+}
+
+// staticURL returns the url of a static file, by its path within /static, under the name with its
+// content hash so it's fetched again only once it changes
+func staticURL(name string) string {
+	if hashed, ok := assetPaths[name]; ok {
+		name = hashed
+	}
+	return pathURL("/static/" + name)
+}
+
+// photoURL is the path of a photo by its id, which the photo's routes are under
+func photoURL(photo store.Photo) string {
+	return pathURL("/photos/id/" + url.PathEscape(photo.ID))
+}
+
 func photoImageURL(photo store.Photo) string {
-	encodedName := url.PathEscape(photo.PhotoName)
-	return fmt.Sprintf("/photos/%d/%s/image", photo.Category, encodedName)
+	return photoURL(photo) + "/image"
+}
+
+func photoThumbnailURL(photo store.Photo) string {
+	return photoImageURL(photo) + "?w=400&h=400&fit=cover"
+}
+
+// photoPageURL is where the page of a category's photos starting at offset is rendered
+func photoPageURL(category, offset, limit int) string {
+	return pathURL(fmt.Sprintf("/ui/photos/%d?offset=%d&limit=%d", category, offset, limit))
 }
 
 func playImageURL(photo store.Photo) string {
-	return fmt.Sprintf("/slideshow/play/%s/category/%d", url.PathEscape(photo.PhotoName), photo.Category)
+	return photoURL(photo) + "/play"
+}
+
+func hiddenURL(photo store.Photo) string {
+	return photoURL(photo) + "/hidden"
+}
+
+func pinnedURL(photo store.Photo) string {
+	return photoURL(photo) + "/pinned"
+}
+
+// reactionsURL is where the hearts and comments on a photo are listed and left
+func reactionsURL(photo store.Photo) string {
+	return photoURL(photo) + "/reactions"
+}
+
+// approvalURL is where a photo waiting for approval is approved or rejected, by action
+func approvalURL(photo store.Photo, action string) string {
+	return photoURL(photo) + "/" + action
 }
 
 func deleteURL(photo store.Photo) string {
-	encodedName := url.PathEscape(photo.PhotoName)
-	return fmt.Sprintf("/photos/%s/category/%d", encodedName, photo.Category)
+	return photoURL(photo)
+}
+
+func guestUploadURL(token string) string {
+	return pathURL("/guest/" + url.PathEscape(token))
+}
+
+// guestbookURL is where a guest signs the guestbook with their upload link
+func guestbookURL(token string) string {
+	return guestUploadURL(token) + "/guestbook"
+}
+
+// expiresSoon is whether a retention rule expires the photo within the warning period
+func expiresSoon(photo store.Photo) bool {
+	return !photo.ExpiresAt.IsZero() && time.Until(photo.ExpiresAt) < expiryWarning
+}
+
+// daysUntilExpiry is the number of days from today to the day the photo expires
+func daysUntilExpiry(photo store.Photo) int {
+	day := func(t time.Time) time.Time {
+		y, m, d := t.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	days := day(photo.ExpiresAt.Local()).Sub(day(time.Now().Local())) / (24 * time.Hour)
+	return max(int(days), 0)
 }
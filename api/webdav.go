@@ -0,0 +1,299 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/aouyang1/digitalphotoframe/paths"
+	"github.com/aouyang1/digitalphotoframe/util"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/webdav"
+)
+
+// webdavPrefix is where the webdav share of My Photos is mounted
+const webdavPrefix = "/webdav"
+
+// webdavMethods are the methods a webdav client uses, beyond those gin routes with Any
+var webdavMethods = []string{
+	http.MethodOptions, http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete,
+	"PROPFIND", "PROPPATCH", "MKCOL", "COPY", "MOVE", "LOCK", "UNLOCK",
+}
+
+// webdavUploaderKey holds the user name a webdav client logged in with in the request context
+type webdavUploaderKey struct{}
+
+// webdavFS shares the originals of My Photos over webdav. Files are written to a staging directory
+// and added through the same pipeline as uploads once they are closed with content, so they are
+// checked, downsized, and registered. Photos already on the frame are read only. Hidden files such
+// as those Finder writes alongside a copy are kept in staging so clients can finish copying.
+type webdavFS struct {
+	ws      *WebServer
+	photos  webdav.Dir
+	staging webdav.Dir
+}
+
+func (ws *WebServer) newWebDAVHandler() (*webdav.Handler, error) {
+	photosDir := ws.paths.OriginalDir(paths.CategoryOriginal)
+	stagingDir := ws.paths.WebDAVDir()
+	for _, dir := range []string{photosDir, stagingDir} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create directory, %w", err)
+		}
+	}
+
+	return &webdav.Handler{
+		Prefix: webdavPrefix,
+		FileSystem: &webdavFS{
+			ws:      ws,
+			photos:  webdav.Dir(photosDir),
+			staging: webdav.Dir(stagingDir),
+		},
+		LockSystem: webdav.NewMemLS(),
+		Logger: func(r *http.Request, err error) {
+			if err != nil {
+				slog.Warn("webdav request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+			}
+		},
+	}, nil
+}
+
+// handleWebDAV serves the webdav share, crediting new photos to the user name the client logged
+// in with, if any. Requests made under the base path are served with it put back, so the links in
+// listings and the destinations of moves and copies, which the client sees with the base path,
+// line up with the share.
+func (ws *WebServer) handleWebDAV(c *gin.Context) {
+	ctx := c.Request.Context()
+	if user, _, ok := c.Request.BasicAuth(); ok && strings.TrimSpace(user) != "" {
+		ctx = context.WithValue(ctx, webdavUploaderKey{}, strings.TrimSpace(user))
+	}
+	r := c.Request.WithContext(ctx)
+
+	handler := ws.webdav
+	if ws.basePath != "" && strings.HasPrefix(r.RequestURI, ws.basePath+"/") {
+		// the copy shares the file system and locks with requests made at the root
+		proxied := *ws.webdav
+		proxied.Prefix = ws.basePath + webdavPrefix
+		handler = &proxied
+
+		u := *r.URL
+		u.Path, u.RawPath = ws.basePath+u.Path, ""
+		r.URL = &u
+	}
+	handler.ServeHTTP(c.Writer, r)
+}
+
+// webdavName returns the file name for a path in the share, which only holds files at the top
+func webdavName(name string) (string, bool) {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" || strings.Contains(name, "/") {
+		return "", false
+	}
+	return name, true
+}
+
+func isHiddenFile(name string) bool {
+	return strings.HasPrefix(name, ".")
+}
+
+func (f *webdavFS) Mkdir(ctx context.Context, name string, perm os.FileMode) error {
+	return os.ErrPermission
+}
+
+func (f *webdavFS) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (webdav.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND) == 0 {
+		return f.open(ctx, name)
+	}
+
+	fileName, ok := webdavName(name)
+	if !ok {
+		return nil, os.ErrPermission
+	}
+	if !isHiddenFile(fileName) {
+		if !util.SupportedExt.Contains(filepath.Ext(fileName)) {
+			return nil, os.ErrPermission
+		}
+		if _, err := f.photos.Stat(ctx, fileName); err == nil {
+			return nil, os.ErrExist
+		}
+	}
+
+	file, err := f.staging.OpenFile(ctx, fileName, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return &webdavUpload{File: file, fs: f, ctx: ctx, name: fileName}, nil
+}
+
+// open opens a file for reading from staging or else the photos, listing both at the top
+func (f *webdavFS) open(ctx context.Context, name string) (webdav.File, error) {
+	if path.Clean("/"+name) == "/" {
+		dir, err := f.photos.OpenFile(ctx, "/", os.O_RDONLY, 0)
+		if err != nil {
+			return nil, err
+		}
+		return &webdavDir{File: dir, staging: f.ws.paths.WebDAVDir()}, nil
+	}
+
+	fileName, ok := webdavName(name)
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	file, err := f.staging.OpenFile(ctx, fileName, os.O_RDONLY, 0)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return file, err
+	}
+	return f.photos.OpenFile(ctx, fileName, os.O_RDONLY, 0)
+}
+
+func (f *webdavFS) RemoveAll(ctx context.Context, name string) error {
+	fileName, ok := webdavName(name)
+	if !ok {
+		return os.ErrPermission
+	}
+	if _, err := f.staging.Stat(ctx, fileName); err != nil {
+		// photos are deleted through the web ui
+		return os.ErrPermission
+	}
+	return f.staging.RemoveAll(ctx, fileName)
+}
+
+// Rename only moves files still in staging, adding them as photos if they were given a photo's name
+func (f *webdavFS) Rename(ctx context.Context, oldName, newName string) error {
+	oldFileName, ok := webdavName(oldName)
+	if !ok {
+		return os.ErrPermission
+	}
+	newFileName, ok := webdavName(newName)
+	if !ok {
+		return os.ErrPermission
+	}
+	if _, err := f.staging.Stat(ctx, oldFileName); err != nil {
+		return os.ErrPermission
+	}
+	if !isHiddenFile(newFileName) && !util.SupportedExt.Contains(filepath.Ext(newFileName)) {
+		return os.ErrPermission
+	}
+
+	if err := f.staging.Rename(ctx, oldFileName, newFileName); err != nil {
+		return err
+	}
+	return f.add(ctx, newFileName)
+}
+
+func (f *webdavFS) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	if path.Clean("/"+name) == "/" {
+		return f.photos.Stat(ctx, "/")
+	}
+
+	fileName, ok := webdavName(name)
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	info, err := f.staging.Stat(ctx, fileName)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return info, err
+	}
+	info, err = f.photos.Stat(ctx, fileName)
+	if err == nil && info.IsDir() {
+		return nil, os.ErrNotExist
+	}
+	return info, err
+}
+
+// add moves a staged photo into My Photos and registers it. Hidden and empty files are left in
+// staging since clients such as Finder create a file empty before writing to it.
+func (f *webdavFS) add(ctx context.Context, name string) error {
+	if isHiddenFile(name) {
+		return nil
+	}
+	stagedPath := filepath.Join(f.ws.paths.WebDAVDir(), name)
+	info, err := os.Stat(stagedPath)
+	if err != nil {
+		return err
+	}
+	if info.Size() == 0 {
+		return nil
+	}
+
+	exists, err := f.ws.db.PhotoExists(name, paths.CategoryOriginal)
+	if err != nil {
+		return fmt.Errorf("database error, %w", err)
+	}
+	photoPath := f.ws.paths.Original(paths.CategoryOriginal, name)
+	if _, err := os.Stat(photoPath); exists || err == nil {
+		os.Remove(stagedPath)
+		return fmt.Errorf("photo with name '%s' already exists, %w", name, os.ErrExist)
+	}
+	if err := os.Rename(stagedPath, photoPath); err != nil {
+		return fmt.Errorf("failed to move photo out of staging, %w", err)
+	}
+
+	uploadedBy, _ := ctx.Value(webdavUploaderKey{}).(string)
+	if err := f.ws.photoService.Add(name, paths.CategoryOriginal, uploadedBy, false); err != nil {
+		return err
+	}
+	slog.Info("photo added over webdav", "name", name, "uploaded_by", uploadedBy)
+	f.ws.notifier.Uploaded(uploadedBy)
+	f.ws.requestRestart()
+	return nil
+}
+
+// webdavUpload is a file being written to staging which is added as a photo once closed
+type webdavUpload struct {
+	webdav.File
+	fs   *webdavFS
+	ctx  context.Context
+	name string
+}
+
+func (u *webdavUpload) Close() error {
+	if err := u.File.Close(); err != nil {
+		return err
+	}
+	return u.fs.add(u.ctx, u.name)
+}
+
+// webdavDir lists the photos along with the files in staging, leaving out the surprise photos
+// kept in a subdirectory
+type webdavDir struct {
+	webdav.File
+	staging string
+}
+
+func (d *webdavDir) Readdir(count int) ([]fs.FileInfo, error) {
+	infos, err := d.File.Readdir(count)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []fs.FileInfo
+	for _, info := range infos {
+		if !info.IsDir() {
+			files = append(files, info)
+		}
+	}
+
+	// every entry is read at once when count is not positive, which is how webdav lists a directory
+	if count <= 0 {
+		entries, err := os.ReadDir(d.staging)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			files = append(files, info)
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/aouyang1/digitalphotoframe/api/models"
+	"github.com/aouyang1/digitalphotoframe/api/web/templates"
+	"github.com/aouyang1/digitalphotoframe/service"
+	"github.com/aouyang1/digitalphotoframe/slideshow"
+	"github.com/gin-gonic/gin"
+)
+
+// handleWebSlideshowPage serves a slideshow that plays in the browser so a spare tablet or tv
+// can act as another frame
+func (ws *WebServer) handleWebSlideshowPage(c *gin.Context) {
+	templates.WebSlideshowPage().Render(c.Request.Context(), c.Writer)
+}
+
+// handleWebSlideshowPlaylist returns the playlist the frame would play with the current settings,
+// along with the interval and overlay text for the browser slideshow
+func (ws *WebServer) handleWebSlideshowPlaylist(c *gin.Context) {
+	settings, err := ws.db.GetAppSettings()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to get settings: %v", err)})
+		return
+	}
+
+	photos, err := ws.buildPlaylist(settings)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: tr(c, "Failed to build playlist: %v", err)})
+		return
+	}
+
+	if settings.ShowReactions {
+		photos = ws.withReactions(photos)
+	}
+
+	applyOverlayDefaults(settings)
+	resp := models.WebSlideshowResponse{
+		IntervalSeconds:       settings.SlideshowIntervalSeconds,
+		IntervalJitterSeconds: settings.IntervalJitterSeconds,
+		OverlayPosition:       settings.OverlayPosition,
+		OverlaySize:           settings.OverlaySize,
+		Slides:                make([]models.WebSlide, len(photos)),
+	}
+	if resp.IntervalSeconds <= 0 {
+		resp.IntervalSeconds = slideshow.DefaultInterval
+	}
+	for i, photo := range photos {
+		resp.Slides[i] = models.WebSlide{
+			ID:        photo.ID,
+			PhotoName: photo.PhotoName,
+			Category:  photo.Category,
+			ImageURL:  fmt.Sprintf("%s/photos/id/%s/image", ws.basePath, url.PathEscape(photo.ID)),
+			Overlay:   overlayText(photo, settings),
+		}
+		if settings.PlayMotion {
+			resp.Slides[i].MotionURL = ws.motionURL(photo)
+			resp.Slides[i].MotionType = service.MotionMimeTypes[photo.Motion]
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,190 @@
+// Package audio plays background music or an internet radio stream alongside the slideshow with
+// mpv, controlled over mpv's json ipc socket
+package audio
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/runner"
+)
+
+const (
+	mpvPath = "mpv"
+
+	// ipcTimeout is how long a command sent to mpv has to be answered
+	ipcTimeout = 2 * time.Second
+)
+
+// errNotListening is returned for commands sent before mpv opens its socket, such as while it's
+// starting up
+var errNotListening = errors.New("mpv is not listening")
+
+// Player runs a single mpv process playing one source at a time
+type Player struct {
+	mu sync.Mutex
+
+	// socketPath is where mpv listens for commands
+	socketPath string
+
+	// proc is the running mpv process playing source, or nil when stopped or after mpv quit
+	proc   runner.Process
+	source string
+	volume int
+}
+
+// NewPlayer returns a stopped player controlling mpv over socketPath
+func NewPlayer(socketPath string) *Player {
+	return &Player{socketPath: socketPath}
+}
+
+// Play starts playing source at volume percent on repeat, replacing whatever was playing. When
+// source is already playing only the volume is changed so a stream isn't interrupted. Source is
+// anything mpv plays, such as a music file, a directory or playlist of them, or a stream url.
+func (p *Player) Play(source string, volume int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.proc != nil && p.source == source {
+		if volume == p.volume {
+			return nil
+		}
+		return p.setVolume(volume)
+	}
+
+	// an mpv left behind by an earlier run would play over the new one
+	p.proc = nil
+	if err := killMpv(); err != nil {
+		slog.Debug("no mpv to stop before playing", "error", err)
+	}
+
+	args := []string{
+		"--no-video",
+		"--no-terminal",
+		"--loop-playlist=inf",
+		"--volume=" + strconv.Itoa(volume),
+		"--input-ipc-server=" + p.socketPath,
+		source,
+	}
+	proc, err := runner.Default().Start(mpvPath, args...)
+	if err != nil {
+		return fmt.Errorf("failed to start mpv, %w", err)
+	}
+	p.proc, p.source, p.volume = proc, source, volume
+	go p.wait(proc)
+
+	slog.Info("started background audio", "pid", proc.Pid(), "source", source, "volume", volume)
+	return nil
+}
+
+// wait forgets proc once it quits so the next Play starts it again
+func (p *Player) wait(proc runner.Process) {
+	err := proc.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.proc == proc {
+		p.proc = nil
+		slog.Warn("mpv quit while playing background audio", "source", p.source, "error", err)
+	}
+}
+
+// Stop stops playing
+func (p *Player) Stop() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.proc == nil {
+		return nil
+	}
+	p.proc = nil
+	slog.Info("stopped background audio", "source", p.source)
+	return killMpv()
+}
+
+// SetVolume changes the volume to percent, taking effect right away when mpv is listening and
+// otherwise the next time Play is called with the same volume
+func (p *Player) SetVolume(percent int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.proc == nil {
+		p.volume = percent
+		return nil
+	}
+	err := p.setVolume(percent)
+	if errors.Is(err, errNotListening) {
+		slog.Debug("mpv is starting, leaving the volume for the next play", "volume", percent)
+		return nil
+	}
+	return err
+}
+
+// Playing returns the source being played, or false when stopped
+func (p *Player) Playing() (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.source, p.proc != nil
+}
+
+// setVolume sends the volume to mpv. Callers must hold mu.
+func (p *Player) setVolume(percent int) error {
+	if err := p.command("set_property", "volume", percent); err != nil {
+		return err
+	}
+	p.volume = percent
+	slog.Info("updated background audio volume", "volume", percent)
+	return nil
+}
+
+// ipcReply is mpv's answer to a command, told apart from the events mpv also writes to the socket
+// by having no event name
+type ipcReply struct {
+	Event string `json:"event"`
+	Error string `json:"error"`
+}
+
+// command sends a command to mpv and waits for it to succeed
+func (p *Player) command(args ...any) error {
+	conn, err := net.DialTimeout("unix", p.socketPath, ipcTimeout)
+	if err != nil {
+		return fmt.Errorf("%w, %w", errNotListening, err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(ipcTimeout))
+
+	if err := json.NewEncoder(conn).Encode(map[string]any{"command": args}); err != nil {
+		return fmt.Errorf("unable to send mpv command, %w", err)
+	}
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var reply ipcReply
+		if err := json.Unmarshal(scanner.Bytes(), &reply); err != nil || reply.Event != "" {
+			continue
+		}
+		if reply.Error != "success" {
+			return fmt.Errorf("mpv command %v failed, %s", args, reply.Error)
+		}
+		return nil
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("no reply from mpv, %w", err)
+	}
+	return errors.New("mpv closed the connection without replying")
+}
+
+func killMpv() error {
+	if _, err := runner.Default().Run("pkill", "-x", "mpv"); err != nil {
+		// pkill returns error if no process found, which is fine
+		return fmt.Errorf("mpv not running or already killed, %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package audio
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/aouyang1/digitalphotoframe/runner"
+)
+
+func useFake(t *testing.T) *runner.Fake {
+	t.Helper()
+	fake := runner.NewFake()
+	previous := runner.Default()
+	runner.SetDefault(fake)
+	t.Cleanup(func() { runner.SetDefault(previous) })
+	return fake
+}
+
+func TestPlay(t *testing.T) {
+	fake := useFake(t)
+	socketPath := filepath.Join(t.TempDir(), "mpv.sock")
+	p := NewPlayer(socketPath)
+
+	if err := p.Play("https://radio.example/stream", 40); err != nil {
+		t.Fatalf("Play() error = %v", err)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("Play() ran %d commands, want 2", len(calls))
+	}
+	if calls[0].Name != "pkill" || !slices.Equal(calls[0].Args, []string{"-x", "mpv"}) {
+		t.Errorf("Play() first ran %s %v, want pkill -x mpv", calls[0].Name, calls[0].Args)
+	}
+	want := []string{
+		"--no-video",
+		"--no-terminal",
+		"--loop-playlist=inf",
+		"--volume=40",
+		"--input-ipc-server=" + socketPath,
+		"https://radio.example/stream",
+	}
+	if calls[1].Name != mpvPath || !slices.Equal(calls[1].Args, want) {
+		t.Errorf("Play() started %s %v, want %s %v", calls[1].Name, calls[1].Args, mpvPath, want)
+	}
+
+	if source, playing := p.Playing(); !playing || source != "https://radio.example/stream" {
+		t.Errorf("Playing() = %s, %v, want the stream playing", source, playing)
+	}
+
+	// the same source at the same volume is left playing
+	if err := p.Play("https://radio.example/stream", 40); err != nil {
+		t.Fatalf("Play() again error = %v", err)
+	}
+	if n := len(fake.Calls()); n != 2 {
+		t.Errorf("Play() with the same source ran %d more commands, want none", n-2)
+	}
+}
+
+func TestStop(t *testing.T) {
+	fake := useFake(t)
+	p := NewPlayer(filepath.Join(t.TempDir(), "mpv.sock"))
+
+	// stopping when nothing plays runs nothing
+	if err := p.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if calls := fake.Calls(); len(calls) != 0 {
+		t.Errorf("Stop() while stopped ran %+v, want nothing", calls)
+	}
+
+	if err := p.Play("/music", 50); err != nil {
+		t.Fatalf("Play() error = %v", err)
+	}
+	if err := p.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	calls := fake.Calls()
+	if last := calls[len(calls)-1]; last.Name != "pkill" || !slices.Equal(last.Args, []string{"-x", "mpv"}) {
+		t.Errorf("Stop() ran %s %v, want pkill -x mpv", last.Name, last.Args)
+	}
+	if _, playing := p.Playing(); playing {
+		t.Error("Playing() after Stop() = true, want false")
+	}
+}
+
+func TestSetVolumeWhileStopped(t *testing.T) {
+	fake := useFake(t)
+	p := NewPlayer(filepath.Join(t.TempDir(), "mpv.sock"))
+
+	if err := p.SetVolume(30); err != nil {
+		t.Fatalf("SetVolume() error = %v", err)
+	}
+	if err := p.Play("/music", 30); err != nil {
+		t.Fatalf("Play() error = %v", err)
+	}
+	calls := fake.Calls()
+	if last := calls[len(calls)-1]; !slices.Contains(last.Args, "--volume=30") {
+		t.Errorf("Play() started mpv with %v, want --volume=30", last.Args)
+	}
+}
+
+func TestSetVolumeBeforeMpvListens(t *testing.T) {
+	useFake(t)
+	socketPath := filepath.Join(t.TempDir(), "mpv.sock")
+	p := NewPlayer(socketPath)
+
+	if err := p.Play("/music", 40); err != nil {
+		t.Fatalf("Play() error = %v", err)
+	}
+	// mpv hasn't opened its socket yet
+	if err := p.SetVolume(60); err != nil {
+		t.Fatalf("SetVolume() before mpv listens error = %v", err)
+	}
+
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	commands := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		commands <- strings.TrimSpace(line)
+		fmt.Fprintln(conn, `{"error":"success"}`)
+	}()
+
+	// the volume is sent once mpv is listening
+	if err := p.Play("/music", 60); err != nil {
+		t.Fatalf("Play() after mpv listens error = %v", err)
+	}
+	if got, want := <-commands, `{"command":["set_property","volume",60]}`; got != want {
+		t.Errorf("Play() sent %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,94 @@
+// Package cache is a size bounded in-memory cache for served image bytes
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type Entry struct {
+	Data        []byte
+	ContentType string
+	ModTime     time.Time
+}
+
+type item struct {
+	key   string
+	entry Entry
+}
+
+// LRU evicts the least recently used entries once the total size of cached data exceeds maxBytes
+type LRU struct {
+	mu       sync.Mutex
+	maxBytes int
+	curBytes int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+func NewLRU(maxBytes int) *LRU {
+	return &LRU{
+		maxBytes: maxBytes,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (l *LRU) Get(key string) (Entry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elem, ok := l.items[key]
+	if !ok {
+		return Entry{}, false
+	}
+	l.ll.MoveToFront(elem)
+	return elem.Value.(*item).entry, true
+}
+
+// Add inserts or replaces the entry for key. Entries larger than the cache are not stored.
+func (l *LRU) Add(key string, entry Entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(entry.Data) > l.maxBytes {
+		return
+	}
+
+	if elem, ok := l.items[key]; ok {
+		l.curBytes -= len(elem.Value.(*item).entry.Data)
+		elem.Value.(*item).entry = entry
+		l.curBytes += len(entry.Data)
+		l.ll.MoveToFront(elem)
+	} else {
+		l.items[key] = l.ll.PushFront(&item{key: key, entry: entry})
+		l.curBytes += len(entry.Data)
+	}
+
+	for l.curBytes > l.maxBytes {
+		l.removeElement(l.ll.Back())
+	}
+}
+
+func (l *LRU) Remove(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if elem, ok := l.items[key]; ok {
+		l.removeElement(elem)
+	}
+}
+
+// Size returns the number of entries and total bytes currently cached
+func (l *LRU) Size() (int, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.ll.Len(), l.curBytes
+}
+
+func (l *LRU) removeElement(elem *list.Element) {
+	it := l.ll.Remove(elem).(*item)
+	delete(l.items, it.key)
+	l.curBytes -= len(it.entry.Data)
+}
@@ -0,0 +1,70 @@
+package display
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/aouyang1/digitalphotoframe/runner"
+)
+
+// backlightDir holds the kernel backlight devices of panels attached over DSI, such as the
+// official touchscreen. Other panels are dimmed over DDC/CI with ddcutil.
+var backlightDir = "/sys/class/backlight"
+
+// DisableBacklight leaves the kernel backlight alone, such as when not running on the frame so the
+// host's own screen isn't dimmed
+func DisableBacklight() {
+	backlightDir = ""
+}
+
+// ddcBrightness is the VCP feature code for a monitor's brightness
+const ddcBrightness = "10"
+
+// SetBrightness sets the panel's brightness to percent of its maximum
+func SetBrightness(percent int) error {
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("invalid brightness %d, must be between 0 and 100", percent)
+	}
+
+	if device, ok := backlightDevice(); ok {
+		return setBacklight(device, percent)
+	}
+
+	if out, err := runner.Default().Run("ddcutil", "setvcp", ddcBrightness, strconv.Itoa(percent)); err != nil {
+		return fmt.Errorf("failed to run ddcutil: %s, %w", out, err)
+	}
+	return nil
+}
+
+// backlightDevice returns the first kernel backlight device if there is one
+func backlightDevice() (string, bool) {
+	if backlightDir == "" {
+		return "", false
+	}
+	devices, err := filepath.Glob(filepath.Join(backlightDir, "*"))
+	if err != nil || len(devices) == 0 {
+		return "", false
+	}
+	return devices[0], true
+}
+
+// setBacklight scales percent to the device's range of brightness levels
+func setBacklight(device string, percent int) error {
+	out, err := os.ReadFile(filepath.Join(device, "max_brightness"))
+	if err != nil {
+		return fmt.Errorf("failed to read max brightness of %s, %w", device, err)
+	}
+	maxBrightness, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil {
+		return fmt.Errorf("invalid max brightness of %s, %w", device, err)
+	}
+
+	level := strconv.Itoa(maxBrightness * percent / 100)
+	if err := os.WriteFile(filepath.Join(device, "brightness"), []byte(level), 0o644); err != nil {
+		return fmt.Errorf("failed to set brightness of %s, %w", device, err)
+	}
+	return nil
+}
@@ -3,7 +3,10 @@ package display
 import (
 	"encoding/json"
 	"fmt"
-	"os/exec"
+	"slices"
+	"strconv"
+
+	"github.com/aouyang1/digitalphotoframe/runner"
 )
 
 const OutputName = "HDMI-A-1"
@@ -36,32 +39,71 @@ type Mode struct {
 	Current   bool    `json:"current"`
 }
 
+// String formats the mode the way wlr-randr's --mode flag takes it
+func (m Mode) String() string {
+	return fmt.Sprintf("%dx%d@%.3f", m.Width, m.Height, m.Refresh)
+}
+
 type Position struct {
 	X int `json:"x"`
 	Y int `json:"y"`
 }
 
-// GetEnabled inspects the current state of the HDMI-A-1 output using wlr-randr.
-// It returns true if the output is enabled, false if disabled.
-func GetEnabled() (bool, error) {
-	cmd := exec.Command("wlr-randr", "--output", OutputName, "--json")
-	out, err := cmd.Output()
+// CurrentMode returns the mode the output is driven at, or nil if it's off
+func (o *Output) CurrentMode() *Mode {
+	for i := range o.Modes {
+		if o.Modes[i].Current {
+			return &o.Modes[i]
+		}
+	}
+	return nil
+}
+
+// LogicalSize is the size of the screen as applications draw to it, the current mode turned by the
+// output's rotation. It's false when the output is off.
+func (o *Output) LogicalSize() (width, height int, ok bool) {
+	mode := o.CurrentMode()
+	if mode == nil {
+		return 0, 0, false
+	}
+	if o.Transform == "90" || o.Transform == "270" || o.Transform == "flipped-90" || o.Transform == "flipped-270" {
+		return mode.Height, mode.Width, true
+	}
+	return mode.Width, mode.Height, true
+}
+
+// Transforms lists the rotations the output can be set to, clockwise in degrees
+var Transforms = []string{"normal", "90", "180", "270"}
+
+// GetOutput inspects the current state of the HDMI-A-1 output using wlr-randr.
+func GetOutput() (*Output, error) {
+	out, err := runner.Default().Output("wlr-randr", "--output", OutputName, "--json")
 	if err != nil {
-		return false, fmt.Errorf("failed to run wlr-randr: %w", err)
+		return nil, fmt.Errorf("failed to run wlr-randr: %w", err)
 	}
 
 	var results []Output
 	if err := json.Unmarshal(out, &results); err != nil {
-		return false, fmt.Errorf("failed to unmarshal wlr-randr output: %w", err)
+		return nil, fmt.Errorf("failed to unmarshal wlr-randr output: %w", err)
 	}
 
 	for _, result := range results {
 		if result.Name == OutputName {
-			return result.Enabled, nil
+			return &result, nil
 		}
 	}
 
-	return false, fmt.Errorf("output %s not found", OutputName)
+	return nil, fmt.Errorf("output %s not found", OutputName)
+}
+
+// GetEnabled inspects the current state of the HDMI-A-1 output using wlr-randr.
+// It returns true if the output is enabled, false if disabled.
+func GetEnabled() (bool, error) {
+	output, err := GetOutput()
+	if err != nil {
+		return false, err
+	}
+	return output.Enabled, nil
 }
 
 // UpdateEnabled updates the enabled state of the HDMI-A-1 output using wlr-randr.
@@ -70,9 +112,46 @@ func UpdateEnabled(enabled bool) error {
 	if enabled {
 		arg = "--on"
 	}
-	cmd := exec.Command("wlr-randr", "--output", OutputName, arg)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to run wlr-randr: %w", err)
+	if out, err := runner.Default().Run("wlr-randr", "--output", OutputName, arg); err != nil {
+		return fmt.Errorf("failed to run wlr-randr: %s, %w", out, err)
+	}
+	return nil
+}
+
+// UpdateTransform rotates the HDMI-A-1 output using wlr-randr. transform must be one of
+// Transforms.
+func UpdateTransform(transform string) error {
+	if !slices.Contains(Transforms, transform) {
+		return fmt.Errorf("invalid transform %q, must be one of %v", transform, Transforms)
+	}
+	if out, err := runner.Default().Run("wlr-randr", "--output", OutputName, "--transform", transform); err != nil {
+		return fmt.Errorf("failed to run wlr-randr: %s, %w", out, err)
 	}
 	return nil
 }
+
+// UpdateMode drives the HDMI-A-1 output at mode, formatted like Mode.String, and scale using
+// wlr-randr. An empty mode leaves the resolution alone and only sets the scale.
+func UpdateMode(mode string, scale float64) error {
+	if scale <= 0 {
+		return fmt.Errorf("invalid scale %v, must be positive", scale)
+	}
+	args := []string{"--output", OutputName}
+	if mode != "" {
+		args = append(args, "--mode", mode)
+	}
+	args = append(args, "--scale", strconv.FormatFloat(scale, 'f', -1, 64))
+	if out, err := runner.Default().Run("wlr-randr", args...); err != nil {
+		return fmt.Errorf("failed to run wlr-randr: %s, %w", out, err)
+	}
+	return nil
+}
+
+// Screenshot captures what the compositor is showing on the HDMI-A-1 output as a PNG using grim
+func Screenshot() ([]byte, error) {
+	out, err := runner.Default().Output("grim", "-o", OutputName, "-t", "png", "-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to run grim: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,59 @@
+package display
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const readyPollInterval = 500 * time.Millisecond
+
+// WaitReady blocks until the compositor is accepting clients and reports the HDMI-A-1 output, so
+// imv isn't started into a graphics stack that is still coming up. It gives up after timeout,
+// returning the last reason the compositor wasn't ready.
+func WaitReady(timeout time.Duration) error {
+	start := time.Now()
+	deadline := start.Add(timeout)
+
+	var err error
+	for {
+		if err = checkReady(); err == nil {
+			slog.Info("compositor is ready", "waited", time.Since(start).Round(time.Millisecond))
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("compositor not ready after %s, %w", timeout, err)
+		}
+		slog.Debug("waiting for compositor", "error", err)
+		time.Sleep(readyPollInterval)
+	}
+}
+
+func checkReady() error {
+	if socket := waylandSocket(); socket != "" {
+		if _, err := os.Stat(socket); err != nil {
+			return fmt.Errorf("wayland socket not available, %w", err)
+		}
+	}
+	_, err := GetOutput()
+	return err
+}
+
+// waylandSocket is the path of the socket wayland clients connect to, or empty when the
+// environment doesn't say where it is
+func waylandSocket() string {
+	name := os.Getenv("WAYLAND_DISPLAY")
+	if name == "" {
+		name = "wayland-0"
+	}
+	if filepath.IsAbs(name) {
+		return name
+	}
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		return ""
+	}
+	return filepath.Join(runtimeDir, name)
+}
@@ -0,0 +1,308 @@
+package i18n
+
+// de is the German catalog
+var de = map[string]string{
+	"%d new photos from %s":                                    "%d neue Fotos von %s",
+	"%d photos were added to the frame":                        "%d Fotos wurden zum Rahmen hinzugefügt",
+	"%s added %d photos to the frame":                          "%s hat %d Fotos zum Rahmen hinzugefügt",
+	"%s added 1 photo to the frame":                            "%s hat 1 Foto zum Rahmen hinzugefügt",
+	"%s approved %s":                                           "%s hat %s freigegeben",
+	"%s changed the schedule":                                  "%s hat den Zeitplan geändert",
+	"%s changed the settings":                                  "%s hat die Einstellungen geändert",
+	"%s is already someone's name":                             "%s ist bereits der Name einer anderen Person",
+	"%s rejected %s":                                           "%s hat %s abgelehnt",
+	"1 new photo from %s":                                      "1 neues Foto von %s",
+	"1 photo was added to the frame":                           "1 Foto wurde zum Rahmen hinzugefügt",
+	"Album pairing %d deleted successfully":                    "Albumkopplung %d erfolgreich gelöscht",
+	"Album pairing %d not found":                               "Albumkopplung %d nicht gefunden",
+	"Album schedule %d deleted successfully":                   "Albenzeitplan %d erfolgreich gelöscht",
+	"Album schedule %d not found":                              "Albenzeitplan %d nicht gefunden",
+	"Announcement %d deleted successfully":                     "Ankündigung %d erfolgreich gelöscht",
+	"Announcement %d not found":                                "Ankündigung %d nicht gefunden",
+	"Approve for slideshow":                                    "Für die Diashow freigeben",
+	"Category is required":                                     "Kategorie ist erforderlich",
+	"Category must be an integer, %v":                          "Kategorie muss eine ganze Zahl sein, %v",
+	"Comments":                                                 "Kommentare",
+	"Connect":                                                  "Verbinden",
+	"Connecting to %s":                                         "Verbinde mit %s",
+	"Database error: %v":                                       "Datenbankfehler: %v",
+	"Delete photo":                                             "Foto löschen",
+	"Delete this photo?":                                       "Dieses Foto löschen?",
+	"Endpoint disabled, set DPF_ADMIN_TOKEN to enable":         "Funktion deaktiviert, zum Aktivieren DPF_ADMIN_TOKEN setzen",
+	"Endpoint disabled, set DPF_FEED_TOKEN to enable":          "Endpunkt deaktiviert, setze DPF_FEED_TOKEN, um ihn zu aktivieren",
+	"Error fetching photos: %v":                                "Fehler beim Laden der Fotos: %v",
+	"Expires in %d days":                                       "Läuft in %d Tagen ab",
+	"Expires today":                                            "Läuft heute ab",
+	"Expires tomorrow":                                         "Läuft morgen ab",
+	"Failed to add reaction: %v":                               "Reaktion konnte nicht hinzugefügt werden: %v",
+	"Failed to build feed: %v":                                 "Feed konnte nicht erstellt werden: %v",
+	"Failed to build playlist: %v":                             "Wiedergabeliste konnte nicht erstellt werden: %v",
+	"Failed to capture screenshot: %v":                         "Bildschirmfoto konnte nicht aufgenommen werden: %v",
+	"Failed to create album pairing: %v":                       "Albumkopplung konnte nicht erstellt werden: %v",
+	"Failed to create album schedule: %v":                      "Albenzeitplan konnte nicht erstellt werden: %v",
+	"Failed to create announcement: %v":                        "Ankündigung konnte nicht erstellt werden: %v",
+	"Failed to create guest link: %v":                          "Gastlink konnte nicht erstellt werden: %v",
+	"Failed to create person: %v":                              "Person konnte nicht erstellt werden: %v",
+	"Failed to create retention rule: %v":                      "Ablaufregel konnte nicht erstellt werden: %v",
+	"Failed to create seasonal rule: %v":                       "Saisonregel konnte nicht erstellt werden: %v",
+	"Failed to create share link: %v":                          "Freigabelink konnte nicht erstellt werden: %v",
+	"Failed to create special date: %v":                        "Besonderes Datum konnte nicht erstellt werden: %v",
+	"Failed to delete album pairing: %v":                       "Albumkopplung konnte nicht gelöscht werden: %v",
+	"Failed to delete album schedule: %v":                      "Albenzeitplan konnte nicht gelöscht werden: %v",
+	"Failed to delete announcement: %v":                        "Ankündigung konnte nicht gelöscht werden: %v",
+	"Failed to delete guestbook message: %v":                   "Gästebuch-Nachricht konnte nicht gelöscht werden: %v",
+	"Failed to delete person: %v":                              "Person konnte nicht gelöscht werden: %v",
+	"Failed to delete photo: %v":                               "Foto konnte nicht gelöscht werden: %v",
+	"Failed to delete reaction: %v":                            "Reaktion konnte nicht gelöscht werden: %v",
+	"Failed to delete retention rule: %v":                      "Ablaufregel konnte nicht gelöscht werden: %v",
+	"Failed to delete schedule profile: %v":                    "Zeitplanprofil konnte nicht gelöscht werden: %v",
+	"Failed to delete seasonal rule: %v":                       "Saisonregel konnte nicht gelöscht werden: %v",
+	"Failed to delete special date: %v":                        "Besonderes Datum konnte nicht gelöscht werden: %v",
+	"Failed to generate QR code":                               "QR-Code konnte nicht erzeugt werden",
+	"Failed to generate pairing token: %v":                     "Kopplungstoken konnte nicht erzeugt werden: %v",
+	"Failed to generate share token: %v":                       "Freigabetoken konnte nicht erzeugt werden: %v",
+	"Failed to generate upload token: %v":                      "Upload-Token konnte nicht erzeugt werden: %v",
+	"Failed to get album pairings: %v":                         "Albumkopplungen konnten nicht abgerufen werden: %v",
+	"Failed to get album schedules: %v":                        "Albenzeitpläne konnten nicht abgerufen werden: %v",
+	"Failed to get albums: %v":                                 "Alben konnten nicht abgerufen werden: %v",
+	"Failed to get announcements: %v":                          "Ankündigungen konnten nicht abgerufen werden: %v",
+	"Failed to get category size: %v":                          "Größe der Kategorie konnte nicht abgerufen werden: %v",
+	"Failed to get display state: %v":                          "Bildschirmstatus konnte nicht abgerufen werden: %v",
+	"Failed to get display usage: %v":                          "Bildschirmnutzung konnte nicht abgerufen werden: %v",
+	"Failed to get guestbook: %v":                              "Gästebuch konnte nicht abgerufen werden: %v",
+	"Failed to get image paths: %v":                            "Bildpfade konnten nicht abgerufen werden: %v",
+	"Failed to get people: %v":                                 "Personen konnten nicht abgerufen werden: %v",
+	"Failed to get photo count: %v":                            "Fotoanzahl konnte nicht abgerufen werden: %v",
+	"Failed to get photos for restart: %v":                     "Fotos für den Neustart konnten nicht abgerufen werden: %v",
+	"Failed to get reactions: %v":                              "Reaktionen konnten nicht abgerufen werden: %v",
+	"Failed to get retention rules: %v":                        "Ablaufregeln konnten nicht abgerufen werden: %v",
+	"Failed to get schedule profiles: %v":                      "Zeitplanprofile konnten nicht abgerufen werden: %v",
+	"Failed to get seasonal rules: %v":                         "Saisonregeln konnten nicht abgerufen werden: %v",
+	"Failed to get settings":                                   "Einstellungen konnten nicht abgerufen werden",
+	"Failed to get settings history: %v":                       "Einstellungsverlauf konnte nicht abgerufen werden: %v",
+	"Failed to get settings: %v":                               "Einstellungen konnten nicht abgerufen werden: %v",
+	"Failed to get shared albums: %v":                          "Geteilte Alben konnten nicht abgerufen werden: %v",
+	"Failed to get slideshow state: %v":                        "Status der Diashow konnte nicht abgerufen werden: %v",
+	"Failed to get special dates: %v":                          "Besondere Daten konnten nicht abgerufen werden: %v",
+	"Failed to hold slideshow: %v":                             "Diashow konnte nicht angehalten werden: %v",
+	"Failed to import archive: %v":                             "Archiv konnte nicht importiert werden: %v",
+	"Failed to insert photo into database: %v":                 "Foto konnte nicht in der Datenbank gespeichert werden: %v",
+	"Failed to look up share link":                             "Freigabelink konnte nicht gefunden werden",
+	"Failed to look up shared album: %v":                       "Geteiltes Album konnte nicht gesucht werden: %v",
+	"Failed to look up upload link":                            "Upload-Link konnte nicht gefunden werden",
+	"Failed to organize photos: %v":                            "Fotos konnten nicht organisiert werden: %v",
+	"Failed to perform %s: %v":                                 "%s konnte nicht ausgeführt werden: %v",
+	"Failed to prepare photo":                                  "Foto konnte nicht vorbereitet werden",
+	"Failed to read archive: %v":                               "Archiv konnte nicht gelesen werden: %v",
+	"Failed to read resized photo: %v":                         "Verkleinertes Foto konnte nicht gelesen werden: %v",
+	"Failed to read settings version: %v":                      "Einstellungsversion konnte nicht gelesen werden: %v",
+	"Failed to release slideshow: %v":                          "Diashow konnte nicht fortgesetzt werden: %v",
+	"Failed to render announcement: %v":                        "Ankündigung konnte nicht gerendert werden: %v",
+	"Failed to resize photo: %v":                               "Foto konnte nicht verkleinert werden: %v",
+	"Failed to restart slideshow: %v":                          "Diashow konnte nicht neu gestartet werden: %v",
+	"Failed to save schedule profile: %v":                      "Zeitplanprofil konnte nicht gespeichert werden: %v",
+	"Failed to share album: %v":                                "Album konnte nicht geteilt werden: %v",
+	"Failed to show photo: %v":                                 "Foto konnte nicht angezeigt werden: %v",
+	"Failed to sign the guestbook: %v":                         "Eintrag ins Gästebuch fehlgeschlagen: %v",
+	"Failed to stat photo file: %v":                            "Fotodatei konnte nicht gelesen werden: %v",
+	"Failed to stop sharing album: %v":                         "Teilen des Albums konnte nicht beendet werden: %v",
+	"Failed to update album schedule: %v":                      "Albenzeitplan konnte nicht aktualisiert werden: %v",
+	"Failed to update album: %v":                               "Album konnte nicht aktualisiert werden: %v",
+	"Failed to update caption: %v":                             "Bildunterschrift konnte nicht aktualisiert werden: %v",
+	"Failed to update display mode: %v":                        "Bildschirmmodus konnte nicht aktualisiert werden: %v",
+	"Failed to update display state: %v":                       "Bildschirmstatus konnte nicht geändert werden: %v",
+	"Failed to update display transform: %v":                   "Bildschirmdrehung konnte nicht aktualisiert werden: %v",
+	"Failed to update person: %v":                              "Person konnte nicht aktualisiert werden: %v",
+	"Failed to update photo: %v":                               "Foto konnte nicht aktualisiert werden: %v",
+	"Failed to update schedule: %v":                            "Zeitplan konnte nicht aktualisiert werden: %v",
+	"Failed to update settings: %v":                            "Einstellungen konnten nicht aktualisiert werden: %v",
+	"Failed to update special date: %v":                        "Besonderes Datum konnte nicht aktualisiert werden: %v",
+	"Failed to update volume: %v":                              "Lautstärke konnte nicht aktualisiert werden: %v",
+	"Frame sync failed":                                        "Synchronisierung des Rahmens fehlgeschlagen",
+	"Guestbook message %d deleted successfully":                "Gästebuch-Nachricht %d erfolgreich gelöscht",
+	"Guestbook message %d not found":                           "Gästebuch-Nachricht %d nicht gefunden",
+	"Happy Anniversary, %s!":                                   "Alles Gute zum Jahrestag, %s!",
+	"Happy Birthday, %s!":                                      "Alles Gute zum Geburtstag, %s!",
+	"Hide from slideshow":                                      "In der Diashow ausblenden",
+	"Invalid album pairing id":                                 "Ungültige Albumkopplungs-ID",
+	"Invalid album schedule id":                                "Ungültige Albenzeitplan-ID",
+	"Invalid announcement id":                                  "Ungültige Ankündigungs-ID",
+	"Invalid category":                                         "Ungültige Kategorie",
+	"Invalid category parameter":                               "Ungültiger Kategorieparameter",
+	"Invalid date format: need 12-31, got %s":                  "Ungültiges Datumsformat: 12-31 erwartet, erhalten %s",
+	"Invalid end date format: need 12-31, got %s":              "Ungültiges Enddatum: erwartet 12-31, erhalten %s",
+	"Invalid end date format: need 2006-01-02, got %s":         "Ungültiges Enddatumsformat: 2006-01-02 erwartet, erhalten %s",
+	"Invalid end time format: need 23:15, got %s":              "Ungültiges Format der Endzeit: erwartet 23:15, erhalten %s",
+	"Invalid guestbook message id":                             "Ungültige Gästebuch-Nachrichten-ID",
+	"Invalid h parameter: %v":                                  "Ungültiger Parameter h: %v",
+	"Invalid limit parameter":                                  "Ungültiger Parameter limit",
+	"Invalid offset parameter":                                 "Ungültiger Parameter offset",
+	"Invalid or missing admin token":                           "Ungültiges oder fehlendes Admin-Token",
+	"Invalid or missing feed token":                            "Ungültiges oder fehlendes Feed-Token",
+	"Invalid overlay position %s or size %s":                   "Ungültige Position %s oder Größe %s der Einblendung",
+	"Invalid page parameter":                                   "Ungültiger Parameter page",
+	"Invalid pairing url: %v":                                  "Ungültige Kopplungs-URL: %v",
+	"Invalid person id":                                        "Ungültige Personen-ID",
+	"Invalid photo name":                                       "Ungültiger Fotoname",
+	"Invalid photo name encoding":                              "Ungültige Kodierung des Fotonamens",
+	"Invalid photo of the day time format: need 23:15, got %s": "Ungültiges Zeitformat für das Foto des Tages: erwartet 23:15, erhalten %s",
+	"Invalid reaction id":                                      "Ungültige Reaktions-ID",
+	"Invalid request body: %v":                                 "Ungültiger Anfrageinhalt: %v",
+	"Invalid retention rule id":                                "Ungültige Ablaufregel-ID",
+	"Invalid seasonal rule id":                                 "Ungültige Saisonregel-ID",
+	"Invalid shared album id":                                  "Ungültige ID des geteilten Albums",
+	"Invalid show_from date format: need 2006-01-02, got %s":   "Ungültiges show_from-Datumsformat: 2006-01-02 erwartet, erhalten %s",
+	"Invalid show_until date format: need 2006-01-02, got %s":  "Ungültiges show_until-Datumsformat: 2006-01-02 erwartet, erhalten %s",
+	"Invalid since date format: need 2006-01-02, got %s":       "Ungültiges Datumsformat für since: erwartet 2006-01-02, erhalten %s",
+	"Invalid special date id":                                  "Ungültige ID für besonderes Datum",
+	"Invalid start date format: need 12-01, got %s":            "Ungültiges Startdatum: erwartet 12-01, erhalten %s",
+	"Invalid start date format: need 2006-01-02, got %s":       "Ungültiges Startdatumsformat: 2006-01-02 erwartet, erhalten %s",
+	"Invalid start time format: need 23:15, got %s":            "Ungültiges Format der Startzeit: erwartet 23:15, erhalten %s",
+	"Invalid until date format: need 2006-01-02, got %s":       "Ungültiges Datumsformat für until: erwartet 2006-01-02, erhalten %s",
+	"Invalid version id %s":                                    "Ungültige Versions-ID %s",
+	"Invalid w parameter: %v":                                  "Ungültiger Parameter w: %v",
+	"Leave a heart":                                            "Ein Herz hinterlassen",
+	"Leave a message to be shown on the photo frame, with a selfie if you like.": "Hinterlasse eine Nachricht für den Fotorahmen, gern mit einem Selfie.",
+	"My Photos":                                                  "Meine Fotos",
+	"Network name":                                               "Netzwerkname",
+	"New photos on the frame":                                    "Neue Fotos im Rahmen",
+	"No new photos this week":                                    "Keine neuen Fotos diese Woche",
+	"No photos available to start slideshow":                     "Keine Fotos zum Starten der Diashow vorhanden",
+	"No photos to show":                                          "Keine Fotos zum Anzeigen",
+	"No photos were selected":                                    "Es wurden keine Fotos ausgewählt",
+	"No remote is configured to sync with":                       "Es ist kein entferntes Ziel zum Synchronisieren konfiguriert",
+	"Password":                                                   "Passwort",
+	"Pausing the slideshow":                                      "Diashow wird angehalten",
+	"Person %d deleted successfully":                             "Person %d erfolgreich gelöscht",
+	"Person %d not found":                                        "Person %d nicht gefunden",
+	"Photo '%s' deleted successfully":                            "Foto '%s' gelöscht",
+	"Photo '%s' has no motion video":                             "Foto '%s' hat kein Bewegungsvideo",
+	"Photo '%s' in category %d not found":                        "Foto '%s' in Kategorie %d nicht gefunden",
+	"Photo '%s' in category %d not found in current playlist":    "Foto '%s' in Kategorie %d ist nicht in der aktuellen Wiedergabeliste",
+	"Photo '%s' not found":                                       "Foto '%s' nicht gefunden",
+	"Photo Frame Wifi Setup":                                     "WLAN-Einrichtung des Bilderrahmens",
+	"Photo by %s":                                                "Foto von %s",
+	"Photo file does not exist: %s":                              "Fotodatei existiert nicht: %s",
+	"Photo file not found: %s":                                   "Fotodatei nicht gefunden: %s",
+	"Photo name is required":                                     "Fotoname ist erforderlich",
+	"Photo with id '%s' not found":                               "Foto mit der ID '%s' nicht gefunden",
+	"Pick a wifi network":                                        "Wählen Sie ein WLAN aus",
+	"Pick photos to add them to the photo frame.":                "Wählen Sie Fotos aus, um sie zum Bilderrahmen hinzuzufügen.",
+	"Pick the wifi network the photo frame should use.":          "Wählen Sie das WLAN, das der Bilderrahmen verwenden soll.",
+	"Pin to every slideshow":                                     "An alle Diashows anheften",
+	"Play slideshow from this photo":                             "Diashow ab diesem Foto abspielen",
+	"Reaction %d deleted successfully":                           "Reaktion %d erfolgreich gelöscht",
+	"Reaction %d not found":                                      "Reaktion %d nicht gefunden",
+	"Rebooting":                                                  "Wird neu gestartet",
+	"Reject and hide":                                            "Ablehnen und ausblenden",
+	"Resuming the slideshow":                                     "Diashow wird fortgesetzt",
+	"Retention rule %d deleted successfully":                     "Ablaufregel %d erfolgreich gelöscht",
+	"Retention rule %d not found":                                "Ablaufregel %d nicht gefunden",
+	"Schedule profile %s deleted successfully":                   "Zeitplanprofil %s erfolgreich gelöscht",
+	"Schedule profile %s not found":                              "Zeitplanprofil %s nicht gefunden",
+	"Seasonal rule %d deleted successfully":                      "Saisonregel %d erfolgreich gelöscht",
+	"Seasonal rule %d not found":                                 "Saisonregel %d nicht gefunden",
+	"Settings version %d not found":                              "Einstellungsversion %d nicht gefunden",
+	"Share Photos":                                               "Fotos teilen",
+	"Share your photos":                                          "Teilen Sie Ihre Fotos",
+	"Shared album %d not found":                                  "Geteiltes Album %d nicht gefunden",
+	"Show in slideshow":                                          "In der Diashow zeigen",
+	"Showing the next photo":                                     "Nächstes Foto wird angezeigt",
+	"Showing the previous photo":                                 "Vorheriges Foto wird angezeigt",
+	"Shutting down":                                              "Wird heruntergefahren",
+	"Sign":                                                       "Eintragen",
+	"Sign the guestbook":                                         "Ins Gästebuch eintragen",
+	"Slideshow":                                                  "Diashow",
+	"Slideshow is held, release it before showing another photo": "Die Diashow ist angehalten, bitte zuerst fortsetzen, um ein anderes Foto anzuzeigen",
+	"Special date %d deleted successfully":                       "Besonderes Datum %d erfolgreich gelöscht",
+	"Special date %d not found":                                  "Besonderes Datum %d nicht gefunden",
+	"Stopped sharing album %d":                                   "Teilen von Album %d beendet",
+	"Surprise":                                                   "Überraschung",
+	"Surprise photos are synced from %s and would be removed by the next sync, upload them there instead": "Überraschungsfotos werden von %s synchronisiert und bei der nächsten Synchronisierung entfernt, lade sie stattdessen dort hoch",
+	"Syncing photos from %s failed: %v":                     "Synchronisierung der Fotos von %s fehlgeschlagen: %v",
+	"Thank you! Uploaded %d photos.":                        "Danke! %d Fotos hochgeladen.",
+	"Thank you! Your message will be on the frame shortly.": "Danke! Deine Nachricht erscheint gleich auf dem Rahmen.",
+	"The display does not support %dx%d":                    "Der Bildschirm unterstützt %dx%d nicht",
+	"The frame will leave setup mode now. If it can't join, the setup network will come back in a minute with the error.": "Der Rahmen verlässt jetzt den Einrichtungsmodus. Kann er sich nicht verbinden, erscheint das Einrichtungsnetz in einer Minute mit dem Fehler wieder.",
+	"This album is no longer shared":                                          "Dieses Album wird nicht mehr geteilt",
+	"This link has expired or does not exist":                                 "Dieser Link ist abgelaufen oder existiert nicht",
+	"This photo is no longer available":                                       "Dieses Foto ist nicht mehr verfügbar",
+	"This upload link has expired or does not exist":                          "Dieser Upload-Link ist abgelaufen oder existiert nicht",
+	"Turning off the photo frame":                                             "Bilderrahmen wird ausgeschaltet",
+	"Turning on the photo frame":                                              "Bilderrahmen wird eingeschaltet",
+	"Unable to fetch app settings, %v":                                        "Einstellungen konnten nicht abgerufen werden, %v",
+	"Unable to reach the shared album: %v":                                    "Geteiltes Album nicht erreichbar: %v",
+	"Unable to read your photo, try a JPEG or PNG":                            "Dein Foto konnte nicht gelesen werden, versuche es mit JPEG oder PNG",
+	"Unknown settings version kind %s":                                        "Unbekannte Art der Einstellungsversion %s",
+	"Unpin from every slideshow":                                              "Aus allen Diashows lösen",
+	"Unrecognized voice command, intent %q text %q":                           "Unbekannter Sprachbefehl, Absicht %q Text %q",
+	"Unsupported file extension: %s. Supported: .jpeg, .jpg, .png":            "Nicht unterstützte Dateiendung: %s. Unterstützt: .jpeg, .jpg, .png",
+	"Unsupported or missing file extension: %s. Supported: .jpeg, .jpg, .png": "Fehlende oder nicht unterstützte Dateiendung: %s. Unterstützt: .jpeg, .jpg, .png",
+	"Upload":                       "Hochladen",
+	"Uploaded %d of %d photos: %v": "%d von %d Fotos hochgeladen: %v",
+	"Wifi setup is only available while the setup hotspot is running": "Die WLAN-Einrichtung ist nur verfügbar, solange der Einrichtungs-Hotspot läuft",
+	"Your message":                   "Deine Nachricht",
+	"Your name":                      "Ihr Name",
+	"a heart or comment is required": "ein Herz oder Kommentar ist erforderlich",
+	"accent_color must be a hex color like %s": "accent_color muss eine Hex-Farbe wie %s sein",
+	"action must be %s or %s":                  "action muss %s oder %s sein",
+	"action must be one of %s":                 "action muss einer der folgenden Werte sein: %s",
+	"album is required":                        "Album ist erforderlich",
+	"album must be at most %d characters":      "das Album darf höchstens %d Zeichen lang sein",
+	"album_weights must be between 0 and %d for albums named with at most %d characters":  "album_weights muss zwischen 0 und %d liegen, für Alben mit Namen von höchstens %d Zeichen",
+	"audio_source is required to play audio":                                              "audio_source ist erforderlich, um Audio abzuspielen",
+	"audio_source must be an http or https url or an absolute path on the frame":          "audio_source muss eine http- oder https-URL oder ein absoluter Pfad auf dem Rahmen sein",
+	"audio_volume must be between 0 and %d":                                               "audio_volume muss zwischen 0 und %d liegen",
+	"auto_organize must be one of %s":                                                     "auto_organize muss eines von %s sein",
+	"caption must be at most %d characters":                                               "Bildunterschrift darf höchstens %d Zeichen lang sein",
+	"category must be 0 (surprise) or 1 (original)":                                       "Kategorie muss 0 (Überraschung) oder 1 (Original) sein",
+	"comment must be at most %d characters":                                               "Kommentar darf höchstens %d Zeichen lang sein",
+	"days must be among %s":                                                               "die Tage müssen aus %s stammen",
+	"days must be between 1 and %d":                                                       "days muss zwischen 1 und %d liegen",
+	"dim_percent must be between 1 and 100":                                               "dim_percent muss zwischen 1 und 100 liegen",
+	"dry_run must be true or false":                                                       "dry_run muss true oder false sein",
+	"end must not be before start":                                                        "Ende darf nicht vor dem Start liegen",
+	"expires_in_hours must be at most %d":                                                 "expires_in_hours darf höchstens %d sein",
+	"expires_in_hours must be positive":                                                   "expires_in_hours muss positiv sein",
+	"failed to refresh photos":                                                            "Fotos konnten nicht aktualisiert werden",
+	"from %s":                                                                             "von %s",
+	"guestbook_days must be between 1 and %d":                                             "guestbook_days muss zwischen 1 und %d liegen",
+	"interval_jitter_seconds must be at least 0 and less than slideshow_interval_seconds": "interval_jitter_seconds muss mindestens 0 und kleiner als slideshow_interval_seconds sein",
+	"invalid live photo video: %v":                                                        "ungültiges Live-Photo-Video: %v",
+	"invalid sidecar: %v":                                                                 "ungültige Sidecar-Datei: %v",
+	"kind must be %s or %s":                                                               "kind muss %s oder %s sein",
+	"label must be at most %d characters":                                                 "Bezeichnung darf höchstens %d Zeichen lang sein",
+	"language must be one of %s":                                                          "Sprache muss eine von %s sein",
+	"limit must be between 1 and %d":                                                      "limit muss zwischen 1 und %d liegen",
+	"message is required":                                                                 "Nachricht ist erforderlich",
+	"message must be at most %d characters":                                               "Nachricht darf höchstens %d Zeichen lang sein",
+	"minutes must be between 1 and %d":                                                    "Minuten müssen zwischen 1 und %d liegen",
+	"mode must be %s or %s":                                                               "Modus muss %s oder %s sein",
+	"name is required":                                                                    "Name ist erforderlich",
+	"name must be at most %d characters":                                                  "Name darf höchstens %d Zeichen lang sein",
+	"no file provided":                                                                    "keine Datei angegeben",
+	"not a zip archive: %v":                                                               "kein ZIP-Archiv: %v",
+	"photo with name '%s' already exists":                                                 "ein Foto mit dem Namen '%s' existiert bereits",
+	"photo_name is required":                                                              "photo_name ist erforderlich",
+	"playlist_order must be one of %s":                                                    "playlist_order muss eines von %s sein",
+	"profile name must be 1 to %d characters":                                             "der Profilname muss 1 bis %d Zeichen lang sein",
+	"scale must be between %v and %v":                                                     "scale muss zwischen %v und %v liegen",
+	"show_until must not be before show_from":                                             "show_until darf nicht vor show_from liegen",
+	"slideshow_interval_seconds must be positive":                                         "slideshow_interval_seconds muss positiv sein",
+	"slow_interval_seconds must be positive":                                              "slow_interval_seconds muss positiv sein",
+	"state must be 0 (off) or 1 (on)":                                                     "Status muss 0 (aus) oder 1 (an) sein",
+	"text is required":                                                                    "Text ist erforderlich",
+	"text must be at most %d characters on %d lines":                                      "Text darf höchstens %d Zeichen auf %d Zeilen haben",
+	"the shared bucket":                                                                   "dem geteilten Bucket",
+	"theme must be one of %s":                                                             "Design muss eines von %s sein",
+	"transform must be one of %s":                                                         "transform muss einer der folgenden Werte sein: %s",
+	"type must be %s or %s":                                                               "Typ muss %s oder %s sein",
+	"unsupported file extension: %s. Supported: .jpeg, .jpg, .png":                        "nicht unterstützte Dateiendung: %s. Unterstützt: .jpeg, .jpg, .png",
+	"unsupported live photo video extension: %s. Supported: .mov, .mp4":                   "nicht unterstützte Live-Photo-Videoerweiterung: %s. Unterstützt: .mov, .mp4",
+	"unsupported sidecar extension: %s. Supported: .json, .xmp":                           "nicht unterstützte Sidecar-Erweiterung: %s. Unterstützt: .json, .xmp",
+	"uploaded_after must be a date like 2024-06-01 or a time like 2024-06-01T15:04:05Z":  "uploaded_after muss ein Datum wie 2024-06-01 oder eine Zeit wie 2024-06-01T15:04:05Z sein",
+	"uploaded_before must be a date like 2024-06-01 or a time like 2024-06-01T15:04:05Z": "uploaded_before muss ein Datum wie 2024-06-01 oder eine Zeit wie 2024-06-01T15:04:05Z sein",
+	"volume must be between 0 and %d":              "die Lautstärke muss zwischen 0 und %d liegen",
+	"waiting for approval":                         "warten auf Freigabe",
+	"watermark_text must be at most %d characters": "watermark_text darf höchstens %d Zeichen lang sein",
+}
@@ -0,0 +1,308 @@
+package i18n
+
+// es is the Spanish catalog
+var es = map[string]string{
+	"%d new photos from %s":                                    "%d fotos nuevas de %s",
+	"%d photos were added to the frame":                        "Se añadieron %d fotos al marco",
+	"%s added %d photos to the frame":                          "%s añadió %d fotos al marco",
+	"%s added 1 photo to the frame":                            "%s añadió 1 foto al marco",
+	"%s approved %s":                                           "%s aprobó %s",
+	"%s changed the schedule":                                  "%s cambió el horario",
+	"%s changed the settings":                                  "%s cambió la configuración",
+	"%s is already someone's name":                             "%s ya es el nombre de otra persona",
+	"%s rejected %s":                                           "%s rechazó %s",
+	"1 new photo from %s":                                      "1 foto nueva de %s",
+	"1 photo was added to the frame":                           "Se añadió 1 foto al marco",
+	"Album pairing %d deleted successfully":                    "Emparejamiento de álbum %d eliminado correctamente",
+	"Album pairing %d not found":                               "Emparejamiento de álbum %d no encontrado",
+	"Album schedule %d deleted successfully":                   "Horario de álbum %d eliminado correctamente",
+	"Album schedule %d not found":                              "Horario de álbum %d no encontrado",
+	"Announcement %d deleted successfully":                     "Anuncio %d eliminado correctamente",
+	"Announcement %d not found":                                "Anuncio %d no encontrado",
+	"Approve for slideshow":                                    "Aprobar para la presentación",
+	"Category is required":                                     "La categoría es obligatoria",
+	"Category must be an integer, %v":                          "La categoría debe ser un número entero, %v",
+	"Comments":                                                 "Comentarios",
+	"Connect":                                                  "Conectar",
+	"Connecting to %s":                                         "Conectando a %s",
+	"Database error: %v":                                       "Error de base de datos: %v",
+	"Delete photo":                                             "Eliminar foto",
+	"Delete this photo?":                                       "¿Eliminar esta foto?",
+	"Endpoint disabled, set DPF_ADMIN_TOKEN to enable":         "Función desactivada, configure DPF_ADMIN_TOKEN para activarla",
+	"Endpoint disabled, set DPF_FEED_TOKEN to enable":          "Endpoint deshabilitado, configura DPF_FEED_TOKEN para habilitarlo",
+	"Error fetching photos: %v":                                "Error al obtener las fotos: %v",
+	"Expires in %d days":                                       "Caduca en %d días",
+	"Expires today":                                            "Caduca hoy",
+	"Expires tomorrow":                                         "Caduca mañana",
+	"Failed to add reaction: %v":                               "Error al añadir la reacción: %v",
+	"Failed to build feed: %v":                                 "Error al generar el feed: %v",
+	"Failed to build playlist: %v":                             "No se pudo crear la lista de reproducción: %v",
+	"Failed to capture screenshot: %v":                         "No se pudo capturar la pantalla: %v",
+	"Failed to create album pairing: %v":                       "No se pudo crear el emparejamiento de álbum: %v",
+	"Failed to create album schedule: %v":                      "No se pudo crear el horario del álbum: %v",
+	"Failed to create announcement: %v":                        "Error al crear el anuncio: %v",
+	"Failed to create guest link: %v":                          "No se pudo crear el enlace de invitado: %v",
+	"Failed to create person: %v":                              "Error al crear la persona: %v",
+	"Failed to create retention rule: %v":                      "No se pudo crear la regla de caducidad: %v",
+	"Failed to create seasonal rule: %v":                       "No se pudo crear la regla de temporada: %v",
+	"Failed to create share link: %v":                          "No se pudo crear el enlace para compartir: %v",
+	"Failed to create special date: %v":                        "Error al crear la fecha especial: %v",
+	"Failed to delete album pairing: %v":                       "No se pudo eliminar el emparejamiento de álbum: %v",
+	"Failed to delete album schedule: %v":                      "No se pudo eliminar el horario del álbum: %v",
+	"Failed to delete announcement: %v":                        "Error al eliminar el anuncio: %v",
+	"Failed to delete guestbook message: %v":                   "Error al eliminar el mensaje del libro de visitas: %v",
+	"Failed to delete person: %v":                              "Error al eliminar la persona: %v",
+	"Failed to delete photo: %v":                               "No se pudo eliminar la foto: %v",
+	"Failed to delete reaction: %v":                            "Error al eliminar la reacción: %v",
+	"Failed to delete retention rule: %v":                      "No se pudo eliminar la regla de caducidad: %v",
+	"Failed to delete schedule profile: %v":                    "No se pudo eliminar el perfil de horario: %v",
+	"Failed to delete seasonal rule: %v":                       "No se pudo eliminar la regla de temporada: %v",
+	"Failed to delete special date: %v":                        "Error al eliminar la fecha especial: %v",
+	"Failed to generate QR code":                               "No se pudo generar el código QR",
+	"Failed to generate pairing token: %v":                     "No se pudo generar el token de emparejamiento: %v",
+	"Failed to generate share token: %v":                       "No se pudo generar el token para compartir: %v",
+	"Failed to generate upload token: %v":                      "No se pudo generar el token de subida: %v",
+	"Failed to get album pairings: %v":                         "No se pudieron obtener los emparejamientos de álbumes: %v",
+	"Failed to get album schedules: %v":                        "No se pudieron obtener los horarios de álbumes: %v",
+	"Failed to get albums: %v":                                 "No se pudieron obtener los álbumes: %v",
+	"Failed to get announcements: %v":                          "Error al obtener los anuncios: %v",
+	"Failed to get category size: %v":                          "No se pudo obtener el tamaño de la categoría: %v",
+	"Failed to get display state: %v":                          "No se pudo obtener el estado de la pantalla: %v",
+	"Failed to get display usage: %v":                          "No se pudo obtener el uso de la pantalla: %v",
+	"Failed to get guestbook: %v":                              "Error al obtener el libro de visitas: %v",
+	"Failed to get image paths: %v":                            "No se pudieron obtener las rutas de las imágenes: %v",
+	"Failed to get people: %v":                                 "Error al obtener las personas: %v",
+	"Failed to get photo count: %v":                            "No se pudo obtener el número de fotos: %v",
+	"Failed to get photos for restart: %v":                     "No se pudieron obtener las fotos para reiniciar: %v",
+	"Failed to get reactions: %v":                              "Error al obtener las reacciones: %v",
+	"Failed to get retention rules: %v":                        "No se pudieron obtener las reglas de caducidad: %v",
+	"Failed to get schedule profiles: %v":                      "No se pudieron obtener los perfiles de horario: %v",
+	"Failed to get seasonal rules: %v":                         "No se pudieron obtener las reglas de temporada: %v",
+	"Failed to get settings":                                   "No se pudo obtener la configuración",
+	"Failed to get settings history: %v":                       "Error al obtener el historial de configuración: %v",
+	"Failed to get settings: %v":                               "No se pudo obtener la configuración: %v",
+	"Failed to get shared albums: %v":                          "No se pudieron obtener los álbumes compartidos: %v",
+	"Failed to get slideshow state: %v":                        "No se pudo obtener el estado de la presentación: %v",
+	"Failed to get special dates: %v":                          "Error al obtener las fechas especiales: %v",
+	"Failed to hold slideshow: %v":                             "No se pudo fijar la presentación: %v",
+	"Failed to import archive: %v":                             "No se pudo importar el archivo: %v",
+	"Failed to insert photo into database: %v":                 "No se pudo guardar la foto en la base de datos: %v",
+	"Failed to look up share link":                             "No se pudo buscar el enlace compartido",
+	"Failed to look up shared album: %v":                       "No se pudo buscar el álbum compartido: %v",
+	"Failed to look up upload link":                            "No se pudo buscar el enlace de subida",
+	"Failed to organize photos: %v":                            "No se pudieron organizar las fotos: %v",
+	"Failed to perform %s: %v":                                 "No se pudo realizar %s: %v",
+	"Failed to prepare photo":                                  "No se pudo preparar la foto",
+	"Failed to read archive: %v":                               "No se pudo leer el archivo: %v",
+	"Failed to read resized photo: %v":                         "No se pudo leer la foto redimensionada: %v",
+	"Failed to read settings version: %v":                      "Error al leer la versión de configuración: %v",
+	"Failed to release slideshow: %v":                          "No se pudo reanudar la presentación: %v",
+	"Failed to render announcement: %v":                        "Error al generar el anuncio: %v",
+	"Failed to resize photo: %v":                               "No se pudo redimensionar la foto: %v",
+	"Failed to restart slideshow: %v":                          "No se pudo reiniciar la presentación: %v",
+	"Failed to save schedule profile: %v":                      "No se pudo guardar el perfil de horario: %v",
+	"Failed to share album: %v":                                "No se pudo compartir el álbum: %v",
+	"Failed to show photo: %v":                                 "No se pudo mostrar la foto: %v",
+	"Failed to sign the guestbook: %v":                         "Error al firmar el libro de visitas: %v",
+	"Failed to stat photo file: %v":                            "No se pudo leer el archivo de la foto: %v",
+	"Failed to stop sharing album: %v":                         "No se pudo dejar de compartir el álbum: %v",
+	"Failed to update album schedule: %v":                      "No se pudo actualizar el horario del álbum: %v",
+	"Failed to update album: %v":                               "No se pudo actualizar el álbum: %v",
+	"Failed to update caption: %v":                             "No se pudo actualizar el pie de foto: %v",
+	"Failed to update display mode: %v":                        "No se pudo actualizar el modo de la pantalla: %v",
+	"Failed to update display state: %v":                       "No se pudo cambiar el estado de la pantalla: %v",
+	"Failed to update display transform: %v":                   "No se pudo actualizar la rotación de la pantalla: %v",
+	"Failed to update person: %v":                              "Error al actualizar la persona: %v",
+	"Failed to update photo: %v":                               "No se pudo actualizar la foto: %v",
+	"Failed to update schedule: %v":                            "No se pudo actualizar el horario: %v",
+	"Failed to update settings: %v":                            "No se pudo actualizar la configuración: %v",
+	"Failed to update special date: %v":                        "Error al actualizar la fecha especial: %v",
+	"Failed to update volume: %v":                              "No se pudo actualizar el volumen: %v",
+	"Frame sync failed":                                        "Falló la sincronización del marco",
+	"Guestbook message %d deleted successfully":                "Mensaje del libro de visitas %d eliminado correctamente",
+	"Guestbook message %d not found":                           "Mensaje del libro de visitas %d no encontrado",
+	"Happy Anniversary, %s!":                                   "¡Feliz aniversario, %s!",
+	"Happy Birthday, %s!":                                      "¡Feliz cumpleaños, %s!",
+	"Hide from slideshow":                                      "Ocultar de la presentación",
+	"Invalid album pairing id":                                 "Id de emparejamiento de álbum no válido",
+	"Invalid album schedule id":                                "ID de horario de álbum no válido",
+	"Invalid announcement id":                                  "ID de anuncio no válido",
+	"Invalid category":                                         "Categoría no válida",
+	"Invalid category parameter":                               "Parámetro de categoría no válido",
+	"Invalid date format: need 12-31, got %s":                  "Formato de fecha no válido: se necesita 12-31, se recibió %s",
+	"Invalid end date format: need 12-31, got %s":              "Formato de fecha de fin no válido: se necesita 12-31, se recibió %s",
+	"Invalid end date format: need 2006-01-02, got %s":         "Formato de fecha de fin no válido: se necesita 2006-01-02, se recibió %s",
+	"Invalid end time format: need 23:15, got %s":              "Formato de hora de fin no válido: se esperaba 23:15, se recibió %s",
+	"Invalid guestbook message id":                             "Id de mensaje del libro de visitas no válido",
+	"Invalid h parameter: %v":                                  "Parámetro h no válido: %v",
+	"Invalid limit parameter":                                  "Parámetro limit no válido",
+	"Invalid offset parameter":                                 "Parámetro offset no válido",
+	"Invalid or missing admin token":                           "Token de administrador no válido o ausente",
+	"Invalid or missing feed token":                            "Token de feed no válido o ausente",
+	"Invalid overlay position %s or size %s":                   "Posición %s o tamaño %s de la superposición no válidos",
+	"Invalid page parameter":                                   "Parámetro page no válido",
+	"Invalid pairing url: %v":                                  "URL de emparejamiento no válida: %v",
+	"Invalid person id":                                        "Id de persona no válido",
+	"Invalid photo name":                                       "Nombre de foto no válido",
+	"Invalid photo name encoding":                              "Codificación del nombre de la foto no válida",
+	"Invalid photo of the day time format: need 23:15, got %s": "Formato de hora de la foto del día no válido: se necesita 23:15, se recibió %s",
+	"Invalid reaction id":                                      "Id de reacción no válido",
+	"Invalid request body: %v":                                 "Cuerpo de la solicitud no válido: %v",
+	"Invalid retention rule id":                                "Id de regla de caducidad no válido",
+	"Invalid seasonal rule id":                                 "Id de regla de temporada no válido",
+	"Invalid shared album id":                                  "Id de álbum compartido no válido",
+	"Invalid show_from date format: need 2006-01-02, got %s":   "Formato de fecha show_from no válido: se necesita 2006-01-02, se recibió %s",
+	"Invalid show_until date format: need 2006-01-02, got %s":  "Formato de fecha show_until no válido: se necesita 2006-01-02, se recibió %s",
+	"Invalid since date format: need 2006-01-02, got %s":       "Formato de fecha since no válido: se esperaba 2006-01-02, se recibió %s",
+	"Invalid special date id":                                  "ID de fecha especial no válido",
+	"Invalid start date format: need 12-01, got %s":            "Formato de fecha de inicio no válido: se necesita 12-01, se recibió %s",
+	"Invalid start date format: need 2006-01-02, got %s":       "Formato de fecha de inicio no válido: se necesita 2006-01-02, se recibió %s",
+	"Invalid start time format: need 23:15, got %s":            "Formato de hora de inicio no válido: se esperaba 23:15, se recibió %s",
+	"Invalid until date format: need 2006-01-02, got %s":       "Formato de fecha until no válido: se esperaba 2006-01-02, se recibió %s",
+	"Invalid version id %s":                                    "ID de versión no válido %s",
+	"Invalid w parameter: %v":                                  "Parámetro w no válido: %v",
+	"Leave a heart":                                            "Dejar un corazón",
+	"Leave a message to be shown on the photo frame, with a selfie if you like.": "Deja un mensaje para mostrar en el marco de fotos, con un selfie si quieres.",
+	"My Photos":                                                  "Mis fotos",
+	"Network name":                                               "Nombre de la red",
+	"New photos on the frame":                                    "Fotos nuevas en el marco",
+	"No new photos this week":                                    "No hay fotos nuevas esta semana",
+	"No photos available to start slideshow":                     "No hay fotos para iniciar la presentación",
+	"No photos to show":                                          "No hay fotos para mostrar",
+	"No photos were selected":                                    "No se seleccionó ninguna foto",
+	"No remote is configured to sync with":                       "No hay ningún remoto configurado para sincronizar",
+	"Password":                                                   "Contraseña",
+	"Pausing the slideshow":                                      "Pausando la presentación",
+	"Person %d deleted successfully":                             "Persona %d eliminada correctamente",
+	"Person %d not found":                                        "Persona %d no encontrada",
+	"Photo '%s' deleted successfully":                            "Foto '%s' eliminada",
+	"Photo '%s' has no motion video":                             "La foto '%s' no tiene vídeo en movimiento",
+	"Photo '%s' in category %d not found":                        "No se encontró la foto '%s' en la categoría %d",
+	"Photo '%s' in category %d not found in current playlist":    "La foto '%s' de la categoría %d no está en la lista actual",
+	"Photo '%s' not found":                                       "No se encontró la foto '%s'",
+	"Photo Frame Wifi Setup":                                     "Configuración Wi-Fi del marco de fotos",
+	"Photo by %s":                                                "Foto de %s",
+	"Photo file does not exist: %s":                              "El archivo de la foto no existe: %s",
+	"Photo file not found: %s":                                   "No se encontró el archivo de la foto: %s",
+	"Photo name is required":                                     "El nombre de la foto es obligatorio",
+	"Photo with id '%s' not found":                               "No se encontró la foto con id '%s'",
+	"Pick a wifi network":                                        "Elija una red Wi-Fi",
+	"Pick photos to add them to the photo frame.":                "Elija fotos para añadirlas al marco de fotos.",
+	"Pick the wifi network the photo frame should use.":          "Elija la red Wi-Fi que usará el marco de fotos.",
+	"Pin to every slideshow":                                     "Anclar a todas las presentaciones",
+	"Play slideshow from this photo":                             "Reproducir la presentación desde esta foto",
+	"Reaction %d deleted successfully":                           "Reacción %d eliminada correctamente",
+	"Reaction %d not found":                                      "Reacción %d no encontrada",
+	"Rebooting":                                                  "Reiniciando",
+	"Reject and hide":                                            "Rechazar y ocultar",
+	"Resuming the slideshow":                                     "Reanudando la presentación",
+	"Retention rule %d deleted successfully":                     "Regla de caducidad %d eliminada correctamente",
+	"Retention rule %d not found":                                "Regla de caducidad %d no encontrada",
+	"Schedule profile %s deleted successfully":                   "Perfil de horario %s eliminado correctamente",
+	"Schedule profile %s not found":                              "No se encontró el perfil de horario %s",
+	"Seasonal rule %d deleted successfully":                      "Regla de temporada %d eliminada correctamente",
+	"Seasonal rule %d not found":                                 "Regla de temporada %d no encontrada",
+	"Settings version %d not found":                              "Versión de configuración %d no encontrada",
+	"Share Photos":                                               "Compartir fotos",
+	"Share your photos":                                          "Comparta sus fotos",
+	"Shared album %d not found":                                  "Álbum compartido %d no encontrado",
+	"Show in slideshow":                                          "Mostrar en la presentación",
+	"Showing the next photo":                                     "Mostrando la siguiente foto",
+	"Showing the previous photo":                                 "Mostrando la foto anterior",
+	"Shutting down":                                              "Apagando",
+	"Sign":                                                       "Firmar",
+	"Sign the guestbook":                                         "Firma el libro de visitas",
+	"Slideshow":                                                  "Presentación",
+	"Slideshow is held, release it before showing another photo": "La presentación está fijada, reanúdela antes de mostrar otra foto",
+	"Special date %d deleted successfully":                       "Fecha especial %d eliminada correctamente",
+	"Special date %d not found":                                  "Fecha especial %d no encontrada",
+	"Stopped sharing album %d":                                   "Se dejó de compartir el álbum %d",
+	"Surprise":                                                   "Sorpresa",
+	"Surprise photos are synced from %s and would be removed by the next sync, upload them there instead": "Las fotos sorpresa se sincronizan desde %s y la próxima sincronización las eliminaría, súbelas allí",
+	"Syncing photos from %s failed: %v":                     "Falló la sincronización de fotos de %s: %v",
+	"Thank you! Uploaded %d photos.":                        "¡Gracias! Se subieron %d fotos.",
+	"Thank you! Your message will be on the frame shortly.": "¡Gracias! Tu mensaje aparecerá en el marco en breve.",
+	"The display does not support %dx%d":                    "La pantalla no admite %dx%d",
+	"The frame will leave setup mode now. If it can't join, the setup network will come back in a minute with the error.": "El marco saldrá ahora del modo de configuración. Si no puede conectarse, la red de configuración volverá en un minuto con el error.",
+	"This album is no longer shared":                                          "Este álbum ya no se comparte",
+	"This link has expired or does not exist":                                 "Este enlace ha caducado o no existe",
+	"This photo is no longer available":                                       "Esta foto ya no está disponible",
+	"This upload link has expired or does not exist":                          "Este enlace de subida ha caducado o no existe",
+	"Turning off the photo frame":                                             "Apagando el marco de fotos",
+	"Turning on the photo frame":                                              "Encendiendo el marco de fotos",
+	"Unable to fetch app settings, %v":                                        "No se pudo obtener la configuración, %v",
+	"Unable to reach the shared album: %v":                                    "No se pudo acceder al álbum compartido: %v",
+	"Unable to read your photo, try a JPEG or PNG":                            "No se pudo leer tu foto, prueba con un JPEG o PNG",
+	"Unknown settings version kind %s":                                        "Tipo de versión de configuración desconocido %s",
+	"Unpin from every slideshow":                                              "Desanclar de todas las presentaciones",
+	"Unrecognized voice command, intent %q text %q":                           "Comando de voz no reconocido, intención %q texto %q",
+	"Unsupported file extension: %s. Supported: .jpeg, .jpg, .png":            "Extensión de archivo no compatible: %s. Compatibles: .jpeg, .jpg, .png",
+	"Unsupported or missing file extension: %s. Supported: .jpeg, .jpg, .png": "Extensión de archivo ausente o no compatible: %s. Compatibles: .jpeg, .jpg, .png",
+	"Upload":                       "Subir",
+	"Uploaded %d of %d photos: %v": "Se subieron %d de %d fotos: %v",
+	"Wifi setup is only available while the setup hotspot is running": "La configuración Wi-Fi solo está disponible mientras el punto de acceso de configuración está activo",
+	"Your message":                   "Tu mensaje",
+	"Your name":                      "Su nombre",
+	"a heart or comment is required": "se requiere un corazón o un comentario",
+	"accent_color must be a hex color like %s": "accent_color debe ser un color hexadecimal como %s",
+	"action must be %s or %s":                  "action debe ser %s o %s",
+	"action must be one of %s":                 "action debe ser uno de %s",
+	"album is required":                        "el álbum es obligatorio",
+	"album must be at most %d characters":      "el álbum debe tener como máximo %d caracteres",
+	"album_weights must be between 0 and %d for albums named with at most %d characters":  "album_weights debe estar entre 0 y %d para álbumes con nombres de como máximo %d caracteres",
+	"audio_source is required to play audio":                                              "audio_source es obligatorio para reproducir audio",
+	"audio_source must be an http or https url or an absolute path on the frame":          "audio_source debe ser una url http o https o una ruta absoluta en el marco",
+	"audio_volume must be between 0 and %d":                                               "audio_volume debe estar entre 0 y %d",
+	"auto_organize must be one of %s":                                                     "auto_organize debe ser uno de %s",
+	"caption must be at most %d characters":                                               "el pie de foto debe tener como máximo %d caracteres",
+	"category must be 0 (surprise) or 1 (original)":                                       "la categoría debe ser 0 (sorpresa) o 1 (original)",
+	"comment must be at most %d characters":                                               "el comentario debe tener como máximo %d caracteres",
+	"days must be among %s":                                                               "los días deben estar entre %s",
+	"days must be between 1 and %d":                                                       "days debe estar entre 1 y %d",
+	"dim_percent must be between 1 and 100":                                               "dim_percent debe estar entre 1 y 100",
+	"dry_run must be true or false":                                                       "dry_run debe ser true o false",
+	"end must not be before start":                                                        "el fin no debe ser anterior al inicio",
+	"expires_in_hours must be at most %d":                                                 "expires_in_hours debe ser como máximo %d",
+	"expires_in_hours must be positive":                                                   "expires_in_hours debe ser positivo",
+	"failed to refresh photos":                                                            "no se pudieron actualizar las fotos",
+	"from %s":                                                                             "de %s",
+	"guestbook_days must be between 1 and %d":                                             "guestbook_days debe estar entre 1 y %d",
+	"interval_jitter_seconds must be at least 0 and less than slideshow_interval_seconds": "interval_jitter_seconds debe ser al menos 0 y menor que slideshow_interval_seconds",
+	"invalid live photo video: %v":                                                        "vídeo de Live Photo no válido: %v",
+	"invalid sidecar: %v":                                                                 "archivo auxiliar no válido: %v",
+	"kind must be %s or %s":                                                               "kind debe ser %s o %s",
+	"label must be at most %d characters":                                                 "la etiqueta debe tener como máximo %d caracteres",
+	"language must be one of %s":                                                          "el idioma debe ser uno de %s",
+	"limit must be between 1 and %d":                                                      "limit debe estar entre 1 y %d",
+	"message is required":                                                                 "el mensaje es obligatorio",
+	"message must be at most %d characters":                                               "el mensaje debe tener como máximo %d caracteres",
+	"minutes must be between 1 and %d":                                                    "los minutos deben estar entre 1 y %d",
+	"mode must be %s or %s":                                                               "el modo debe ser %s o %s",
+	"name is required":                                                                    "el nombre es obligatorio",
+	"name must be at most %d characters":                                                  "el nombre debe tener como máximo %d caracteres",
+	"no file provided":                                                                    "no se proporcionó ningún archivo",
+	"not a zip archive: %v":                                                               "no es un archivo zip: %v",
+	"photo with name '%s' already exists":                                                 "ya existe una foto con el nombre '%s'",
+	"photo_name is required":                                                              "photo_name es obligatorio",
+	"playlist_order must be one of %s":                                                    "playlist_order debe ser uno de %s",
+	"profile name must be 1 to %d characters":                                             "el nombre del perfil debe tener entre 1 y %d caracteres",
+	"scale must be between %v and %v":                                                     "scale debe estar entre %v y %v",
+	"show_until must not be before show_from":                                             "show_until no debe ser anterior a show_from",
+	"slideshow_interval_seconds must be positive":                                         "slideshow_interval_seconds debe ser positivo",
+	"slow_interval_seconds must be positive":                                              "slow_interval_seconds debe ser positivo",
+	"state must be 0 (off) or 1 (on)":                                                     "el estado debe ser 0 (apagado) o 1 (encendido)",
+	"text is required":                                                                    "el texto es obligatorio",
+	"text must be at most %d characters on %d lines":                                      "el texto debe tener como máximo %d caracteres en %d líneas",
+	"the shared bucket":                                                                   "el bucket compartido",
+	"theme must be one of %s":                                                             "el tema debe ser uno de %s",
+	"transform must be one of %s":                                                         "transform debe ser uno de %s",
+	"type must be %s or %s":                                                               "el tipo debe ser %s o %s",
+	"unsupported file extension: %s. Supported: .jpeg, .jpg, .png":                        "extensión de archivo no compatible: %s. Compatibles: .jpeg, .jpg, .png",
+	"unsupported live photo video extension: %s. Supported: .mov, .mp4":                   "extensión de vídeo de Live Photo no compatible: %s. Compatibles: .mov, .mp4",
+	"unsupported sidecar extension: %s. Supported: .json, .xmp":                           "extensión de archivo auxiliar no admitida: %s. Admitidas: .json, .xmp",
+	"uploaded_after must be a date like 2024-06-01 or a time like 2024-06-01T15:04:05Z":  "uploaded_after debe ser una fecha como 2024-06-01 o una hora como 2024-06-01T15:04:05Z",
+	"uploaded_before must be a date like 2024-06-01 or a time like 2024-06-01T15:04:05Z": "uploaded_before debe ser una fecha como 2024-06-01 o una hora como 2024-06-01T15:04:05Z",
+	"volume must be between 0 and %d":              "el volumen debe estar entre 0 y %d",
+	"waiting for approval":                         "pendientes de aprobación",
+	"watermark_text must be at most %d characters": "watermark_text debe tener como máximo %d caracteres",
+}
@@ -0,0 +1,308 @@
+package i18n
+
+// fr is the French catalog
+var fr = map[string]string{
+	"%d new photos from %s":                                    "%d nouvelles photos de %s",
+	"%d photos were added to the frame":                        "%d photos ont été ajoutées au cadre",
+	"%s added %d photos to the frame":                          "%s a ajouté %d photos au cadre",
+	"%s added 1 photo to the frame":                            "%s a ajouté 1 photo au cadre",
+	"%s approved %s":                                           "%s a approuvé %s",
+	"%s changed the schedule":                                  "%s a modifié le programme",
+	"%s changed the settings":                                  "%s a modifié les paramètres",
+	"%s is already someone's name":                             "%s est déjà le nom de quelqu'un",
+	"%s rejected %s":                                           "%s a refusé %s",
+	"1 new photo from %s":                                      "1 nouvelle photo de %s",
+	"1 photo was added to the frame":                           "1 photo a été ajoutée au cadre",
+	"Album pairing %d deleted successfully":                    "Appairage d'album %d supprimé avec succès",
+	"Album pairing %d not found":                               "Appairage d'album %d introuvable",
+	"Album schedule %d deleted successfully":                   "Programmation d'album %d supprimée avec succès",
+	"Album schedule %d not found":                              "Programmation d'album %d introuvable",
+	"Announcement %d deleted successfully":                     "Annonce %d supprimée avec succès",
+	"Announcement %d not found":                                "Annonce %d introuvable",
+	"Approve for slideshow":                                    "Approuver pour le diaporama",
+	"Category is required":                                     "La catégorie est obligatoire",
+	"Category must be an integer, %v":                          "La catégorie doit être un nombre entier, %v",
+	"Comments":                                                 "Commentaires",
+	"Connect":                                                  "Se connecter",
+	"Connecting to %s":                                         "Connexion à %s",
+	"Database error: %v":                                       "Erreur de base de données : %v",
+	"Delete photo":                                             "Supprimer la photo",
+	"Delete this photo?":                                       "Supprimer cette photo ?",
+	"Endpoint disabled, set DPF_ADMIN_TOKEN to enable":         "Fonction désactivée, définissez DPF_ADMIN_TOKEN pour l'activer",
+	"Endpoint disabled, set DPF_FEED_TOKEN to enable":          "Point de terminaison désactivé, définissez DPF_FEED_TOKEN pour l'activer",
+	"Error fetching photos: %v":                                "Erreur lors du chargement des photos : %v",
+	"Expires in %d days":                                       "Expire dans %d jours",
+	"Expires today":                                            "Expire aujourd'hui",
+	"Expires tomorrow":                                         "Expire demain",
+	"Failed to add reaction: %v":                               "Échec de l'ajout de la réaction : %v",
+	"Failed to build feed: %v":                                 "Échec de la génération du flux : %v",
+	"Failed to build playlist: %v":                             "Impossible de créer la liste de lecture : %v",
+	"Failed to capture screenshot: %v":                         "Impossible de capturer l'écran : %v",
+	"Failed to create album pairing: %v":                       "Impossible de créer l'appairage d'album : %v",
+	"Failed to create album schedule: %v":                      "Impossible de créer la programmation de l'album : %v",
+	"Failed to create announcement: %v":                        "Impossible de créer l'annonce : %v",
+	"Failed to create guest link: %v":                          "Impossible de créer le lien invité : %v",
+	"Failed to create person: %v":                              "Échec de la création de la personne : %v",
+	"Failed to create retention rule: %v":                      "Impossible de créer la règle d'expiration : %v",
+	"Failed to create seasonal rule: %v":                       "Impossible de créer la règle saisonnière : %v",
+	"Failed to create share link: %v":                          "Impossible de créer le lien de partage : %v",
+	"Failed to create special date: %v":                        "Impossible de créer la date spéciale : %v",
+	"Failed to delete album pairing: %v":                       "Impossible de supprimer l'appairage d'album : %v",
+	"Failed to delete album schedule: %v":                      "Impossible de supprimer la programmation de l'album : %v",
+	"Failed to delete announcement: %v":                        "Impossible de supprimer l'annonce : %v",
+	"Failed to delete guestbook message: %v":                   "Échec de la suppression du message du livre d'or : %v",
+	"Failed to delete person: %v":                              "Échec de la suppression de la personne : %v",
+	"Failed to delete photo: %v":                               "Échec de la suppression de la photo : %v",
+	"Failed to delete reaction: %v":                            "Échec de la suppression de la réaction : %v",
+	"Failed to delete retention rule: %v":                      "Impossible de supprimer la règle d'expiration : %v",
+	"Failed to delete schedule profile: %v":                    "Impossible de supprimer le profil d'horaire : %v",
+	"Failed to delete seasonal rule: %v":                       "Impossible de supprimer la règle saisonnière : %v",
+	"Failed to delete special date: %v":                        "Impossible de supprimer la date spéciale : %v",
+	"Failed to generate QR code":                               "Impossible de générer le code QR",
+	"Failed to generate pairing token: %v":                     "Impossible de générer le jeton d'appairage : %v",
+	"Failed to generate share token: %v":                       "Impossible de générer le jeton de partage : %v",
+	"Failed to generate upload token: %v":                      "Impossible de générer le jeton d'envoi : %v",
+	"Failed to get album pairings: %v":                         "Impossible d'obtenir les appairages d'albums : %v",
+	"Failed to get album schedules: %v":                        "Impossible d'obtenir les programmations d'albums : %v",
+	"Failed to get albums: %v":                                 "Impossible d'obtenir les albums : %v",
+	"Failed to get announcements: %v":                          "Impossible de récupérer les annonces : %v",
+	"Failed to get category size: %v":                          "Impossible d'obtenir la taille de la catégorie : %v",
+	"Failed to get display state: %v":                          "Impossible d'obtenir l'état de l'écran : %v",
+	"Failed to get display usage: %v":                          "Impossible de récupérer l'utilisation de l'écran : %v",
+	"Failed to get guestbook: %v":                              "Échec de la récupération du livre d'or : %v",
+	"Failed to get image paths: %v":                            "Impossible d'obtenir les chemins des images : %v",
+	"Failed to get people: %v":                                 "Échec de la récupération des personnes : %v",
+	"Failed to get photo count: %v":                            "Impossible d'obtenir le nombre de photos : %v",
+	"Failed to get photos for restart: %v":                     "Impossible d'obtenir les photos pour le redémarrage : %v",
+	"Failed to get reactions: %v":                              "Échec de la récupération des réactions : %v",
+	"Failed to get retention rules: %v":                        "Impossible d'obtenir les règles d'expiration : %v",
+	"Failed to get schedule profiles: %v":                      "Impossible de récupérer les profils d'horaire : %v",
+	"Failed to get seasonal rules: %v":                         "Impossible d'obtenir les règles saisonnières : %v",
+	"Failed to get settings":                                   "Impossible d'obtenir les paramètres",
+	"Failed to get settings history: %v":                       "Échec de la récupération de l'historique des paramètres : %v",
+	"Failed to get settings: %v":                               "Impossible d'obtenir les paramètres : %v",
+	"Failed to get shared albums: %v":                          "Impossible d'obtenir les albums partagés : %v",
+	"Failed to get slideshow state: %v":                        "Impossible d'obtenir l'état du diaporama : %v",
+	"Failed to get special dates: %v":                          "Impossible de récupérer les dates spéciales : %v",
+	"Failed to hold slideshow: %v":                             "Impossible de figer le diaporama : %v",
+	"Failed to import archive: %v":                             "Impossible d'importer l'archive : %v",
+	"Failed to insert photo into database: %v":                 "Impossible d'enregistrer la photo dans la base de données : %v",
+	"Failed to look up share link":                             "Impossible de trouver le lien de partage",
+	"Failed to look up shared album: %v":                       "Impossible de rechercher l'album partagé : %v",
+	"Failed to look up upload link":                            "Impossible de trouver le lien d'envoi",
+	"Failed to organize photos: %v":                            "Impossible d'organiser les photos : %v",
+	"Failed to perform %s: %v":                                 "Impossible d'effectuer %s : %v",
+	"Failed to prepare photo":                                  "Impossible de préparer la photo",
+	"Failed to read archive: %v":                               "Impossible de lire l'archive : %v",
+	"Failed to read resized photo: %v":                         "Impossible de lire la photo redimensionnée : %v",
+	"Failed to read settings version: %v":                      "Échec de la lecture de la version des paramètres : %v",
+	"Failed to release slideshow: %v":                          "Impossible de reprendre le diaporama : %v",
+	"Failed to render announcement: %v":                        "Impossible de générer l'annonce : %v",
+	"Failed to resize photo: %v":                               "Impossible de redimensionner la photo : %v",
+	"Failed to restart slideshow: %v":                          "Impossible de redémarrer le diaporama : %v",
+	"Failed to save schedule profile: %v":                      "Impossible d'enregistrer le profil d'horaire : %v",
+	"Failed to share album: %v":                                "Impossible de partager l'album : %v",
+	"Failed to show photo: %v":                                 "Impossible d'afficher la photo : %v",
+	"Failed to sign the guestbook: %v":                         "Échec de la signature du livre d'or : %v",
+	"Failed to stat photo file: %v":                            "Impossible de lire le fichier photo : %v",
+	"Failed to stop sharing album: %v":                         "Impossible d'arrêter le partage de l'album : %v",
+	"Failed to update album schedule: %v":                      "Impossible de mettre à jour la programmation de l'album : %v",
+	"Failed to update album: %v":                               "Impossible de mettre à jour l'album : %v",
+	"Failed to update caption: %v":                             "Impossible de mettre à jour la légende : %v",
+	"Failed to update display mode: %v":                        "Impossible de mettre à jour le mode de l'écran : %v",
+	"Failed to update display state: %v":                       "Impossible de modifier l'état de l'écran : %v",
+	"Failed to update display transform: %v":                   "Impossible de mettre à jour la rotation de l'écran : %v",
+	"Failed to update person: %v":                              "Échec de la mise à jour de la personne : %v",
+	"Failed to update photo: %v":                               "Impossible de mettre à jour la photo : %v",
+	"Failed to update schedule: %v":                            "Impossible de mettre à jour le programme : %v",
+	"Failed to update settings: %v":                            "Impossible de mettre à jour les paramètres : %v",
+	"Failed to update special date: %v":                        "Impossible de mettre à jour la date spéciale : %v",
+	"Failed to update volume: %v":                              "Échec de la mise à jour du volume : %v",
+	"Frame sync failed":                                        "Échec de la synchronisation du cadre",
+	"Guestbook message %d deleted successfully":                "Message du livre d'or %d supprimé avec succès",
+	"Guestbook message %d not found":                           "Message du livre d'or %d introuvable",
+	"Happy Anniversary, %s!":                                   "Joyeux anniversaire de mariage, %s !",
+	"Happy Birthday, %s!":                                      "Joyeux anniversaire, %s !",
+	"Hide from slideshow":                                      "Masquer du diaporama",
+	"Invalid album pairing id":                                 "Identifiant d'appairage d'album invalide",
+	"Invalid album schedule id":                                "Identifiant de programmation d'album invalide",
+	"Invalid announcement id":                                  "ID d'annonce invalide",
+	"Invalid category":                                         "Catégorie invalide",
+	"Invalid category parameter":                               "Paramètre de catégorie invalide",
+	"Invalid date format: need 12-31, got %s":                  "Format de date invalide : 12-31 attendu, reçu %s",
+	"Invalid end date format: need 12-31, got %s":              "Format de date de fin invalide : attendu 12-31, reçu %s",
+	"Invalid end date format: need 2006-01-02, got %s":         "Format de date de fin invalide : 2006-01-02 attendu, reçu %s",
+	"Invalid end time format: need 23:15, got %s":              "Format d'heure de fin invalide : attendu 23:15, reçu %s",
+	"Invalid guestbook message id":                             "Identifiant de message du livre d'or invalide",
+	"Invalid h parameter: %v":                                  "Paramètre h invalide : %v",
+	"Invalid limit parameter":                                  "Paramètre limit invalide",
+	"Invalid offset parameter":                                 "Paramètre offset invalide",
+	"Invalid or missing admin token":                           "Jeton administrateur invalide ou manquant",
+	"Invalid or missing feed token":                            "Jeton de flux invalide ou manquant",
+	"Invalid overlay position %s or size %s":                   "Position %s ou taille %s de l'incrustation invalide",
+	"Invalid page parameter":                                   "Paramètre page invalide",
+	"Invalid pairing url: %v":                                  "URL d'appairage invalide : %v",
+	"Invalid person id":                                        "Identifiant de personne invalide",
+	"Invalid photo name":                                       "Nom de photo invalide",
+	"Invalid photo name encoding":                              "Encodage du nom de la photo invalide",
+	"Invalid photo of the day time format: need 23:15, got %s": "Format d'heure de la photo du jour invalide : attendu 23:15, reçu %s",
+	"Invalid reaction id":                                      "Identifiant de réaction invalide",
+	"Invalid request body: %v":                                 "Corps de requête invalide : %v",
+	"Invalid retention rule id":                                "Identifiant de règle d'expiration invalide",
+	"Invalid seasonal rule id":                                 "Identifiant de règle saisonnière invalide",
+	"Invalid shared album id":                                  "Identifiant d'album partagé invalide",
+	"Invalid show_from date format: need 2006-01-02, got %s":   "Format de date show_from invalide : attendu 2006-01-02, reçu %s",
+	"Invalid show_until date format: need 2006-01-02, got %s":  "Format de date show_until invalide : attendu 2006-01-02, reçu %s",
+	"Invalid since date format: need 2006-01-02, got %s":       "Format de date since invalide : attendu 2006-01-02, reçu %s",
+	"Invalid special date id":                                  "ID de date spéciale invalide",
+	"Invalid start date format: need 12-01, got %s":            "Format de date de début invalide : attendu 12-01, reçu %s",
+	"Invalid start date format: need 2006-01-02, got %s":       "Format de date de début invalide : 2006-01-02 attendu, reçu %s",
+	"Invalid start time format: need 23:15, got %s":            "Format d'heure de début invalide : attendu 23:15, reçu %s",
+	"Invalid until date format: need 2006-01-02, got %s":       "Format de date until invalide : attendu 2006-01-02, reçu %s",
+	"Invalid version id %s":                                    "Identifiant de version invalide %s",
+	"Invalid w parameter: %v":                                  "Paramètre w invalide : %v",
+	"Leave a heart":                                            "Laisser un cœur",
+	"Leave a message to be shown on the photo frame, with a selfie if you like.": "Laissez un message à afficher sur le cadre photo, avec un selfie si vous le souhaitez.",
+	"My Photos":                                                  "Mes photos",
+	"Network name":                                               "Nom du réseau",
+	"New photos on the frame":                                    "Nouvelles photos sur le cadre",
+	"No new photos this week":                                    "Aucune nouvelle photo cette semaine",
+	"No photos available to start slideshow":                     "Aucune photo disponible pour lancer le diaporama",
+	"No photos to show":                                          "Aucune photo à afficher",
+	"No photos were selected":                                    "Aucune photo sélectionnée",
+	"No remote is configured to sync with":                       "Aucun stockage distant n'est configuré pour la synchronisation",
+	"Password":                                                   "Mot de passe",
+	"Pausing the slideshow":                                      "Mise en pause du diaporama",
+	"Person %d deleted successfully":                             "Personne %d supprimée avec succès",
+	"Person %d not found":                                        "Personne %d introuvable",
+	"Photo '%s' deleted successfully":                            "Photo '%s' supprimée",
+	"Photo '%s' has no motion video":                             "La photo '%s' n'a pas de vidéo animée",
+	"Photo '%s' in category %d not found":                        "Photo '%s' introuvable dans la catégorie %d",
+	"Photo '%s' in category %d not found in current playlist":    "Photo '%s' de la catégorie %d absente de la liste de lecture",
+	"Photo '%s' not found":                                       "Photo '%s' introuvable",
+	"Photo Frame Wifi Setup":                                     "Configuration Wi-Fi du cadre photo",
+	"Photo by %s":                                                "Photo de %s",
+	"Photo file does not exist: %s":                              "Le fichier photo n'existe pas : %s",
+	"Photo file not found: %s":                                   "Fichier photo introuvable : %s",
+	"Photo name is required":                                     "Le nom de la photo est obligatoire",
+	"Photo with id '%s' not found":                               "Photo avec l'identifiant '%s' introuvable",
+	"Pick a wifi network":                                        "Choisissez un réseau Wi-Fi",
+	"Pick photos to add them to the photo frame.":                "Choisissez des photos à ajouter au cadre photo.",
+	"Pick the wifi network the photo frame should use.":          "Choisissez le réseau Wi-Fi que le cadre photo doit utiliser.",
+	"Pin to every slideshow":                                     "Épingler à tous les diaporamas",
+	"Play slideshow from this photo":                             "Lancer le diaporama à partir de cette photo",
+	"Reaction %d deleted successfully":                           "Réaction %d supprimée avec succès",
+	"Reaction %d not found":                                      "Réaction %d introuvable",
+	"Rebooting":                                                  "Redémarrage",
+	"Reject and hide":                                            "Refuser et masquer",
+	"Resuming the slideshow":                                     "Reprise du diaporama",
+	"Retention rule %d deleted successfully":                     "Règle d'expiration %d supprimée avec succès",
+	"Retention rule %d not found":                                "Règle d'expiration %d introuvable",
+	"Schedule profile %s deleted successfully":                   "Profil d'horaire %s supprimé avec succès",
+	"Schedule profile %s not found":                              "Profil d'horaire %s introuvable",
+	"Seasonal rule %d deleted successfully":                      "Règle saisonnière %d supprimée avec succès",
+	"Seasonal rule %d not found":                                 "Règle saisonnière %d introuvable",
+	"Settings version %d not found":                              "Version des paramètres %d introuvable",
+	"Share Photos":                                               "Partager des photos",
+	"Share your photos":                                          "Partagez vos photos",
+	"Shared album %d not found":                                  "Album partagé %d introuvable",
+	"Show in slideshow":                                          "Afficher dans le diaporama",
+	"Showing the next photo":                                     "Affichage de la photo suivante",
+	"Showing the previous photo":                                 "Affichage de la photo précédente",
+	"Shutting down":                                              "Arrêt en cours",
+	"Sign":                                                       "Signer",
+	"Sign the guestbook":                                         "Signez le livre d'or",
+	"Slideshow":                                                  "Diaporama",
+	"Slideshow is held, release it before showing another photo": "Le diaporama est figé, reprenez-le avant d'afficher une autre photo",
+	"Special date %d deleted successfully":                       "Date spéciale %d supprimée avec succès",
+	"Special date %d not found":                                  "Date spéciale %d introuvable",
+	"Stopped sharing album %d":                                   "Partage de l'album %d arrêté",
+	"Surprise":                                                   "Surprise",
+	"Surprise photos are synced from %s and would be removed by the next sync, upload them there instead": "Les photos surprises sont synchronisées depuis %s et seraient supprimées à la prochaine synchronisation, ajoutez-les plutôt là-bas",
+	"Syncing photos from %s failed: %v":                     "La synchronisation des photos de %s a échoué : %v",
+	"Thank you! Uploaded %d photos.":                        "Merci ! %d photos envoyées.",
+	"Thank you! Your message will be on the frame shortly.": "Merci ! Votre message sera bientôt sur le cadre.",
+	"The display does not support %dx%d":                    "L'écran ne prend pas en charge %dx%d",
+	"The frame will leave setup mode now. If it can't join, the setup network will come back in a minute with the error.": "Le cadre quitte maintenant le mode de configuration. S'il ne peut pas se connecter, le réseau de configuration reviendra dans une minute avec l'erreur.",
+	"This album is no longer shared":                                          "Cet album n'est plus partagé",
+	"This link has expired or does not exist":                                 "Ce lien a expiré ou n'existe pas",
+	"This photo is no longer available":                                       "Cette photo n'est plus disponible",
+	"This upload link has expired or does not exist":                          "Ce lien d'envoi a expiré ou n'existe pas",
+	"Turning off the photo frame":                                             "Extinction du cadre photo",
+	"Turning on the photo frame":                                              "Allumage du cadre photo",
+	"Unable to fetch app settings, %v":                                        "Impossible d'obtenir les paramètres, %v",
+	"Unable to reach the shared album: %v":                                    "Impossible d'atteindre l'album partagé : %v",
+	"Unable to read your photo, try a JPEG or PNG":                            "Impossible de lire votre photo, essayez un JPEG ou un PNG",
+	"Unknown settings version kind %s":                                        "Type de version des paramètres inconnu %s",
+	"Unpin from every slideshow":                                              "Désépingler de tous les diaporamas",
+	"Unrecognized voice command, intent %q text %q":                           "Commande vocale non reconnue, intention %q texte %q",
+	"Unsupported file extension: %s. Supported: .jpeg, .jpg, .png":            "Extension de fichier non prise en charge : %s. Prises en charge : .jpeg, .jpg, .png",
+	"Unsupported or missing file extension: %s. Supported: .jpeg, .jpg, .png": "Extension de fichier manquante ou non prise en charge : %s. Prises en charge : .jpeg, .jpg, .png",
+	"Upload":                       "Envoyer",
+	"Uploaded %d of %d photos: %v": "%d photos sur %d envoyées : %v",
+	"Wifi setup is only available while the setup hotspot is running": "La configuration Wi-Fi n'est disponible que lorsque le point d'accès de configuration est actif",
+	"Your message":                   "Votre message",
+	"Your name":                      "Votre nom",
+	"a heart or comment is required": "un cœur ou un commentaire est requis",
+	"accent_color must be a hex color like %s": "accent_color doit être une couleur hexadécimale comme %s",
+	"action must be %s or %s":                  "action doit être %s ou %s",
+	"action must be one of %s":                 "action doit être l'un des suivants : %s",
+	"album is required":                        "l'album est obligatoire",
+	"album must be at most %d characters":      "l'album doit comporter au plus %d caractères",
+	"album_weights must be between 0 and %d for albums named with at most %d characters":  "album_weights doit être compris entre 0 et %d pour des albums dont le nom comporte au plus %d caractères",
+	"audio_source is required to play audio":                                              "audio_source est requis pour lire de l'audio",
+	"audio_source must be an http or https url or an absolute path on the frame":          "audio_source doit être une url http ou https ou un chemin absolu sur le cadre",
+	"audio_volume must be between 0 and %d":                                               "audio_volume doit être compris entre 0 et %d",
+	"auto_organize must be one of %s":                                                     "auto_organize doit être l'un des suivants : %s",
+	"caption must be at most %d characters":                                               "la légende doit comporter au plus %d caractères",
+	"category must be 0 (surprise) or 1 (original)":                                       "la catégorie doit être 0 (surprise) ou 1 (original)",
+	"comment must be at most %d characters":                                               "le commentaire doit comporter au plus %d caractères",
+	"days must be among %s":                                                               "les jours doivent faire partie de %s",
+	"days must be between 1 and %d":                                                       "days doit être compris entre 1 et %d",
+	"dim_percent must be between 1 and 100":                                               "dim_percent doit être compris entre 1 et 100",
+	"dry_run must be true or false":                                                       "dry_run doit être true ou false",
+	"end must not be before start":                                                        "la fin ne doit pas précéder le début",
+	"expires_in_hours must be at most %d":                                                 "expires_in_hours doit être au plus %d",
+	"expires_in_hours must be positive":                                                   "expires_in_hours doit être positif",
+	"failed to refresh photos":                                                            "impossible d'actualiser les photos",
+	"from %s":                                                                             "de %s",
+	"guestbook_days must be between 1 and %d":                                             "guestbook_days doit être compris entre 1 et %d",
+	"interval_jitter_seconds must be at least 0 and less than slideshow_interval_seconds": "interval_jitter_seconds doit être au moins 0 et inférieur à slideshow_interval_seconds",
+	"invalid live photo video: %v":                                                        "vidéo Live Photo non valide : %v",
+	"invalid sidecar: %v":                                                                 "fichier annexe invalide : %v",
+	"kind must be %s or %s":                                                               "kind doit être %s ou %s",
+	"label must be at most %d characters":                                                 "le libellé doit comporter au plus %d caractères",
+	"language must be one of %s":                                                          "la langue doit être l'une des suivantes : %s",
+	"limit must be between 1 and %d":                                                      "limit doit être compris entre 1 et %d",
+	"message is required":                                                                 "le message est obligatoire",
+	"message must be at most %d characters":                                               "le message doit comporter au plus %d caractères",
+	"minutes must be between 1 and %d":                                                    "les minutes doivent être comprises entre 1 et %d",
+	"mode must be %s or %s":                                                               "le mode doit être %s ou %s",
+	"name is required":                                                                    "le nom est obligatoire",
+	"name must be at most %d characters":                                                  "le nom doit comporter au plus %d caractères",
+	"no file provided":                                                                    "aucun fichier fourni",
+	"not a zip archive: %v":                                                               "n'est pas une archive zip : %v",
+	"photo with name '%s' already exists":                                                 "une photo nommée '%s' existe déjà",
+	"photo_name is required":                                                              "photo_name est obligatoire",
+	"playlist_order must be one of %s":                                                    "playlist_order doit être l'un des suivants : %s",
+	"profile name must be 1 to %d characters":                                             "le nom du profil doit comporter de 1 à %d caractères",
+	"scale must be between %v and %v":                                                     "scale doit être compris entre %v et %v",
+	"show_until must not be before show_from":                                             "show_until ne doit pas être antérieur à show_from",
+	"slideshow_interval_seconds must be positive":                                         "slideshow_interval_seconds doit être positif",
+	"slow_interval_seconds must be positive":                                              "slow_interval_seconds doit être positif",
+	"state must be 0 (off) or 1 (on)":                                                     "l'état doit être 0 (éteint) ou 1 (allumé)",
+	"text is required":                                                                    "le texte est obligatoire",
+	"text must be at most %d characters on %d lines":                                      "le texte doit comporter au plus %d caractères sur %d lignes",
+	"the shared bucket":                                                                   "le bucket partagé",
+	"theme must be one of %s":                                                             "le thème doit être l'un des suivants : %s",
+	"transform must be one of %s":                                                         "transform doit être l'un des suivants : %s",
+	"type must be %s or %s":                                                               "le type doit être %s ou %s",
+	"unsupported file extension: %s. Supported: .jpeg, .jpg, .png":                        "extension de fichier non prise en charge : %s. Prises en charge : .jpeg, .jpg, .png",
+	"unsupported live photo video extension: %s. Supported: .mov, .mp4":                   "extension de vidéo Live Photo non prise en charge : %s. Prises en charge : .mov, .mp4",
+	"unsupported sidecar extension: %s. Supported: .json, .xmp":                           "extension de fichier annexe non prise en charge : %s. Prises en charge : .json, .xmp",
+	"uploaded_after must be a date like 2024-06-01 or a time like 2024-06-01T15:04:05Z":  "uploaded_after doit être une date comme 2024-06-01 ou une heure comme 2024-06-01T15:04:05Z",
+	"uploaded_before must be a date like 2024-06-01 or a time like 2024-06-01T15:04:05Z": "uploaded_before doit être une date comme 2024-06-01 ou une heure comme 2024-06-01T15:04:05Z",
+	"volume must be between 0 and %d":              "le volume doit être compris entre 0 et %d",
+	"waiting for approval":                         "en attente d'approbation",
+	"watermark_text must be at most %d characters": "watermark_text doit comporter au plus %d caractères",
+}
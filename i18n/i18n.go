@@ -0,0 +1,65 @@
+// Package i18n translates server rendered text and error messages into the configured language
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// DefaultLanguage is the language messages are written in and the fallback for missing translations
+const DefaultLanguage = "en"
+
+// catalogs map a language to its translations keyed by the english message or format string
+var catalogs = map[string]map[string]string{
+	"de": de,
+	"es": es,
+	"fr": fr,
+}
+
+type contextKey struct{}
+
+// Supported lists the available language codes
+func Supported() []string {
+	langs := []string{DefaultLanguage}
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	slices.Sort(langs)
+	return langs
+}
+
+func IsSupported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok || lang == DefaultLanguage
+}
+
+// WithLanguage returns a copy of ctx that renders messages in lang
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, contextKey{}, lang)
+}
+
+// Language returns the language of ctx, falling back to the default language
+func Language(ctx context.Context) string {
+	if lang, ok := ctx.Value(contextKey{}).(string); ok && lang != "" {
+		return lang
+	}
+	return DefaultLanguage
+}
+
+// Translate looks up msg in the catalog for lang and formats it with args. Messages without a
+// translation are formatted in english.
+func Translate(lang, msg string, args ...any) string {
+	if translated, ok := catalogs[lang][msg]; ok {
+		msg = translated
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// T translates msg into the language of ctx
+func T(ctx context.Context, msg string, args ...any) string {
+	return Translate(Language(ctx), msg, args...)
+}
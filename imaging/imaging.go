@@ -0,0 +1,243 @@
+// Package imaging decodes, resizes, and encodes photos served by the api
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rwcarlsen/goexif/exif"
+	"golang.org/x/image/draw"
+)
+
+type Fit string
+
+const (
+	// FitContain scales the image to fit entirely within the requested box
+	FitContain Fit = "contain"
+	// FitCover scales the image to fill the requested box, cropping the overflow
+	FitCover Fit = "cover"
+	// FitFill stretches the image to exactly the requested box
+	FitFill Fit = "fill"
+)
+
+const MaxDim = 4096
+
+func ParseFit(s string) (Fit, error) {
+	switch Fit(s) {
+	case "":
+		return FitContain, nil
+	case FitContain, FitCover, FitFill:
+		return Fit(s), nil
+	}
+	return "", fmt.Errorf("unsupported fit %q, must be one of contain, cover, fill", s)
+}
+
+// Decode reads the image at path using the decoder matching its extension
+func Decode(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open image, %w", err)
+	}
+	defer f.Close()
+
+	var img image.Image
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jpg", ".jpeg":
+		img, err = jpeg.Decode(f)
+	case ".png":
+		img, err = png.Decode(f)
+	default:
+		return nil, fmt.Errorf("unsupported image extension, %s", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode image, %w", err)
+	}
+	return img, nil
+}
+
+// Encode writes img in the format implied by the extension of name
+func Encode(w io.Writer, img image.Image, name string) error {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".jpg", ".jpeg":
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: 85})
+	case ".png":
+		return png.Encode(w, img)
+	}
+	return fmt.Errorf("unsupported image extension, %s", filepath.Ext(name))
+}
+
+// Resize scales img into a width x height box according to fit. A zero width or height
+// is derived from the other dimension keeping the aspect ratio. Images are never upscaled.
+func Resize(img image.Image, width, height int, fit Fit) image.Image {
+	src := img.Bounds()
+	srcW, srcH := src.Dx(), src.Dy()
+	if srcW == 0 || srcH == 0 {
+		return img
+	}
+
+	if width <= 0 && height <= 0 {
+		return img
+	}
+	if width <= 0 {
+		width = max(1, srcW*height/srcH)
+		fit = FitFill
+	}
+	if height <= 0 {
+		height = max(1, srcH*width/srcW)
+		fit = FitFill
+	}
+
+	srcRect := src
+	dstW, dstH := width, height
+	switch fit {
+	case FitContain:
+		scale := min(float64(width)/float64(srcW), float64(height)/float64(srcH))
+		dstW = max(1, int(float64(srcW)*scale))
+		dstH = max(1, int(float64(srcH)*scale))
+	case FitCover:
+		// crop the source to the aspect ratio of the box, centered
+		scale := max(float64(width)/float64(srcW), float64(height)/float64(srcH))
+		cropW := min(srcW, int(float64(width)/scale))
+		cropH := min(srcH, int(float64(height)/scale))
+		x0 := src.Min.X + (srcW-cropW)/2
+		y0 := src.Min.Y + (srcH-cropH)/2
+		srcRect = image.Rect(x0, y0, x0+cropW, y0+cropH)
+	}
+
+	// never upscale
+	if dstW >= srcRect.Dx() && dstH >= srcRect.Dy() {
+		dstW, dstH = srcRect.Dx(), srcRect.Dy()
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, srcRect, draw.Src, nil)
+	return dst
+}
+
+// Rotate rotates img clockwise by degrees, which must be a multiple of 90
+func Rotate(img image.Image, degrees int) image.Image {
+	degrees = ((degrees % 360) + 360) % 360
+	if degrees == 0 {
+		return img
+	}
+
+	src := img.Bounds()
+	w, h := src.Dx(), src.Dy()
+
+	var dst *image.RGBA
+	if degrees == 180 {
+		dst = image.NewRGBA(image.Rect(0, 0, w, h))
+	} else {
+		dst = image.NewRGBA(image.Rect(0, 0, h, w))
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := img.At(src.Min.X+x, src.Min.Y+y)
+			switch degrees {
+			case 90:
+				dst.Set(h-1-y, x, c)
+			case 180:
+				dst.Set(w-1-x, h-1-y, c)
+			case 270:
+				dst.Set(y, w-1-x, c)
+			}
+		}
+	}
+	return dst
+}
+
+// WriteFile atomically writes img to path in the format implied by its extension
+func WriteFile(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create image directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".imaging-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := Encode(tmp, img, path); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to encode image: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write image: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// ResizeFile resizes the image at srcPath and atomically writes the result to dstPath
+func ResizeFile(srcPath, dstPath string, width, height int, fit Fit) error {
+	img, err := Decode(srcPath)
+	if err != nil {
+		return err
+	}
+	return WriteFile(dstPath, Resize(img, width, height, fit))
+}
+
+// DecodeUpright reads the image at path like Decode, turned upright according to the orientation
+// in its EXIF metadata, such as for a photo taken on a phone held sideways. Mirrored orientations
+// are left as they are.
+func DecodeUpright(path string) (image.Image, error) {
+	img, err := Decode(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open image, %w", err)
+	}
+	defer f.Close()
+
+	x, err := exif.Decode(f)
+	if err != nil {
+		return img, nil
+	}
+	tag, err := x.Get(exif.Orientation)
+	if err != nil {
+		return img, nil
+	}
+	orientation, err := tag.Int(0)
+	if err != nil {
+		return img, nil
+	}
+	switch orientation {
+	case 3:
+		return Rotate(img, 180), nil
+	case 6:
+		return Rotate(img, 90), nil
+	case 8:
+		return Rotate(img, 270), nil
+	}
+	return img, nil
+}
+
+// DateTaken reads the date the photo was taken from its EXIF metadata
+func DateTaken(path string) (time.Time, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to open image, %w", err)
+	}
+	defer f.Close()
+
+	x, err := exif.Decode(f)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to decode exif, %w", err)
+	}
+	taken, err := x.DateTime()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to read exif date taken, %w", err)
+	}
+	return taken, nil
+}
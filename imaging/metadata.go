@@ -0,0 +1,194 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/rwcarlsen/goexif/exif"
+)
+
+// JPEG markers of segments holding metadata: EXIF and XMP, Photoshop and IPTC, and comments
+const (
+	jpegAPP1  = 0xE1
+	jpegAPP13 = 0xED
+	jpegCOM   = 0xFE
+	jpegSOS   = 0xDA
+	jpegEOI   = 0xD9
+)
+
+// pngMetadataChunks hold EXIF, text such as descriptions or software, and modification time
+var pngMetadataChunks = []string{"eXIf", "tEXt", "zTXt", "iTXt", "tIME"}
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// StripMetadata copies the JPEG or PNG image in r to w without metadata that could reveal where,
+// when, or with what it was taken. The image data is copied as is, and a JPEG keeps its EXIF
+// orientation so it still displays upright. The video of a motion photo, which has metadata of
+// its own, is left out.
+func StripMetadata(w io.Writer, r io.Reader, name string) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("unable to read image, %w", err)
+	}
+
+	var stripped []byte
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".jpg", ".jpeg":
+		stripped, err = stripJPEG(data)
+	case ".png":
+		stripped, err = stripPNG(data)
+	default:
+		return fmt.Errorf("unsupported image extension, %s", filepath.Ext(name))
+	}
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(stripped)
+	return err
+}
+
+func stripJPEG(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, errors.New("not a jpeg image")
+	}
+
+	if still, _, ok := SplitMotionPhoto(data); ok {
+		data = still
+	}
+
+	var segments [][]byte
+	orientation := 0
+	i := 2
+	for i < len(data) {
+		if data[i] != 0xFF || i+1 >= len(data) {
+			return nil, errors.New("malformed jpeg image")
+		}
+		marker := data[i+1]
+		switch {
+		case marker == 0xFF:
+			// fill byte before a marker
+			i++
+			continue
+		case marker == jpegSOS || marker == jpegEOI:
+			// everything from the start of the scan on is image data
+			segments = append(segments, data[i:])
+			i = len(data)
+			continue
+		case marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7):
+			// markers without a length
+			segments = append(segments, data[i:i+2])
+			i += 2
+			continue
+		}
+
+		if i+4 > len(data) {
+			return nil, errors.New("malformed jpeg image")
+		}
+		end := i + 2 + int(binary.BigEndian.Uint16(data[i+2:i+4]))
+		if end > len(data) {
+			return nil, errors.New("malformed jpeg image")
+		}
+		segment := data[i:end]
+		i = end
+
+		switch marker {
+		case jpegAPP1:
+			if payload := segment[4:]; bytes.HasPrefix(payload, []byte("Exif\x00\x00")) && orientation == 0 {
+				orientation = exifOrientation(payload)
+			}
+		case jpegAPP13, jpegCOM:
+		default:
+			segments = append(segments, segment)
+		}
+	}
+
+	out := []byte{0xFF, 0xD8}
+	// the orientation goes after the JFIF header, which has to come first
+	if len(segments) > 0 && len(segments[0]) > 1 && segments[0][1] == 0xE0 {
+		out = append(out, segments[0]...)
+		segments = segments[1:]
+	}
+	if orientation > 1 {
+		out = append(out, orientationSegment(orientation)...)
+	}
+	for _, segment := range segments {
+		out = append(out, segment...)
+	}
+	return out, nil
+}
+
+// exifOrientation reads the orientation from an EXIF block, or 0 if it has none
+func exifOrientation(payload []byte) int {
+	x, err := exif.Decode(bytes.NewReader(payload))
+	if err != nil {
+		return 0
+	}
+	tag, err := x.Get(exif.Orientation)
+	if err != nil {
+		return 0
+	}
+	orientation, err := tag.Int(0)
+	if err != nil {
+		return 0
+	}
+	return orientation
+}
+
+// orientationSegment is an APP1 segment holding EXIF with only the orientation
+func orientationSegment(orientation int) []byte {
+	var payload bytes.Buffer
+	payload.WriteString("Exif\x00\x00")
+	// big endian tiff header pointing at the first ifd right after it
+	payload.WriteString("MM\x00\x2a")
+	binary.Write(&payload, binary.BigEndian, uint32(8))
+	// a single entry: orientation, a short, one value padded to four bytes
+	binary.Write(&payload, binary.BigEndian, uint16(1))
+	binary.Write(&payload, binary.BigEndian, uint16(0x0112))
+	binary.Write(&payload, binary.BigEndian, uint16(3))
+	binary.Write(&payload, binary.BigEndian, uint32(1))
+	binary.Write(&payload, binary.BigEndian, uint16(orientation))
+	binary.Write(&payload, binary.BigEndian, uint16(0))
+	// no next ifd
+	binary.Write(&payload, binary.BigEndian, uint32(0))
+
+	segment := []byte{0xFF, jpegAPP1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(payload.Len()+2))
+	return append(segment, payload.Bytes()...)
+}
+
+func stripPNG(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, errors.New("not a png image")
+	}
+
+	out := append([]byte{}, pngSignature...)
+	i := len(pngSignature)
+	for i < len(data) {
+		// length, type, data, and crc
+		if i+8 > len(data) {
+			return nil, errors.New("malformed png image")
+		}
+		end := i + 12 + int(binary.BigEndian.Uint32(data[i:i+4]))
+		if end > len(data) || end < i {
+			return nil, errors.New("malformed png image")
+		}
+		chunkType := string(data[i+4 : i+8])
+		isMetadata := false
+		for _, t := range pngMetadataChunks {
+			if chunkType == t {
+				isMetadata = true
+				break
+			}
+		}
+		if !isMetadata {
+			out = append(out, data[i:end]...)
+		}
+		i = end
+	}
+	return out, nil
+}
@@ -0,0 +1,151 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+// Motion photos append a short MP4 video to the JPEG still. The XMP says how long the video is,
+// as a container item in newer photos and as an offset from the end of the file in older ones.
+var (
+	motionPhotoItem  = regexp.MustCompile(`<Container:Item\b[^>]*Item:Semantic="MotionPhoto"[^>]*>`)
+	motionItemLength = regexp.MustCompile(`Item:Length="(\d+)"`)
+	microVideoOffset = regexp.MustCompile(`MicroVideoOffset="(\d+)"`)
+)
+
+// motionMetadataSize is how far into a photo its XMP is looked for, which comes before the image
+// data after at most the EXIF and its thumbnail
+const motionMetadataSize = 256 << 10
+
+// motionScanSize is how much of a photo is read at a time while looking for its video
+const motionScanSize = 64 << 10
+
+// SplitMotionPhoto returns the JPEG still and the MP4 video of a motion photo, such as one taken
+// with Google's or Samsung's camera app, with ok false when data isn't a motion photo
+func SplitMotionPhoto(data []byte) (still, video []byte, ok bool) {
+	start, ok, err := MotionVideoStart(bytes.NewReader(data), int64(len(data)))
+	if err != nil || !ok {
+		return nil, nil, false
+	}
+	return data[:start], data[start:], true
+}
+
+// MotionVideoStart finds where the video of a motion photo starts in r, which holds size bytes,
+// reading it a piece at a time so a photo is never held in memory whole. ok is false when r isn't
+// a motion photo.
+func MotionVideoStart(r io.ReaderAt, size int64) (start int64, ok bool, err error) {
+	head := make([]byte, min(size, motionMetadataSize))
+	if _, err := r.ReadAt(head, 0); err != nil && err != io.EOF {
+		return 0, false, err
+	}
+	if len(head) < 4 || head[0] != 0xFF || head[1] != 0xD8 {
+		return 0, false, nil
+	}
+
+	scan, err := scanMotionPhoto(r, size)
+	if err != nil {
+		return 0, false, err
+	}
+	// every motion photo says so in its metadata, which keeps a still that happens to have the
+	// bytes of a video header in it from being split
+	if !scan.tagged {
+		return 0, false, nil
+	}
+
+	// the length in the XMP is trusted first, falling back to the first video header after the
+	// still for photos whose XMP can't be read
+	if item := motionPhotoItem.Find(head); item != nil {
+		if m := motionItemLength.FindSubmatch(item); m != nil {
+			if start := fromEnd(size, m[1]); scan.isMP4At(start) {
+				return start, true, nil
+			}
+		}
+	}
+	if m := microVideoOffset.FindSubmatch(head); m != nil {
+		if start := fromEnd(size, m[1]); scan.isMP4At(start) {
+			return start, true, nil
+		}
+	}
+	for _, start := range scan.boxes {
+		if scan.isMP4At(start) {
+			return start, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// motionScan is what a pass over a photo found of a motion photo in it
+type motionScan struct {
+	tagged bool    // the metadata says it's a motion photo
+	eoi    int64   // offset of the first end of image marker, or -1
+	boxes  []int64 // offsets of what looks like the ftyp box every MP4 starts with
+}
+
+// scanMotionPhoto reads through the size bytes of r a piece at a time. The end of each piece is
+// carried into the next so a marker split between them, along with the box size before an ftyp,
+// is still found.
+func scanMotionPhoto(r io.ReaderAt, size int64) (motionScan, error) {
+	const carry = 16
+
+	scan := motionScan{eoi: -1}
+	buf := make([]byte, carry+motionScanSize)
+	kept := 0
+	for off := int64(0); off < size; {
+		n, err := r.ReadAt(buf[kept:kept+int(min(motionScanSize, size-off))], off)
+		if err != nil && err != io.EOF {
+			return scan, fmt.Errorf("failed to read photo, %w", err)
+		}
+		if n == 0 {
+			break
+		}
+		window := buf[:kept+n]
+		base := off - int64(kept)
+
+		scan.tagged = scan.tagged || bytes.Contains(window, []byte("MotionPhoto")) ||
+			bytes.Contains(window, []byte("MicroVideo"))
+		if i := bytes.Index(window, []byte{0xFF, jpegEOI}); scan.eoi < 0 && i >= 0 {
+			scan.eoi = base + int64(i)
+		}
+		// an ftyp ending in the carried bytes was already found in the previous piece
+		for i := 0; ; {
+			j := bytes.Index(window[i:], []byte("ftyp"))
+			if j < 0 {
+				break
+			}
+			if at := i + j; at+4 > kept && at >= 4 {
+				// the box only holds a few brands, so a longer one is image data that happens to
+				// look like it
+				if box := binary.BigEndian.Uint32(window[at-4 : at]); box >= 8 && box <= 256 {
+					scan.boxes = append(scan.boxes, base+int64(at-4))
+				}
+			}
+			i += j + 1
+		}
+
+		off += int64(n)
+		kept = min(carry, len(window))
+		copy(buf, window[len(window)-kept:])
+	}
+	return scan, nil
+}
+
+// isMP4At reports whether an MP4 file starts at the offset, which is after the JPEG still with
+// the ftyp box every MP4 starts with
+func (s motionScan) isMP4At(start int64) bool {
+	return s.eoi >= 0 && s.eoi+2 <= start && slices.Contains(s.boxes, start)
+}
+
+// fromEnd is the offset of the last length bytes of a photo holding size bytes, or -1 if length
+// isn't a valid length
+func fromEnd(size int64, length []byte) int64 {
+	n, err := strconv.ParseInt(string(length), 10, 64)
+	if err != nil || n <= 0 || n >= size {
+		return -1
+	}
+	return size - n
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"github.com/aouyang1/digitalphotoframe/cache"
+	"github.com/aouyang1/digitalphotoframe/paths"
+	"github.com/aouyang1/digitalphotoframe/service"
+	"github.com/aouyang1/digitalphotoframe/store"
+)
+
+// importCommands are the subcommands that import an export from another photo library on disk,
+// for exports too large to upload to the frame comfortably, along with what they take
+var importCommands = map[string]string{
+	"import-takeout":      "takeout.zip",
+	"import-apple-photos": "EXPORT_FOLDER|export.zip",
+}
+
+// runImport adds the photos in the export given to an import subcommand to My Photos
+func runImport(rootPath, command string, args []string) error {
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	from := flags.String("from", "", "who the photos are recorded as uploaded by")
+	dryRun := flags.Bool("dry-run", false, "report what would be imported without writing anything")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: dpf %s [-from NAME] [-dry-run] %s\n", command, importCommands[command])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("one export is required")
+	}
+
+	database, err := store.NewDatabase(filepath.Join(rootPath, "photos.db"))
+	if err != nil {
+		return fmt.Errorf("failed to initialize database, %w", err)
+	}
+	defer database.Close()
+
+	photoService, err := service.NewPhotoService(database, paths.New(rootPath), cache.NewLRU(0))
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	opts := service.ImportOptions{UploadedBy: *from, DryRun: *dryRun}
+	var (
+		report    *service.ImportReport
+		importErr error
+	)
+	switch command {
+	case "import-takeout":
+		archive, closeArchive, err := service.OpenTakeout(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer closeArchive()
+		report, importErr = photoService.ImportTakeout(ctx, archive, opts)
+	case "import-apple-photos":
+		export, closeExport, err := service.OpenExport(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer closeExport()
+		report, importErr = photoService.ImportApplePhotos(ctx, export, opts)
+	}
+	if report != nil {
+		added := "added"
+		if report.DryRun {
+			added = "would add"
+		}
+		fmt.Printf("%s %d (%.1f MB), duplicates %d, unsupported %d, failed %d\n", added, report.Added, float64(report.Bytes)/(1<<20), report.Duplicates, report.Unsupported, report.Failed)
+	}
+	return importErr
+}
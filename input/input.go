@@ -0,0 +1,73 @@
+// Package input translates hardware input events such as IR remotes into slideshow actions
+package input
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const (
+	minBackoff = time.Second
+	maxBackoff = time.Minute
+)
+
+type Action string
+
+const (
+	ActionNext          Action = "next"
+	ActionPrev          Action = "prev"
+	ActionTogglePause   Action = "pause"
+	ActionToggleDisplay Action = "display"
+)
+
+// Handler is called for every action an input source produces
+type Handler func(Action)
+
+func ParseAction(s string) (Action, error) {
+	switch a := Action(strings.ToLower(strings.TrimSpace(s))); a {
+	case ActionNext, ActionPrev, ActionTogglePause, ActionToggleDisplay:
+		return a, nil
+	}
+	return "", fmt.Errorf("unknown action %q, must be one of next, prev, pause, display", s)
+}
+
+// ParseKeymap parses a comma separated list of key=action pairs such as "KEY_1=next,KEY_2=prev"
+func ParseKeymap(s string) (map[string]Action, error) {
+	keymap := make(map[string]Action)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, actionStr, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid keymap entry %q, need key=action", pair)
+		}
+		action, err := ParseAction(actionStr)
+		if err != nil {
+			return nil, err
+		}
+		keymap[strings.TrimSpace(key)] = action
+	}
+	return keymap, nil
+}
+
+// runWithBackoff repeatedly calls listen, which blocks until its input source goes away, waiting
+// with exponential backoff between attempts
+func runWithBackoff(source string, listen func() error) {
+	backoff := minBackoff
+	for {
+		start := time.Now()
+		err := listen()
+		slog.Warn("input source lost, reconnecting", "source", source, "error", err, "backoff", backoff)
+
+		// reset the backoff once a connection has been healthy for a while
+		if time.Since(start) > maxBackoff {
+			backoff = minBackoff
+		}
+		time.Sleep(backoff)
+		backoff = min(2*backoff, maxBackoff)
+	}
+}
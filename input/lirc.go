@@ -0,0 +1,93 @@
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const DefaultLIRCSocket = "/var/run/lirc/lircd"
+
+// DefaultLIRCKeymap maps common LIRC button names from panel remotes to actions
+var DefaultLIRCKeymap = map[string]Action{
+	"KEY_RIGHT":       ActionNext,
+	"KEY_NEXT":        ActionNext,
+	"KEY_FASTFORWARD": ActionNext,
+	"KEY_CHANNELUP":   ActionNext,
+	"KEY_LEFT":        ActionPrev,
+	"KEY_PREVIOUS":    ActionPrev,
+	"KEY_REWIND":      ActionPrev,
+	"KEY_CHANNELDOWN": ActionPrev,
+	"KEY_PLAY":        ActionTogglePause,
+	"KEY_PAUSE":       ActionTogglePause,
+	"KEY_PLAYPAUSE":   ActionTogglePause,
+	"KEY_OK":          ActionTogglePause,
+	"KEY_ENTER":       ActionTogglePause,
+	"KEY_POWER":       ActionToggleDisplay,
+}
+
+// LIRC reads button presses from the lircd socket and dispatches the mapped actions
+type LIRC struct {
+	socketPath string
+	keymap     map[string]Action
+	handler    Handler
+}
+
+func NewLIRC(socketPath string, keymap map[string]Action, handler Handler) *LIRC {
+	return &LIRC{
+		socketPath: socketPath,
+		keymap:     keymap,
+		handler:    handler,
+	}
+}
+
+// Run listens for button presses, reconnecting with backoff whenever lircd goes away
+func (l *LIRC) Run() {
+	runWithBackoff("lirc", l.listen)
+}
+
+func (l *LIRC) listen() error {
+	conn, err := net.Dial("unix", l.socketPath)
+	if err != nil {
+		return fmt.Errorf("unable to connect to lircd, %w", err)
+	}
+	defer conn.Close()
+
+	slog.Info("listening for ir remote events", "socket", l.socketPath)
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		key, ok := parseLIRCEvent(scanner.Text())
+		if !ok {
+			continue
+		}
+
+		action, ok := l.keymap[key]
+		if !ok {
+			slog.Debug("ignoring unmapped ir remote button", "key", key)
+			continue
+		}
+		slog.Info("ir remote button pressed", "key", key, "action", action)
+		l.handler(action)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("lircd closed the connection")
+}
+
+// parseLIRCEvent extracts the button name from a lircd broadcast line of the form
+// "<code> <repeat> <button> <remote>", ignoring repeats from a held button
+func parseLIRCEvent(line string) (string, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return "", false
+	}
+	repeat, err := strconv.ParseUint(fields[1], 16, 64)
+	if err != nil || repeat != 0 {
+		return "", false
+	}
+	return fields[2], true
+}
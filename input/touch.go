@@ -0,0 +1,168 @@
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/runner"
+)
+
+const (
+	// swipeThreshold is the minimum distance as a percentage of the screen for a swipe
+	swipeThreshold = 15.0
+	// tapThreshold is the maximum distance as a percentage of the screen for a tap
+	tapThreshold = 3.0
+	// tapMaxDuration is the longest a finger can stay down and still count as a tap
+	tapMaxDuration = 500 * time.Millisecond
+)
+
+// Touch reads touchscreen events from libinput and translates swipes and taps into actions.
+// Swiping left moves to the next image, swiping right to the previous image, and tapping
+// toggles pause.
+type Touch struct {
+	device   string
+	rotation int
+	handler  Handler
+
+	// the first finger down tracks the current gesture, any other fingers are ignored
+	tracking     bool
+	slot         string
+	startX       float64
+	startY       float64
+	lastX        float64
+	lastY        float64
+	startElapsed time.Duration
+}
+
+// NewTouch creates a touchscreen input for device such as /dev/input/event0. rotation is the
+// clockwise rotation in degrees of the displayed content relative to the panel, so swipes
+// follow the picture as the viewer sees it.
+func NewTouch(device string, rotation int, handler Handler) *Touch {
+	return &Touch{
+		device:   device,
+		rotation: rotation,
+		handler:  handler,
+	}
+}
+
+// Run listens for touch events, restarting libinput with backoff whenever it exits
+func (t *Touch) Run() {
+	runWithBackoff("touch", t.listen)
+}
+
+func (t *Touch) listen() error {
+	proc, stdout, err := runner.Default().StartWithStdout("libinput", "debug-events", "--device", t.device)
+	if err != nil {
+		return fmt.Errorf("unable to start libinput, %w", err)
+	}
+
+	slog.Info("listening for touch events", "device", t.device)
+	t.tracking = false
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		t.handleEvent(scanner.Text())
+	}
+	if err := proc.Wait(); err != nil {
+		return fmt.Errorf("libinput exited, %w", err)
+	}
+	return fmt.Errorf("libinput exited")
+}
+
+// handleEvent parses a libinput debug-events touch line such as
+// " event0   TOUCH_DOWN   +1.345s	0 (0) 50.72/42.35 (137.03/64.00mm)"
+// where the first coordinates are a percentage of the screen
+func (t *Touch) handleEvent(line string) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return
+	}
+	event := fields[1]
+	elapsed, err := time.ParseDuration(strings.TrimPrefix(fields[2], "+"))
+	if err != nil {
+		return
+	}
+
+	switch event {
+	case "TOUCH_DOWN", "TOUCH_MOTION":
+		if len(fields) < 6 {
+			return
+		}
+		slot := fields[3]
+		x, y, ok := parseTouchPosition(fields[5])
+		if !ok {
+			return
+		}
+		if event == "TOUCH_DOWN" && !t.tracking {
+			t.tracking = true
+			t.slot = slot
+			t.startX, t.startY = x, y
+			t.startElapsed = elapsed
+		}
+		if t.tracking && slot == t.slot {
+			t.lastX, t.lastY = x, y
+		}
+	case "TOUCH_UP":
+		if !t.tracking || (len(fields) > 3 && fields[3] != t.slot) {
+			return
+		}
+		t.tracking = false
+		if action, ok := t.gesture(elapsed - t.startElapsed); ok {
+			slog.Info("touch gesture detected", "action", action)
+			t.handler(action)
+		}
+	case "TOUCH_CANCEL":
+		t.tracking = false
+	}
+}
+
+// gesture classifies the finished touch into an action
+func (t *Touch) gesture(duration time.Duration) (Action, bool) {
+	dx, dy := rotateDelta(t.lastX-t.startX, t.lastY-t.startY, t.rotation)
+	dist := math.Hypot(dx, dy)
+
+	switch {
+	case dist <= tapThreshold && duration <= tapMaxDuration:
+		return ActionTogglePause, true
+	case math.Abs(dx) >= swipeThreshold && math.Abs(dx) > math.Abs(dy):
+		if dx < 0 {
+			return ActionNext, true
+		}
+		return ActionPrev, true
+	}
+	return "", false
+}
+
+// rotateDelta maps a movement on the panel into the orientation of content rotated clockwise
+// by degrees
+func rotateDelta(dx, dy float64, degrees int) (float64, float64) {
+	switch ((degrees % 360) + 360) % 360 {
+	case 90:
+		return dy, -dx
+	case 180:
+		return -dx, -dy
+	case 270:
+		return -dy, dx
+	}
+	return dx, dy
+}
+
+func parseTouchPosition(s string) (float64, float64, bool) {
+	xStr, yStr, ok := strings.Cut(s, "/")
+	if !ok {
+		return 0, 0, false
+	}
+	x, err := strconv.ParseFloat(xStr, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	y, err := strconv.ParseFloat(yStr, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return x, y, true
+}
@@ -5,11 +5,20 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/aouyang1/digitalphotoframe/api"
-	"github.com/aouyang1/digitalphotoframe/slideshow"
+	"github.com/aouyang1/digitalphotoframe/display"
+	"github.com/aouyang1/digitalphotoframe/runner"
 	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/aouyang1/digitalphotoframe/thermal"
+)
+
+const (
+	defaultPort = "80"
+
+	defaultReadyTimeout = 60 * time.Second
 )
 
 func main() {
@@ -19,6 +28,28 @@ func main() {
 		log.Fatal("DPF_ROOT_PATH environment variable is required")
 	}
 
+	if len(os.Args) > 1 && importCommands[os.Args[1]] != "" {
+		if err := runImport(rootPath, os.Args[1], os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// simulate the display and slideshow so the server can run without a screen, imv, or imgp
+	simulate := os.Getenv("DPF_SIMULATE") == "1"
+	if simulate {
+		slog.Info("DPF_SIMULATE enabled, simulating the display and slideshow")
+		sim := runner.NewSimulator()
+		runner.SetDefault(sim)
+		display.DisableBacklight()
+		thermal.Disable()
+	}
+
+	port := os.Getenv("DPF_PORT")
+	if port == "" {
+		port = defaultPort
+	}
+
 	// Initialize database
 	dbPath := filepath.Join(rootPath, "photos.db")
 	database, err := store.NewDatabase(dbPath)
@@ -30,24 +61,32 @@ func main() {
 	// Initialize and start web server
 	webServer := api.NewWebServer(database, rootPath)
 
-	// wait for graphics to load up. maybe there's something we can check on the pi to ensure
-	// the graphics hardware is ready before starting imv
-	time.Sleep(5 * time.Second)
+	// wait for the compositor to come up before starting imv, starting anyway if it takes too
+	// long since imv is restarted if it quits
+	if !simulate {
+		if err := display.WaitReady(readyTimeout()); err != nil {
+			slog.Warn("starting slideshow before the compositor is ready", "error", err)
+		}
+	}
 
 	// Start slideshow
-	imgPaths, err := webServer.GetImgPaths()
-	if err != nil {
-		log.Fatalf("Failed to get image paths: %v", err)
+	if err := webServer.RestartSlideshow(); err != nil {
+		slog.Warn("Failed to start slideshow on initialization, continuing", "error", err)
 	}
 
-	settings, err := webServer.GetAppSettings()
-	if err != nil {
-		log.Fatalf("error while getting settings, %v", err)
-	}
+	webServer.Start("0.0.0.0:" + port)
+}
 
-	if err := slideshow.RestartSlideshow(imgPaths, settings.SlideshowIntervalSeconds); err != nil {
-		slog.Warn("Failed to start slideshow on initialization, continuing", "error", err)
+// readyTimeout is how long to wait for the compositor at startup, from DPF_READY_TIMEOUT_SECONDS
+func readyTimeout() time.Duration {
+	timeoutStr := os.Getenv("DPF_READY_TIMEOUT_SECONDS")
+	if timeoutStr == "" {
+		return defaultReadyTimeout
 	}
-
-	webServer.Start("0.0.0.0:80")
+	seconds, err := strconv.Atoi(timeoutStr)
+	if err != nil || seconds <= 0 {
+		slog.Warn("unable to parse DPF_READY_TIMEOUT_SECONDS, using default", "DPF_READY_TIMEOUT_SECONDS", timeoutStr, "default", defaultReadyTimeout)
+		return defaultReadyTimeout
+	}
+	return time.Duration(seconds) * time.Second
 }
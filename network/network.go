@@ -0,0 +1,178 @@
+// Package network manages wifi connections and the setup hotspot through NetworkManager's nmcli
+package network
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/aouyang1/digitalphotoframe/runner"
+)
+
+// SetupConnectionName is the NetworkManager connection used for the setup access point
+const SetupConnectionName = "dpf-setup"
+
+type WifiNetwork struct {
+	SSID     string `json:"ssid"`
+	Signal   int    `json:"signal"`
+	Security string `json:"security"`
+}
+
+// Connected reports whether NetworkManager has an active connection beyond the local link
+func Connected() (bool, error) {
+	out, err := runner.Default().Output("nmcli", "-t", "-f", "STATE", "general")
+	if err != nil {
+		return false, fmt.Errorf("failed to run nmcli: %w", err)
+	}
+	return strings.TrimSpace(string(out)) == "connected", nil
+}
+
+// Connectivity asks NetworkManager to check internet reachability, returning one of full,
+// limited, portal, none, or unknown
+func Connectivity() (string, error) {
+	out, err := runner.Default().Output("nmcli", "networking", "connectivity", "check")
+	if err != nil {
+		return "unknown", fmt.Errorf("failed to check connectivity: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ActiveWifi returns the wifi network ifname is currently connected to, or nil if it isn't
+// connected
+func ActiveWifi(ifname string) (*WifiNetwork, error) {
+	out, err := runner.Default().Output("nmcli", "-t", "-f", "ACTIVE,SSID,SIGNAL,SECURITY", "device", "wifi", "list", "ifname", ifname, "--rescan", "no")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wifi networks: %w", err)
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := splitTerse(line)
+		if len(fields) < 4 || fields[0] != "yes" {
+			continue
+		}
+		signal, _ := strconv.Atoi(fields[2])
+		return &WifiNetwork{
+			SSID:     fields[1],
+			Signal:   signal,
+			Security: fields[3],
+		}, nil
+	}
+	return nil, nil
+}
+
+// IPAddresses lists the addresses of every interface that is up, excluding loopback
+func IPAddresses() ([]string, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network interfaces: %w", err)
+	}
+
+	var ips []string
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLinkLocalUnicast() {
+				ips = append(ips, ipNet.IP.String())
+			}
+		}
+	}
+	return ips, nil
+}
+
+// ScanWifi lists the wifi networks visible from ifname, strongest first as reported by nmcli
+func ScanWifi(ifname string) ([]WifiNetwork, error) {
+	out, err := runner.Default().Output("nmcli", "-t", "-f", "SSID,SIGNAL,SECURITY", "device", "wifi", "list", "ifname", ifname, "--rescan", "yes")
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan wifi networks: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var networks []WifiNetwork
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := splitTerse(line)
+		if len(fields) < 3 || fields[0] == "" || seen[fields[0]] {
+			continue
+		}
+		seen[fields[0]] = true
+
+		signal, _ := strconv.Atoi(fields[1])
+		networks = append(networks, WifiNetwork{
+			SSID:     fields[0],
+			Signal:   signal,
+			Security: fields[2],
+		})
+	}
+	return networks, nil
+}
+
+// ConnectWifi joins the wifi network on ifname, saving it so NetworkManager reconnects on boot
+func ConnectWifi(ifname, ssid, password string) error {
+	args := []string{"device", "wifi", "connect", ssid, "ifname", ifname}
+	if password != "" {
+		args = append(args, "password", password)
+	}
+	if out, err := runner.Default().Run("nmcli", args...); err != nil {
+		return fmt.Errorf("failed to connect to %s, %s, %w", ssid, strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
+// StartHotspot brings up an open access point on ifname with NetworkManager handing out
+// addresses to connected clients
+func StartHotspot(ifname, ssid string) error {
+	// recreate the connection so changes to the ssid or interface take effect
+	runner.Default().Run("nmcli", "connection", "delete", SetupConnectionName)
+
+	if out, err := runner.Default().Run("nmcli", "connection", "add",
+		"type", "wifi",
+		"ifname", ifname,
+		"con-name", SetupConnectionName,
+		"autoconnect", "no",
+		"ssid", ssid,
+		"802-11-wireless.mode", "ap",
+		"ipv4.method", "shared",
+	); err != nil {
+		return fmt.Errorf("failed to create setup hotspot, %s, %w", strings.TrimSpace(string(out)), err)
+	}
+
+	if out, err := runner.Default().Run("nmcli", "connection", "up", SetupConnectionName); err != nil {
+		return fmt.Errorf("failed to start setup hotspot, %s, %w", strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
+// StopHotspot takes down the setup access point
+func StopHotspot() error {
+	if out, err := runner.Default().Run("nmcli", "connection", "down", SetupConnectionName); err != nil {
+		return fmt.Errorf("failed to stop setup hotspot, %s, %w", strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
+// splitTerse splits a line of nmcli terse output on unescaped colons
+func splitTerse(line string) []string {
+	var (
+		fields []string
+		field  strings.Builder
+	)
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line):
+			i++
+			field.WriteByte(line[i])
+		case line[i] == ':':
+			fields = append(fields, field.String())
+			field.Reset()
+		default:
+			field.WriteByte(line[i])
+		}
+	}
+	return append(fields, field.String())
+}
@@ -0,0 +1,110 @@
+package network
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/aouyang1/digitalphotoframe/runner"
+)
+
+func useFake(t *testing.T) *runner.Fake {
+	t.Helper()
+	fake := runner.NewFake()
+	previous := runner.Default()
+	runner.SetDefault(fake)
+	t.Cleanup(func() { runner.SetDefault(previous) })
+	return fake
+}
+
+func TestConnectWifi(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		want     []string
+	}{
+		{"open", "", []string{"device", "wifi", "connect", "Home", "ifname", "wlan0"}},
+		{"secured", "secret", []string{"device", "wifi", "connect", "Home", "ifname", "wlan0", "password", "secret"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := useFake(t)
+			if err := ConnectWifi("wlan0", "Home", tt.password); err != nil {
+				t.Fatalf("ConnectWifi() error = %v", err)
+			}
+			calls := fake.Calls()
+			if len(calls) != 1 || calls[0].Name != "nmcli" || !slices.Equal(calls[0].Args, tt.want) {
+				t.Errorf("ConnectWifi() ran %+v, want nmcli %v", calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartHotspot(t *testing.T) {
+	fake := useFake(t)
+	if err := StartHotspot("wlan0", "PhotoFrame-Setup"); err != nil {
+		t.Fatalf("StartHotspot() error = %v", err)
+	}
+
+	want := [][]string{
+		{"connection", "delete", SetupConnectionName},
+		{"connection", "add", "type", "wifi", "ifname", "wlan0", "con-name", SetupConnectionName, "autoconnect", "no", "ssid", "PhotoFrame-Setup", "802-11-wireless.mode", "ap", "ipv4.method", "shared"},
+		{"connection", "up", SetupConnectionName},
+	}
+	calls := fake.Calls()
+	if len(calls) != len(want) {
+		t.Fatalf("StartHotspot() ran %d commands, want %d", len(calls), len(want))
+	}
+	for i, call := range calls {
+		if call.Name != "nmcli" || !slices.Equal(call.Args, want[i]) {
+			t.Errorf("command %d = %s %v, want nmcli %v", i, call.Name, call.Args, want[i])
+		}
+	}
+}
+
+func TestScanWifi(t *testing.T) {
+	fake := useFake(t)
+	fake.Handle("nmcli", func(args []string) ([]byte, error) {
+		return []byte("Home:80:WPA2\nCafe\\:Guest:40:\n:30:WPA2\nHome:20:WPA2\n"), nil
+	})
+
+	networks, err := ScanWifi("wlan0")
+	if err != nil {
+		t.Fatalf("ScanWifi() error = %v", err)
+	}
+	want := []WifiNetwork{
+		{SSID: "Home", Signal: 80, Security: "WPA2"},
+		{SSID: "Cafe:Guest", Signal: 40, Security: ""},
+	}
+	if !slices.Equal(networks, want) {
+		t.Errorf("ScanWifi() = %+v, want %+v", networks, want)
+	}
+
+	wantArgs := []string{"-t", "-f", "SSID,SIGNAL,SECURITY", "device", "wifi", "list", "ifname", "wlan0", "--rescan", "yes"}
+	if calls := fake.Calls(); !slices.Equal(calls[0].Args, wantArgs) {
+		t.Errorf("ScanWifi() ran nmcli %v, want %v", calls[0].Args, wantArgs)
+	}
+}
+
+func TestConnected(t *testing.T) {
+	tests := []struct {
+		state string
+		want  bool
+	}{
+		{"connected\n", true},
+		{"connecting\n", false},
+		{"disconnected\n", false},
+	}
+	for _, tt := range tests {
+		fake := useFake(t)
+		fake.Handle("nmcli", func(args []string) ([]byte, error) {
+			return []byte(tt.state), nil
+		})
+		got, err := Connected()
+		if err != nil {
+			t.Fatalf("Connected() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("Connected() with state %q = %v, want %v", tt.state, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// Package notify sends push notifications to the frame owner's phone through ntfy or Pushover, and
+// messages to a family chat through Slack or Discord webhooks
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	requestTimeout = 10 * time.Second
+
+	pushoverURL = "https://api.pushover.net/1/messages.json"
+)
+
+// Message is a notification with a link opened when it is tapped
+type Message struct {
+	Title string
+	Body  string
+	URL   string
+}
+
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Notifiers sends each message to every configured service
+type Notifiers []Notifier
+
+func (n Notifiers) Send(ctx context.Context, msg Message) error {
+	var errs []error
+	for _, notifier := range n {
+		if err := notifier.Send(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// FromEnv returns the services configured through DPF_NTFY_URL and DPF_PUSHOVER_TOKEN with
+// DPF_PUSHOVER_USER, which is empty when none are
+func FromEnv() Notifiers {
+	client := &http.Client{Timeout: requestTimeout}
+
+	var notifiers Notifiers
+	if topicURL := os.Getenv("DPF_NTFY_URL"); topicURL != "" {
+		notifiers = append(notifiers, &Ntfy{
+			client:   client,
+			topicURL: topicURL,
+			token:    os.Getenv("DPF_NTFY_TOKEN"),
+		})
+	}
+
+	token, user := os.Getenv("DPF_PUSHOVER_TOKEN"), os.Getenv("DPF_PUSHOVER_USER")
+	switch {
+	case token != "" && user != "":
+		notifiers = append(notifiers, &Pushover{client: client, token: token, user: user})
+	case token != "" || user != "":
+		slog.Warn("both DPF_PUSHOVER_TOKEN and DPF_PUSHOVER_USER are needed, pushover notifications disabled")
+	}
+
+	if len(notifiers) == 0 {
+		slog.Info("no push notification service configured in DPF_NTFY_URL or DPF_PUSHOVER_TOKEN, notifications disabled")
+	}
+	return notifiers
+}
+
+// WebhooksFromEnv returns the chat channels configured through DPF_SLACK_WEBHOOK_URL and
+// DPF_DISCORD_WEBHOOK_URL, which is empty when none are
+func WebhooksFromEnv() Notifiers {
+	client := &http.Client{Timeout: requestTimeout}
+
+	var webhooks Notifiers
+	if webhookURL := os.Getenv("DPF_SLACK_WEBHOOK_URL"); webhookURL != "" {
+		webhooks = append(webhooks, &Webhook{client: client, webhookURL: webhookURL, field: "text", service: "slack"})
+	}
+	if webhookURL := os.Getenv("DPF_DISCORD_WEBHOOK_URL"); webhookURL != "" {
+		webhooks = append(webhooks, &Webhook{client: client, webhookURL: webhookURL, field: "content", service: "discord"})
+	}
+	return webhooks
+}
+
+// Ntfy publishes to a topic on ntfy.sh or a self hosted ntfy server
+type Ntfy struct {
+	client   *http.Client
+	topicURL string
+
+	// access token for protected topics
+	token string
+}
+
+func (n *Ntfy) Send(ctx context.Context, msg Message) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.topicURL, strings.NewReader(msg.Body))
+	if err != nil {
+		return fmt.Errorf("unable to create ntfy request, %w", err)
+	}
+	req.Header.Set("Title", msg.Title)
+	req.Header.Set("Tags", "frame_with_picture")
+	if msg.URL != "" {
+		req.Header.Set("Click", msg.URL)
+	}
+	if n.token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.token)
+	}
+	return send(n.client, req, "ntfy")
+}
+
+// Pushover sends to the devices of a Pushover user through an application token
+type Pushover struct {
+	client *http.Client
+	token  string
+	user   string
+}
+
+func (p *Pushover) Send(ctx context.Context, msg Message) error {
+	form := url.Values{
+		"token":   {p.token},
+		"user":    {p.user},
+		"title":   {msg.Title},
+		"message": {msg.Body},
+	}
+	if msg.URL != "" {
+		form.Set("url", msg.URL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, pushoverURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("unable to create pushover request, %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return send(p.client, req, "pushover")
+}
+
+// Webhook posts to a Slack or Discord incoming webhook, which show the body and link as a chat
+// message
+type Webhook struct {
+	client     *http.Client
+	webhookURL string
+
+	// json field holding the message text, which is text for slack and content for discord
+	field   string
+	service string
+}
+
+func (w *Webhook) Send(ctx context.Context, msg Message) error {
+	text := msg.Body
+	if msg.URL != "" {
+		text += "\n" + msg.URL
+	}
+	payload, err := json.Marshal(map[string]string{w.field: text})
+	if err != nil {
+		return fmt.Errorf("unable to encode %s message, %w", w.service, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("unable to create %s request, %w", w.service, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return send(w.client, req, w.service)
+}
+
+func send(client *http.Client, req *http.Request, service string) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to reach %s, %w", service, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s returned %s, %s", service, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+// Package overlay burns caption and watermark text into slideshow derivatives, and renders slides
+// of text, so it is shown on screen by imv
+package overlay
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+	"sync"
+
+	"github.com/aouyang1/digitalphotoframe/imaging"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+var parseFont = sync.OnceValues(func() (*opentype.Font, error) {
+	return opentype.Parse(goregular.TTF)
+})
+
+type Position string
+
+const (
+	BottomRight Position = "bottom-right"
+	BottomLeft  Position = "bottom-left"
+	TopRight    Position = "top-right"
+	TopLeft     Position = "top-left"
+)
+
+// Positions lists the corners captions can be drawn in
+var Positions = []Position{BottomRight, BottomLeft, TopRight, TopLeft}
+
+type Size string
+
+const (
+	Small  Size = "small"
+	Medium Size = "medium"
+	Large  Size = "large"
+)
+
+// Sizes lists the caption text sizes
+var Sizes = []Size{Small, Medium, Large}
+
+// sizeDivisors scale the text relative to the shorter side of the image
+var sizeDivisors = map[Size]float64{
+	Small:  40,
+	Medium: 28,
+	Large:  18,
+}
+
+// watermarkDivisor keeps the watermark smaller than even small captions
+const watermarkDivisor = 55
+
+// Text is what is drawn over a photo
+type Text struct {
+	// Caption is drawn over a translucent box in the corner picked by the options, with each line
+	// on its own row
+	Caption string
+
+	// Watermark is a single line of small faint text along the bottom, in the corner across from
+	// the caption
+	Watermark string
+}
+
+// IsZero reports whether there is nothing to draw
+func (t Text) IsZero() bool {
+	return t.Caption == "" && t.Watermark == ""
+}
+
+type Options struct {
+	// Rotation is the clockwise rotation in degrees already applied to the derivative. Text is
+	// drawn upright relative to the original photo so it reads correctly on the mounted frame.
+	Rotation int
+
+	// Position is the corner the caption is drawn in, defaulting to the bottom right
+	Position Position
+
+	// Size is the text size, defaulting to medium
+	Size Size
+}
+
+// CaptionFile draws text onto the image at srcPath and writes the result to dstPath
+func CaptionFile(srcPath, dstPath string, text Text, opts Options) error {
+	img, err := imaging.Decode(srcPath)
+	if err != nil {
+		return err
+	}
+
+	captioned, err := Caption(img, text, opts)
+	if err != nil {
+		return err
+	}
+	return imaging.WriteFile(dstPath, captioned)
+}
+
+// Caption returns a copy of img with the caption and watermark in text drawn over it
+func Caption(img image.Image, text Text, opts Options) (image.Image, error) {
+	f, err := parseFont()
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse caption font, %w", err)
+	}
+
+	upright := imaging.Rotate(img, -opts.Rotation)
+	bounds := upright.Bounds()
+	canvas := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(canvas, canvas.Bounds(), upright, bounds.Min, draw.Src)
+
+	if text.Caption != "" {
+		if err := drawCaption(canvas, f, text.Caption, opts); err != nil {
+			return nil, err
+		}
+	}
+	if text.Watermark != "" {
+		if err := drawWatermark(canvas, f, text.Watermark, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return imaging.Rotate(canvas, opts.Rotation), nil
+}
+
+// newFace returns the font at a size scaled with the canvas so text looks the same regardless of
+// resolution
+func newFace(f *opentype.Font, canvas *image.RGBA, divisor float64, minSize float64) (font.Face, float64, error) {
+	bounds := canvas.Bounds()
+	size := max(minSize, float64(min(bounds.Dx(), bounds.Dy()))/divisor)
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to create caption font face, %w", err)
+	}
+	return face, size, nil
+}
+
+// drawCaption draws text over a translucent box in a corner. Each line of text is drawn on its
+// own row.
+func drawCaption(canvas *image.RGBA, f *opentype.Font, text string, opts Options) error {
+	divisor, ok := sizeDivisors[opts.Size]
+	if !ok {
+		divisor = sizeDivisors[Medium]
+	}
+	face, size, err := newFace(f, canvas, divisor, 12)
+	if err != nil {
+		return err
+	}
+	defer face.Close()
+
+	drawer := &font.Drawer{
+		Dst:  canvas,
+		Src:  image.White,
+		Face: face,
+	}
+
+	lines := strings.Split(text, "\n")
+	metrics := face.Metrics()
+	lineH := (metrics.Ascent + metrics.Descent).Ceil()
+	var textW int
+	for _, line := range lines {
+		textW = max(textW, drawer.MeasureString(line).Ceil())
+	}
+	textH := lineH * len(lines)
+	pad := int(size / 2)
+	margin := int(size)
+
+	boxW, boxH := textW+2*pad, textH+2*pad
+	x0 := canvas.Bounds().Dx() - margin - boxW
+	y0 := canvas.Bounds().Dy() - margin - boxH
+	switch opts.Position {
+	case BottomLeft:
+		x0 = margin
+	case TopRight:
+		y0 = margin
+	case TopLeft:
+		x0, y0 = margin, margin
+	}
+	box := image.Rect(x0, y0, x0+boxW, y0+boxH)
+	draw.Draw(canvas, box, image.NewUniform(color.RGBA{A: 140}), image.Point{}, draw.Over)
+
+	for i, line := range lines {
+		drawer.Dot = fixed.P(box.Min.X+pad, box.Min.Y+pad+i*lineH+metrics.Ascent.Ceil())
+		drawer.DrawString(line)
+	}
+	return nil
+}
+
+// drawWatermark draws faint text with a soft shadow so it stays readable on light and dark photos
+// without drawing the eye. It goes in the bottom corner on the other side from the caption.
+func drawWatermark(canvas *image.RGBA, f *opentype.Font, text string, opts Options) error {
+	face, size, err := newFace(f, canvas, watermarkDivisor, 10)
+	if err != nil {
+		return err
+	}
+	defer face.Close()
+
+	drawer := &font.Drawer{Dst: canvas, Face: face}
+	textW := drawer.MeasureString(text).Ceil()
+	margin := int(size)
+	x := canvas.Bounds().Dx() - margin - textW
+	if opts.Position == BottomRight || opts.Position == TopRight || opts.Position == "" {
+		x = margin
+	}
+	y := canvas.Bounds().Dy() - margin - face.Metrics().Descent.Ceil()
+	shadow := max(1, int(size/12))
+
+	drawer.Src = image.NewUniform(color.RGBA{A: 110})
+	drawer.Dot = fixed.P(x+shadow, y+shadow)
+	drawer.DrawString(text)
+
+	drawer.Src = image.NewUniform(color.RGBA{R: 170, G: 170, B: 170, A: 170})
+	drawer.Dot = fixed.P(x, y)
+	drawer.DrawString(text)
+	return nil
+}
@@ -0,0 +1,132 @@
+package overlay
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+
+	"github.com/aouyang1/digitalphotoframe/imaging"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	// slideDivisor sizes slide text relative to the shorter side, shrinking it until the longest
+	// line fits within slideTextWidth of the slide
+	slideDivisor   = 8
+	slideTextWidth = 0.85
+
+	// slidePhotoMargin is the share of its half of the slide left around a photo
+	slidePhotoMargin = 0.1
+)
+
+// slideBackground is the dark backdrop text slides are drawn on
+var slideBackground = color.RGBA{R: 28, G: 28, B: 30, A: 255}
+
+// Slide renders text centered on a plain slide of width x height, with each line on its own row.
+// Like captions, the text is drawn upright relative to the mounted frame and the slide is then
+// rotated by rotation degrees clockwise.
+func Slide(text string, width, height, rotation int) (image.Image, error) {
+	return PhotoSlide(text, nil, width, height, rotation)
+}
+
+// PhotoSlide renders a slide like Slide with photo beside the text, on the left half of a
+// landscape slide or the top half of a portrait one. The text is drawn on its own when photo is
+// nil.
+func PhotoSlide(text string, photo image.Image, width, height, rotation int) (image.Image, error) {
+	f, err := parseFont()
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse caption font, %w", err)
+	}
+
+	bounds := image.Rect(0, 0, width, height)
+	if rotation%180 != 0 {
+		bounds = image.Rect(0, 0, height, width)
+	}
+	canvas := image.NewRGBA(bounds)
+	draw.Draw(canvas, bounds, image.NewUniform(slideBackground), image.Point{}, draw.Src)
+
+	textArea := bounds
+	if photo != nil {
+		photoArea := bounds
+		if bounds.Dx() >= bounds.Dy() {
+			photoArea.Max.X = bounds.Dx() / 2
+			textArea.Min.X = photoArea.Max.X
+		} else {
+			photoArea.Max.Y = bounds.Dy() / 2
+			textArea.Min.Y = photoArea.Max.Y
+		}
+
+		fitW := int(float64(photoArea.Dx()) * (1 - slidePhotoMargin))
+		fitH := int(float64(photoArea.Dy()) * (1 - slidePhotoMargin))
+		fitted := imaging.Resize(photo, fitW, fitH, imaging.FitContain)
+		size := fitted.Bounds().Size()
+		at := photoArea.Min.Add(photoArea.Size().Sub(size).Div(2))
+		draw.Draw(canvas, image.Rectangle{Min: at, Max: at.Add(size)}, fitted, fitted.Bounds().Min, draw.Src)
+	}
+
+	if err := drawSlideText(canvas, f, textArea, strings.Split(text, "\n")); err != nil {
+		return nil, err
+	}
+	return imaging.Rotate(canvas, rotation), nil
+}
+
+// drawSlideText draws lines centered within area of the canvas, shrinking them until the longest
+// fits
+func drawSlideText(canvas *image.RGBA, f *opentype.Font, area image.Rectangle, lines []string) error {
+	divisor := float64(slideDivisor)
+	for {
+		face, _, err := newFace(f, canvas, divisor, 12)
+		if err != nil {
+			return err
+		}
+
+		drawer := &font.Drawer{Dst: canvas, Src: image.White, Face: face}
+		var textW int
+		for _, line := range lines {
+			textW = max(textW, drawer.MeasureString(line).Ceil())
+		}
+		if float64(textW) > float64(area.Dx())*slideTextWidth && divisor < 60 {
+			face.Close()
+			divisor *= 1.15
+			continue
+		}
+
+		metrics := face.Metrics()
+		lineH := (metrics.Ascent + metrics.Descent).Ceil()
+		y0 := area.Min.Y + (area.Dy()-lineH*len(lines))/2
+		for i, line := range lines {
+			x := area.Min.X + (area.Dx()-drawer.MeasureString(line).Ceil())/2
+			drawer.Dot = fixed.P(x, y0+i*lineH+metrics.Ascent.Ceil())
+			drawer.DrawString(line)
+		}
+		face.Close()
+		return nil
+	}
+}
+
+// SlideFile renders a text slide and writes it to dstPath
+func SlideFile(dstPath, text string, width, height, rotation int) error {
+	return PhotoSlideFile(dstPath, text, "", width, height, rotation)
+}
+
+// PhotoSlideFile renders a slide of text beside the photo at photoPath and writes it to dstPath,
+// or a text slide when photoPath is empty
+func PhotoSlideFile(dstPath, text, photoPath string, width, height, rotation int) error {
+	var photo image.Image
+	if photoPath != "" {
+		var err error
+		if photo, err = imaging.Decode(photoPath); err != nil {
+			return err
+		}
+	}
+
+	slide, err := PhotoSlide(text, photo, width, height, rotation)
+	if err != nil {
+		return err
+	}
+	return imaging.WriteFile(dstPath, slide)
+}
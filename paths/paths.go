@@ -0,0 +1,229 @@
+// Package paths resolves where each category's originals and everything generated from them are
+// stored under the root path
+package paths
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	CategorySurprise = 0
+	CategoryOriginal = 1
+
+	// DerivativeSuffix marks the downsized and rotated copy of a photo shown by the slideshow
+	DerivativeSuffix = "_IMGP"
+)
+
+// Categories lists every photo category in playlist order
+var Categories = []int{CategorySurprise, CategoryOriginal}
+
+// CategoryName is what a category is called in the ui
+func CategoryName(category int) string {
+	if category == CategorySurprise {
+		return "Surprise"
+	}
+	return "My Photos"
+}
+
+// Layout resolves file locations under Root:
+//
+//	original/            originals uploaded to the frame (category 1)
+//	original/surprise/   originals synced from s3 (category 0)
+//	photos/              slideshow derivatives of category 1
+//	photos/surprise/     slideshow derivatives of category 0
+//	cache/resized/       resized copies served to the ui, by category and size
+//	cache/captions/      derivatives with captions drawn on them
+//	cache/transitions/   crossfade frames played between slideshow images
+//	cache/collages/      slides of several related photos composed from their derivatives
+//	cache/panoramas/     frames panning across wide panoramas, a directory per panorama
+//	cache/announcements/ text slides rendered from announcements
+//	cache/greetings/     greeting slides for birthdays and anniversaries
+//	cache/guestbook/     slides rendered from guestbook messages
+//	cache/sync_failures.json  s3 objects that failed to download on the last sync
+//	cache/s3_synced.json  versions of the s3 objects the local surprise photos were synced from
+//	cache/slideshow_state.json  slideshow position saved by older versions, imported once
+//	cache/playlist.txt   images imv is playing, one path per line
+//	cache/mpv.sock       socket background audio is controlled over while mpv plays it
+//	cache/webdav/        files being written over webdav before they are added as photos
+//	ingest/              files dropped off to be added to category 1
+//	ingest/surprise/     files dropped off to be added to category 0
+//	ingest/rejected/     dropped off files that could not be added
+//	quarantine/          synced photos removed from s3, kept for a while before they are deleted
+//	guestbook/           selfies left with guestbook messages
+//	motion/              videos of motion and live photos in category 1
+//	motion/surprise/     videos of motion and live photos in category 0
+type Layout struct {
+	Root string
+}
+
+func New(root string) Layout {
+	return Layout{Root: root}
+}
+
+// categoryDir is the subdirectory a category is stored in within the original and photos trees
+func categoryDir(category int) string {
+	if category == CategorySurprise {
+		return "surprise"
+	}
+	return ""
+}
+
+// OriginalDir is the directory holding the untouched originals of a category
+func (l Layout) OriginalDir(category int) string {
+	return filepath.Join(l.Root, "original", categoryDir(category))
+}
+
+// Original is the path to the untouched original of a photo
+func (l Layout) Original(category int, name string) string {
+	return filepath.Join(l.OriginalDir(category), name)
+}
+
+// DerivativeDir is the directory holding the slideshow derivatives of a category
+func (l Layout) DerivativeDir(category int) string {
+	return filepath.Join(l.Root, "photos", categoryDir(category))
+}
+
+// Derivative is the path to the downsized and rotated copy of a photo shown by the slideshow
+func (l Layout) Derivative(category int, name string) string {
+	return filepath.Join(l.DerivativeDir(category), DerivativeName(name))
+}
+
+// DerivativeName is the file name of a photo's slideshow derivative
+func DerivativeName(name string) string {
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + DerivativeSuffix + ext
+}
+
+// OriginalOf is the path to the original a slideshow derivative was made from, false when path
+// isn't a derivative under the layout
+func (l Layout) OriginalOf(path string) (string, bool) {
+	name := filepath.Base(path)
+	if !IsDerivative(name) {
+		return "", false
+	}
+	for _, category := range Categories {
+		if filepath.Dir(path) != l.DerivativeDir(category) {
+			continue
+		}
+		ext := filepath.Ext(name)
+		return l.Original(category, strings.TrimSuffix(strings.TrimSuffix(name, ext), DerivativeSuffix)+ext), true
+	}
+	return "", false
+}
+
+// IsDerivative reports whether name is a slideshow derivative rather than an original
+func IsDerivative(name string) bool {
+	return strings.Contains(name, DerivativeSuffix+".")
+}
+
+// ResizedDir is the directory holding every size of resized copy for a category
+func (l Layout) ResizedDir(category int) string {
+	return filepath.Join(l.Root, "cache", "resized", strconv.Itoa(category))
+}
+
+// Resized is the path to one size of resized copy of a photo
+func (l Layout) Resized(category int, variant, name string) string {
+	return filepath.Join(l.ResizedDir(category), variant, name)
+}
+
+// CaptionsDir is the directory holding captioned derivatives
+func (l Layout) CaptionsDir() string {
+	return filepath.Join(l.Root, "cache", "captions")
+}
+
+// TransitionsDir is the directory holding the crossfade frames played between slideshow images
+func (l Layout) TransitionsDir() string {
+	return filepath.Join(l.Root, "cache", "transitions")
+}
+
+// CollagesDir is the directory holding slides composed of several related photos
+func (l Layout) CollagesDir() string {
+	return filepath.Join(l.Root, "cache", "collages")
+}
+
+// PanoramasDir is the directory holding the frames played to pan across wide panoramas
+func (l Layout) PanoramasDir() string {
+	return filepath.Join(l.Root, "cache", "panoramas")
+}
+
+// Announcement is the path of the slide rendered for an announcement
+func (l Layout) Announcement(id int64) string {
+	return filepath.Join(l.Root, "cache", "announcements", strconv.FormatInt(id, 10)+".jpg")
+}
+
+// Greeting is the path of the greeting slide rendered for a special date
+func (l Layout) Greeting(id int64) string {
+	return filepath.Join(l.Root, "cache", "greetings", strconv.FormatInt(id, 10)+".jpg")
+}
+
+// GuestbookSlide is the path of the slide rendered for a guestbook message
+func (l Layout) GuestbookSlide(id int64) string {
+	return filepath.Join(l.Root, "cache", "guestbook", strconv.FormatInt(id, 10)+".jpg")
+}
+
+// Selfie is the path of the selfie left with a guestbook message
+func (l Layout) Selfie(id int64) string {
+	return filepath.Join(l.Root, "guestbook", strconv.FormatInt(id, 10)+".jpg")
+}
+
+// MotionDir is the directory holding the videos of a category's motion and live photos
+func (l Layout) MotionDir(category int) string {
+	return filepath.Join(l.Root, "motion", categoryDir(category))
+}
+
+// Motion is the path of the video of a motion or live photo, kept with the extension it was
+// captured with such as .mov
+func (l Layout) Motion(category int, name, ext string) string {
+	return filepath.Join(l.MotionDir(category), name+ext)
+}
+
+// WebDAVDir is the directory files written over webdav are kept in until they are added as photos
+func (l Layout) WebDAVDir() string {
+	return filepath.Join(l.Root, "cache", "webdav")
+}
+
+// IngestDir is the directory watched for files to add to a category
+func (l Layout) IngestDir(category int) string {
+	return filepath.Join(l.Root, "ingest", categoryDir(category))
+}
+
+// IngestRejectedDir is where dropped off files that could not be added are moved
+func (l Layout) IngestRejectedDir() string {
+	return filepath.Join(l.Root, "ingest", "rejected")
+}
+
+// QuarantineDir is where synced photos removed from s3 are moved rather than deleted right away
+func (l Layout) QuarantineDir() string {
+	return filepath.Join(l.Root, "quarantine")
+}
+
+// SyncFailures is the file recording s3 objects that failed to download so they are retried on
+// the next sync
+func (l Layout) SyncFailures() string {
+	return filepath.Join(l.Root, "cache", "sync_failures.json")
+}
+
+// Playlist is the file imv reads the images it plays from, one path per line, which is always
+// the playlist currently on screen
+func (l Layout) Playlist() string {
+	return filepath.Join(l.Root, "cache", "playlist.txt")
+}
+
+// AudioSocket is the socket mpv listens on for commands while playing background audio
+func (l Layout) AudioSocket() string {
+	return filepath.Join(l.Root, "cache", "mpv.sock")
+}
+
+// SyncedObjects is the file recording the version of each s3 object the local surprise photos were
+// synced from, so photos changed on either side since can be told apart
+func (l Layout) SyncedObjects() string {
+	return filepath.Join(l.Root, "cache", "s3_synced.json")
+}
+
+// LegacySlideshowState is the file the slideshow's playlist and position were kept in before
+// they were saved to the database
+func (l Layout) LegacySlideshowState() string {
+	return filepath.Join(l.Root, "cache", "slideshow_state.json")
+}
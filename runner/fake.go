@@ -0,0 +1,149 @@
+package runner
+
+import (
+	"io"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// Call is a command run through a Fake
+type Call struct {
+	Name string
+	Args []string
+
+	// Stdin is the file a started process reads its standard input from, if any
+	Stdin string
+}
+
+// HandlerFunc produces the output of a faked command
+type HandlerFunc func(args []string) ([]byte, error)
+
+// Fake records the commands it is asked to run instead of running them. Commands without a
+// handler succeed with no output, and started processes run until stopped with Stop, writing
+// nothing to their standard output.
+type Fake struct {
+	mu        sync.Mutex
+	handlers  map[string]HandlerFunc
+	calls     []Call
+	processes []*fakeProcess
+	nextPid   int
+}
+
+func NewFake() *Fake {
+	return &Fake{
+		handlers: make(map[string]HandlerFunc),
+		nextPid:  1000,
+	}
+}
+
+// Handle sets the handler producing the output of the named command
+func (f *Fake) Handle(name string, handler HandlerFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handlers[name] = handler
+}
+
+// Calls returns every command run so far in the order they were run
+func (f *Fake) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.calls)
+}
+
+// Running returns the pids of started processes that have not been stopped whose program has the
+// given base name, the same way pgrep matches
+func (f *Fake) Running(name string) []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var pids []int
+	for _, p := range f.processes {
+		if p.name == name {
+			pids = append(pids, p.pid)
+		}
+	}
+	return pids
+}
+
+// Stop ends every started process whose program has the given base name, returning how many
+// were stopped
+func (f *Fake) Stop(name string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var stopped int
+	f.processes = slices.DeleteFunc(f.processes, func(p *fakeProcess) bool {
+		if p.name != name {
+			return false
+		}
+		close(p.done)
+		stopped++
+		return true
+	})
+	return stopped
+}
+
+func (f *Fake) Run(name string, args ...string) ([]byte, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, Call{Name: name, Args: args})
+	handler := f.handlers[name]
+	f.mu.Unlock()
+
+	if handler == nil {
+		return nil, nil
+	}
+	return handler(args)
+}
+
+func (f *Fake) Output(name string, args ...string) ([]byte, error) {
+	return f.Run(name, args...)
+}
+
+func (f *Fake) Start(name string, args ...string) (Process, error) {
+	return f.start(Call{Name: name, Args: args})
+}
+
+func (f *Fake) StartWithStdin(stdinPath, name string, args ...string) (Process, error) {
+	return f.start(Call{Name: name, Args: args, Stdin: stdinPath})
+}
+
+func (f *Fake) StartWithStdout(name string, args ...string) (Process, io.Reader, error) {
+	p, err := f.start(Call{Name: name, Args: args})
+	if err != nil {
+		return nil, nil, err
+	}
+	stdout, w := io.Pipe()
+	go func() {
+		p.Wait()
+		w.Close()
+	}()
+	return p, stdout, nil
+}
+
+func (f *Fake) start(call Call) (Process, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, call)
+	name := call.Name
+	f.nextPid++
+	p := &fakeProcess{name: filepath.Base(name), pid: f.nextPid, done: make(chan struct{})}
+	f.processes = append(f.processes, p)
+	return p, nil
+}
+
+type fakeProcess struct {
+	name string
+	pid  int
+	done chan struct{}
+}
+
+func (p *fakeProcess) Pid() int {
+	return p.pid
+}
+
+func (p *fakeProcess) Wait() error {
+	<-p.done
+	return nil
+}
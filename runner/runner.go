@@ -0,0 +1,115 @@
+// Package runner runs the external programs the frame depends on, such as imv, imgp, and
+// wlr-randr, behind an interface so they can be replaced when developing or testing off the pi
+package runner
+
+import (
+	"io"
+	"os"
+	"os/exec"
+)
+
+// std is the runner every package runs external programs through
+var std Runner = Exec{}
+
+// Default returns the runner every package runs external programs through
+func Default() Runner {
+	return std
+}
+
+// SetDefault replaces the runner every package runs external programs through, such as with a
+// simulator when not running on the frame or a fake in tests. It's set before anything runs.
+func SetDefault(r Runner) {
+	std = r
+}
+
+// Runner runs external commands
+type Runner interface {
+	// Run runs a command to completion returning its combined stdout and stderr
+	Run(name string, args ...string) ([]byte, error)
+
+	// Output runs a command to completion returning only its stdout
+	Output(name string, args ...string) ([]byte, error)
+
+	// Start starts a long running command with its output passed through to ours
+	Start(name string, args ...string) (Process, error)
+
+	// StartWithStdin starts a long running command like Start, reading its standard input from the
+	// file at stdinPath
+	StartWithStdin(stdinPath, name string, args ...string) (Process, error)
+
+	// StartWithStdout starts a long running command like Start, returning its standard output to
+	// be read as it's written instead of passing it through
+	StartWithStdout(name string, args ...string) (Process, io.Reader, error)
+}
+
+// Process is a command started by a Runner
+type Process interface {
+	Pid() int
+
+	// Wait blocks until the process exits
+	Wait() error
+}
+
+// Exec runs commands on the host with os/exec
+type Exec struct{}
+
+func (Exec) Run(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).CombinedOutput()
+}
+
+func (Exec) Output(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).Output()
+}
+
+func (Exec) Start(name string, args ...string) (Process, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return execProcess{cmd}, nil
+}
+
+func (Exec) StartWithStdin(stdinPath, name string, args ...string) (Process, error) {
+	stdin, err := os.Open(stdinPath)
+	if err != nil {
+		return nil, err
+	}
+	// the child has its own copy of the descriptor once started
+	defer stdin.Close()
+
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return execProcess{cmd}, nil
+}
+
+func (Exec) StartWithStdout(name string, args ...string) (Process, io.Reader, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, nil, err
+	}
+	return execProcess{cmd}, stdout, nil
+}
+
+type execProcess struct {
+	cmd *exec.Cmd
+}
+
+func (p execProcess) Pid() int {
+	return p.cmd.Process.Pid
+}
+
+func (p execProcess) Wait() error {
+	return p.cmd.Wait()
+}
@@ -0,0 +1,519 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aouyang1/digitalphotoframe/paths"
+	"github.com/aouyang1/digitalphotoframe/util"
+)
+
+// size of the blank screen captured when nothing is shown
+const (
+	screenWidth  = 1920
+	screenHeight = 1080
+)
+
+// simulatedModes are the resolutions the simulated panel supports, the first being its native mode
+var simulatedModes = []string{"1920x1080@60.000", "1920x1080@50.000", "1280x720@60.000"}
+
+// errNoProcess is what pgrep and pkill report when nothing matches
+var errNoProcess = errors.New("exit status 1")
+
+// Simulator stands in for wlr-randr, grim, ddcutil, imv, imgp, rclone, mpv, nmcli, libinput, and
+// systemctl so the server can be developed on a machine without a display. The display's power,
+// rotation, and brightness, imv's position in its playlist, and mpv's volume are tracked in memory,
+// and imgp derivatives are plain copies of the original.
+type Simulator struct {
+	*Fake
+
+	mu               sync.Mutex
+	displayOn        bool
+	displayTransform string
+	displayMode      string
+	displayScale     float64
+	brightness       int
+
+	// imvImages is imv's list of images and imvIndex the 1-based image on screen
+	imvImages []string
+	imvIndex  int
+
+	// mpvIPC answers the commands sent to mpv's ipc socket, adjusting mpvVolume
+	mpvIPC    net.Listener
+	mpvVolume int
+}
+
+func NewSimulator() *Simulator {
+	s := &Simulator{
+		Fake:             NewFake(),
+		displayOn:        true,
+		displayTransform: "normal",
+		displayMode:      simulatedModes[0],
+		displayScale:     1,
+		brightness:       100,
+	}
+	s.Handle("wlr-randr", s.wlrRandr)
+	s.Handle("pgrep", s.pgrep)
+	s.Handle("pkill", s.pkill)
+	s.Handle("imgp", s.imgp)
+	s.Handle("imv-msg", s.imvMsg)
+	s.Handle("grim", s.grim)
+	s.Handle("ddcutil", s.ddcutil)
+	s.Handle("rclone", rclone)
+	s.Handle("nmcli", nmcli)
+	s.Handle("systemctl", func(args []string) ([]byte, error) {
+		slog.Info("simulating systemctl", "args", args)
+		return nil, nil
+	})
+	return s
+}
+
+// Start records imv's list of images so navigation and screenshots can be simulated, and listens
+// on mpv's ipc socket so its volume can be changed
+func (s *Simulator) Start(name string, args ...string) (Process, error) {
+	s.startImv(name, args, "")
+	s.startMpv(name, args)
+	slog.Info("simulating process start", "name", name, "args", len(args))
+	return s.Fake.Start(name, args...)
+}
+
+// StartWithStdout starts a process such as libinput that never writes anything
+func (s *Simulator) StartWithStdout(name string, args ...string) (Process, io.Reader, error) {
+	slog.Info("simulating process start", "name", name, "args", len(args))
+	return s.Fake.StartWithStdout(name, args...)
+}
+
+// StartWithStdin records imv's list of images, including those it reads from stdin
+func (s *Simulator) StartWithStdin(stdinPath, name string, args ...string) (Process, error) {
+	s.startImv(name, args, stdinPath)
+	slog.Info("simulating process start", "name", name, "args", len(args), "stdin", stdinPath)
+	return s.Fake.StartWithStdin(stdinPath, name, args...)
+}
+
+func (s *Simulator) startImv(name string, args []string, stdinPath string) {
+	if filepath.Base(name) != "imv-wayland" {
+		return
+	}
+	images := imvImages(args, stdinPath)
+	s.mu.Lock()
+	s.imvImages, s.imvIndex = images, 1
+	s.mu.Unlock()
+}
+
+// imvImages lists the images imv would show given its arguments, reading the directory when imv
+// is asked to load one recursively and the paths in stdinPath when asked to read them from stdin
+func imvImages(args []string, stdinPath string) []string {
+	var images []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-s", "-t":
+			i++
+		case "-f":
+		case "-":
+			content, _ := os.ReadFile(stdinPath)
+			for line := range strings.Lines(string(content)) {
+				if line = strings.TrimSuffix(line, "\n"); line != "" {
+					images = append(images, line)
+				}
+			}
+		case "-r":
+			if i+1 < len(args) {
+				entries, _ := os.ReadDir(args[i+1])
+				for _, entry := range entries {
+					if !entry.IsDir() {
+						images = append(images, filepath.Join(args[i+1], entry.Name()))
+					}
+				}
+				i++
+			}
+		default:
+			images = append(images, args[i])
+		}
+	}
+	return images
+}
+
+func (s *Simulator) wlrRandr(args []string) ([]byte, error) {
+	output := ""
+	if i := slices.Index(args, "--output"); i >= 0 && i+1 < len(args) {
+		output = args[i+1]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case slices.Contains(args, "--on"):
+		s.displayOn = true
+	case slices.Contains(args, "--off"):
+		s.displayOn = false
+	case slices.Contains(args, "--json"):
+		return json.Marshal([]map[string]any{s.output(output)})
+	}
+	if i := slices.Index(args, "--transform"); i >= 0 && i+1 < len(args) {
+		s.displayTransform = args[i+1]
+	}
+	if i := slices.Index(args, "--mode"); i >= 0 && i+1 < len(args) {
+		mode := strings.TrimSuffix(args[i+1], "Hz")
+		if !slices.Contains(simulatedModes, mode) {
+			return nil, fmt.Errorf("mode %s is not supported", mode)
+		}
+		s.displayMode = mode
+	}
+	if i := slices.Index(args, "--scale"); i >= 0 && i+1 < len(args) {
+		scale, err := strconv.ParseFloat(args[i+1], 64)
+		if err != nil {
+			return nil, err
+		}
+		s.displayScale = scale
+	}
+	return nil, nil
+}
+
+// output describes the simulated panel the way wlr-randr --json does
+func (s *Simulator) output(name string) map[string]any {
+	var modes []map[string]any
+	for i, mode := range simulatedModes {
+		var width, height int
+		var refresh float64
+		fmt.Sscanf(mode, "%dx%d@%f", &width, &height, &refresh)
+		modes = append(modes, map[string]any{
+			"width":     width,
+			"height":    height,
+			"refresh":   refresh,
+			"preferred": i == 0,
+			"current":   s.displayOn && mode == s.displayMode,
+		})
+	}
+	return map[string]any{
+		"name":          name,
+		"description":   "Simulated Display",
+		"make":          "Simulated",
+		"model":         "Display",
+		"physical_size": map[string]int{"width": 527, "height": 296},
+		"enabled":       s.displayOn,
+		"modes":         modes,
+		"transform":     s.displayTransform,
+		"scale":         s.displayScale,
+	}
+}
+
+// ddcutil tracks the brightness set over DDC/CI
+func (s *Simulator) ddcutil(args []string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(args) == 3 && args[0] == "setvcp" && args[1] == "10" {
+		brightness, err := strconv.Atoi(args[2])
+		if err != nil {
+			return nil, err
+		}
+		s.brightness = brightness
+		slog.Info("simulating brightness", "percent", brightness)
+		return nil, nil
+	}
+	if len(args) == 2 && args[0] == "getvcp" && args[1] == "10" {
+		return fmt.Appendf(nil, "VCP code 0x10 (Brightness): current value = %d, max value = 100\n", s.brightness), nil
+	}
+	return nil, fmt.Errorf("unsupported ddcutil command %v", args)
+}
+
+// rclone copies or syncs photos from a local directory given as the remote, ignoring photos
+// already copied the way --ignore-existing does
+func rclone(args []string) ([]byte, error) {
+	if len(args) < 3 || (args[0] != "copy" && args[0] != "sync") {
+		return nil, fmt.Errorf("unsupported rclone command %v", args)
+	}
+	mode, src, dst := args[0], args[1], args[2]
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return []byte(fmt.Sprintf("only local directories are simulated as remotes, %v", err)), err
+	}
+
+	names := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.IsDir() || !util.SupportedExt.Contains(filepath.Ext(entry.Name())) {
+			continue
+		}
+		names[entry.Name()] = true
+		if _, err := os.Stat(filepath.Join(dst, entry.Name())); err == nil {
+			continue
+		}
+		if err := copyFile(filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())); err != nil {
+			return nil, err
+		}
+	}
+
+	if mode == "sync" {
+		existing, err := os.ReadDir(dst)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range existing {
+			if !entry.IsDir() && util.SupportedExt.Contains(filepath.Ext(entry.Name())) && !names[entry.Name()] {
+				os.Remove(filepath.Join(dst, entry.Name()))
+			}
+		}
+	}
+	return nil, nil
+}
+
+// simulatedWifi is the terse nmcli listing of the wifi network the simulated frame is connected to
+const simulatedWifi = "Simulated WiFi:80:WPA2"
+
+// nmcli reports a frame connected to the internet over wifi and pretends to change connections
+func nmcli(args []string) ([]byte, error) {
+	joined := strings.Join(args, " ")
+	switch {
+	case joined == "-t -f STATE general":
+		return []byte("connected\n"), nil
+	case joined == "networking connectivity check":
+		return []byte("full\n"), nil
+	case strings.HasPrefix(joined, "-t -f ACTIVE,"):
+		return []byte("yes:" + simulatedWifi + "\n"), nil
+	case strings.HasPrefix(joined, "-t -f SSID,"):
+		return []byte(simulatedWifi + "\n"), nil
+	}
+	slog.Info("simulating nmcli", "args", args)
+	return nil, nil
+}
+
+func (s *Simulator) pgrep(args []string) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, errors.New("no process name given")
+	}
+	pids := s.Running(args[len(args)-1])
+	if len(pids) == 0 {
+		return nil, errNoProcess
+	}
+
+	var out strings.Builder
+	for _, pid := range pids {
+		fmt.Fprintln(&out, pid)
+	}
+	return []byte(out.String()), nil
+}
+
+func (s *Simulator) pkill(args []string) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, errors.New("no process name given")
+	}
+	if args[len(args)-1] == "mpv" {
+		s.stopMpv()
+	}
+	if s.Stop(args[len(args)-1]) == 0 {
+		return nil, errNoProcess
+	}
+	return nil, nil
+}
+
+// imgp overwrites the image when downsizing with -w, which is skipped, and otherwise writes the
+// rotated copy next to it with the derivative suffix
+func (s *Simulator) imgp(args []string) ([]byte, error) {
+	if len(args) == 0 || slices.Contains(args, "-w") {
+		return nil, nil
+	}
+	src := args[len(args)-1]
+	dst := filepath.Join(filepath.Dir(src), paths.DerivativeName(filepath.Base(src)))
+	return nil, copyFile(src, dst)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// grim captures the image imv has on screen, or a black screen when the display is off or imv
+// isn't running
+func (s *Simulator) grim(args []string) ([]byte, error) {
+	s.mu.Lock()
+	var imgPath string
+	if s.displayOn && len(s.Running("imv-wayland")) > 0 && s.imvIndex >= 1 && s.imvIndex <= len(s.imvImages) {
+		imgPath = s.imvImages[s.imvIndex-1]
+	}
+	s.mu.Unlock()
+
+	var img image.Image = image.NewGray(image.Rect(0, 0, screenWidth, screenHeight))
+	if imgPath != "" {
+		f, err := os.Open(imgPath)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		if img, _, err = image.Decode(f); err != nil {
+			return nil, fmt.Errorf("unable to decode %s, %w", imgPath, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// imvMsg applies the playback commands the slideshow controller sends to imv
+func (s *Simulator) imvMsg(args []string) ([]byte, error) {
+	if len(args) < 2 {
+		return nil, errors.New("usage: imv-msg <pid> <command>")
+	}
+	pid, err := strconv.Atoi(args[0])
+	if err != nil || !slices.Contains(s.Running("imv-wayland"), pid) {
+		return nil, fmt.Errorf("imv is not running with pid %s", args[0])
+	}
+	command, arg, _ := strings.Cut(args[1], " ")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch command {
+	case "next":
+		s.imvIndex = s.imvIndex%max(len(s.imvImages), 1) + 1
+	case "prev":
+		s.imvIndex--
+		if s.imvIndex < 1 {
+			s.imvIndex = max(len(s.imvImages), 1)
+		}
+	case "goto":
+		if idx, err := strconv.Atoi(arg); err == nil && idx >= 1 && idx <= len(s.imvImages) {
+			s.imvIndex = idx
+		}
+	case "open":
+		s.imvImages = append(s.imvImages, unquoteImvArg(arg))
+		s.imvIndex = len(s.imvImages)
+	case "close":
+		if len(s.imvImages) > 0 {
+			s.imvImages = slices.Delete(s.imvImages, s.imvIndex-1, s.imvIndex)
+		}
+		s.imvIndex = min(s.imvIndex, max(len(s.imvImages), 1))
+	case "exec":
+		// the controller reads the current index back from a file the command writes to
+		if _, file, ok := strings.Cut(arg, "> "); ok && strings.Contains(arg, "$imv_current_index") {
+			return nil, os.WriteFile(strings.TrimSpace(file), []byte(strconv.Itoa(s.imvIndex)+"\n"), 0o644)
+		}
+	}
+	return nil, nil
+}
+
+// unquoteImvArg undoes the single quotes the controller wraps paths in for imv
+func unquoteImvArg(arg string) string {
+	if len(arg) < 2 || arg[0] != '\'' || arg[len(arg)-1] != '\'' {
+		return arg
+	}
+	return strings.ReplaceAll(arg[1:len(arg)-1], `'\''`, "'")
+}
+
+// startMpv listens on the ipc socket mpv is started with in place of mpv
+func (s *Simulator) startMpv(name string, args []string) {
+	if filepath.Base(name) != "mpv" {
+		return
+	}
+	s.stopMpv()
+
+	var socketPath string
+	volume := 100
+	for _, arg := range args {
+		if v, ok := strings.CutPrefix(arg, "--input-ipc-server="); ok {
+			socketPath = v
+		}
+		if v, ok := strings.CutPrefix(arg, "--volume="); ok {
+			volume, _ = strconv.Atoi(v)
+		}
+	}
+	if socketPath == "" {
+		return
+	}
+
+	os.Remove(socketPath)
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		slog.Warn("unable to simulate mpv ipc socket", "path", socketPath, "error", err)
+		return
+	}
+	s.mu.Lock()
+	s.mpvIPC, s.mpvVolume = ln, volume
+	s.mu.Unlock()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.mpvCommands(conn)
+		}
+	}()
+}
+
+// stopMpv closes the ipc socket of the simulated mpv
+func (s *Simulator) stopMpv() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mpvIPC != nil {
+		s.mpvIPC.Close()
+		s.mpvIPC = nil
+	}
+}
+
+// mpvCommands answers the json commands sent over a connection to mpv's ipc socket, of which
+// getting and setting the volume are simulated
+func (s *Simulator) mpvCommands(conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	encoder := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var req struct {
+			Command []any `json:"command"`
+		}
+		reply := map[string]any{"error": "success"}
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil || len(req.Command) < 2 || req.Command[1] != "volume" {
+			reply["error"] = "invalid parameter"
+			encoder.Encode(reply)
+			continue
+		}
+
+		s.mu.Lock()
+		switch {
+		case req.Command[0] == "get_property":
+			reply["data"] = s.mpvVolume
+		case req.Command[0] == "set_property" && len(req.Command) == 3:
+			volume, ok := req.Command[2].(float64)
+			if !ok {
+				reply["error"] = "unsupported format for accessing property"
+				break
+			}
+			s.mpvVolume = int(volume)
+			slog.Info("simulating mpv volume", "percent", s.mpvVolume)
+		default:
+			reply["error"] = "invalid parameter"
+		}
+		s.mu.Unlock()
+		encoder.Encode(reply)
+	}
+}
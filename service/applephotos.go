@@ -0,0 +1,178 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/aouyang1/digitalphotoframe/util"
+)
+
+// appleResourceForks holds the metadata macOS adds to zip archives made in the Finder
+const appleResourceForks = "__MACOSX"
+
+// appleMoment matches the folders Photos exports moments to, named for the day and, when it has
+// one, the place, like "Santa Cruz, July 4, 2019"
+var appleMoment = regexp.MustCompile(`(^|, )(January|February|March|April|May|June|July|August|September|October|November|December) \d{1,2}, \d{4}$`)
+
+// ImportApplePhotos adds the photos in an export from Apple Photos to My Photos. Photos in a
+// folder, as albums are exported to, are put in an album named after it, while those at the top of
+// the export and in folders for moments aren't put in one. The title or caption, keywords, and date
+// in the XMP file Photos exports with each photo are kept, and favorites are pinned. Copies of
+// photos already on the frame, such as a photo exported from more than one album, are skipped. A
+// dry run reports what would be imported without writing anything.
+func (s *PhotoService) ImportApplePhotos(ctx context.Context, export fs.FS, opts ImportOptions) (*ImportReport, error) {
+	export, err := exportRoot(export)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read export, %w", err)
+	}
+
+	var items []importItem
+	err = fs.WalkDir(export, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != "." && (strings.HasPrefix(d.Name(), ".") || d.Name() == appleResourceForks) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		// edits are exported as the photos they make, so their adjustment files are left behind
+		if strings.HasPrefix(d.Name(), ".") || IsSidecar(d.Name()) || strings.EqualFold(path.Ext(d.Name()), ".aae") {
+			return nil
+		}
+		// the video of a live photo is imported with its still
+		if IsMotionVideo(d.Name()) && exportMotionPhoto(export, p) {
+			return nil
+		}
+		item := importItem{
+			name:   d.Name(),
+			source: p,
+			open: func() (io.ReadCloser, error) {
+				return export.Open(p)
+			},
+			album: appleAlbum(path.Dir(p)),
+			meta:  readExportSidecar(export, p),
+		}
+		if motion, ok := findExportMotion(export, p); ok {
+			item.motion = func() (io.ReadCloser, error) {
+				return export.Open(motion)
+			}
+			item.motionName = path.Base(motion)
+		}
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read export, %w", err)
+	}
+	albumsFirst(items)
+
+	im, err := s.newImporter(opts)
+	if err != nil {
+		return nil, err
+	}
+	err = im.importAll(ctx, items)
+	slog.Info("imported apple photos export", "dry_run", opts.DryRun, "added", im.report.Added, "duplicates", im.report.Duplicates, "unsupported", im.report.Unsupported, "failed", im.report.Failed, "bytes", im.report.Bytes)
+	return im.report, err
+}
+
+// appleAlbum names the album photos exported to the folder were in, or is empty for the top of the
+// export and moments
+func appleAlbum(dir string) string {
+	if dir == "." || appleMoment.MatchString(path.Base(dir)) {
+		return ""
+	}
+	return path.Base(dir)
+}
+
+// exportRoot is the top of an export, which is the folder an archive holds when it holds only one,
+// as it does when the export folder is compressed
+func exportRoot(export fs.FS) (fs.FS, error) {
+	entries, err := fs.ReadDir(export, ".")
+	if err != nil {
+		return nil, err
+	}
+	var top fs.DirEntry
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") || entry.Name() == appleResourceForks {
+			continue
+		}
+		if top != nil || !entry.IsDir() {
+			return export, nil
+		}
+		top = entry
+	}
+	if top == nil {
+		return export, nil
+	}
+	return fs.Sub(export, top.Name())
+}
+
+// readExportSidecar reads the sidecar exported alongside a photo, returning nil when it has none or
+// it can't be read
+func readExportSidecar(export fs.FS, photo string) *Sidecar {
+	for _, candidate := range SidecarCandidates(photo) {
+		f, err := export.Open(candidate)
+		if err != nil {
+			continue
+		}
+		sidecar, err := ParseSidecar(f, path.Ext(candidate))
+		f.Close()
+		if err != nil {
+			slog.Warn("unable to read exported sidecar, skipping its metadata", "photo", photo, "sidecar", candidate, "error", err)
+			return nil
+		}
+		return sidecar
+	}
+	return nil
+}
+
+// findExportMotion returns the path of the live photo video exported alongside a photo
+func findExportMotion(export fs.FS, photo string) (string, bool) {
+	if !util.SupportedExt.Contains(path.Ext(photo)) {
+		return "", false
+	}
+	for _, candidate := range MotionCandidates(photo) {
+		if _, err := fs.Stat(export, candidate); err == nil {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// exportMotionPhoto reports whether the video is of a live photo exported alongside it
+func exportMotionPhoto(export fs.FS, video string) bool {
+	stem := strings.TrimSuffix(video, path.Ext(video))
+	for ext := range util.SupportedExt.Iter() {
+		if _, err := fs.Stat(export, stem+ext); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// OpenExport opens a folder exported from a photo library, or a zip archive of one, returning a
+// function to close it
+func OpenExport(exportPath string) (fs.FS, func() error, error) {
+	info, err := os.Stat(exportPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to open export, %w", err)
+	}
+	if info.IsDir() {
+		return os.DirFS(exportPath), func() error { return nil }, nil
+	}
+	rc, err := zip.OpenReader(exportPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to open export archive, %w", err)
+	}
+	return rc, rc.Close, nil
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aouyang1/digitalphotoframe/util"
+)
+
+// CheckImage makes sure the file is an image that can be decoded
+func CheckImage(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, _, err := image.DecodeConfig(f); err != nil {
+		return fmt.Errorf("not a readable image, %w", err)
+	}
+	return nil
+}
+
+// FreeName returns name, or name with a number added before the extension if a photo in the
+// category already has it
+func (s *PhotoService) FreeName(category int, name string) (string, error) {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	candidate := name
+	for i := 2; ; i++ {
+		exists, err := s.db.PhotoExists(candidate, category)
+		if err != nil {
+			return "", fmt.Errorf("database error, %w", err)
+		}
+		if _, err := os.Stat(s.paths.Original(category, candidate)); !exists && os.IsNotExist(err) {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s_%d%s", base, i, ext)
+	}
+}
+
+// FindDuplicate returns the name of the photo in the category with the same content as the file,
+// comparing only photos of the same size
+func (s *PhotoService) FindDuplicate(category int, path string) (string, error) {
+	index, err := s.newDuplicateIndex(category)
+	if err != nil {
+		return "", err
+	}
+	return index.find(path)
+}
+
+// duplicateIndex finds photos in a category with the same content as a file. Photos are listed
+// once by size and their checksums are read only when a file of the same size is looked up, so
+// checking many files, as an import does, doesn't reread the whole category each time.
+type duplicateIndex struct {
+	dir    string
+	bySize map[int64][]string
+	sums   map[string][]byte
+}
+
+func (s *PhotoService) newDuplicateIndex(category int) (*duplicateIndex, error) {
+	index := &duplicateIndex{
+		dir:    s.paths.OriginalDir(category),
+		bySize: make(map[int64][]string),
+		sums:   make(map[string][]byte),
+	}
+	entries, err := os.ReadDir(index.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return index, nil
+		}
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !util.SupportedExt.Contains(filepath.Ext(entry.Name())) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		index.add(entry.Name(), info.Size(), nil)
+	}
+	return index, nil
+}
+
+// add records a photo added to the category. Its checksum is read when first needed unless given,
+// which it should be when the photo has been downsized since it was checked.
+func (d *duplicateIndex) add(name string, size int64, sum []byte) {
+	d.bySize[size] = append(d.bySize[size], name)
+	if sum != nil {
+		d.sums[name] = sum
+	}
+}
+
+func (d *duplicateIndex) find(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	return d.lookup(info.Size(), func() ([]byte, error) {
+		return fileChecksum(path)
+	})
+}
+
+// lookup returns the photo with the size and checksum, the checksum being taken only when a photo
+// has the same size
+func (d *duplicateIndex) lookup(size int64, checksum func() ([]byte, error)) (string, error) {
+	var (
+		sum []byte
+		err error
+	)
+	for _, name := range d.bySize[size] {
+		if sum == nil {
+			if sum, err = checksum(); err != nil {
+				return "", err
+			}
+		}
+		existingSum, ok := d.sums[name]
+		if !ok {
+			if existingSum, err = fileChecksum(filepath.Join(d.dir, name)); err != nil {
+				continue
+			}
+			d.sums[name] = existingSum
+		}
+		if bytes.Equal(sum, existingSum) {
+			return name, nil
+		}
+	}
+	return "", nil
+}
+
+// checkImageContent makes sure the content is an image that can be decoded without writing it
+// anywhere, reading it to the end for its checksum and size
+func checkImageContent(r io.Reader) ([]byte, int64, error) {
+	h := sha256.New()
+	var size byteCounter
+	tee := io.TeeReader(r, io.MultiWriter(h, &size))
+	if _, _, err := image.DecodeConfig(tee); err != nil {
+		return nil, 0, fmt.Errorf("not a readable image, %w", err)
+	}
+	if _, err := io.Copy(io.Discard, tee); err != nil {
+		return nil, 0, err
+	}
+	return h.Sum(nil), int64(size), nil
+}
+
+type byteCounter int64
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
+
+func fileChecksum(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/paths"
+	"github.com/aouyang1/digitalphotoframe/thermal"
+	"github.com/aouyang1/digitalphotoframe/util"
+)
+
+// partialImportPattern names photos being copied in, which aren't picked up as photos by the
+// local directory scan
+const partialImportPattern = ".import-*.part"
+
+// ImportOptions are how photos from an export are imported
+type ImportOptions struct {
+	// UploadedBy is who the photos are recorded as uploaded by
+	UploadedBy string
+
+	// DryRun checks the export and reports what would be imported without writing anything
+	DryRun bool
+}
+
+// ImportReport counts what an import did with the files it was given, or would do in a dry run
+type ImportReport struct {
+	DryRun     bool `json:"dry_run"`
+	Added      int  `json:"added"`
+	Duplicates int  `json:"duplicates"`
+
+	// Unsupported files are videos and image formats the frame can't show
+	Unsupported int `json:"unsupported"`
+
+	// Failed files couldn't be read or added
+	Failed int `json:"failed"`
+
+	// Bytes is the size of the photos added, before they're downsized
+	Bytes int64 `json:"bytes"`
+}
+
+// importItem is a photo from an export along with what the export knows about it
+type importItem struct {
+	// name is the file name, and source where it came from in the export for logging
+	name   string
+	source string
+
+	open  func() (io.ReadCloser, error)
+	album string
+	meta  *Sidecar
+
+	// motion, if set, opens the video of a live photo exported along with it, named motionName
+	motion     func() (io.ReadCloser, error)
+	motionName string
+
+	// uploadedBy, if set, is who the photo is recorded as uploaded by instead of whoever the import
+	// is from
+	uploadedBy string
+
+	// copied, if set, is called once the photo is on the frame with the name it was added as, or
+	// empty when it was a copy of a photo already there
+	copied func(name string)
+}
+
+// importer adds photos from an export to My Photos, dropping copies of photos already on the frame
+type importer struct {
+	s          *PhotoService
+	opts       ImportOptions
+	duplicates *duplicateIndex
+	report     *ImportReport
+}
+
+func (s *PhotoService) newImporter(opts ImportOptions) (*importer, error) {
+	duplicates, err := s.newDuplicateIndex(paths.CategoryOriginal)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list photos, %w", err)
+	}
+	return &importer{
+		s:          s,
+		opts:       opts,
+		duplicates: duplicates,
+		report:     &ImportReport{DryRun: opts.DryRun},
+	}, nil
+}
+
+// importAll adds the items in order, stopping early when the context is done
+func (im *importer) importAll(ctx context.Context, items []importItem) error {
+	for _, item := range items {
+		// imports can be thousands of photos, so they wait out a hot cpu
+		err := thermal.Wait(ctx, 0, 1)
+		if err == nil {
+			err = ctx.Err()
+		}
+		if err != nil {
+			return fmt.Errorf("import stopped after %d photos, %w", im.report.Added, err)
+		}
+		if !util.SupportedExt.Contains(filepath.Ext(item.name)) {
+			im.report.Unsupported++
+			continue
+		}
+		add := im.add
+		if im.opts.DryRun {
+			add = im.check
+		}
+		if err := add(item); err != nil {
+			slog.Warn("unable to import photo", "source", item.source, "error", err)
+			im.report.Failed++
+		}
+	}
+	return nil
+}
+
+// albumsFirst orders the items from albums ahead of the rest, keeping their order otherwise, so
+// when an export has a photo both in an album and outside of it the copy outside is the one skipped
+func albumsFirst(items []importItem) {
+	slices.SortStableFunc(items, func(a, b importItem) int {
+		switch {
+		case a.album != "" && b.album == "":
+			return -1
+		case a.album == "" && b.album != "":
+			return 1
+		}
+		return 0
+	})
+}
+
+// add copies a photo into My Photos and registers it with the album and metadata it came with
+func (im *importer) add(item importItem) error {
+	category := paths.CategoryOriginal
+	originalDir := im.s.paths.OriginalDir(category)
+	if err := os.MkdirAll(originalDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory, %w", err)
+	}
+
+	tmpPath, size, err := copyToTemp(originalDir, item)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+
+	if err := CheckImage(tmpPath); err != nil {
+		return err
+	}
+	duplicate, err := im.duplicates.find(tmpPath)
+	if err != nil {
+		return err
+	}
+	if duplicate != "" {
+		slog.Debug("skipping imported photo already on the frame", "source", item.source, "duplicate_of", duplicate)
+		im.report.Duplicates++
+		if item.copied != nil {
+			item.copied("")
+		}
+		return nil
+	}
+
+	// taken before the photo is downsized, so later copies in the export still match it
+	sum, err := fileChecksum(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	name, err := im.s.FreeName(category, item.name)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, im.s.paths.Original(category, name)); err != nil {
+		return fmt.Errorf("failed to move imported photo, %w", err)
+	}
+	if item.motion != nil {
+		if err := copyMotion(item, im.s.paths.Original(category, name)); err != nil {
+			slog.Warn("unable to import live photo video, keeping only the still", "source", item.source, "error", err)
+		}
+	}
+	uploadedBy := im.opts.UploadedBy
+	if item.uploadedBy != "" {
+		uploadedBy = item.uploadedBy
+	}
+	if err := im.s.Add(name, category, uploadedBy, false); err != nil {
+		return err
+	}
+	im.duplicates.add(name, size, sum)
+	im.report.Added++
+	im.report.Bytes += size
+
+	var takenAt time.Time
+	if item.meta != nil {
+		im.s.applyMetadata(name, category, item.meta)
+		takenAt = item.meta.TakenAt
+	}
+	if item.album != "" {
+		if err := im.s.db.UpdatePhotoAlbum(name, category, item.album, takenAt); err != nil {
+			slog.Warn("unable to add imported photo to album", "name", name, "album", item.album, "error", err)
+		}
+	}
+	if item.copied != nil {
+		item.copied(name)
+	}
+	return nil
+}
+
+// check counts a photo as add would without writing it anywhere, reading it through to see whether
+// it's an image and a copy of a photo already on the frame or earlier in the export
+func (im *importer) check(item importItem) error {
+	src, err := item.open()
+	if err != nil {
+		return fmt.Errorf("unable to open %s, %w", item.source, err)
+	}
+	defer src.Close()
+
+	sum, size, err := checkImageContent(src)
+	if err != nil {
+		return err
+	}
+	duplicate, err := im.duplicates.lookup(size, func() ([]byte, error) { return sum, nil })
+	if err != nil {
+		return err
+	}
+	if duplicate != "" {
+		im.report.Duplicates++
+		return nil
+	}
+
+	// the source stands in for the name it would be given, its checksum already known
+	im.duplicates.add(item.source, size, sum)
+	im.report.Added++
+	im.report.Bytes += size
+	return nil
+}
+
+// copyMotion copies the video of a live photo alongside the photo it was imported as, where it's
+// picked up when the photo is registered
+func copyMotion(item importItem, photoPath string) error {
+	src, err := item.motion()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(strings.TrimSuffix(photoPath, filepath.Ext(photoPath)) + strings.ToLower(filepath.Ext(item.motionName)))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, src)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func copyToTemp(dir string, item importItem) (string, int64, error) {
+	src, err := item.open()
+	if err != nil {
+		return "", 0, fmt.Errorf("unable to open %s, %w", item.source, err)
+	}
+	defer src.Close()
+
+	tmp, err := os.CreateTemp(dir, partialImportPattern)
+	if err != nil {
+		return "", 0, fmt.Errorf("unable to create file for import, %w", err)
+	}
+	size, err := io.Copy(tmp, src)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", 0, fmt.Errorf("unable to copy %s, %w", item.source, err)
+	}
+	return tmp.Name(), size, nil
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/aouyang1/digitalphotoframe/imaging"
+	"github.com/aouyang1/digitalphotoframe/util"
+)
+
+// MotionMimeTypes are the content types the videos of motion and live photos are served with
+var MotionMimeTypes = map[string]string{
+	".mov": "video/quicktime",
+	".mp4": "video/mp4",
+}
+
+// IsMotionVideo reports whether name is a video that can belong to a live photo
+func IsMotionVideo(name string) bool {
+	return util.MotionExt.Contains(filepath.Ext(name))
+}
+
+// MotionCandidates lists the names the video of a live photo may have, which is the photo's name
+// with the extension replaced like IMG_0001.MOV
+func MotionCandidates(photo string) []string {
+	exts := util.MotionExt.ToSlice()
+	slices.Sort(exts)
+
+	stem := strings.TrimSuffix(photo, filepath.Ext(photo))
+	candidates := make([]string, 0, len(exts))
+	for _, ext := range exts {
+		candidates = append(candidates, stem+ext)
+	}
+	return candidates
+}
+
+// FindMotionVideo returns the path of the live photo video alongside the photo
+func FindMotionVideo(photoPath string) (string, bool) {
+	for _, candidate := range MotionCandidates(photoPath) {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// MotionVideoPhoto returns the path of the photo in the same directory the live photo video
+// belongs to
+func MotionVideoPhoto(videoPath string) (string, bool) {
+	stem := strings.TrimSuffix(videoPath, filepath.Ext(videoPath))
+	for ext := range util.SupportedExt.Iter() {
+		if _, err := os.Stat(stem + ext); err == nil {
+			return stem + ext, true
+		}
+	}
+	return "", false
+}
+
+// importMotion keeps the video of a live or motion photo so it can be played before the still. A
+// live photo's video is moved from alongside the photo, and a motion photo's is copied out of it,
+// leaving the original whole.
+func (s *PhotoService) importMotion(name string, category int) {
+	ext, err := s.saveMotion(name, category)
+	if err != nil {
+		slog.Warn("unable to import motion video, keeping only the still", "name", name, "error", err)
+		return
+	}
+	if ext == "" {
+		return
+	}
+	if err := s.db.UpdatePhotoMotion(name, category, ext); err != nil {
+		slog.Warn("unable to record motion video", "name", name, "error", err)
+		return
+	}
+	slog.Info("imported motion video", "name", name, "category", category, "ext", ext)
+}
+
+// saveMotion stores the video of a live or motion photo, returning its extension or empty when
+// the photo has none
+func (s *PhotoService) saveMotion(name string, category int) (string, error) {
+	original := s.paths.Original(category, name)
+	if video, ok := FindMotionVideo(original); ok {
+		ext := strings.ToLower(filepath.Ext(video))
+		if err := os.MkdirAll(s.paths.MotionDir(category), 0o755); err != nil {
+			return "", fmt.Errorf("failed to create directory, %w", err)
+		}
+		if err := os.Rename(video, s.paths.Motion(category, name, ext)); err != nil {
+			return "", fmt.Errorf("failed to move live photo video, %w", err)
+		}
+		return ext, nil
+	}
+
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".jpg", ".jpeg":
+	default:
+		return "", nil
+	}
+	f, err := os.Open(original)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	start, ok, err := imaging.MotionVideoStart(f, info.Size())
+	if err != nil || !ok {
+		return "", err
+	}
+
+	if err := os.MkdirAll(s.paths.MotionDir(category), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create directory, %w", err)
+	}
+	dst, err := os.Create(s.paths.Motion(category, name, ".mp4"))
+	if err != nil {
+		return "", fmt.Errorf("failed to create motion photo video, %w", err)
+	}
+	_, err = io.Copy(dst, io.NewSectionReader(f, start, info.Size()-start))
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(dst.Name())
+		return "", fmt.Errorf("failed to write motion photo video, %w", err)
+	}
+	return ".mp4", nil
+}
+
+// deleteMotion removes the video of a live or motion photo, along with a live photo video still
+// alongside the photo if it was never registered
+func (s *PhotoService) deleteMotion(name string, category int) error {
+	var files []string
+	if video, ok := FindMotionVideo(s.paths.Original(category, name)); ok {
+		files = append(files, video)
+	}
+	for ext := range MotionMimeTypes {
+		files = append(files, s.paths.Motion(category, name, ext))
+	}
+	for _, path := range files {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+)
+
+// PairedPhoto is a photo in an album another frame shares with this one
+type PairedPhoto struct {
+	// Name is the photo's file name on the other frame
+	Name string
+	Open func() (io.ReadCloser, error)
+	Meta *Sidecar
+
+	// UploadedBy is who uploaded the photo to the other frame, recorded in place of who the import
+	// is from when set
+	UploadedBy string
+
+	// Copied is called once the photo is on this frame with the name it was added as, or empty
+	// when it was a copy of a photo already here. It isn't called for photos that failed.
+	Copied func(name string)
+}
+
+// ImportPaired adds the photos from an album another frame shares to the album in My Photos,
+// keeping their captions, tags, and dates. Copies of photos already on the frame are skipped.
+func (s *PhotoService) ImportPaired(ctx context.Context, album string, photos []PairedPhoto, opts ImportOptions) (*ImportReport, error) {
+	items := make([]importItem, 0, len(photos))
+	for _, photo := range photos {
+		items = append(items, importItem{
+			name:       photo.Name,
+			source:     photo.Name,
+			open:       photo.Open,
+			album:      album,
+			meta:       photo.Meta,
+			uploadedBy: photo.UploadedBy,
+			copied:     photo.Copied,
+		})
+	}
+
+	im, err := s.newImporter(opts)
+	if err != nil {
+		return nil, err
+	}
+	err = im.importAll(ctx, items)
+	if im.report.Added > 0 || im.report.Failed > 0 {
+		slog.Info("imported paired album", "album", album, "added", im.report.Added, "duplicates", im.report.Duplicates, "unsupported", im.report.Unsupported, "failed", im.report.Failed, "bytes", im.report.Bytes)
+	}
+	return im.report, err
+}
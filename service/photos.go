@@ -0,0 +1,273 @@
+// Package service holds the photo operations shared by the http handlers and the background
+// managers so both go straight to the database and disk instead of through the http api
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/aouyang1/digitalphotoframe/cache"
+	"github.com/aouyang1/digitalphotoframe/imaging"
+	"github.com/aouyang1/digitalphotoframe/paths"
+	"github.com/aouyang1/digitalphotoframe/slideshow"
+	"github.com/aouyang1/digitalphotoframe/store"
+	"github.com/aouyang1/digitalphotoframe/thermal"
+	"github.com/aouyang1/digitalphotoframe/util"
+	mapset "github.com/deckarep/golang-set/v2"
+)
+
+var (
+	ErrInvalidCategory = errors.New("category must be 0 (surprise) or 1 (original)")
+	ErrUnsupportedExt  = errors.New("unsupported file extension")
+	ErrFileNotFound    = errors.New("photo file does not exist")
+	ErrExists          = errors.New("photo already exists")
+	ErrNotFound        = errors.New("photo not found")
+)
+
+// PhotoService registers and removes photos, keeping the database in step with the files on disk
+type PhotoService struct {
+	db    *store.Database
+	paths paths.Layout
+
+	// resized copies served to the ui, dropped when a photo is deleted
+	imageCache *cache.LRU
+}
+
+func NewPhotoService(db *store.Database, layout paths.Layout, imageCache *cache.LRU) (*PhotoService, error) {
+	if db == nil {
+		return nil, errors.New("no database provided for photo service")
+	}
+	return &PhotoService{
+		db:         db,
+		paths:      layout,
+		imageCache: imageCache,
+	}, nil
+}
+
+// Register adds a photo whose original is already on disk to the end of its category, returning
+// ErrExists if it is already registered. A photo synced from a remote or uploaded by a guest is
+// reviewed, waiting for approval when it is required. Metadata in a sidecar file alongside the
+// original is imported.
+func (s *PhotoService) Register(name string, category int, uploadedBy string, review bool) error {
+	if category != paths.CategorySurprise && category != paths.CategoryOriginal {
+		return ErrInvalidCategory
+	}
+	if ext := filepath.Ext(name); !util.SupportedExt.Contains(ext) {
+		return fmt.Errorf("%w: %s", ErrUnsupportedExt, ext)
+	}
+
+	if _, err := os.Stat(s.paths.Original(category, name)); os.IsNotExist(err) {
+		return fmt.Errorf("%w: %s", ErrFileNotFound, name)
+	}
+
+	exists, err := s.db.PhotoExists(name, category)
+	if err != nil {
+		return fmt.Errorf("database error, %w", err)
+	}
+	if exists {
+		return fmt.Errorf("%w: %s", ErrExists, name)
+	}
+
+	var pending bool
+	if review {
+		settings, err := s.db.GetAppSettings()
+		if err != nil {
+			return fmt.Errorf("database error, %w", err)
+		}
+		pending = settings.ApproveSurprise
+	}
+
+	order, err := s.db.InsertPhotoAutoOrder(name, category, uploadedBy, pending)
+	if err != nil {
+		return fmt.Errorf("failed to insert photo into database, %w", err)
+	}
+	slog.Info("photo registered successfully", "name", name, "category", category, "order", order, "pending", pending)
+
+	// a sidecar's date overrides the exif date
+	s.recordDateTaken(name, category)
+	s.importSidecar(name, category)
+	s.importMotion(name, category)
+	return nil
+}
+
+// recordDateTaken stores when a photo was taken from its exif so overlays and albums don't decode
+// it again, reporting whether it had a date
+func (s *PhotoService) recordDateTaken(name string, category int) bool {
+	taken, err := imaging.DateTaken(s.paths.Original(category, name))
+	if err != nil {
+		slog.Debug("photo has no exif date", "name", name, "error", err)
+		return false
+	}
+	if err := s.db.UpdatePhotoTakenAt(name, category, taken); err != nil {
+		slog.Warn("unable to record when photo was taken", "name", name, "error", err)
+		return false
+	}
+	return true
+}
+
+// DateUndated records the exif date of photos registered before dates were stored on import,
+// returning how many were dated. Photos in an album without a date were already found to have
+// none.
+func (s *PhotoService) DateUndated() (int, error) {
+	var dated int
+	for _, category := range paths.Categories {
+		for photo, err := range s.db.AllPhotos(store.PhotoFilter{Category: category}) {
+			if err != nil {
+				return dated, err
+			}
+			if !photo.TakenAt.IsZero() || photo.Album != "" {
+				continue
+			}
+			if s.recordDateTaken(photo.PhotoName, photo.Category) {
+				dated++
+			}
+		}
+	}
+	return dated, nil
+}
+
+// Add downsizes a photo whose original was just saved to disk and registers it, removing the
+// original if it can't be registered. A guest's photo is reviewed like one synced from a remote.
+func (s *PhotoService) Add(name string, category int, uploadedBy string, review bool) error {
+	originalDir := s.paths.OriginalDir(category)
+	filePath := filepath.Join(originalDir, name)
+
+	// auto resize so that viewing in ui is more reliable
+	targetMaxDimStr := os.Getenv("DPF_TARGET_MAX_DIM")
+	targetMaxDim, err := strconv.Atoi(targetMaxDimStr)
+	if err != nil {
+		slog.Warn("unable to parse DPF_TARGET_MAX_DIM, using default", "DPF_TARGET_MAX_DIM", targetMaxDimStr, "default", slideshow.DefaultTargetMaxDim)
+		targetMaxDim = slideshow.DefaultTargetMaxDim
+	}
+
+	// the slideshow downsizes it along with the rest when it restarts, so a hot cpu leaves it
+	// until then rather than keeping the upload waiting
+	if thermal.Hot() {
+		slog.Info("deferring downsizing while the cpu is hot", "name", name)
+	} else if rOpt, err := slideshow.GenerateRotateOptions(originalDir, name, targetMaxDim); err != nil {
+		slog.Warn("unable generate rotate options", "error", err)
+	} else if err := slideshow.Downsize(rOpt); err != nil {
+		slog.Warn("failed to downsize image", "name", rOpt.Name, "error", err)
+	}
+
+	// Insert into database
+	if err := s.Register(name, category, uploadedBy, review); err != nil {
+		// Clean up file if DB insert fails
+		if remErr := os.Remove(filePath); remErr != nil {
+			return fmt.Errorf("%w, with failed file removal, %w", err, remErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// Delete removes a photo from the database along with its original and everything generated from
+// it, returning ErrNotFound if it is not registered
+func (s *PhotoService) Delete(name string, category int) error {
+	exists, err := s.db.PhotoExists(name, category)
+	if err != nil {
+		return fmt.Errorf("database error, %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	if err := s.deleteFiles(category, name); err != nil {
+		return fmt.Errorf("failed to delete file, %w", err)
+	}
+
+	if err := s.db.DeletePhoto(name, category); err != nil {
+		return fmt.Errorf("failed to delete photo from database, %w", err)
+	}
+	return nil
+}
+
+// Replaced removes everything generated from a photo whose original was replaced with new content,
+// so it is generated again from the new original
+func (s *PhotoService) Replaced(name string, category int) error {
+	if err := s.deleteGenerated(category, name); err != nil {
+		return fmt.Errorf("failed to delete generated files, %w", err)
+	}
+	return nil
+}
+
+// deleteFiles removes a photo's original, its sidecar, and the video of a motion or live photo
+// along with everything generated from it
+func (s *PhotoService) deleteFiles(category int, name string) error {
+	if sidecar, ok := FindSidecar(s.paths.Original(category, name)); ok {
+		if err := os.Remove(sidecar); err != nil {
+			return err
+		}
+	}
+	if err := s.deleteMotion(name, category); err != nil {
+		return err
+	}
+	if err := os.Remove(s.paths.Original(category, name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return s.deleteGenerated(category, name)
+}
+
+// deleteGenerated removes a photo's slideshow derivative and any resized copies on disk or in
+// memory. Captioned copies are cleaned up when the slideshow restarts.
+func (s *PhotoService) deleteGenerated(category int, name string) error {
+	files := []string{s.paths.Derivative(category, name)}
+
+	variants, err := os.ReadDir(s.paths.ResizedDir(category))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, variant := range variants {
+		if !variant.IsDir() {
+			continue
+		}
+		resizedPath := s.paths.Resized(category, variant.Name(), name)
+		s.imageCache.Remove(resizedPath)
+		files = append(files, resizedPath)
+	}
+
+	for _, path := range files {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reconcile registers every photo in names and deregisters photos in the category that are no
+// longer among them, returning how many photos were added and removed. New photos are reviewed
+// when they were synced from a remote.
+func (s *PhotoService) Reconcile(category int, names mapset.Set[string], review bool) (int, int, error) {
+	var added int
+	for name := range names.Iter() {
+		err := s.Register(name, category, "", review)
+		switch {
+		case err == nil:
+			added++
+		case errors.Is(err, ErrExists):
+		default:
+			slog.Warn("error while registering local photo", "name", name, "category", category, "error", err)
+		}
+	}
+
+	var removed int
+	for photo, err := range s.db.AllPhotos(store.PhotoFilter{Category: category}) {
+		if err != nil {
+			return added, removed, fmt.Errorf("error getting registered photos, %w", err)
+		}
+		if names.Contains(photo.PhotoName) {
+			continue
+		}
+		slog.Info("deregistering photo not present locally", "name", photo.PhotoName, "category", category)
+		if err := s.Delete(photo.PhotoName, category); err != nil {
+			slog.Warn("error while deregistering photo", "name", photo.PhotoName, "category", category, "error", err)
+			continue
+		}
+		removed++
+	}
+	return added, removed, nil
+}
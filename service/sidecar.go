@@ -0,0 +1,306 @@
+package service
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/util"
+)
+
+// maxSidecarSize keeps a stray large file named like a sidecar from being read into memory
+const maxSidecarSize = 1 << 20
+
+// sidecarDateLayouts are the date formats accepted from sidecars, those without a zone being in
+// local time
+var sidecarDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006:01:02 15:04:05",
+	"2006-01-02",
+}
+
+// favoriteRating is the XMP rating of a favorite, the five stars Photos and other apps write for
+// favorites on export
+const favoriteRating = "5"
+
+// Sidecar is the metadata curated for a photo elsewhere, read from a JSON or XMP file kept
+// alongside it
+type Sidecar struct {
+	Caption  string
+	Tags     []string
+	TakenAt  time.Time
+	Favorite bool
+}
+
+// IsSidecar reports whether the file name is that of a sidecar
+func IsSidecar(name string) bool {
+	return util.SidecarExt.Contains(filepath.Ext(name))
+}
+
+// SidecarCandidates lists the names a photo's sidecar may have, in the order they're looked for:
+// after the whole file like IMG_0001.jpg.xmp, or with the extension replaced like IMG_0001.xmp
+func SidecarCandidates(photo string) []string {
+	exts := util.SidecarExt.ToSlice()
+	slices.Sort(exts)
+
+	var candidates []string
+	for _, stem := range []string{photo, strings.TrimSuffix(photo, filepath.Ext(photo))} {
+		for _, ext := range exts {
+			candidates = append(candidates, stem+ext)
+		}
+	}
+	return candidates
+}
+
+// FindSidecar returns the path of the sidecar alongside the photo
+func FindSidecar(photoPath string) (string, bool) {
+	for _, candidate := range SidecarCandidates(photoPath) {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// SidecarPhoto returns the path of a photo in the same directory the sidecar belongs to
+func SidecarPhoto(sidecarPath string) (string, bool) {
+	stem := strings.TrimSuffix(sidecarPath, filepath.Ext(sidecarPath))
+	if util.SupportedExt.Contains(filepath.Ext(stem)) {
+		if _, err := os.Stat(stem); err == nil {
+			return stem, true
+		}
+	}
+	for ext := range util.SupportedExt.Iter() {
+		if _, err := os.Stat(stem + ext); err == nil {
+			return stem + ext, true
+		}
+	}
+	return "", false
+}
+
+// ReadSidecar parses a JSON or XMP sidecar file
+func ReadSidecar(path string) (*Sidecar, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseSidecar(f, filepath.Ext(path))
+}
+
+// ParseSidecar parses sidecar content, JSON or XMP according to the extension ext
+func ParseSidecar(r io.Reader, ext string) (*Sidecar, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxSidecarSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read sidecar, %w", err)
+	}
+	if len(data) > maxSidecarSize {
+		return nil, errors.New("sidecar is too large")
+	}
+
+	switch strings.ToLower(ext) {
+	case ".json":
+		return parseJSONSidecar(data)
+	case ".xmp":
+		return parseXMPSidecar(data)
+	default:
+		return nil, fmt.Errorf("unsupported sidecar extension, %s", ext)
+	}
+}
+
+// jsonSidecar is the JSON sidecar format, accepting the names other tools commonly use as well
+type jsonSidecar struct {
+	Caption     string   `json:"caption"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	Keywords    []string `json:"keywords"`
+	TakenAt     string   `json:"taken_at"`
+	Date        string   `json:"date"`
+	Favorite    bool     `json:"favorite"`
+}
+
+func parseJSONSidecar(data []byte) (*Sidecar, error) {
+	var raw jsonSidecar
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("unable to parse json sidecar, %w", err)
+	}
+
+	sidecar := &Sidecar{
+		Caption:  cmp.Or(raw.Caption, raw.Description),
+		Tags:     cleanTags(append(raw.Tags, raw.Keywords...)),
+		Favorite: raw.Favorite,
+	}
+	if date := cmp.Or(raw.TakenAt, raw.Date); date != "" {
+		takenAt, err := parseSidecarDate(date)
+		if err != nil {
+			return nil, err
+		}
+		sidecar.TakenAt = takenAt
+	}
+	return sidecar, nil
+}
+
+// parseXMPSidecar reads the description, or the title without one, as the caption, the subject as
+// tags, the first of the original, created, or creation dates as when the photo was taken, and a
+// five star rating as a favorite. Values may be given as elements or as attributes of
+// rdf:Description, as lightroom, darktable, digikam, and Photos write them.
+func parseXMPSidecar(data []byte) (*Sidecar, error) {
+	var (
+		caption string
+		title   string
+		rating  string
+		tags    []string
+		dates   = make(map[string]string)
+		stack   []string
+	)
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse xmp sidecar, %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			stack = append(stack, t.Name.Local)
+			for _, attr := range t.Attr {
+				if isXMPDate(attr.Name.Local) {
+					dates[attr.Name.Local] = cmp.Or(dates[attr.Name.Local], attr.Value)
+				}
+				if attr.Name.Local == "Rating" {
+					rating = cmp.Or(rating, attr.Value)
+				}
+			}
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			text := strings.TrimSpace(string(t))
+			if text == "" || len(stack) == 0 {
+				continue
+			}
+			switch field := xmpField(stack); {
+			case field == "description":
+				caption = cmp.Or(caption, text)
+			case field == "title":
+				title = cmp.Or(title, text)
+			case field == "subject":
+				tags = append(tags, text)
+			case field == "Rating":
+				rating = cmp.Or(rating, text)
+			case isXMPDate(field):
+				dates[field] = cmp.Or(dates[field], text)
+			}
+		}
+	}
+
+	sidecar := &Sidecar{
+		Caption:  cmp.Or(caption, title),
+		Tags:     cleanTags(tags),
+		Favorite: rating == favoriteRating,
+	}
+	if date := cmp.Or(dates["DateTimeOriginal"], dates["DateCreated"], dates["CreateDate"]); date != "" {
+		takenAt, err := parseSidecarDate(date)
+		if err != nil {
+			return nil, err
+		}
+		sidecar.TakenAt = takenAt
+	}
+	return sidecar, nil
+}
+
+// xmpField names the property the innermost element belongs to, skipping the rdf containers that
+// hold the values of list and language properties
+func xmpField(stack []string) string {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch stack[i] {
+		case "li", "Alt", "Bag", "Seq":
+			continue
+		}
+		return stack[i]
+	}
+	return ""
+}
+
+func isXMPDate(name string) bool {
+	return name == "DateTimeOriginal" || name == "DateCreated" || name == "CreateDate"
+}
+
+func parseSidecarDate(value string) (time.Time, error) {
+	for _, layout := range sidecarDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized sidecar date %q", value)
+}
+
+// cleanTags trims tags and drops empty and repeated ones, keeping their order
+func cleanTags(tags []string) []string {
+	var cleaned []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !slices.Contains(cleaned, tag) {
+			cleaned = append(cleaned, tag)
+		}
+	}
+	return cleaned
+}
+
+// importSidecar applies the metadata in the sidecar alongside a photo's original, if it has one.
+// Only what the sidecar sets is changed.
+func (s *PhotoService) importSidecar(name string, category int) {
+	path, ok := FindSidecar(s.paths.Original(category, name))
+	if !ok {
+		return
+	}
+	sidecar, err := ReadSidecar(path)
+	if err != nil {
+		slog.Warn("unable to read sidecar, skipping its metadata", "name", name, "sidecar", filepath.Base(path), "error", err)
+		return
+	}
+
+	s.applyMetadata(name, category, sidecar)
+	slog.Info("imported sidecar metadata", "name", name, "sidecar", filepath.Base(path))
+}
+
+// applyMetadata records what the sidecar sets for a photo, leaving the rest as it is
+func (s *PhotoService) applyMetadata(name string, category int, sidecar *Sidecar) {
+	if sidecar.Caption != "" {
+		if err := s.db.UpdatePhotoCaption(name, category, sidecar.Caption); err != nil {
+			slog.Warn("unable to import photo caption", "name", name, "error", err)
+		}
+	}
+	if len(sidecar.Tags) > 0 {
+		if err := s.db.UpdatePhotoTags(name, category, sidecar.Tags); err != nil {
+			slog.Warn("unable to import photo tags", "name", name, "error", err)
+		}
+	}
+	if !sidecar.TakenAt.IsZero() {
+		if err := s.db.UpdatePhotoTakenAt(name, category, sidecar.TakenAt); err != nil {
+			slog.Warn("unable to import photo date", "name", name, "error", err)
+		}
+	}
+	// favorites are pinned so they keep coming around, but a photo isn't unpinned for not being one
+	if sidecar.Favorite {
+		if err := s.db.UpdatePhotoPinned(name, category, true); err != nil {
+			slog.Warn("unable to pin favorite photo", "name", name, "error", err)
+		}
+	}
+}
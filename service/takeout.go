@@ -0,0 +1,223 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// takeoutTrash holds photos deleted in Google Photos, which aren't imported
+	takeoutTrash = "Trash"
+
+	// takeoutAlbumMetadata describes the album a folder was exported from
+	takeoutAlbumMetadata = "metadata.json"
+
+	// takeoutSupplemental is added to metadata file names by newer exports, before .json
+	takeoutSupplemental = ".supplemental-metadata"
+
+	// takeoutMaxName is the length metadata file names are cut to, so the ends of longer names
+	// are missing
+	takeoutMaxName = 51
+)
+
+var (
+	// takeoutYearFolder matches the folders photos that aren't in an album are exported to
+	takeoutYearFolder = regexp.MustCompile(`(?i)^photos from \d{4}$`)
+
+	// takeoutCopy matches the numbered names given to photos with the same name in a folder, like
+	// IMG_0001(1).jpg, whose metadata is in IMG_0001.jpg(1).json
+	takeoutCopy = regexp.MustCompile(`^(.*)(\(\d+\))(\.[^.]+)$`)
+)
+
+// takeoutMetadata is the part of a photo's metadata file in a Google Takeout archive that the
+// frame keeps
+type takeoutMetadata struct {
+	Description    string `json:"description"`
+	PhotoTakenTime struct {
+		Timestamp string `json:"timestamp"`
+	} `json:"photoTakenTime"`
+	People []struct {
+		Name string `json:"name"`
+	} `json:"people"`
+}
+
+// takeoutFolder is a folder of an archive with its photos and metadata files
+type takeoutFolder struct {
+	files    []*zip.File
+	metadata map[string]*zip.File
+}
+
+// ImportTakeout adds the photos in a Google Takeout archive to My Photos. Photos exported from
+// an album are put in an album of the same name, and the description, people, and time taken in
+// each photo's metadata file become its caption, tags, and date. Photos in the trash are left out,
+// and copies of photos already on the frame, such as a photo exported from both an album and its
+// year, are skipped. A dry run reports what would be imported without writing anything.
+func (s *PhotoService) ImportTakeout(ctx context.Context, archive *zip.Reader, opts ImportOptions) (*ImportReport, error) {
+	folders := make(map[string]*takeoutFolder)
+	for _, f := range archive.File {
+		name := path.Base(f.Name)
+		if f.FileInfo().IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		dir := path.Dir(f.Name)
+		folder, ok := folders[dir]
+		if !ok {
+			folder = &takeoutFolder{metadata: make(map[string]*zip.File)}
+			folders[dir] = folder
+		}
+		switch strings.ToLower(path.Ext(name)) {
+		case ".json":
+			folder.metadata[name] = f
+		case ".html":
+			// the archive's index page
+		default:
+			folder.files = append(folder.files, f)
+		}
+	}
+
+	var items []importItem
+	for _, dir := range slices.Sorted(maps.Keys(folders)) {
+		if path.Base(dir) == takeoutTrash {
+			continue
+		}
+		folder := folders[dir]
+		album := folder.album(dir)
+		for _, f := range folder.files {
+			items = append(items, importItem{
+				name:   path.Base(f.Name),
+				source: f.Name,
+				open:   f.Open,
+				album:  album,
+				meta:   folder.photoMetadata(path.Base(f.Name)),
+			})
+		}
+	}
+	albumsFirst(items)
+
+	im, err := s.newImporter(opts)
+	if err != nil {
+		return nil, err
+	}
+	err = im.importAll(ctx, items)
+	slog.Info("imported google takeout archive", "dry_run", opts.DryRun, "added", im.report.Added, "duplicates", im.report.Duplicates, "unsupported", im.report.Unsupported, "failed", im.report.Failed, "bytes", im.report.Bytes)
+	return im.report, err
+}
+
+// album names the album the folder was exported from, or is empty for the folders of photos by
+// year and folders outside Google Photos
+func (f *takeoutFolder) album(dir string) string {
+	if metadata, ok := f.metadata[takeoutAlbumMetadata]; ok {
+		var album struct {
+			Title string `json:"title"`
+		}
+		if err := readTakeoutJSON(metadata, &album); err != nil {
+			slog.Warn("unable to read takeout album metadata", "folder", dir, "error", err)
+		} else if title := strings.TrimSpace(album.Title); title != "" {
+			return title
+		}
+	}
+	if takeoutYearFolder.MatchString(path.Base(dir)) || dir == "." {
+		return ""
+	}
+	return path.Base(dir)
+}
+
+// photoMetadata reads the metadata file of a photo in the folder, returning nil when it has none
+// or it can't be read
+func (f *takeoutFolder) photoMetadata(name string) *Sidecar {
+	file, ok := f.metadataFile(name)
+	if !ok {
+		return nil
+	}
+
+	var raw takeoutMetadata
+	if err := readTakeoutJSON(file, &raw); err != nil {
+		slog.Warn("unable to read takeout photo metadata", "photo", name, "metadata", file.Name, "error", err)
+		return nil
+	}
+
+	sidecar := &Sidecar{Caption: strings.TrimSpace(raw.Description)}
+	for _, person := range raw.People {
+		sidecar.Tags = append(sidecar.Tags, person.Name)
+	}
+	sidecar.Tags = cleanTags(sidecar.Tags)
+	if seconds, err := strconv.ParseInt(raw.PhotoTakenTime.Timestamp, 10, 64); err == nil && seconds > 0 {
+		sidecar.TakenAt = time.Unix(seconds, 0)
+	}
+	return sidecar
+}
+
+// metadataFile finds the metadata file of a photo, which is named after the whole photo file
+// with .json or .supplemental-metadata.json added. Numbered copies put the number after the
+// extension, edited photos share the metadata of the original, and names too long are cut short.
+func (f *takeoutFolder) metadataFile(name string) (*zip.File, bool) {
+	var candidates []string
+	for _, photo := range []string{name, strings.Replace(name, "-edited", "", 1)} {
+		candidates = append(candidates, photo+takeoutSupplemental+".json", photo+".json")
+		if m := takeoutCopy.FindStringSubmatch(photo); m != nil {
+			base, number, ext := m[1], m[2], m[3]
+			candidates = append(candidates, base+ext+takeoutSupplemental+number+".json", base+ext+number+".json")
+		}
+	}
+	for _, candidate := range candidates {
+		if file, ok := f.metadata[candidate]; ok {
+			return file, true
+		}
+	}
+
+	// a cut short name is a prefix of the photo's name, possibly ending partway into
+	// .supplemental-metadata
+	var best *zip.File
+	var bestLen int
+	for metadataName, file := range f.metadata {
+		if len(metadataName) < takeoutMaxName-len(".json") || metadataName == takeoutAlbumMetadata {
+			continue
+		}
+		stem := strings.TrimSuffix(metadataName, ".json")
+		if i := strings.LastIndex(stem, "."); i >= 0 && strings.HasPrefix(takeoutSupplemental, stem[i:]) {
+			stem = stem[:i]
+		}
+		if len(stem) > bestLen && strings.HasPrefix(name, stem) {
+			best, bestLen = file, len(stem)
+		}
+	}
+	return best, best != nil
+}
+
+func readTakeoutJSON(f *zip.File, v any) error {
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(io.LimitReader(r, maxSidecarSize))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("unable to parse %s, %w", f.Name, err)
+	}
+	return nil
+}
+
+// OpenTakeout opens a Google Takeout zip archive at the path for ImportTakeout, returning a
+// function to close it
+func OpenTakeout(archivePath string) (*zip.Reader, func() error, error) {
+	rc, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to open takeout archive, %w", err)
+	}
+	return &rc.Reader, rc.Close, nil
+}
@@ -0,0 +1,84 @@
+package slideshow
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"slices"
+
+	"github.com/aouyang1/digitalphotoframe/imaging"
+	"github.com/aouyang1/digitalphotoframe/paths"
+	"golang.org/x/image/draw"
+)
+
+// collageGapDivisor sets the gap between the photos of a collage as a fraction of its shorter side
+const collageGapDivisor = 80
+
+// applyCollages leaves out the collages in imgPaths that haven't been rendered yet, queueing them
+// to be rendered in the background from the derivatives listed for them in collages. Collages
+// that haven't been played for a while are removed.
+func applyCollages(rootPath string, imgPaths []string, collages map[string][]string) []string {
+	imgPaths = slices.DeleteFunc(slices.Clone(imgPaths), func(imgPath string) bool {
+		members, ok := collages[imgPath]
+		if !ok || cached(imgPath) {
+			return false
+		}
+		background.queue(imgPath, func() error {
+			return renderCollage(members, imgPath)
+		})
+		return true
+	})
+	pruneCache(paths.New(rootPath).CollagesDir())
+	return imgPaths
+}
+
+// renderCollage composes two photos side by side or four in a grid into dstPath. Derivatives are
+// already rotated for the frame, so the collage takes the shape of the first and is split along
+// its longer side, keeping the layout upright once the frame is mounted.
+func renderCollage(members []string, dstPath string) error {
+	if len(members) != 2 && len(members) != 4 {
+		return fmt.Errorf("collage needs 2 or 4 photos, got %d", len(members))
+	}
+
+	imgs := make([]image.Image, len(members))
+	for i, member := range members {
+		img, err := imaging.Decode(member)
+		if err != nil {
+			return err
+		}
+		imgs[i] = img
+	}
+
+	bounds := imgs[0].Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return errors.New("collage photo is empty")
+	}
+	gap := max(min(width, height)/collageGapDivisor, 1)
+
+	cols, rows := 2, 2
+	if len(imgs) == 2 {
+		cols, rows = 2, 1
+		if height > width {
+			cols, rows = 1, 2
+		}
+	}
+	cellW := (width - gap*(cols+1)) / cols
+	cellH := (height - gap*(rows+1)) / rows
+
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	for i, img := range imgs {
+		x := gap + (i%cols)*(cellW+gap)
+		y := gap + (i/cols)*(cellH+gap)
+		cell := imaging.Resize(img, cellW, cellH, imaging.FitCover)
+
+		// small photos aren't upscaled to fill the cell, so they're centered in it
+		cb := cell.Bounds()
+		x += (cellW - cb.Dx()) / 2
+		y += (cellH - cb.Dy()) / 2
+		draw.Draw(canvas, image.Rect(x, y, x+cb.Dx(), y+cb.Dy()), cell, cb.Min, draw.Src)
+	}
+	return imaging.WriteFile(dstPath, canvas)
+}
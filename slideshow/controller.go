@@ -0,0 +1,659 @@
+package slideshow
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/overlay"
+	"github.com/aouyang1/digitalphotoframe/runner"
+	"github.com/aouyang1/digitalphotoframe/store"
+)
+
+const (
+	indexRetries       = 20
+	indexRetryInterval = 50 * time.Millisecond
+)
+
+// Controller serializes restarts of the imv-wayland slideshow and sends it playback commands
+// over imv's IPC
+type Controller struct {
+	// this ensures only one go routine can restart or control the slideshow at a time
+	mu sync.Mutex
+
+	pid      int
+	interval int
+	paused   bool
+
+	// jitter varies the time each image is shown by up to this many seconds either side of the
+	// interval, fade is set when imv plays crossfade frames between images, and timeline maps
+	// images to imv's list, which has several frames for a panorama being panned across. imv only
+	// advances at a fixed delay and a step at a time, so with any of them the controller advances
+	// imv itself through advance, with advanceGen telling a stale timer apart from the current one.
+	jitter     int
+	fade       *crossfade
+	timeline   *timeline
+	advance    *time.Timer
+	advanceGen int
+
+	// held freezes the slideshow on the current image until released. Restarts requested while
+	// held are deferred so new photos or settings don't move the frame off the held image.
+	held    bool
+	pending *restartRequest
+
+	// asleep stops imv changing images while the display is off, so it isn't decoding photos no
+	// one can see. It's kept across restarts and lifted when the display is turned back on.
+	asleep bool
+
+	// imgPaths is the playlist imv was started with, used to find images to show by index
+	imgPaths []string
+
+	// index is the 1-based index in imgPaths of the image on screen, followed as the controller
+	// moves imv and read back from imv periodically while imv advances on its own. savedIndex is
+	// the position last saved to db, at savedAt.
+	index      int
+	savedIndex int
+	savedAt    time.Time
+
+	// show is set while a photo is shown out of order through Show
+	show *showState
+
+	// showGen tells a resume that fires after its show was extended or cancelled apart from the
+	// current one, since stopping the timer doesn't stop a resume already waiting on mu
+	showGen int
+
+	// proc is the running imv process, which is restarted if it quits on its own
+	proc      runner.Process
+	startedAt time.Time
+
+	// last is the most recent restart, repeated to bring imv back after it quits
+	last *restartRequest
+
+	// retry is the pending restart after imv quit, backing off as it keeps failing. retryGen
+	// tells a retry that fires after being replaced or cancelled apart from the current one.
+	retry    *time.Timer
+	retryGen int
+
+	restarts    int
+	failures    int
+	lastFailure time.Time
+	lastErr     error
+
+	// db is where the playlist and position are saved to, and resume is the state loaded from it
+	// at startup which the first restart picks up from
+	db     *store.Database
+	resume *store.SlideshowState
+}
+
+// showState remembers where the slideshow was before Show so it can be resumed
+type showState struct {
+	timer *time.Timer
+
+	// path is the image being shown
+	path string
+
+	// prevIndex is the 1-based imv index shown before Show or 0 if it couldn't be determined
+	prevIndex int
+
+	// opened is true when the shown image wasn't in the playlist and was appended to imv's list
+	opened bool
+}
+
+// Pacing is how the slideshow moves from one image to the next
+type Pacing struct {
+	// Interval is how many seconds each image is shown, or DefaultInterval when 0
+	Interval int
+
+	// Jitter varies the time each image is shown by up to this many seconds either side of the
+	// interval
+	Jitter int
+
+	// Crossfade blends each image into the next over a few frames rendered just before the
+	// transition, instead of cutting straight to it
+	Crossfade bool
+
+	// PanPanoramas pans slowly along panoramas too long for the screen over the time they're
+	// shown, instead of shrinking them to fit
+	PanPanoramas bool
+}
+
+type restartRequest struct {
+	imgPaths    []string
+	collages    map[string][]string
+	pacing      Pacing
+	captions    map[string]overlay.Text
+	captionOpts overlay.Options
+	eraseExif   bool
+}
+
+// ErrHeld is returned when asked to change images while the slideshow is held
+var ErrHeld = errors.New("slideshow is held on the current image")
+
+// NewController returns a controller saving the slideshow's state to db, resuming from the state
+// saved there before the first restart
+func NewController(db *store.Database) *Controller {
+	c := &Controller{db: db}
+	if db == nil {
+		return c
+	}
+
+	resume, err := db.GetSlideshowState()
+	if err != nil {
+		slog.Warn("unable to load slideshow state, starting from the beginning", "error", err)
+	}
+	c.resume = resume
+	return c
+}
+
+// Restart regenerates any missing derivatives, erasing their EXIF metadata when eraseExif is set,
+// and restarts imv with imgPaths, moving between them according to pacing. Paths with an entry in
+// collages are composed from the derivatives listed for them. Paths with an entry in captions are
+// shown with the caption and watermark drawn on screen styled by captionOpts.
+func (c *Controller) Restart(imgPaths []string, collages map[string][]string, pacing Pacing, captions map[string]overlay.Text, captionOpts overlay.Options, eraseExif bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cancelShow()
+	if c.held {
+		slog.Info("deferring slideshow restart while held")
+		c.pending = &restartRequest{
+			imgPaths:    imgPaths,
+			collages:    collages,
+			pacing:      pacing,
+			captions:    captions,
+			captionOpts: captionOpts,
+			eraseExif:   eraseExif,
+		}
+		return nil
+	}
+	return c.restart(imgPaths, collages, pacing, captions, captionOpts, eraseExif)
+}
+
+// restart restarts imv with imgPaths. Callers must hold mu.
+func (c *Controller) restart(imgPaths []string, collages map[string][]string, pacing Pacing, captions map[string]overlay.Text, captionOpts overlay.Options, eraseExif bool) error {
+	c.last = &restartRequest{
+		imgPaths:    imgPaths,
+		collages:    collages,
+		pacing:      pacing,
+		captions:    captions,
+		captionOpts: captionOpts,
+		eraseExif:   eraseExif,
+	}
+	c.cancelRetry()
+	c.cancelAdvance()
+
+	interval := pacing.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	jitter := min(max(pacing.Jitter, 0), interval-1)
+
+	imgPaths, resumeIndex, resumePaused := c.resumePlaylist(imgPaths)
+
+	// imv doesn't advance on its own when the controller varies the pace or fades, while the
+	// display is off, or when resuming paused
+	imvInterval := interval
+	if jitter > 0 || pacing.Crossfade || c.asleep || resumePaused {
+		imvInterval = 0
+	}
+
+	run, err := restartSlideshow(imgPaths, collages, imvInterval, pacing.Crossfade, pacing.PanPanoramas, captions, captionOpts, eraseExif)
+	if err != nil {
+		return err
+	}
+
+	// collages that couldn't be composed are left out, moving the images after them
+	if resumeIndex > 0 {
+		resumeIndex = slices.Index(run.playlist, imgPaths[resumeIndex-1]) + 1
+	}
+
+	c.proc = run.proc
+	c.startedAt = time.Now()
+	go c.supervise(run.proc)
+
+	c.pid = run.proc.Pid()
+	c.interval = interval
+	c.jitter = jitter
+	c.fade = run.fade
+	c.timeline = run.timeline
+	c.paused = resumePaused
+	c.imgPaths = run.playlist
+	c.index = 1
+	c.scheduleAdvance()
+
+	if resumeIndex > 1 {
+		if err := c.goTo(resumeIndex); err != nil {
+			slog.Warn("unable to resume slideshow position", "error", err)
+			resumeIndex = 1
+		} else {
+			slog.Info("resumed slideshow position", "index", resumeIndex, "paused", resumePaused)
+		}
+	}
+	c.saveState(resumeIndex)
+	return nil
+}
+
+// Stop quits the running imv slideshow
+func (c *Controller) Stop() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cancelShow()
+	c.cancelRetry()
+	c.cancelAdvance()
+	c.pid = 0
+	c.proc = nil
+	return killImvWayland()
+}
+
+// Hold freezes the slideshow on the current image until Release is called
+func (c *Controller) Hold() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.held {
+		return nil
+	}
+	if err := c.send("slideshow 0"); err != nil {
+		return err
+	}
+	// holding keeps whatever is on screen, including a photo shown through Show
+	c.cancelShow()
+	c.held = true
+	slog.Info("holding slideshow on current image")
+	return nil
+}
+
+// Release resumes the slideshow after Hold, applying any restart deferred while held
+func (c *Controller) Release() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.held {
+		return nil
+	}
+	c.held = false
+	slog.Info("released slideshow hold")
+
+	if pending := c.pending; pending != nil {
+		c.pending = nil
+		return c.restart(pending.imgPaths, pending.collages, pending.pacing, pending.captions, pending.captionOpts, pending.eraseExif)
+	}
+	return c.setPaused(c.paused)
+}
+
+// Recaption redraws the captions of the images in changed, removing those with an empty caption,
+// such as when the hearts shown over a photo change. imv is restarted with the playlist in the
+// same order, picking up from the image on screen.
+func (c *Controller) Recaption(changed map[string]overlay.Text) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	req := c.pending
+	if req == nil {
+		req = c.last
+	}
+	if req == nil {
+		return nil
+	}
+	req.captions = maps.Clone(req.captions)
+	if req.captions == nil {
+		req.captions = make(map[string]overlay.Text)
+	}
+	for path, text := range changed {
+		if text.IsZero() {
+			delete(req.captions, path)
+		} else {
+			req.captions[path] = text
+		}
+	}
+
+	// a deferred restart picks the captions up when it's applied
+	if req == c.pending || c.held || c.pid == 0 {
+		return nil
+	}
+
+	if err := c.readIndex(); err != nil {
+		return err
+	}
+	idx := c.position()
+	c.cancelShow()
+	c.resume = &store.SlideshowState{Playlist: c.imgPaths, Index: idx, Paused: c.paused}
+	return c.restart(c.imgPaths, req.collages, req.pacing, req.captions, req.captionOpts, req.eraseExif)
+}
+
+// SetAsleep stops or resumes changing images as the display is turned off or on. Unlike pausing,
+// it's lifted automatically once the display is back on, leaving the slideshow paused if it was
+// before.
+func (c *Controller) SetAsleep(asleep bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.asleep == asleep {
+		return nil
+	}
+	c.asleep = asleep
+	slog.Info("updated slideshow sleep", "asleep", asleep)
+
+	// imv is started asleep when it isn't running yet
+	if c.pid == 0 {
+		return nil
+	}
+	return c.setPaused(c.paused)
+}
+
+// Current returns the path of the image imv has on screen
+func (c *Controller) Current() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.pid == 0 {
+		return "", errors.New("slideshow is not running")
+	}
+	if c.show != nil && c.show.opened {
+		return c.show.path, nil
+	}
+
+	idx, err := c.currentIndex()
+	if err != nil {
+		return "", err
+	}
+	if idx < 1 || idx > len(c.imgPaths) {
+		return "", fmt.Errorf("image %d on screen is not in the playlist", idx)
+	}
+	return c.imgPaths[idx-1], nil
+}
+
+// Held reports whether the slideshow is held on the current image
+func (c *Controller) Held() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.held
+}
+
+// Asleep reports whether the slideshow is stopped while the display is off
+func (c *Controller) Asleep() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.asleep
+}
+
+// Next advances the slideshow to the next image
+func (c *Controller) Next() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.held {
+		return ErrHeld
+	}
+	return c.step(1)
+}
+
+// Prev moves the slideshow back to the previous image
+func (c *Controller) Prev() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.held {
+		return ErrHeld
+	}
+	return c.step(-1)
+}
+
+// TogglePause stops or resumes automatically advancing images, returning whether the slideshow
+// is now paused
+func (c *Controller) TogglePause() (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.setPaused(!c.paused); err != nil {
+		return c.paused, err
+	}
+	return c.paused, nil
+}
+
+// SetPaused stops or resumes automatically advancing images
+func (c *Controller) SetPaused(paused bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.setPaused(paused)
+}
+
+// Show interrupts the playlist to display imgPath for d, then returns to the image that was on
+// screen before and resumes advancing. Showing another photo before d elapses extends the
+// interruption while still resuming to the original position.
+func (c *Controller) Show(imgPath string, d time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.held {
+		return ErrHeld
+	}
+	if c.pid == 0 {
+		return errors.New("slideshow is not running")
+	}
+
+	show := c.show
+	if show != nil {
+		show.timer.Stop()
+		if show.opened {
+			c.send("close")
+			show.opened = false
+		}
+	} else {
+		show = &showState{}
+		prevIndex, err := c.currentIndex()
+		if err != nil {
+			slog.Warn("unable to determine slideshow position, will resume from shown photo", "error", err)
+		}
+		show.prevIndex = prevIndex
+	}
+
+	if err := c.send("slideshow 0"); err != nil {
+		return err
+	}
+	if idx := slices.Index(c.imgPaths, imgPath); idx >= 0 {
+		if err := c.goTo(idx + 1); err != nil {
+			return err
+		}
+	} else {
+		// imv appends opened images to its list and selects them
+		if err := c.send("open " + quoteImvArg(imgPath)); err != nil {
+			return err
+		}
+		show.opened = true
+	}
+
+	c.showGen++
+	gen := c.showGen
+	show.path = imgPath
+	show.timer = time.AfterFunc(d, func() { c.resumeShow(gen) })
+	c.show = show
+	slog.Info("showing photo", "path", imgPath, "duration", d)
+	return nil
+}
+
+// resumeShow returns to the playlist position from before Show, unless the show of gen has since
+// been extended or cancelled
+func (c *Controller) resumeShow(gen int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	show := c.show
+	if show == nil || gen != c.showGen {
+		return
+	}
+	c.show = nil
+
+	if show.opened {
+		if err := c.send("close"); err != nil {
+			slog.Warn("unable to close shown photo", "error", err)
+		}
+	}
+	if show.prevIndex > 0 {
+		if err := c.goTo(show.prevIndex); err != nil {
+			slog.Warn("unable to return to previous slideshow position", "error", err)
+		}
+	}
+	if err := c.setPaused(c.paused); err != nil {
+		slog.Warn("unable to resume slideshow after showing photo", "error", err)
+	}
+	slog.Info("resumed slideshow after showing photo", "index", show.prevIndex)
+}
+
+// cancelShow stops a pending resume from Show. Callers must hold mu.
+func (c *Controller) cancelShow() {
+	if c.show != nil {
+		c.show.timer.Stop()
+		c.show = nil
+		c.showGen++
+	}
+}
+
+// step moves delta images through the playlist, skipping over the crossfade and panning frames
+// between them. The pacing starts over on the new image so a fade or pan under way doesn't carry
+// on from it, and a panorama stepped to starts panning straight away. Callers must hold mu.
+func (c *Controller) step(delta int) error {
+	if c.fade == nil && !c.timeline.panning() {
+		command := "next"
+		if delta < 0 {
+			command = "prev"
+		}
+		if err := c.send(command); err != nil {
+			return err
+		}
+		if err := c.readIndex(); err != nil {
+			slog.Warn("unable to read slideshow position", "error", err)
+		}
+		return nil
+	}
+
+	idx, err := c.currentIndex()
+	if err != nil {
+		return err
+	}
+	n := len(c.imgPaths)
+	idx = ((idx-1+delta)%n+n)%n + 1
+	if err := c.goTo(idx); err != nil {
+		return err
+	}
+
+	c.cancelAdvance()
+	if c.timeline.panned(idx) {
+		go c.pan(c.advanceGen, c.timeline, idx, 0)
+		return nil
+	}
+	c.scheduleAdvance()
+	return nil
+}
+
+// goTo shows the image at the 1-based index in the playlist, from the start of its pan when it's
+// panned across. Callers must hold mu.
+func (c *Controller) goTo(idx int) error {
+	if err := c.send("goto " + strconv.Itoa(c.timeline.entry(idx))); err != nil {
+		return err
+	}
+	c.moved(idx)
+	return nil
+}
+
+// currentIndex asks imv for the 1-based index in the playlist of the image on screen, or the one
+// being faded from. Callers must hold mu.
+func (c *Controller) currentIndex() (int, error) {
+	entry, err := c.currentEntry()
+	if err != nil {
+		return 0, err
+	}
+	idx, _ := c.timeline.image(entry)
+	return idx, nil
+}
+
+// currentEntry asks imv for the 1-based index in its list of the frame on screen. imv only
+// exposes its own index to commands it executes, so the index is written to a temporary file and
+// read back. Callers must hold mu.
+func (c *Controller) currentEntry() (int, error) {
+	f, err := os.CreateTemp("", "dpf-imv-index-*")
+	if err != nil {
+		return 0, fmt.Errorf("unable to create index file, %w", err)
+	}
+	name := f.Name()
+	f.Close()
+	defer os.Remove(name)
+
+	if err := c.send(`exec printf "%s\n" "$imv_current_index" > ` + name); err != nil {
+		return 0, err
+	}
+
+	// imv runs the command asynchronously so wait for it to write a full line
+	for range indexRetries {
+		time.Sleep(indexRetryInterval)
+		content, err := os.ReadFile(name)
+		if err != nil || !strings.HasSuffix(string(content), "\n") {
+			continue
+		}
+		return strconv.Atoi(strings.TrimSpace(string(content)))
+	}
+	return 0, errors.New("timed out waiting for imv to report its current index")
+}
+
+// setPaused updates the imv slideshow delay, which stays at 0 while asleep. While held only the
+// paused state is recorded so it takes effect on release. Callers must hold mu.
+func (c *Controller) setPaused(paused bool) error {
+	if c.held {
+		c.setPausedState(paused)
+		return nil
+	}
+
+	// imv stops advancing when the slideshow delay is set to 0, and is left stopped while the
+	// controller advances it
+	delay := c.interval
+	if paused || c.asleep || c.advancing() {
+		delay = 0
+	}
+	if err := c.send("slideshow " + strconv.Itoa(delay)); err != nil {
+		return err
+	}
+	c.setPausedState(paused)
+	slog.Info("updated slideshow pause", "paused", c.paused)
+	return nil
+}
+
+// setPausedState records whether the slideshow is paused, saving it when it changes. Callers must
+// hold mu.
+func (c *Controller) setPausedState(paused bool) {
+	if c.paused == paused {
+		return
+	}
+	c.paused = paused
+	if c.db == nil {
+		return
+	}
+	if err := c.db.UpdateSlideshowPaused(paused); err != nil {
+		slog.Warn("unable to save slideshow pause", "error", err)
+	}
+}
+
+// quoteImvArg quotes arg for an imv command, which splits its arguments on spaces and expands
+// them like a shell would
+func quoteImvArg(arg string) string {
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// send issues an imv command to the running imv process. Callers must hold mu.
+func (c *Controller) send(command string) error {
+	if c.pid == 0 {
+		return errors.New("slideshow is not running")
+	}
+
+	if out, err := runner.Default().Run("imv-msg", strconv.Itoa(c.pid), command); err != nil {
+		return fmt.Errorf("failed to send imv command %q, %s, %w", command, out, err)
+	}
+	return nil
+}
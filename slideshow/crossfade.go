@@ -0,0 +1,188 @@
+package slideshow
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/display"
+	"github.com/aouyang1/digitalphotoframe/imaging"
+	"github.com/aouyang1/digitalphotoframe/paths"
+	"golang.org/x/image/draw"
+)
+
+const (
+	// crossfadeFrames is how many blended frames are played between two images, each shown for
+	// crossfadeFrameDelay
+	crossfadeFrames     = 8
+	crossfadeFrameDelay = 60 * time.Millisecond
+)
+
+// crossfade holds the frames played between consecutive images. imv is started with
+// crossfadeFrames slots after every image, which link to the next image until the blend is
+// rendered into them just before the transition, so only the transitions about to play take up
+// space and a transition that isn't ready in time just cuts to the next image.
+type crossfade struct {
+	mu sync.Mutex
+
+	// images are the frames imv shows for each image, in playlist order, which is more than one
+	// when panning across a panorama
+	images [][]string
+	dir    string
+
+	// rendered is the transition currently blended into its slots, or -1
+	rendered int
+}
+
+// prepareCrossfade lays out the slots for the frames played between each of images and the next,
+// returning the list to start imv with. Fewer than two images have nothing to fade between.
+func prepareCrossfade(rootPath string, images [][]string) ([]string, *crossfade, error) {
+	if len(images) < 2 {
+		return slices.Concat(images...), nil, nil
+	}
+
+	dir := paths.New(rootPath).TransitionsDir()
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, nil, fmt.Errorf("unable to clear %s, %w", dir, err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, nil, fmt.Errorf("unable to create %s, %w", dir, err)
+	}
+
+	fade := &crossfade{images: images, dir: dir, rendered: -1}
+	list := make([]string, 0, len(images)*(crossfadeFrames+1))
+	for i, frames := range images {
+		list = append(list, frames...)
+		for frame := 1; frame <= crossfadeFrames; frame++ {
+			slot := fade.slot(i, frame)
+			if err := os.Symlink(fade.next(i), slot); err != nil {
+				return nil, nil, fmt.Errorf("unable to create crossfade slot, %w", err)
+			}
+			list = append(list, slot)
+		}
+	}
+	return list, fade, nil
+}
+
+// stride is how many entries imv has for each image, the image and the frames after it, not
+// counting any more frames the image is played as
+func (f *crossfade) stride() int {
+	if f == nil {
+		return 1
+	}
+	return crossfadeFrames + 1
+}
+
+// slot is the path of a frame of the transition from image i to the next
+func (f *crossfade) slot(i, frame int) string {
+	return filepath.Join(f.dir, fmt.Sprintf("%06d-%d.jpg", i, frame))
+}
+
+// last is the last frame of image i, which the transition fades from
+func (f *crossfade) last(i int) string {
+	frames := f.images[i]
+	return frames[len(frames)-1]
+}
+
+// next is the first frame of the image after image i, wrapping around to the first
+func (f *crossfade) next(i int) string {
+	return f.images[(i+1)%len(f.images)][0]
+}
+
+// render blends the transition from image i to the next into its slots, putting back the links
+// of the transition rendered before so the frames don't pile up
+func (f *crossfade) render(i int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	i %= len(f.images)
+	if f.rendered == i {
+		return nil
+	}
+	if f.rendered >= 0 {
+		f.unrender(f.rendered)
+		f.rendered = -1
+	}
+
+	from, err := imaging.Decode(f.last(i))
+	if err != nil {
+		return err
+	}
+	to, err := imaging.Decode(f.next(i))
+	if err != nil {
+		return err
+	}
+
+	bounds := crossfadeCanvas(from, to)
+	fromCanvas := fitCanvas(from, bounds)
+	toCanvas := fitCanvas(to, bounds)
+	blended := image.NewRGBA(bounds)
+	for frame := 1; frame <= crossfadeFrames; frame++ {
+		blend(blended, fromCanvas, toCanvas, frame*256/(crossfadeFrames+1))
+		if err := imaging.WriteFile(f.slot(i, frame), blended); err != nil {
+			f.unrender(i)
+			return err
+		}
+	}
+	f.rendered = i
+	return nil
+}
+
+// unrender links the slots of transition i back to the next image. Callers must hold mu.
+func (f *crossfade) unrender(i int) {
+	for frame := 1; frame <= crossfadeFrames; frame++ {
+		slot := f.slot(i, frame)
+		os.Remove(slot)
+		if err := os.Symlink(f.next(i), slot); err != nil {
+			slog.Warn("unable to reset crossfade slot", "slot", slot, "error", err)
+		}
+	}
+}
+
+// crossfadeCanvas is the size frames are rendered at, the shape of the screen so imv scales them
+// the same as the images on either side. It's as large as the bigger of the two images, or that
+// image's own shape when the screen can't be inspected.
+func crossfadeCanvas(from, to image.Image) image.Rectangle {
+	size := max(from.Bounds().Dx(), from.Bounds().Dy(), to.Bounds().Dx(), to.Bounds().Dy())
+
+	width, height := from.Bounds().Dx(), from.Bounds().Dy()
+	if output, err := display.GetOutput(); err == nil {
+		if w, h, ok := output.LogicalSize(); ok {
+			width, height = w, h
+		}
+	}
+	if width <= 0 || height <= 0 {
+		return image.Rect(0, 0, size, size)
+	}
+
+	if width >= height {
+		return image.Rect(0, 0, size, max(1, size*height/width))
+	}
+	return image.Rect(0, 0, max(1, size*width/height), size)
+}
+
+// fitCanvas scales img to fit within bounds, centered on black the way imv letterboxes it
+func fitCanvas(img image.Image, bounds image.Rectangle) *image.RGBA {
+	canvas := image.NewRGBA(bounds)
+	draw.Draw(canvas, bounds, image.NewUniform(color.Black), image.Point{}, draw.Src)
+
+	src := img.Bounds()
+	scale := min(float64(bounds.Dx())/float64(src.Dx()), float64(bounds.Dy())/float64(src.Dy()))
+	w, h := int(float64(src.Dx())*scale), int(float64(src.Dy())*scale)
+	x, y := (bounds.Dx()-w)/2, (bounds.Dy()-h)/2
+	draw.ApproxBiLinear.Scale(canvas, image.Rect(x, y, x+w, y+h), img, src, draw.Src, nil)
+	return canvas
+}
+
+// blend writes from faded toward to by weight out of 256 into dst
+func blend(dst, from, to *image.RGBA, weight int) {
+	for i := range dst.Pix {
+		dst.Pix[i] = uint8((int(from.Pix[i])*(256-weight) + int(to.Pix[i])*weight) >> 8)
+	}
+}
@@ -0,0 +1,174 @@
+package slideshow
+
+import (
+	"log/slog"
+	"math/rand/v2"
+	"time"
+)
+
+// nextDelay returns how long to show the next image, the interval varied randomly by up to jitter
+// seconds either way
+func nextDelay(interval, jitter int) time.Duration {
+	seconds := interval + rand.IntN(2*jitter+1) - jitter
+	return time.Duration(max(seconds, 1)) * time.Second
+}
+
+// advancing reports whether the controller advances imv rather than imv's own timer. Callers must
+// hold mu.
+func (c *Controller) advancing() bool {
+	return c.jitter > 0 || c.fade != nil || c.timeline.panning()
+}
+
+// scheduleAdvance starts the timer advancing imv to the next image when the controller paces it.
+// Callers must hold mu.
+func (c *Controller) scheduleAdvance() {
+	c.scheduleAdvanceIn(nextDelay(c.interval, c.jitter))
+}
+
+// scheduleAdvanceIn starts the timer advancing imv to the next image after d when the controller
+// paces it. Callers must hold mu.
+func (c *Controller) scheduleAdvanceIn(d time.Duration) {
+	if !c.advancing() || c.pid == 0 {
+		return
+	}
+
+	c.advanceGen++
+	gen := c.advanceGen
+	c.advance = time.AfterFunc(d, func() {
+		c.advanceSlide(gen)
+	})
+}
+
+// advanceSlide moves to the next image unless the slideshow is paused, held, asleep, or showing a
+// photo out of order, then schedules the next advance
+func (c *Controller) advanceSlide(gen int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.advance == nil || c.advanceGen != gen {
+		return
+	}
+	c.advance = nil
+
+	if !c.paused && !c.held && !c.asleep && c.show == nil {
+		if c.fade != nil || c.timeline.panning() {
+			entry, err := c.currentEntry()
+			if err == nil {
+				go c.playNext(gen, c.fade, c.timeline, entry)
+				return
+			}
+			slog.Warn("unable to determine slideshow position to advance from", "error", err)
+		} else if err := c.send("next"); err != nil {
+			slog.Warn("unable to advance slideshow", "error", err)
+		} else {
+			c.moved(c.index%len(c.imgPaths) + 1)
+		}
+	}
+	c.scheduleAdvance()
+}
+
+// playNext moves on from the frame at the 1-based entry in imv's list, panning the rest of the way
+// along a panorama that hasn't reached its end, such as one skipped to, or otherwise playing the
+// transition to the next image
+func (c *Controller) playNext(gen int, fade *crossfade, tl *timeline, entry int) {
+	idx, frame := tl.image(entry)
+	if tl.panned(idx) && frame < tl.frames[idx-1]-1 {
+		c.pan(gen, tl, idx, frame)
+		return
+	}
+	c.playFade(gen, fade, tl, idx-1)
+}
+
+// playFade renders the transition from image i to the next and steps imv through its frames onto
+// the next image, then renders the transition after it ahead of time and pans along the next
+// image if it's a panorama. Without crossfade it cuts straight to the next image. It gives up
+// when the slideshow is restarted, paused, held, put to sleep, or interrupted along the way.
+func (c *Controller) playFade(gen int, fade *crossfade, tl *timeline, i int) {
+	if fade != nil {
+		if err := fade.render(i); err != nil {
+			slog.Warn("unable to render crossfade, cutting to next image", "error", err)
+		}
+	}
+
+	for range fade.stride() {
+		c.mu.Lock()
+		if c.advanceGen != gen || c.fade != fade || c.timeline != tl {
+			c.mu.Unlock()
+			return
+		}
+		if c.paused || c.held || c.asleep || c.show != nil {
+			c.scheduleAdvance()
+			c.mu.Unlock()
+			return
+		}
+		if err := c.send("next"); err != nil {
+			slog.Warn("unable to advance slideshow", "error", err)
+		}
+		c.mu.Unlock()
+		time.Sleep(crossfadeFrameDelay)
+	}
+
+	next := (i+1)%len(tl.frames) + 1
+	c.mu.Lock()
+	current := c.advanceGen == gen && c.fade == fade && c.timeline == tl
+	if current {
+		c.moved(next)
+		if !tl.panned(next) {
+			c.scheduleAdvance()
+		}
+	}
+	c.mu.Unlock()
+
+	if fade != nil {
+		if err := fade.render(i + 1); err != nil {
+			slog.Warn("unable to render upcoming crossfade", "error", err)
+		}
+	}
+	if current && tl.panned(next) {
+		c.pan(gen, tl, next, 0)
+	}
+}
+
+// pan steps imv through the frames of the panorama at the 1-based index from the 0-based frame on
+// screen, spreading the whole pan over the time the panorama is shown, then schedules the advance
+// off its last frame. It gives up the same way as playFade.
+func (c *Controller) pan(gen int, tl *timeline, idx, frame int) {
+	frames := tl.frames[idx-1]
+	c.mu.Lock()
+	delay := nextDelay(c.interval, c.jitter) / time.Duration(frames)
+	c.mu.Unlock()
+
+	for range frames - 1 - frame {
+		time.Sleep(delay)
+
+		c.mu.Lock()
+		if c.advanceGen != gen || c.timeline != tl {
+			c.mu.Unlock()
+			return
+		}
+		if c.paused || c.held || c.asleep || c.show != nil {
+			c.scheduleAdvance()
+			c.mu.Unlock()
+			return
+		}
+		if err := c.send("next"); err != nil {
+			slog.Warn("unable to pan slideshow", "error", err)
+		}
+		c.mu.Unlock()
+	}
+
+	c.mu.Lock()
+	if c.advanceGen == gen && c.timeline == tl {
+		c.scheduleAdvanceIn(delay)
+	}
+	c.mu.Unlock()
+}
+
+// cancelAdvance stops the pending advance. Callers must hold mu.
+func (c *Controller) cancelAdvance() {
+	if c.advance != nil {
+		c.advance.Stop()
+		c.advance = nil
+	}
+	c.advanceGen++
+}
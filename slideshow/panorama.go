@@ -0,0 +1,242 @@
+package slideshow
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/aouyang1/digitalphotoframe/display"
+	"github.com/aouyang1/digitalphotoframe/imaging"
+	"github.com/aouyang1/digitalphotoframe/overlay"
+	"github.com/aouyang1/digitalphotoframe/paths"
+	"golang.org/x/image/draw"
+)
+
+const (
+	// panoramaMinRatio is how many times longer than wide an image has to be to be panned across,
+	// which leaves out photos merely cropped wide
+	panoramaMinRatio = 2.5
+
+	// panoramaFrames is how many frames a pan is played as, spread over the time the panorama is
+	// on screen
+	panoramaFrames = 30
+
+	// fallbackScreenW and fallbackScreenH are the resolution panoramas are rendered at when the
+	// screen can't be inspected
+	fallbackScreenW = 1920
+	fallbackScreenH = 1080
+)
+
+// preparePanoramas returns the frames panning along each of images that's a panorama too long to
+// fit the screen without shrinking it to a sliver, drawing the image's caption on every frame, or
+// nil for the rest. Frames are rendered in the background into their own directory under
+// cache/panoramas/, so a panorama is shown whole until its frames are ready, and ones that haven't
+// been played for a while are removed.
+func preparePanoramas(rootPath string, images []string, captions map[string]overlay.Text, captionOpts overlay.Options) [][]string {
+	screen := image.Pt(fallbackScreenW, fallbackScreenH)
+	if output, err := display.GetOutput(); err == nil {
+		if w, h, ok := output.LogicalSize(); ok && w > 0 && h > 0 {
+			screen = image.Pt(w, h)
+		}
+	}
+	captionOpts.Rotation = RotateDegrees
+
+	layout := paths.New(rootPath)
+	panoramaDir := layout.PanoramasDir()
+	frames := make([][]string, len(images))
+	for i, img := range images {
+		// only photos are panned, from their original
+		original, ok := layout.OriginalOf(img)
+		if !ok {
+			continue
+		}
+		pan, err := panFrames(panoramaDir, img, original, captions[img], captionOpts, screen)
+		if err != nil {
+			slog.Warn("failed to pan across panorama, shrinking it to fit", "path", img, "error", err)
+			continue
+		}
+		frames[i] = pan
+	}
+	pruneCache(panoramaDir)
+	return frames
+}
+
+// panFrames returns the frames panning along the derivative at imgPath, or nil when it isn't a
+// panorama that needs panning on the screen or its frames are still being rendered from the
+// original
+func panFrames(panoramaDir, imgPath, original string, text overlay.Text, opts overlay.Options, screen image.Point) ([]string, error) {
+	f, err := os.Open(imgPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg, _, err := image.DecodeConfig(f)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read image config, %w", err)
+	}
+	if _, ok := panWindow(cfg.Width, cfg.Height, screen.X, screen.Y); !ok {
+		return nil, nil
+	}
+
+	info, err := os.Stat(original)
+	if err != nil {
+		return nil, err
+	}
+	key := sha1.Sum([]byte(fmt.Sprintf("%s|%d|%dx%d|%s|%s|%s|%s", original, info.ModTime().UnixNano(), screen.X, screen.Y, text.Caption, text.Watermark, opts.Position, opts.Size)))
+	dir := filepath.Join(panoramaDir, hex.EncodeToString(key[:]))
+	frames := make([]string, panoramaFrames)
+	for i := range frames {
+		frames[i] = filepath.Join(dir, fmt.Sprintf("%02d.jpg", i))
+	}
+
+	// the last frame is written last, so the pan is complete when it exists
+	if _, err := os.Stat(frames[len(frames)-1]); err == nil && cached(dir) {
+		return frames, nil
+	}
+	background.queue(dir, func() error {
+		if err := renderPan(original, frames, text, opts, screen); err != nil {
+			os.RemoveAll(dir)
+			return err
+		}
+		return nil
+	})
+	return nil, nil
+}
+
+// panWindow is the size of the part of a width by height image shown at a time while panning,
+// the shape of the screen spanning the image's shorter side. It's false when the image isn't long
+// enough to be a panorama or fits the screen without panning.
+func panWindow(width, height, screenW, screenH int) (image.Point, bool) {
+	if width <= 0 || height <= 0 || float64(max(width, height)) < panoramaMinRatio*float64(min(width, height)) {
+		return image.Point{}, false
+	}
+	if height > width {
+		window := image.Pt(width, max(1, width*screenH/screenW))
+		return window, window.Y < height
+	}
+	window := image.Pt(max(1, height*screenW/screenH), height)
+	return window, window.X < width
+}
+
+// renderPan writes frames moving a window the shape of the screen evenly along the original's
+// longer side once rotated like its derivative, so panning down a tall derivative moves left to
+// right once the frame is mounted. The original is only shrunk to the screen's resolution, which
+// keeps the frames as sharp as the screen can show.
+func renderPan(original string, frames []string, text overlay.Text, opts overlay.Options, screen image.Point) error {
+	img, err := imaging.Decode(original)
+	if err != nil {
+		return err
+	}
+
+	// shrinking before rotating leaves fewer pixels to rotate
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	if RotateDegrees%180 != 0 {
+		width, height = height, width
+	}
+	scale := float64(screen.Y) / float64(height)
+	if height > width {
+		scale = float64(screen.X) / float64(width)
+	}
+	if scale < 1 {
+		bounds := img.Bounds()
+		img = imaging.Resize(img, max(1, int(float64(bounds.Dx())*scale)), max(1, int(float64(bounds.Dy())*scale)), imaging.FitFill)
+	}
+	img = imaging.Rotate(img, RotateDegrees)
+	bounds := img.Bounds()
+
+	window, ok := panWindow(bounds.Dx(), bounds.Dy(), screen.X, screen.Y)
+	if !ok {
+		return errors.New("original is not a panorama")
+	}
+
+	canvas := image.NewRGBA(image.Rectangle{Max: window})
+	for i, frame := range frames {
+		offset := image.Point{}
+		if bounds.Dy() > bounds.Dx() {
+			offset.Y = i * (bounds.Dy() - window.Y) / (len(frames) - 1)
+		} else {
+			offset.X = i * (bounds.Dx() - window.X) / (len(frames) - 1)
+		}
+		draw.Draw(canvas, canvas.Bounds(), img, bounds.Min.Add(offset), draw.Src)
+
+		var out image.Image = canvas
+		if !text.IsZero() {
+			if out, err = overlay.Caption(canvas, text, opts); err != nil {
+				return err
+			}
+		}
+		if err := imaging.WriteFile(frame, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// timeline maps the images of the playlist to imv's list, where each image is played as one or
+// more frames, more when panning across a panorama, followed by any crossfade frames into the
+// next. A nil timeline has a single entry per image.
+type timeline struct {
+	// starts is the 0-based entry in imv's list of the first frame of each image
+	starts []int
+
+	// frames is how many frames each image is played as
+	frames []int
+}
+
+// newTimeline lays out images played as the frames given for each, with the crossfade slots
+// after each when fading
+func newTimeline(frames [][]string, fade *crossfade) *timeline {
+	t := &timeline{starts: make([]int, len(frames)), frames: make([]int, len(frames))}
+	entry := 0
+	for i, f := range frames {
+		t.starts[i] = entry
+		t.frames[i] = len(f)
+		entry += len(f) + fade.stride() - 1
+	}
+	return t
+}
+
+// entry is the 1-based index in imv's list of the first frame of the image at the 1-based index
+func (t *timeline) entry(idx int) int {
+	if t == nil || idx < 1 || idx > len(t.starts) {
+		return idx
+	}
+	return t.starts[idx-1] + 1
+}
+
+// image is the 1-based index of the image being played at the 1-based entry in imv's list, and
+// the 0-based frame of it on screen, which is past its last frame during the crossfade after it
+func (t *timeline) image(entry int) (int, int) {
+	if t == nil {
+		return entry, 0
+	}
+	i := sort.Search(len(t.starts), func(i int) bool { return t.starts[i] >= entry }) - 1
+	if i < 0 {
+		return entry, 0
+	}
+	return i + 1, entry - 1 - t.starts[i]
+}
+
+// panned reports whether the image at the 1-based index is played as frames panning across it
+func (t *timeline) panned(idx int) bool {
+	return t != nil && idx >= 1 && idx <= len(t.frames) && t.frames[idx-1] > 1
+}
+
+// panning reports whether any image is panned across
+func (t *timeline) panning() bool {
+	if t == nil {
+		return false
+	}
+	for idx := range t.frames {
+		if t.panned(idx + 1) {
+			return true
+		}
+	}
+	return false
+}
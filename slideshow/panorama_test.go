@@ -0,0 +1,51 @@
+package slideshow
+
+import (
+	"fmt"
+	"image"
+	"path/filepath"
+	"testing"
+
+	"github.com/aouyang1/digitalphotoframe/imaging"
+	"github.com/aouyang1/digitalphotoframe/overlay"
+)
+
+func TestRenderPan(t *testing.T) {
+	dir := t.TempDir()
+	original := filepath.Join(dir, "pano.jpg")
+	if err := imaging.WriteFile(original, image.NewRGBA(image.Rect(0, 0, 3000, 600))); err != nil {
+		t.Fatal(err)
+	}
+
+	frames := make([]string, 3)
+	for i := range frames {
+		frames[i] = filepath.Join(dir, "frames", fmt.Sprintf("%02d.jpg", i))
+	}
+	screen := image.Pt(160, 90)
+	if err := renderPan(original, frames, overlay.Text{}, overlay.Options{}, screen); err != nil {
+		t.Fatalf("renderPan() error = %v", err)
+	}
+
+	// frames are cut at the screen's resolution, not the original's
+	for _, frame := range frames {
+		img, err := imaging.Decode(frame)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size := img.Bounds().Size(); size != screen {
+			t.Errorf("frame %s is %v, want %v", filepath.Base(frame), size, screen)
+		}
+	}
+}
+
+func TestRenderPanNotPanorama(t *testing.T) {
+	dir := t.TempDir()
+	original := filepath.Join(dir, "photo.jpg")
+	if err := imaging.WriteFile(original, image.NewRGBA(image.Rect(0, 0, 1600, 1200))); err != nil {
+		t.Fatal(err)
+	}
+	frames := []string{filepath.Join(dir, "00.jpg"), filepath.Join(dir, "01.jpg")}
+	if err := renderPan(original, frames, overlay.Text{}, overlay.Options{}, image.Pt(160, 90)); err == nil {
+		t.Error("renderPan() error = nil, want an error for a photo that isn't a panorama")
+	}
+}
@@ -0,0 +1,191 @@
+package slideshow
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/store"
+	mapset "github.com/deckarep/golang-set/v2"
+)
+
+// positionSaveInterval is how often the slideshow's position is saved, so a restart resumes
+// within a minute or so of where it was
+const positionSaveInterval = time.Minute
+
+// playlistID identifies a playlist by the images in it and their order
+func playlistID(imgPaths []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(imgPaths, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// resumePlaylist returns the playlist to start imv with, the 1-based index to go to, and whether
+// to start paused, picking up from the state saved before the frame restarted. The saved order is
+// kept when it has the same photos, so a shuffled playlist isn't reshuffled, otherwise the saved
+// photo is found in the new playlist. Only the first restart resumes. Callers must hold mu.
+func (c *Controller) resumePlaylist(imgPaths []string) ([]string, int, bool) {
+	resume := c.resume
+	c.resume = nil
+	if resume == nil || resume.Index < 1 || resume.Index > len(resume.Playlist) {
+		return imgPaths, 0, false
+	}
+
+	if mapset.NewSet(imgPaths...).Equal(mapset.NewSet(resume.Playlist...)) {
+		return resume.Playlist, resume.Index, resume.Paused
+	}
+	return imgPaths, slices.Index(imgPaths, resume.Playlist[resume.Index-1]) + 1, resume.Paused
+}
+
+// saveState records the playlist imv was just started with. Callers must hold mu.
+func (c *Controller) saveState(index int) {
+	c.index = max(index, 1)
+	if c.db == nil {
+		return
+	}
+	now := time.Now()
+	err := c.db.SaveSlideshowState(&store.SlideshowState{
+		PlaylistID: playlistID(c.imgPaths),
+		Playlist:   c.imgPaths,
+		Index:      c.index,
+		Paused:     c.paused,
+		StartedAt:  c.startedAt,
+		SavedAt:    now,
+	})
+	if err != nil {
+		slog.Warn("unable to save slideshow state", "error", err)
+		return
+	}
+	c.savedIndex, c.savedAt = c.index, now
+}
+
+// moved records that imv moved to the image at the 1-based index, saving the position when it
+// hasn't been saved for positionSaveInterval. Callers must hold mu.
+func (c *Controller) moved(idx int) {
+	c.index = idx
+	if time.Since(c.savedAt) >= positionSaveInterval {
+		c.savePosition()
+	}
+}
+
+// savePosition saves the position in the playlist if it changed since it was last saved. Callers
+// must hold mu.
+func (c *Controller) savePosition() {
+	idx := c.position()
+	if c.db == nil || idx == c.savedIndex || idx < 1 || idx > len(c.imgPaths) {
+		return
+	}
+	now := time.Now()
+	if err := c.db.UpdateSlideshowPosition(idx, now); err != nil {
+		slog.Warn("unable to save slideshow position", "error", err)
+		return
+	}
+	c.savedIndex, c.savedAt = idx, now
+}
+
+// Run saves the slideshow's position periodically so it can be resumed after a restart, reading
+// it back from imv while imv advances on its own
+func (c *Controller) Run() {
+	if c.db == nil {
+		return
+	}
+
+	ticker := time.NewTicker(positionSaveInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		c.mu.Lock()
+		if err := c.readIndex(); err != nil {
+			slog.Warn("unable to read slideshow position", "error", err)
+		}
+		c.savePosition()
+		c.mu.Unlock()
+	}
+}
+
+// readIndex reads the image on screen back from imv while imv advances on its own, since the
+// controller only follows the moves it makes itself. Callers must hold mu.
+func (c *Controller) readIndex() error {
+	if c.pid == 0 || c.show != nil || c.advancing() {
+		return nil
+	}
+	idx, err := c.currentIndex()
+	if err != nil {
+		return err
+	}
+	c.index = idx
+	return nil
+}
+
+// position is the 1-based index of the image on screen in the playlist. A photo shown out of
+// order isn't part of the playlist, so it's the position the slideshow returns to. Callers must
+// hold mu.
+func (c *Controller) position() int {
+	if c.show != nil {
+		return c.show.prevIndex
+	}
+	return c.index
+}
+
+// State returns where the slideshow is in its playlist, or nil if imv hasn't been started
+func (c *Controller) State() *store.SlideshowState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.pid == 0 || len(c.imgPaths) == 0 {
+		return nil
+	}
+	return &store.SlideshowState{
+		PlaylistID: playlistID(c.imgPaths),
+		Playlist:   c.imgPaths,
+		Index:      c.position(),
+		Paused:     c.paused,
+		StartedAt:  c.startedAt,
+		SavedAt:    c.savedAt,
+	}
+}
+
+// ImportStateFile saves the playlist and position kept in the json file at statePath, from before
+// they were saved to db, into db unless it already has a state, then removes the file
+func ImportStateFile(db *store.Database, statePath string) error {
+	content, err := os.ReadFile(statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read %s, %w", statePath, err)
+	}
+
+	var saved struct {
+		Playlist []string  `json:"playlist"`
+		Index    int       `json:"index"`
+		SavedAt  time.Time `json:"saved_at"`
+	}
+	if err := json.Unmarshal(content, &saved); err != nil {
+		return fmt.Errorf("unable to parse %s, %w", statePath, err)
+	}
+
+	existing, err := db.GetSlideshowState()
+	if err != nil {
+		return err
+	}
+	if existing == nil && saved.Index >= 1 && saved.Index <= len(saved.Playlist) {
+		err := db.SaveSlideshowState(&store.SlideshowState{
+			PlaylistID: playlistID(saved.Playlist),
+			Playlist:   saved.Playlist,
+			Index:      saved.Index,
+			StartedAt:  saved.SavedAt,
+			SavedAt:    saved.SavedAt,
+		})
+		if err != nil {
+			return err
+		}
+		slog.Info("imported saved slideshow state", "path", statePath, "index", saved.Index)
+	}
+	return os.Remove(statePath)
+}
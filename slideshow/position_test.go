@@ -0,0 +1,70 @@
+package slideshow
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/aouyang1/digitalphotoframe/store"
+)
+
+func TestImportStateFile(t *testing.T) {
+	dir := t.TempDir()
+	db, err := store.NewDatabase(filepath.Join(dir, "photos.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statePath := filepath.Join(dir, "slideshow_state.json")
+	content := `{"playlist":["a.jpg","b.jpg","c.jpg"],"index":2,"saved_at":"2026-10-01T12:00:00Z"}`
+	if err := os.WriteFile(statePath, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ImportStateFile(db, statePath); err != nil {
+		t.Fatalf("ImportStateFile() error = %v", err)
+	}
+	state, err := db.GetSlideshowState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state == nil || state.Index != 2 || !slices.Equal(state.Playlist, []string{"a.jpg", "b.jpg", "c.jpg"}) {
+		t.Errorf("imported state = %+v, want b.jpg of a.jpg, b.jpg, c.jpg", state)
+	}
+	if _, err := os.Stat(statePath); !os.IsNotExist(err) {
+		t.Errorf("state file still exists after import, stat error = %v", err)
+	}
+
+	// without the file there's nothing left to import
+	if err := ImportStateFile(db, statePath); err != nil {
+		t.Errorf("ImportStateFile() without a file error = %v", err)
+	}
+}
+
+func TestImportStateFileKeepsNewerState(t *testing.T) {
+	dir := t.TempDir()
+	db, err := store.NewDatabase(filepath.Join(dir, "photos.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveSlideshowState(&store.SlideshowState{Playlist: []string{"new.jpg"}, Index: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	statePath := filepath.Join(dir, "slideshow_state.json")
+	if err := os.WriteFile(statePath, []byte(`{"playlist":["old.jpg"],"index":1}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ImportStateFile(db, statePath); err != nil {
+		t.Fatalf("ImportStateFile() error = %v", err)
+	}
+
+	state, err := db.GetSlideshowState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state == nil || !slices.Equal(state.Playlist, []string{"new.jpg"}) {
+		t.Errorf("state = %+v, want the state already saved", state)
+	}
+}
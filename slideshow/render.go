@@ -0,0 +1,103 @@
+package slideshow
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/thermal"
+	mapset "github.com/deckarep/golang-set/v2"
+)
+
+const (
+	// cacheMaxAge is how long a collage or panorama is kept after it was last played
+	cacheMaxAge = 30 * 24 * time.Hour
+
+	// renderQueueSize is how many renders can wait their turn, past which they're left for the
+	// next restart to queue again
+	renderQueueSize = 256
+)
+
+// renderJob renders what's cached at dst
+type renderJob struct {
+	dst    string
+	render func() error
+}
+
+// renderer renders collages and panorama frames one at a time in the background, so restarting
+// the slideshow only picks up what's already in the cache and a render never holds up imv. What's
+// rendered is played from the next restart.
+type renderer struct {
+	mu     sync.Mutex
+	queued mapset.Set[string]
+	jobs   chan renderJob
+	start  sync.Once
+}
+
+var background = &renderer{
+	queued: mapset.NewThreadUnsafeSet[string](),
+	jobs:   make(chan renderJob, renderQueueSize),
+}
+
+// queue renders dst with render in the background unless it's already waiting to be rendered
+func (r *renderer) queue(dst string, render func() error) {
+	r.start.Do(func() { go r.run() })
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.queued.Contains(dst) {
+		return
+	}
+	select {
+	case r.jobs <- renderJob{dst: dst, render: render}:
+		r.queued.Add(dst)
+	default:
+		slog.Debug("render queue is full, leaving it for the next restart", "path", dst)
+	}
+}
+
+func (r *renderer) run() {
+	for job := range r.jobs {
+		// the slideshow plays on without the render, so it waits out a hot cpu
+		thermal.Wait(context.Background(), 0, 1)
+		if err := job.render(); err != nil {
+			slog.Warn("failed to render for the slideshow", "path", job.dst, "error", err)
+		}
+
+		r.mu.Lock()
+		r.queued.Remove(job.dst)
+		r.mu.Unlock()
+	}
+}
+
+// cached reports whether path was rendered, marking it as played so it's kept in the cache
+func cached(path string) bool {
+	if _, err := os.Stat(path); err != nil {
+		return false
+	}
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil {
+		slog.Debug("unable to mark cached render as played", "path", path, "error", err)
+	}
+	return true
+}
+
+// pruneCache removes the entries of dir that haven't been played for cacheMaxAge
+func pruneCache(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < cacheMaxAge {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			slog.Warn("failed to remove stale render", "name", entry.Name(), "error", err)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package slideshow
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/aouyang1/digitalphotoframe/display"
+	"github.com/aouyang1/digitalphotoframe/overlay"
+)
+
+// RenderSlide writes a slide of text to dstPath, shaped like the screen and as large as the
+// derivatives, turned the same way so it reads upright on the mounted frame. A 16:9 screen is
+// assumed when it can't be inspected.
+func RenderSlide(dstPath, text string) error {
+	return RenderPhotoSlide(dstPath, text, "")
+}
+
+// RenderPhotoSlide writes a slide like RenderSlide with the photo at photoPath beside the text
+func RenderPhotoSlide(dstPath, text, photoPath string) error {
+	targetMaxDimStr := os.Getenv("DPF_TARGET_MAX_DIM")
+	targetMaxDim, err := strconv.Atoi(targetMaxDimStr)
+	if err != nil {
+		slog.Warn("unable to parse DPF_TARGET_MAX_DIM, using default", "DPF_TARGET_MAX_DIM", targetMaxDimStr, "default", DefaultTargetMaxDim)
+		targetMaxDim = DefaultTargetMaxDim
+	}
+
+	screenW, screenH := 16, 9
+	if output, err := display.GetOutput(); err == nil {
+		if w, h, ok := output.LogicalSize(); ok && w > 0 && h > 0 {
+			screenW, screenH = w, h
+		}
+	}
+
+	width, height := targetMaxDim, max(1, targetMaxDim*screenH/screenW)
+	if screenH > screenW {
+		width, height = max(1, targetMaxDim*screenW/screenH), targetMaxDim
+	}
+	return overlay.PhotoSlideFile(dstPath, text, photoPath, width, height, RotateDegrees)
+}
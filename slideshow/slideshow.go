@@ -2,27 +2,34 @@
 package slideshow
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"image"
 	"image/jpeg"
 	"image/png"
 	"log/slog"
+	"maps"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/aouyang1/digitalphotoframe/overlay"
+	"github.com/aouyang1/digitalphotoframe/paths"
+	"github.com/aouyang1/digitalphotoframe/runner"
 	"github.com/aouyang1/digitalphotoframe/util"
+	mapset "github.com/deckarep/golang-set/v2"
 )
 
 func clearImgpArtifacts(rootPath string) error {
+	layout := paths.New(rootPath)
 	dirs := []string{
-		filepath.Join(rootPath, "original"),
-		filepath.Join(rootPath, "original/surprise"),
+		layout.OriginalDir(paths.CategoryOriginal),
+		layout.OriginalDir(paths.CategorySurprise),
 	}
 
 	for _, dir := range dirs {
@@ -39,7 +46,7 @@ func clearImgpArtifacts(rootPath string) error {
 
 			name := entry.Name()
 			// Check if file matches *_IMGP.* pattern
-			if strings.Contains(name, "_IMGP.") {
+			if paths.IsDerivative(name) {
 				filePath := filepath.Join(dir, name)
 				if err := os.Remove(filePath); err != nil {
 					slog.Warn("failed to remove imgp artifact", "path", filePath, "error", err)
@@ -57,17 +64,43 @@ type RotateOptions struct {
 	Name    string
 	Degrees int
 	Scale   int
+
+	// EraseExif drops the EXIF metadata, such as where the photo was taken, from the rotated copy
+	EraseExif bool
+}
+
+// Downsize scales the image in place by the options' scale with imgp
+func Downsize(rOpt RotateOptions) error {
+	args := append([]string{"-w", "-x", strconv.Itoa(rOpt.Scale) + "%"}, rOpt.Name)
+	if out, err := runner.Default().Run("imgp", args...); err != nil {
+		return fmt.Errorf("failed to downsize image, %s, %w", out, err)
+	}
+	return nil
+}
+
+// Rotate writes a copy of the image rotated by the options' degrees with imgp
+func Rotate(rOpt RotateOptions) error {
+	args := []string{"-o", strconv.Itoa(rOpt.Degrees)}
+	if rOpt.EraseExif {
+		args = append(args, "-e")
+	}
+	args = append(args, rOpt.Name)
+	if out, err := runner.Default().Run("imgp", args...); err != nil {
+		return fmt.Errorf("failed to rotate image, %s, %w", out, err)
+	}
+	return nil
 }
 
-func rotateImages(rootPath string, targetMaxDim int) error {
+func rotateImages(rootPath string, targetMaxDim int, eraseExif bool) error {
+	layout := paths.New(rootPath)
 	dirs := []string{
-		filepath.Join(rootPath, "original"),
-		filepath.Join(rootPath, "original/surprise"),
+		layout.OriginalDir(paths.CategoryOriginal),
+		layout.OriginalDir(paths.CategorySurprise),
 	}
 
 	photosDirs := []string{
-		filepath.Join(rootPath, "photos"),
-		filepath.Join(rootPath, "photos/surprise"),
+		layout.DerivativeDir(paths.CategoryOriginal),
+		layout.DerivativeDir(paths.CategorySurprise),
 	}
 	for i, dir := range dirs {
 		// Check if directory exists and has files
@@ -92,7 +125,7 @@ func rotateImages(rootPath string, targetMaxDim int) error {
 			name := entry.Name()
 
 			// Skip already rotated files
-			if strings.Contains(name, "_IMGP.") {
+			if paths.IsDerivative(name) {
 				continue
 			}
 
@@ -114,6 +147,7 @@ func rotateImages(rootPath string, targetMaxDim int) error {
 				slog.Warn("unable generate rotate options", "error", err)
 				continue
 			}
+			rOpt.EraseExif = eraseExif
 			imageRotOptions = append(imageRotOptions, rOpt)
 		}
 
@@ -123,16 +157,12 @@ func rotateImages(rootPath string, targetMaxDim int) error {
 
 		// downsize and then rotate
 		for rOpt := range slices.Values(imageRotOptions) {
-			args := append([]string{"-w", "-x", strconv.Itoa(rOpt.Scale) + "%"}, rOpt.Name)
-			cmd := exec.Command("imgp", args...)
-			if err := cmd.Run(); err != nil {
+			if err := Downsize(rOpt); err != nil {
 				slog.Warn("failed to downsize image", "name", rOpt.Name, "error", err)
 				continue
 			}
 
-			args = append([]string{"-o", strconv.Itoa(rOpt.Degrees)}, rOpt.Name)
-			cmd = exec.Command("imgp", args...)
-			if err := cmd.Run(); err != nil {
+			if err := Rotate(rOpt); err != nil {
 				slog.Warn("failed to rotate image", "name", rOpt.Name, "error", err)
 			}
 		}
@@ -210,15 +240,16 @@ func GenerateRotateOptions(dir, name string, targetMaxDim int) (RotateOptions, e
 
 	return RotateOptions{
 		Name:    imageFilePath,
-		Degrees: 90,
+		Degrees: RotateDegrees,
 		Scale:   downScale,
 	}, nil
 }
 
 func moveRotatedImages(rootPath string) error {
 	// Move from original to photos
-	originalDir := filepath.Join(rootPath, "original")
-	photosDir := filepath.Join(rootPath, "photos")
+	layout := paths.New(rootPath)
+	originalDir := layout.OriginalDir(paths.CategoryOriginal)
+	photosDir := layout.DerivativeDir(paths.CategoryOriginal)
 
 	// Ensure photos directory exists
 	if err := os.MkdirAll(photosDir, 0o755); err != nil {
@@ -226,7 +257,7 @@ func moveRotatedImages(rootPath string) error {
 	}
 
 	// Ensure photos/surprise directory exists
-	surprisePhotosDir := filepath.Join(rootPath, "photos/surprise")
+	surprisePhotosDir := layout.DerivativeDir(paths.CategorySurprise)
 	if err := os.MkdirAll(surprisePhotosDir, 0o755); err != nil {
 		return fmt.Errorf("failed to create photos/surprise directory: %w", err)
 	}
@@ -235,7 +266,7 @@ func moveRotatedImages(rootPath string) error {
 	moveDirFiles(originalDir, photosDir)
 
 	// Move files from original/surprise
-	surpriseDir := filepath.Join(rootPath, "original/surprise")
+	surpriseDir := layout.OriginalDir(paths.CategorySurprise)
 	moveDirFiles(surpriseDir, surprisePhotosDir)
 
 	return nil
@@ -253,7 +284,7 @@ func moveDirFiles(srcDir, dstDir string) {
 		}
 
 		name := entry.Name()
-		if !strings.Contains(name, "_IMGP.") {
+		if !paths.IsDerivative(name) {
 			continue
 		}
 
@@ -266,8 +297,7 @@ func moveDirFiles(srcDir, dstDir string) {
 }
 
 func killImvWayland() error {
-	cmd := exec.Command("pkill", "imv-wayland")
-	if err := cmd.Run(); err != nil {
+	if _, err := runner.Default().Run("pkill", "imv-wayland"); err != nil {
 		// pkill returns error if no process found, which is fine
 		return fmt.Errorf("imv-wayland not running or already killed, %w", err)
 	}
@@ -275,8 +305,7 @@ func killImvWayland() error {
 }
 
 func checkImvWayland() (bool, error) {
-	cmd := exec.Command("pgrep", "imv-wayland")
-	out, err := cmd.Output()
+	out, err := runner.Default().Output("pgrep", "imv-wayland")
 	if err != nil {
 		// pkill returns error if no process found, which is fine
 		return false, fmt.Errorf("unable to check if imv-wayland is running, %w", err)
@@ -289,59 +318,166 @@ func checkImvWayland() (bool, error) {
 	return false, nil
 }
 
-const defaultInterval = 15
+const (
+	DefaultInterval = 15
+
+	imvPath = "/usr/bin/imv-wayland"
+)
 
-func startImvWayland(rootPath string, imgPaths []string, interval int) error {
+func startImvWayland(rootPath string, imgPaths []string, interval int) (runner.Process, error) {
 	// Start imv-wayland in background
 	args := []string{"-f", "-s", "full"}
 
-	// set slideshow interval
-	if interval <= 0 {
-		interval = defaultInterval
+	// set slideshow interval, leaving imv on the first image when advancing is left to the
+	// controller
+	if interval > 0 {
+		args = append(args, "-t", strconv.Itoa(interval))
 	}
-	args = append(args, "-t", strconv.Itoa(interval))
 
-	// set explicit order of images or use default ordering by directory
-	if len(imgPaths) > 0 {
-		args = append(args, imgPaths...)
-	} else {
+	// use default ordering by directory when no explicit order of images is given
+	playlistPath := paths.New(rootPath).Playlist()
+	if len(imgPaths) == 0 {
 		slog.Info("no explicit order specified, using default directory ordering for imv")
-		photosDir := filepath.Join(rootPath, "photos")
+		photosDir := paths.New(rootPath).DerivativeDir(paths.CategoryOriginal)
 
 		// Ensure photos directory exists
 		if err := os.MkdirAll(photosDir, 0o755); err != nil {
-			return fmt.Errorf("failed to create photos directory: %w", err)
+			return nil, fmt.Errorf("failed to create photos directory: %w", err)
+		}
+
+		// the directory is the playlist, so a previous one no longer describes what's playing
+		if err := os.Remove(playlistPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("unable to remove stale playlist", "path", playlistPath, "error", err)
 		}
 
 		args = append(args, "-r", photosDir)
+		proc, err := runner.Default().Start(imvPath, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start imv-wayland: %w", err)
+		}
+		slog.Info("started imv-wayland slideshow", "pid", proc.Pid())
+		return proc, nil
+	}
+
+	// the images are read from the playlist file on stdin rather than passed as arguments, which
+	// thousands of photos would overflow
+	if err := writePlaylist(playlistPath, imgPaths); err != nil {
+		return nil, err
 	}
+	args = append(args, "-")
 
-	cmd := exec.Command("/usr/bin/imv-wayland", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("failed to start imv-wayland: %w", err)
+	proc, err := runner.Default().StartWithStdin(playlistPath, imvPath, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start imv-wayland: %w", err)
 	}
 
-	go func() {
-		err := cmd.Wait()
-		slog.Info("imv-wayland quit", "error", err)
-	}()
+	slog.Info("started imv-wayland slideshow", "pid", proc.Pid(), "playlist", playlistPath, "images", len(imgPaths))
+	return proc, nil
+}
 
-	slog.Info("started imv-wayland slideshow")
-	return nil
+// writePlaylist writes imgPaths one per line to playlistPath for imv to read from stdin. The file
+// is replaced with a rename so a running imv, which has the old file open, is never affected.
+func writePlaylist(playlistPath string, imgPaths []string) error {
+	var content strings.Builder
+	for _, imgPath := range imgPaths {
+		content.WriteString(imgPath)
+		content.WriteByte('\n')
+	}
+	if err := os.MkdirAll(filepath.Dir(playlistPath), 0o755); err != nil {
+		return fmt.Errorf("unable to create directory for %s, %w", playlistPath, err)
+	}
+
+	tmpPath := playlistPath + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(content.String()), 0o644); err != nil {
+		return fmt.Errorf("unable to write %s, %w", tmpPath, err)
+	}
+	return os.Rename(tmpPath, playlistPath)
 }
 
 const (
 	DefaultTargetMaxDim = 1024
 	checkRetries        = 30
 	checkInterval       = 1 * time.Second
+
+	// RotateDegrees is the clockwise rotation applied to derivatives for the mounted frame
+	RotateDegrees = 90
 )
 
-func RestartSlideshow(imgPaths []string, interval int) error {
+// applyCaptions swaps each image with a caption or watermark for a captioned
+// copy in the cache, rendering the copy only if it does not exist yet. Copies
+// no longer referenced are removed.
+func applyCaptions(rootPath string, imgPaths []string, captions map[string]overlay.Text, opts overlay.Options) []string {
+	opts.Rotation = RotateDegrees
+
+	captionDir := paths.New(rootPath).CaptionsDir()
+	used := mapset.NewSet[string]()
+
+	captioned := make([]string, len(imgPaths))
+	for i, imgPath := range imgPaths {
+		captioned[i] = imgPath
+
+		text := captions[imgPath]
+		if text.IsZero() {
+			continue
+		}
+
+		info, err := os.Stat(imgPath)
+		if err != nil {
+			slog.Warn("unable to stat image for caption", "path", imgPath, "error", err)
+			continue
+		}
+
+		key := sha1.Sum([]byte(fmt.Sprintf("%s|%d|%s|%s|%s|%s", imgPath, info.ModTime().UnixNano(), text.Caption, text.Watermark, opts.Position, opts.Size)))
+		dst := filepath.Join(captionDir, hex.EncodeToString(key[:])+filepath.Ext(imgPath))
+		if _, err := os.Stat(dst); err != nil {
+			if err := overlay.CaptionFile(imgPath, dst, text, opts); err != nil {
+				slog.Warn("failed to caption image, using uncaptioned image", "path", imgPath, "error", err)
+				continue
+			}
+		}
+		used.Add(filepath.Base(dst))
+		captioned[i] = dst
+	}
+
+	entries, err := os.ReadDir(captionDir)
+	if err != nil {
+		return captioned
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || used.Contains(entry.Name()) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(captionDir, entry.Name())); err != nil {
+			slog.Warn("failed to remove stale captioned image", "name", entry.Name(), "error", err)
+		}
+	}
+	return captioned
+}
+
+// started is the imv process restartSlideshow started and what it's playing
+type started struct {
+	proc runner.Process
+
+	// playlist is the images imv is playing, without collages that couldn't be composed
+	playlist []string
+
+	// fade is the crossfade frames laid out between the images, or nil without crossfade
+	fade *crossfade
+
+	// timeline maps the playlist to imv's list of frames
+	timeline *timeline
+}
+
+// restartSlideshow regenerates missing derivatives, erasing their EXIF
+// metadata when eraseExif is set, and restarts imv with imgPaths. It
+// crossfades between images when fade is set and pans along panoramas when
+// pan is set. Paths in collages are composed from the derivatives listed for
+// them, and paths in captions get the caption and watermark styled by
+// captionOpts.
+func restartSlideshow(imgPaths []string, collages map[string][]string, interval int, fade, pan bool, captions map[string]overlay.Text, captionOpts overlay.Options, eraseExif bool) (*started, error) {
 	rootPath := os.Getenv("DPF_ROOT_PATH")
 	if rootPath == "" {
-		return errors.New("DPF_ROOT_PATH environment variable is required")
+		return nil, errors.New("DPF_ROOT_PATH environment variable is required")
 	}
 	targetMaxDimStr := os.Getenv("DPF_TARGET_MAX_DIM")
 	targetMaxDim, err := strconv.Atoi(targetMaxDimStr)
@@ -352,17 +488,65 @@ func RestartSlideshow(imgPaths []string, interval int) error {
 
 	// Clear old imgp artifacts
 	if err := clearImgpArtifacts(rootPath); err != nil {
-		return fmt.Errorf("error clearing imgp artifacts, %w", err)
+		return nil, fmt.Errorf("error clearing imgp artifacts, %w", err)
 	}
 
 	// Rotate images
-	if err := rotateImages(rootPath, targetMaxDim); err != nil {
-		return fmt.Errorf("error rotating images, %w", err)
+	if err := rotateImages(rootPath, targetMaxDim, eraseExif); err != nil {
+		return nil, fmt.Errorf("error rotating images, %w", err)
 	}
 
 	// Move rotated images
 	if err := moveRotatedImages(rootPath); err != nil {
-		return fmt.Errorf("error moving rotated images, %w", err)
+		return nil, fmt.Errorf("error moving rotated images, %w", err)
+	}
+
+	// Compose collages from the derivatives
+	imgPaths = applyCollages(rootPath, imgPaths, collages)
+	playlist := imgPaths
+
+	// Play panoramas as the frames panning across them, which carry their own captions
+	var frames [][]string
+	if pan {
+		frames = preparePanoramas(rootPath, imgPaths, captions, captionOpts)
+		captions = maps.Clone(captions)
+		for i, f := range frames {
+			if f != nil {
+				delete(captions, imgPaths[i])
+			}
+		}
+	} else {
+		frames = make([][]string, len(imgPaths))
+		if err := os.RemoveAll(paths.New(rootPath).PanoramasDir()); err != nil {
+			slog.Warn("unable to remove panorama frames", "error", err)
+		}
+	}
+
+	// Caption images
+	for i, imgPath := range applyCaptions(rootPath, imgPaths, captions, captionOpts) {
+		if frames[i] == nil {
+			frames[i] = []string{imgPath}
+		}
+	}
+
+	// Lay out crossfade frames between images
+	var transitions *crossfade
+	imgPaths = slices.Concat(frames...)
+	if fade {
+		list, f, err := prepareCrossfade(rootPath, frames)
+		if err != nil {
+			slog.Warn("unable to prepare crossfade, cutting between images", "error", err)
+		} else {
+			imgPaths, transitions = list, f
+		}
+	} else if err := os.RemoveAll(paths.New(rootPath).TransitionsDir()); err != nil {
+		slog.Warn("unable to remove crossfade frames", "error", err)
+	}
+
+	// the controller steps imv through the frames of a pan, so imv is left on the first image
+	tl := newTimeline(frames, transitions)
+	if tl.panning() {
+		interval = 0
 	}
 
 	// Kill existing imv-wayland
@@ -371,31 +555,28 @@ func RestartSlideshow(imgPaths []string, interval int) error {
 	}
 
 	// Start new imv-wayland
-	if err := startImvWayland(rootPath, imgPaths, interval); err != nil {
-		return fmt.Errorf("failed to restart slideshow: %w", err)
+	proc, err := startImvWayland(rootPath, imgPaths, interval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restart slideshow: %w", err)
 	}
 
 	ticker := time.NewTicker(checkInterval)
 	defer ticker.Stop()
 
-	var retries int
-	for range ticker.C {
+	for retries := 1; ; retries++ {
+		<-ticker.C
 		running, err := checkImvWayland()
 		if err != nil {
 			slog.Warn("issue checking if imv-wayland is running", "error", err)
-			retries += 1
-			continue
-		}
-		if !running {
-			if retries >= checkRetries-1 {
-				slog.Warn("exhausted retry check for imv-wayland running")
-				return nil
-			}
-			retries += 1
-			continue
 		}
 		// imv-wayland is running
-		break
+		if running {
+			break
+		}
+		if retries >= checkRetries {
+			slog.Warn("exhausted retry check for imv-wayland running")
+			break
+		}
 	}
-	return nil
+	return &started{proc: proc, playlist: playlist, fade: transitions, timeline: tl}, nil
 }
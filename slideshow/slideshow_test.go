@@ -0,0 +1,142 @@
+package slideshow
+
+import (
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/aouyang1/digitalphotoframe/paths"
+	"github.com/aouyang1/digitalphotoframe/runner"
+)
+
+func useFake(t *testing.T) *runner.Fake {
+	t.Helper()
+	fake := runner.NewFake()
+	previous := runner.Default()
+	runner.SetDefault(fake)
+	t.Cleanup(func() { runner.SetDefault(previous) })
+	return fake
+}
+
+func TestRotate(t *testing.T) {
+	tests := []struct {
+		name string
+		opts RotateOptions
+		want []string
+	}{
+		{"keep exif", RotateOptions{Name: "a.jpg", Degrees: 90}, []string{"-o", "90", "a.jpg"}},
+		{"erase exif", RotateOptions{Name: "a.jpg", Degrees: 270, EraseExif: true}, []string{"-o", "270", "-e", "a.jpg"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := useFake(t)
+			if err := Rotate(tt.opts); err != nil {
+				t.Fatalf("Rotate() error = %v", err)
+			}
+			calls := fake.Calls()
+			if len(calls) != 1 || calls[0].Name != "imgp" || !slices.Equal(calls[0].Args, tt.want) {
+				t.Errorf("Rotate() ran %+v, want imgp %v", calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestDownsize(t *testing.T) {
+	fake := useFake(t)
+	if err := Downsize(RotateOptions{Name: "a.jpg", Scale: 25}); err != nil {
+		t.Fatalf("Downsize() error = %v", err)
+	}
+	want := []string{"-w", "-x", "25%", "a.jpg"}
+	if calls := fake.Calls(); len(calls) != 1 || !slices.Equal(calls[0].Args, want) {
+		t.Errorf("Downsize() ran %+v, want imgp %v", calls, want)
+	}
+}
+
+func TestStartImvWayland(t *testing.T) {
+	tests := []struct {
+		name      string
+		imgPaths  []string
+		interval  int
+		wantArgs  func(root string) []string
+		wantStdin bool
+	}{
+		{
+			name:      "playlist",
+			imgPaths:  []string{"/photos/a.jpg", "/photos/b.jpg"},
+			interval:  15,
+			wantArgs:  func(string) []string { return []string{"-f", "-s", "full", "-t", "15", "-"} },
+			wantStdin: true,
+		},
+		{
+			name:      "advanced by the controller",
+			imgPaths:  []string{"/photos/a.jpg"},
+			interval:  0,
+			wantArgs:  func(string) []string { return []string{"-f", "-s", "full", "-"} },
+			wantStdin: true,
+		},
+		{
+			name:     "directory order",
+			interval: 15,
+			wantArgs: func(root string) []string {
+				return []string{"-f", "-s", "full", "-t", "15", "-r", paths.New(root).DerivativeDir(paths.CategoryOriginal)}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := useFake(t)
+			root := t.TempDir()
+
+			if _, err := startImvWayland(root, tt.imgPaths, tt.interval); err != nil {
+				t.Fatalf("startImvWayland() error = %v", err)
+			}
+
+			calls := fake.Calls()
+			if len(calls) != 1 {
+				t.Fatalf("startImvWayland() ran %d commands, want 1", len(calls))
+			}
+			call := calls[0]
+			if call.Name != imvPath || !slices.Equal(call.Args, tt.wantArgs(root)) {
+				t.Errorf("startImvWayland() ran %s %v, want %s %v", call.Name, call.Args, imvPath, tt.wantArgs(root))
+			}
+
+			playlistPath := paths.New(root).Playlist()
+			if !tt.wantStdin {
+				if call.Stdin != "" {
+					t.Errorf("startImvWayland() read stdin from %s, want none", call.Stdin)
+				}
+				return
+			}
+			if call.Stdin != playlistPath {
+				t.Errorf("startImvWayland() read stdin from %q, want %q", call.Stdin, playlistPath)
+			}
+			content, err := os.ReadFile(playlistPath)
+			if err != nil {
+				t.Fatalf("unable to read playlist: %v", err)
+			}
+			want := ""
+			for _, imgPath := range tt.imgPaths {
+				want += imgPath + "\n"
+			}
+			if string(content) != want {
+				t.Errorf("playlist = %q, want %q", content, want)
+			}
+		})
+	}
+}
+
+func TestQuoteImvArg(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"/photos/a.jpg", "'/photos/a.jpg'"},
+		{"/photos/beach day.jpg", "'/photos/beach day.jpg'"},
+		{"/photos/mom's.jpg", `'/photos/mom'\''s.jpg'`},
+	}
+	for _, tt := range tests {
+		if got := quoteImvArg(tt.arg); got != tt.want {
+			t.Errorf("quoteImvArg(%q) = %s, want %s", tt.arg, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package slideshow
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aouyang1/digitalphotoframe/runner"
+)
+
+const (
+	minRestartBackoff = 1 * time.Second
+	maxRestartBackoff = 5 * time.Minute
+
+	// imv running at least this long after a restart is considered recovered, so the next time it
+	// quits is restarted without waiting
+	stableRunTime = time.Minute
+)
+
+// Status reports whether imv is running and how often it has had to be restarted after quitting
+// on its own
+type Status struct {
+	Running bool
+
+	// Restarts counts every time imv quit on its own since the server started
+	Restarts int
+
+	// Failures counts the times imv quit or failed to start in a row without recovering
+	Failures int
+
+	LastFailure time.Time
+	LastError   error
+}
+
+// Status returns the health of the imv process
+func (c *Controller) Status() Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.resetIfStable()
+	return Status{
+		Running:     c.proc != nil,
+		Restarts:    c.restarts,
+		Failures:    c.failures,
+		LastFailure: c.lastFailure,
+		LastError:   c.lastErr,
+	}
+}
+
+// supervise waits for imv to quit, restarting it with the last playlist unless it was replaced
+// or stopped on purpose
+func (c *Controller) supervise(proc runner.Process) {
+	err := proc.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.proc != proc {
+		slog.Info("imv-wayland quit", "error", err)
+		return
+	}
+	if err == nil {
+		err = errors.New("exited")
+	}
+
+	c.resetIfStable()
+	c.cancelShow()
+	c.proc = nil
+	c.pid = 0
+	c.restarts++
+	c.recordFailure(fmt.Errorf("imv-wayland quit unexpectedly, %w", err))
+}
+
+// recordFailure notes imv quitting or failing to start and schedules the next attempt to start
+// it, doubling the wait each time in a row. Callers must hold mu.
+func (c *Controller) recordFailure(err error) {
+	c.failures++
+	c.lastFailure = time.Now()
+	c.lastErr = err
+
+	// the cap is reached long before the shift could overflow
+	backoff := maxRestartBackoff
+	if c.failures <= 16 {
+		backoff = min(minRestartBackoff<<(c.failures-1), maxRestartBackoff)
+	}
+	slog.Warn("restarting imv-wayland", "failures", c.failures, "backoff", backoff, "error", err)
+
+	c.retryGen++
+	gen := c.retryGen
+	c.retry = time.AfterFunc(backoff, func() {
+		c.retryRestart(gen)
+	})
+}
+
+// retryRestart starts imv again after it quit, unless it was restarted or stopped in the meantime
+func (c *Controller) retryRestart(gen int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.retry == nil || c.retryGen != gen || c.last == nil {
+		return
+	}
+	c.retry = nil
+
+	// the held image is gone with the process, so the deferred restart is applied instead
+	req := c.last
+	if c.pending != nil {
+		req = c.pending
+		c.pending = nil
+	}
+	c.held = false
+
+	if err := c.restart(req.imgPaths, req.collages, req.pacing, req.captions, req.captionOpts, req.eraseExif); err != nil {
+		c.recordFailure(err)
+		return
+	}
+	slog.Info("restarted imv-wayland after it quit", "failures", c.failures)
+}
+
+// EnsurePlaying checks imv is still answering after the display comes back on, which can leave it
+// showing a stale or blank screen. imv is asked to draw the image on screen again when it answers,
+// otherwise it's restarted from the last saved position.
+func (c *Controller) EnsurePlaying() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// nothing to do before the first restart, or when imv already quit and is being restarted
+	if c.last == nil || c.retry != nil {
+		return nil
+	}
+
+	if c.proc != nil {
+		idx, err := c.currentIndex()
+		if err == nil {
+			// a photo shown out of order is left to return to the playlist on its own
+			if c.show != nil {
+				return nil
+			}
+			return c.goTo(idx)
+		}
+		slog.Warn("imv-wayland isn't answering, restarting it", "error", err)
+	}
+
+	if c.db != nil {
+		resume, err := c.db.GetSlideshowState()
+		if err != nil {
+			slog.Warn("unable to load slideshow state, restarting from the beginning", "error", err)
+		}
+		c.resume = resume
+	}
+
+	req := c.last
+	if c.pending != nil {
+		req = c.pending
+		c.pending = nil
+	}
+	c.held = false
+
+	c.cancelShow()
+	if err := c.restart(req.imgPaths, req.collages, req.pacing, req.captions, req.captionOpts, req.eraseExif); err != nil {
+		c.recordFailure(err)
+		return err
+	}
+	slog.Info("restarted imv-wayland to resume playback")
+	return nil
+}
+
+// cancelRetry stops a pending restart after imv quit. Callers must hold mu.
+func (c *Controller) cancelRetry() {
+	if c.retry != nil {
+		c.retry.Stop()
+		c.retry = nil
+	}
+}
+
+// resetIfStable clears the failures once imv has kept running long enough after a restart.
+// Callers must hold mu.
+func (c *Controller) resetIfStable() {
+	if c.proc != nil && time.Since(c.startedAt) >= stableRunTime {
+		c.failures = 0
+	}
+}
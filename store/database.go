@@ -3,9 +3,14 @@ package store
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	_ "modernc.org/sqlite"
 )
@@ -21,7 +26,9 @@ func NewDatabase(dbPath string) (*Database, error) {
 		return nil, fmt.Errorf("failed to create database directory: %w", err)
 	}
 
-	db, err := sql.Open("sqlite", dbPath)
+	// writers from the upload handler and the sync managers wait for each other rather than
+	// failing with the database locked
+	db, err := sql.Open("sqlite", dbPath+"?_pragma=busy_timeout(5000)")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -37,12 +44,18 @@ func NewDatabase(dbPath string) (*Database, error) {
 		return nil, fmt.Errorf("failed to create table: %w", err)
 	}
 
+	// Add columns introduced after a table was first created
+	if err := database.migrate(); err != nil {
+		return nil, fmt.Errorf("failed to migrate tables: %w", err)
+	}
+
 	return database, nil
 }
 
 func (d *Database) createTable() error {
 	query := `
 	CREATE TABLE IF NOT EXISTS photos (
+		id TEXT NOT NULL UNIQUE,
 		photo_name TEXT NOT NULL,
 		category INTEGER NOT NULL,
 		"order" INTEGER NOT NULL,
@@ -63,29 +76,305 @@ func (d *Database) createTable() error {
 		end     TEXT NOT NULL,
 		PRIMARY KEY (singleton)
 	);
+	CREATE TABLE IF NOT EXISTS share_links (
+		token      TEXT NOT NULL,
+		photo_id   TEXT NOT NULL REFERENCES photos(id),
+		expires_at INTEGER NOT NULL,
+		PRIMARY KEY (token)
+	);
+	CREATE TABLE IF NOT EXISTS upload_tokens (
+		token      TEXT NOT NULL,
+		label      TEXT NOT NULL,
+		expires_at INTEGER NOT NULL,
+		PRIMARY KEY (token)
+	);
+	CREATE TABLE IF NOT EXISTS schedule_profiles (
+		name  TEXT NOT NULL,
+		start TEXT NOT NULL,
+		end   TEXT NOT NULL,
+		days  TEXT NOT NULL,
+		PRIMARY KEY (name)
+	);
+	CREATE TABLE IF NOT EXISTS display_usage (
+		day        TEXT NOT NULL,
+		on_seconds INTEGER NOT NULL,
+		PRIMARY KEY (day)
+	);
+	CREATE TABLE IF NOT EXISTS seasonal_rules (
+		id    INTEGER PRIMARY KEY AUTOINCREMENT,
+		album TEXT NOT NULL,
+		label TEXT NOT NULL,
+		start TEXT NOT NULL,
+		end   TEXT NOT NULL
+	);
+	CREATE TABLE IF NOT EXISTS announcements (
+		id    INTEGER PRIMARY KEY AUTOINCREMENT,
+		text  TEXT NOT NULL,
+		start TEXT NOT NULL,
+		end   TEXT NOT NULL
+	);
+	CREATE TABLE IF NOT EXISTS special_dates (
+		id    INTEGER PRIMARY KEY AUTOINCREMENT,
+		name  TEXT NOT NULL,
+		date  TEXT NOT NULL,
+		type  TEXT NOT NULL,
+		boost INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE TABLE IF NOT EXISTS album_schedules (
+		id        INTEGER PRIMARY KEY AUTOINCREMENT,
+		album     TEXT NOT NULL,
+		start     TEXT NOT NULL,
+		end       TEXT NOT NULL,
+		exclusive INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE TABLE IF NOT EXISTS retention_rules (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		album      TEXT NOT NULL,
+		days       INTEGER NOT NULL,
+		action     TEXT NOT NULL,
+		created_at INTEGER NOT NULL
+	);
+	CREATE TABLE IF NOT EXISTS shared_albums (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		album      TEXT NOT NULL,
+		token      TEXT NOT NULL UNIQUE,
+		created_at INTEGER NOT NULL
+	);
+	CREATE TABLE IF NOT EXISTS album_pairings (
+		id             INTEGER PRIMARY KEY AUTOINCREMENT,
+		url            TEXT NOT NULL,
+		token          TEXT NOT NULL,
+		album          TEXT NOT NULL,
+		last_synced_at INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE TABLE IF NOT EXISTS paired_photos (
+		pairing_id INTEGER NOT NULL,
+		remote_id  TEXT NOT NULL,
+		photo_id   TEXT NOT NULL,
+		PRIMARY KEY (pairing_id, remote_id)
+	);
+	CREATE TABLE IF NOT EXISTS settings_history (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		kind       TEXT NOT NULL,
+		changed_at INTEGER NOT NULL,
+		changed_by TEXT NOT NULL,
+		data       TEXT NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_settings_history_kind ON settings_history(kind, id);
+	CREATE TABLE IF NOT EXISTS events (
+		id          INTEGER PRIMARY KEY AUTOINCREMENT,
+		kind        TEXT NOT NULL,
+		occurred_at INTEGER NOT NULL,
+		actor       TEXT NOT NULL,
+		count       INTEGER NOT NULL,
+		detail      TEXT NOT NULL
+	);
+	CREATE TABLE IF NOT EXISTS people (
+		id        INTEGER PRIMARY KEY AUTOINCREMENT,
+		name      TEXT NOT NULL UNIQUE COLLATE NOCASE,
+		exclusive INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE TABLE IF NOT EXISTS photo_people (
+		person_id INTEGER NOT NULL,
+		photo_id  TEXT NOT NULL REFERENCES photos(id),
+		PRIMARY KEY (person_id, photo_id)
+	);
+	CREATE INDEX IF NOT EXISTS idx_photo_people_photo ON photo_people(photo_id);
+	CREATE TABLE IF NOT EXISTS reactions (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		photo_id   TEXT NOT NULL REFERENCES photos(id),
+		name       TEXT NOT NULL DEFAULT '',
+		heart      INTEGER NOT NULL DEFAULT 0,
+		comment    TEXT NOT NULL DEFAULT '',
+		created_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_reactions_photo ON reactions(photo_id);
+	CREATE TABLE IF NOT EXISTS guestbook (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		name       TEXT NOT NULL DEFAULT '',
+		message    TEXT NOT NULL,
+		selfie     INTEGER NOT NULL DEFAULT 0,
+		created_at INTEGER NOT NULL
+	);
+	CREATE TABLE IF NOT EXISTS slideshow_state (
+		singleton   INTEGER NOT NULL DEFAULT 1 CHECK (singleton = 1),
+		playlist_id TEXT NOT NULL,
+		playlist    TEXT NOT NULL,
+		position    INTEGER NOT NULL,
+		paused      INTEGER NOT NULL,
+		started_at  INTEGER NOT NULL,
+		saved_at    INTEGER NOT NULL,
+		PRIMARY KEY (singleton)
+	);
 	`
 	_, err := d.db.Exec(query)
 	return err
 }
 
-func (d *Database) InsertPhoto(name string, category int, order int) error {
-	query := `INSERT INTO photos (photo_name, category, "order") VALUES (?, ?, ?)`
-	_, err := d.db.Exec(query, name, category, order)
+// columnMigrations are columns added to existing tables, applied in order if missing
+var columnMigrations = []struct {
+	table      string
+	column     string
+	definition string
+}{
+	{"photos", "uploaded_by", "TEXT NOT NULL DEFAULT ''"},
+	{"app_settings", "show_uploader", "INTEGER NOT NULL DEFAULT 0"},
+	{"app_settings", "language", "TEXT NOT NULL DEFAULT 'en'"},
+	{"app_settings", "theme", "TEXT NOT NULL DEFAULT 'light'"},
+	{"app_settings", "accent_color", "TEXT NOT NULL DEFAULT '#007AFF'"},
+	{"photos", "caption", "TEXT NOT NULL DEFAULT ''"},
+	{"photos", "album", "TEXT NOT NULL DEFAULT ''"},
+	{"photos", "taken_at", "INTEGER NOT NULL DEFAULT 0"},
+	{"photos", "added_at", "INTEGER NOT NULL DEFAULT 0"},
+	{"app_settings", "show_filename", "INTEGER NOT NULL DEFAULT 0"},
+	{"app_settings", "show_caption", "INTEGER NOT NULL DEFAULT 0"},
+	{"app_settings", "show_date_taken", "INTEGER NOT NULL DEFAULT 0"},
+	{"app_settings", "overlay_position", "TEXT NOT NULL DEFAULT 'bottom-right'"},
+	{"app_settings", "overlay_size", "TEXT NOT NULL DEFAULT 'medium'"},
+	{"app_settings", "photo_of_day_enabled", "INTEGER NOT NULL DEFAULT 0"},
+	{"app_settings", "photo_of_day_time", "TEXT NOT NULL DEFAULT '06:00'"},
+	{"app_settings", "photo_of_day_id", "TEXT NOT NULL DEFAULT ''"},
+	{"app_settings", "playlist_order", "TEXT NOT NULL DEFAULT 'sequential'"},
+	{"app_settings", "album_weights", "TEXT NOT NULL DEFAULT '{}'"},
+	{"app_settings", "auto_organize", "TEXT NOT NULL DEFAULT 'off'"},
+	{"app_settings", "display_transform", "TEXT NOT NULL DEFAULT 'normal'"},
+	{"app_settings", "display_mode", "TEXT NOT NULL DEFAULT ''"},
+	{"app_settings", "display_scale", "REAL NOT NULL DEFAULT 1"},
+	{"schedule", "active_profile", "TEXT NOT NULL DEFAULT ''"},
+	{"schedule", "today_profile", "TEXT NOT NULL DEFAULT ''"},
+	{"schedule", "today_date", "TEXT NOT NULL DEFAULT ''"},
+	{"schedule", "action", "TEXT NOT NULL DEFAULT 'off'"},
+	{"schedule", "dim_percent", "INTEGER NOT NULL DEFAULT 20"},
+	{"schedule", "slow_interval_seconds", "INTEGER NOT NULL DEFAULT 300"},
+	{"schedule_profiles", "action", "TEXT NOT NULL DEFAULT 'off'"},
+	{"schedule_profiles", "dim_percent", "INTEGER NOT NULL DEFAULT 20"},
+	{"schedule_profiles", "slow_interval_seconds", "INTEGER NOT NULL DEFAULT 300"},
+	{"app_settings", "strip_exif", "INTEGER NOT NULL DEFAULT 0"},
+	{"app_settings", "watermark_text", "TEXT NOT NULL DEFAULT ''"},
+	{"app_settings", "watermark_uploader", "INTEGER NOT NULL DEFAULT 0"},
+	{"photos", "hidden", "INTEGER NOT NULL DEFAULT 0"},
+	{"photos", "pending", "INTEGER NOT NULL DEFAULT 0"},
+	{"app_settings", "approve_surprise", "INTEGER NOT NULL DEFAULT 0"},
+	{"photos", "id", "TEXT NOT NULL DEFAULT ''"},
+	{"photos", "pinned", "INTEGER NOT NULL DEFAULT 0"},
+	{"app_settings", "interval_jitter_seconds", "INTEGER NOT NULL DEFAULT 0"},
+	{"app_settings", "crossfade", "INTEGER NOT NULL DEFAULT 0"},
+	{"app_settings", "collages", "INTEGER NOT NULL DEFAULT 0"},
+	{"photos", "tags", "TEXT NOT NULL DEFAULT '[]'"},
+	{"photos", "show_from", "TEXT NOT NULL DEFAULT ''"},
+	{"photos", "show_until", "TEXT NOT NULL DEFAULT ''"},
+	{"app_settings", "show_reactions", "INTEGER NOT NULL DEFAULT 0"},
+	{"app_settings", "guestbook_days", "INTEGER NOT NULL DEFAULT 7"},
+	{"photos", "motion", "TEXT NOT NULL DEFAULT ''"},
+	{"app_settings", "play_motion", "INTEGER NOT NULL DEFAULT 0"},
+	{"app_settings", "pan_panoramas", "INTEGER NOT NULL DEFAULT 0"},
+	{"app_settings", "audio_enabled", "INTEGER NOT NULL DEFAULT 0"},
+	{"app_settings", "audio_source", "TEXT NOT NULL DEFAULT ''"},
+	{"app_settings", "audio_volume", "INTEGER NOT NULL DEFAULT 50"},
+}
+
+// newPhotoID is the sql expression generating a photo's id, which is random so an id is never
+// reused for another photo after one is deleted
+const newPhotoID = `lower(hex(randomblob(16)))`
+
+func (d *Database) migrate() error {
+	for _, m := range columnMigrations {
+		exists, err := d.columnExists(m.table, m.column)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		stmt := fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, m.table, m.column, m.definition)
+		if _, err := d.db.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %w", m.table, m.column, err)
+		}
+	}
+
+	// photos registered before ids were added are given one
+	if _, err := d.db.Exec(`UPDATE photos SET id = ` + newPhotoID + ` WHERE id = ''`); err != nil {
+		return fmt.Errorf("failed to assign photo ids: %w", err)
+	}
+	if _, err := d.db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_photos_id ON photos(id)`); err != nil {
+		return fmt.Errorf("failed to index photo ids: %w", err)
+	}
+	return nil
+}
+
+func (d *Database) columnExists(table, column string) (bool, error) {
+	query := `SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`
+	var count int
+	if err := d.db.QueryRow(query, table, column).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	return count > 0, nil
+}
+
+// InsertPhotoAutoOrder registers a photo at the end of its category, returning the order it was
+// given. The order is picked in the same statement as the insert so photos registered at the same
+// time never share one. The photo waits for approval before it is played when pending is set.
+func (d *Database) InsertPhotoAutoOrder(name string, category int, uploadedBy string, pending bool) (int, error) {
+	query := `
+		INSERT INTO photos (id, photo_name, category, "order", uploaded_by, added_at, pending)
+		SELECT ` + newPhotoID + `, ?, ?, COALESCE(MAX("order"), -1) + 1, ?, ?, ?
+		FROM photos
+		WHERE category = ?
+		RETURNING "order"
+	`
+	var order int
+	err := d.db.QueryRow(query, name, category, uploadedBy, time.Now().Unix(), boolToInt(pending), category).Scan(&order)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert photo: %w", err)
+	}
+	return order, nil
+}
+
+// InsertPhoto registers a photo, which waits for approval before it is played when pending is set
+func (d *Database) InsertPhoto(name string, category int, order int, uploadedBy string, pending bool) error {
+	query := `INSERT INTO photos (id, photo_name, category, "order", uploaded_by, added_at, pending) VALUES (` + newPhotoID + `, ?, ?, ?, ?, ?, ?)`
+	_, err := d.db.Exec(query, name, category, order, uploadedBy, time.Now().Unix(), boolToInt(pending))
 	if err != nil {
 		return fmt.Errorf("failed to insert photo: %w", err)
 	}
 	return nil
 }
 
-func (d *Database) GetPhotos(category int, limit int, offset int) ([]Photo, error) {
+// where is the sql condition matching the filter's photos along with its arguments
+func (f PhotoFilter) where() (string, []any) {
+	conditions := []string{"category = ?"}
+	args := []any{f.Category}
+	if !f.AddedAfter.IsZero() {
+		conditions = append(conditions, "added_at >= ?")
+		args = append(args, f.AddedAfter.Unix())
+	}
+	if !f.AddedBefore.IsZero() {
+		// photos registered before the time added was recorded can't be placed
+		conditions = append(conditions, "added_at < ? AND added_at > 0")
+		args = append(args, f.AddedBefore.Unix())
+	}
+	if f.UploadedBy != "" {
+		conditions = append(conditions, "uploaded_by = ? COLLATE NOCASE")
+		args = append(args, f.UploadedBy)
+	}
+	if f.Tag != "" {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM json_each(tags) WHERE value = ? COLLATE NOCASE)")
+		args = append(args, f.Tag)
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// GetPhotos returns a page of the photos matching the filter in playlist order
+func (d *Database) GetPhotos(filter PhotoFilter, limit int, offset int) ([]Photo, error) {
+	where, args := filter.where()
 	query := `
-		SELECT photo_name, category, "order"
+		SELECT id, photo_name, category, "order", uploaded_by, caption, album, taken_at, added_at, hidden, pending, pinned, tags, show_from, show_until, motion
 		FROM photos
-		WHERE category = ?
+		WHERE ` + where + `
 		ORDER BY "order" ASC
 		LIMIT ? OFFSET ?
 	`
-	rows, err := d.db.Query(query, category, limit, offset)
+	rows, err := d.db.Query(query, append(args, limit, offset)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query photos: %w", err)
 	}
@@ -93,9 +382,9 @@ func (d *Database) GetPhotos(category int, limit int, offset int) ([]Photo, erro
 
 	var photos []Photo
 	for rows.Next() {
-		var p Photo
-		if err := rows.Scan(&p.PhotoName, &p.Category, &p.Order); err != nil {
-			return nil, fmt.Errorf("failed to scan photo: %w", err)
+		p, err := scanPhoto(rows)
+		if err != nil {
+			return nil, err
 		}
 		photos = append(photos, p)
 	}
@@ -107,14 +396,100 @@ func (d *Database) GetPhotos(category int, limit int, offset int) ([]Photo, erro
 	return photos, nil
 }
 
+// photoPageSize is how many rows AllPhotos reads from the database at a time
+const photoPageSize = 500
+
+// GetAllPhotos returns every photo in a category from the highest order down. Large categories are
+// better walked with AllPhotos.
 func (d *Database) GetAllPhotos(category int) ([]Photo, error) {
+	var photos []Photo
+	for photo, err := range d.AllPhotos(PhotoFilter{Category: category}) {
+		if err != nil {
+			return nil, err
+		}
+		photos = append(photos, photo)
+	}
+	return photos, nil
+}
+
+// AllPhotos iterates over the photos matching the filter in the same order as GetAllPhotos,
+// reading them a page at a time so a library of tens of thousands of photos is never held in
+// memory at once. No query is open while the loop body runs, so it may update the database.
+// Iteration stops after yielding an error.
+func (d *Database) AllPhotos(filter PhotoFilter) iter.Seq2[Photo, error] {
+	return func(yield func(Photo, error) bool) {
+		var after *Photo
+		for {
+			page, err := d.GetPhotosAfter(filter, after, photoPageSize)
+			if err != nil {
+				yield(Photo{}, err)
+				return
+			}
+			for _, photo := range page {
+				if !yield(photo, nil) {
+					return
+				}
+			}
+			if len(page) < photoPageSize {
+				return
+			}
+			after = &page[len(page)-1]
+		}
+	}
+}
+
+// GetPhotosAfter returns up to limit photos matching the filter that come after the given photo in
+// the order of GetAllPhotos, or the first ones when after is nil. Paging from the last photo seen
+// rather than an offset stays fast deep into a large library and doesn't skip photos when earlier
+// ones are deleted between pages.
+func (d *Database) GetPhotosAfter(filter PhotoFilter, after *Photo, limit int) ([]Photo, error) {
+	where, args := filter.where()
+	if after != nil {
+		// ids break ties between photos left with the same order by older versions
+		where += ` AND ("order", id) < (?, ?)`
+		args = append(args, after.Order, after.ID)
+	}
 	query := `
-		SELECT photo_name, category, "order"
+		SELECT id, photo_name, category, "order", uploaded_by, caption, album, taken_at, added_at, hidden, pending, pinned, tags, show_from, show_until, motion
 		FROM photos
-		WHERE category = ?
-		ORDER BY "order" DESC
+		WHERE ` + where + `
+		ORDER BY "order" DESC, id DESC
+		LIMIT ?
+	`
+	rows, err := d.db.Query(query, append(args, limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query photos: %w", err)
+	}
+	defer rows.Close()
+
+	var photos []Photo
+	for rows.Next() {
+		p, err := scanPhoto(rows)
+		if err != nil {
+			return nil, err
+		}
+		photos = append(photos, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return photos, nil
+}
+
+// GetPhotosPage returns a page of the photos matching the filter in the order of GetAllPhotos, for
+// showing a category a page at a time
+func (d *Database) GetPhotosPage(filter PhotoFilter, limit int, offset int) ([]Photo, error) {
+	where, args := filter.where()
+	query := `
+		SELECT id, photo_name, category, "order", uploaded_by, caption, album, taken_at, added_at, hidden, pending, pinned, tags, show_from, show_until, motion
+		FROM photos
+		WHERE ` + where + `
+		ORDER BY "order" DESC, id DESC
+		LIMIT ? OFFSET ?
 	`
-	rows, err := d.db.Query(query, category)
+	rows, err := d.db.Query(query, append(args, limit, offset)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query photos: %w", err)
 	}
@@ -122,9 +497,41 @@ func (d *Database) GetAllPhotos(category int) ([]Photo, error) {
 
 	var photos []Photo
 	for rows.Next() {
-		var p Photo
-		if err := rows.Scan(&p.PhotoName, &p.Category, &p.Order); err != nil {
-			return nil, fmt.Errorf("failed to scan photo: %w", err)
+		p, err := scanPhoto(rows)
+		if err != nil {
+			return nil, err
+		}
+		photos = append(photos, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return photos, nil
+}
+
+// GetRecentPhotos returns up to limit photos across categories added since the given time,
+// newest first. Photos registered before the time added was recorded are left out.
+func (d *Database) GetRecentPhotos(since time.Time, limit int) ([]Photo, error) {
+	query := `
+		SELECT id, photo_name, category, "order", uploaded_by, caption, album, taken_at, added_at, hidden, pending, pinned, tags, show_from, show_until, motion
+		FROM photos
+		WHERE added_at >= ? AND added_at > 0
+		ORDER BY added_at DESC, photo_name ASC
+		LIMIT ?
+	`
+	rows, err := d.db.Query(query, since.Unix(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent photos: %w", err)
+	}
+	defer rows.Close()
+
+	var photos []Photo
+	for rows.Next() {
+		p, err := scanPhoto(rows)
+		if err != nil {
+			return nil, err
 		}
 		photos = append(photos, p)
 	}
@@ -136,6 +543,16 @@ func (d *Database) GetAllPhotos(category int) ([]Photo, error) {
 	return photos, nil
 }
 
+// CountPhotos returns how many photos match the filter
+func (d *Database) CountPhotos(filter PhotoFilter) (int, error) {
+	where, args := filter.where()
+	var count int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM photos WHERE `+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to get photo count: %w", err)
+	}
+	return count, nil
+}
+
 func (d *Database) GetPhotoCount(category int) (int, error) {
 	query := `SELECT COUNT(*) FROM photos WHERE category = ?`
 	var count int
@@ -146,33 +563,298 @@ func (d *Database) GetPhotoCount(category int) (int, error) {
 	return count, nil
 }
 
+// DeletePhoto removes the photo along with what referred to it, so a photo added later with the
+// same name starts fresh
 func (d *Database) DeletePhoto(name string, category int) error {
-	query := `DELETE FROM photos WHERE photo_name = ? AND category = ?`
-	result, err := d.db.Exec(query, name, category)
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRow(`DELETE FROM photos WHERE photo_name = ? AND category = ? RETURNING id`, name, category).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("photo not found: %s in category %d", name, category)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to delete photo: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	if _, err := tx.Exec(`DELETE FROM share_links WHERE photo_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete photo share links: %w", err)
+	}
+	// the pairing still remembers the other frame's photo so it isn't copied again
+	if _, err := tx.Exec(`UPDATE paired_photos SET photo_id = '' WHERE photo_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to update paired photos: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM photo_people WHERE photo_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete photo people: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM reactions WHERE photo_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete photo reactions: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (d *Database) UpdatePhotoOrder(name string, category int, order int) error {
+	query := `UPDATE photos SET "order" = ? WHERE photo_name = ? AND category = ?`
+	if _, err := d.db.Exec(query, order, name, category); err != nil {
+		return fmt.Errorf("failed to update photo order: %w", err)
+	}
+	return nil
+}
+
+// rowScanner is a *sql.Row or *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanPhoto reads a row selected with the id, photo_name, category, order, uploaded_by, caption,
+// album, taken_at, added_at, hidden, pending, pinned, tags, show_from, show_until, and motion
+// columns
+func scanPhoto(row rowScanner) (Photo, error) {
+	var p Photo
+	var takenAt, addedAt int64
+	var hiddenInt, pendingInt, pinnedInt int
+	var tagsJSON string
+	if err := row.Scan(&p.ID, &p.PhotoName, &p.Category, &p.Order, &p.UploadedBy, &p.Caption, &p.Album, &takenAt, &addedAt, &hiddenInt, &pendingInt, &pinnedInt, &tagsJSON, &p.ShowFrom, &p.ShowUntil, &p.Motion); err != nil {
+		return p, fmt.Errorf("failed to scan photo: %w", err)
+	}
+	if err := json.Unmarshal([]byte(tagsJSON), &p.Tags); err != nil {
+		return p, fmt.Errorf("failed to parse photo tags: %w", err)
+	}
+	p.Hidden = hiddenInt != 0
+	p.Pending = pendingInt != 0
+	p.Pinned = pinnedInt != 0
+	if takenAt > 0 {
+		p.TakenAt = time.Unix(takenAt, 0)
+	}
+	if addedAt > 0 {
+		p.AddedAt = time.Unix(addedAt, 0)
+	}
+	return p, nil
+}
+
+// GetAlbums returns the names of the albums that have photos in them
+func (d *Database) GetAlbums() ([]string, error) {
+	rows, err := d.db.Query(`SELECT DISTINCT album FROM photos WHERE album != '' ORDER BY album`)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return nil, fmt.Errorf("failed to query albums: %w", err)
 	}
+	defer rows.Close()
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("photo not found: %s in category %d", name, category)
+	var albums []string
+	for rows.Next() {
+		var album string
+		if err := rows.Scan(&album); err != nil {
+			return nil, fmt.Errorf("failed to scan album: %w", err)
+		}
+		albums = append(albums, album)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return albums, nil
+}
+
+// UpdatePhotoAlbum assigns a photo to an album, recording when it was taken if known
+func (d *Database) UpdatePhotoAlbum(name string, category int, album string, takenAt time.Time) error {
+	var takenAtUnix int64
+	if !takenAt.IsZero() {
+		takenAtUnix = takenAt.Unix()
+	}
+
+	// a date already recorded is kept when it isn't known here
+	query := `UPDATE photos SET album = ?, taken_at = COALESCE(NULLIF(?, 0), taken_at) WHERE photo_name = ? AND category = ?`
+	if _, err := d.db.Exec(query, album, takenAtUnix, name, category); err != nil {
+		return fmt.Errorf("failed to update photo album: %w", err)
+	}
+	return nil
+}
+
+// UpdatePhotoMotion records the extension of the video of a motion or live photo, or clears it
+// when ext is empty
+func (d *Database) UpdatePhotoMotion(name string, category int, ext string) error {
+	query := `UPDATE photos SET motion = ? WHERE photo_name = ? AND category = ?`
+	if _, err := d.db.Exec(query, ext, name, category); err != nil {
+		return fmt.Errorf("failed to update photo motion: %w", err)
+	}
+	return nil
+}
+
+// UpdatePhotoTakenAt records when a photo was taken without changing its album
+func (d *Database) UpdatePhotoTakenAt(name string, category int, takenAt time.Time) error {
+	var takenAtUnix int64
+	if !takenAt.IsZero() {
+		takenAtUnix = takenAt.Unix()
+	}
+
+	query := `UPDATE photos SET taken_at = ? WHERE photo_name = ? AND category = ?`
+	if _, err := d.db.Exec(query, takenAtUnix, name, category); err != nil {
+		return fmt.Errorf("failed to update photo taken at: %w", err)
+	}
+	return nil
+}
+
+func (d *Database) UpdatePhotoCaption(name string, category int, caption string) error {
+	query := `UPDATE photos SET caption = ? WHERE photo_name = ? AND category = ?`
+	if _, err := d.db.Exec(query, caption, name, category); err != nil {
+		return fmt.Errorf("failed to update photo caption: %w", err)
+	}
+	return nil
+}
+
+// UpdatePhotoTags replaces the tags of a photo
+func (d *Database) UpdatePhotoTags(name string, category int, tags []string) error {
+	if tags == nil {
+		tags = []string{}
+	}
+	tagsJSON, err := json.Marshal(tags)
+	if err != nil {
+		return fmt.Errorf("failed to encode photo tags: %w", err)
+	}
+
+	query := `UPDATE photos SET tags = ? WHERE photo_name = ? AND category = ?`
+	if _, err := d.db.Exec(query, string(tagsJSON), name, category); err != nil {
+		return fmt.Errorf("failed to update photo tags: %w", err)
+	}
+	return nil
+}
+
+// UpdatePhotoHidden hides a photo from the slideshow or shows it again, keeping it in the library
+func (d *Database) UpdatePhotoHidden(name string, category int, hidden bool) error {
+	query := `UPDATE photos SET hidden = ? WHERE photo_name = ? AND category = ?`
+	if _, err := d.db.Exec(query, boolToInt(hidden), name, category); err != nil {
+		return fmt.Errorf("failed to update photo hidden: %w", err)
+	}
+	return nil
+}
+
+// UpdatePhotoPinned pins a photo into every playlist or unpins it
+func (d *Database) UpdatePhotoPinned(name string, category int, pinned bool) error {
+	query := `UPDATE photos SET pinned = ? WHERE photo_name = ? AND category = ?`
+	if _, err := d.db.Exec(query, boolToInt(pinned), name, category); err != nil {
+		return fmt.Errorf("failed to update photo pinned: %w", err)
+	}
+	return nil
+}
+
+// UpdatePhotoVisibility sets the dates, formatted as 2006-01-02, a photo is shown from and until,
+// either left empty for no limit
+func (d *Database) UpdatePhotoVisibility(name string, category int, showFrom, showUntil string) error {
+	query := `UPDATE photos SET show_from = ?, show_until = ? WHERE photo_name = ? AND category = ?`
+	if _, err := d.db.Exec(query, showFrom, showUntil, name, category); err != nil {
+		return fmt.Errorf("failed to update photo visibility: %w", err)
+	}
+	return nil
+}
+
+// GetScheduledPhotos returns the photos of every category shown only from or until a date
+func (d *Database) GetScheduledPhotos() ([]Photo, error) {
+	query := `
+		SELECT id, photo_name, category, "order", uploaded_by, caption, album, taken_at, added_at, hidden, pending, pinned, tags, show_from, show_until, motion
+		FROM photos
+		WHERE show_from != '' OR show_until != ''
+		ORDER BY category ASC, "order" DESC
+	`
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scheduled photos: %w", err)
+	}
+	defer rows.Close()
+
+	var photos []Photo
+	for rows.Next() {
+		p, err := scanPhoto(rows)
+		if err != nil {
+			return nil, err
+		}
+		photos = append(photos, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return photos, nil
+}
+
+// GetPinnedPhotos returns the pinned photos of every category
+func (d *Database) GetPinnedPhotos() ([]Photo, error) {
+	query := `
+		SELECT id, photo_name, category, "order", uploaded_by, caption, album, taken_at, added_at, hidden, pending, pinned, tags, show_from, show_until, motion
+		FROM photos
+		WHERE pinned = 1
+		ORDER BY category ASC, "order" DESC
+	`
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pinned photos: %w", err)
+	}
+	defer rows.Close()
+
+	var photos []Photo
+	for rows.Next() {
+		p, err := scanPhoto(rows)
+		if err != nil {
+			return nil, err
+		}
+		photos = append(photos, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 
+	return photos, nil
+}
+
+// ApprovePhoto clears a photo's pending flag so it can be played, or hides it when rejected so it
+// isn't offered for approval again while it is still synced
+func (d *Database) ApprovePhoto(name string, category int, approved bool) error {
+	query := `UPDATE photos SET pending = 0, hidden = ? WHERE photo_name = ? AND category = ?`
+	if _, err := d.db.Exec(query, boolToInt(!approved), name, category); err != nil {
+		return fmt.Errorf("failed to update photo approval: %w", err)
+	}
 	return nil
 }
 
-func (d *Database) GetMaxOrder(category int) (int, error) {
-	query := `SELECT COALESCE(MAX("order"), -1) FROM photos WHERE category = ?`
-	var maxOrder int
-	err := d.db.QueryRow(query, category).Scan(&maxOrder)
+// GetPhotoByID returns the photo with the given id, or nil if there is none
+func (d *Database) GetPhotoByID(id string) (*Photo, error) {
+	query := `
+		SELECT id, photo_name, category, "order", uploaded_by, caption, album, taken_at, added_at, hidden, pending, pinned, tags, show_from, show_until, motion
+		FROM photos
+		WHERE id = ?
+	`
+	p, err := scanPhoto(d.db.QueryRow(query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetPhoto returns the photo with the given name in the category, or nil if there is none
+func (d *Database) GetPhoto(name string, category int) (*Photo, error) {
+	query := `
+		SELECT id, photo_name, category, "order", uploaded_by, caption, album, taken_at, added_at, hidden, pending, pinned, tags, show_from, show_until, motion
+		FROM photos
+		WHERE photo_name = ? AND category = ?
+	`
+	p, err := scanPhoto(d.db.QueryRow(query, name, category))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
 	if err != nil {
-		return 0, fmt.Errorf("failed to get max order: %w", err)
+		return nil, err
 	}
-	return maxOrder + 1, nil
+	return &p, nil
 }
 
 func (d *Database) PhotoExists(name string, category int) (bool, error) {
@@ -189,21 +871,87 @@ func (d *Database) GetAppSettings() (*AppSettings, error) {
 	const query = `
 		SELECT slideshow_interval_seconds,
 		       include_surprise,
-		       shuffle_enabled
+		       shuffle_enabled,
+		       show_uploader,
+		       language,
+		       theme,
+		       accent_color,
+		       show_filename,
+		       show_caption,
+		       show_date_taken,
+		       overlay_position,
+		       overlay_size,
+		       photo_of_day_enabled,
+		       photo_of_day_time,
+		       photo_of_day_id,
+		       playlist_order,
+		       album_weights,
+		       auto_organize,
+		       display_transform,
+		       display_mode,
+		       display_scale,
+		       strip_exif,
+		       watermark_text,
+		       watermark_uploader,
+		       approve_surprise,
+		       interval_jitter_seconds,
+		       crossfade,
+		       collages,
+		       show_reactions,
+		       guestbook_days,
+		       play_motion,
+		       pan_panoramas,
+		       audio_enabled,
+		       audio_source,
+		       audio_volume
 		FROM app_settings
 		WHERE singleton = 1
 	`
 
 	var interval int
-	var includeSurpriseInt, shuffleEnabledInt int
+	var includeSurpriseInt, shuffleEnabledInt, showUploaderInt int
+	var language, theme, accentColor string
+	var showFilenameInt, showCaptionInt, showDateTakenInt, stripExifInt, watermarkUploaderInt, approveSurpriseInt, crossfadeInt, collagesInt, showReactionsInt, playMotionInt, panPanoramasInt, audioEnabledInt int
+	var overlayPosition, overlaySize, playlistOrder, albumWeightsJSON, autoOrganize, displayTransform string
+	var photoOfDayEnabledInt, intervalJitterSeconds, guestbookDays, audioVolume int
+	var photoOfDayTime, photoOfDayID, displayMode, watermarkText, audioSource string
+	var displayScale float64
 
-	err := d.db.QueryRow(query).Scan(&interval, &includeSurpriseInt, &shuffleEnabledInt)
+	err := d.db.QueryRow(query).Scan(
+		&interval, &includeSurpriseInt, &shuffleEnabledInt, &showUploaderInt, &language, &theme, &accentColor,
+		&showFilenameInt, &showCaptionInt, &showDateTakenInt, &overlayPosition, &overlaySize,
+		&photoOfDayEnabledInt, &photoOfDayTime, &photoOfDayID,
+		&playlistOrder, &albumWeightsJSON, &autoOrganize, &displayTransform, &displayMode, &displayScale, &stripExifInt,
+		&watermarkText, &watermarkUploaderInt, &approveSurpriseInt,
+		&intervalJitterSeconds,
+		&crossfadeInt,
+		&collagesInt,
+		&showReactionsInt,
+		&guestbookDays,
+		&playMotionInt,
+		&panPanoramasInt,
+		&audioEnabledInt,
+		&audioSource,
+		&audioVolume,
+	)
 	if err == sql.ErrNoRows {
 		// Bootstrap defaults if no settings row exists yet
 		defaults := &AppSettings{
 			SlideshowIntervalSeconds: 15,
 			IncludeSurprise:          true,
 			ShuffleEnabled:           false,
+			Language:                 "en",
+			Theme:                    "light",
+			AccentColor:              "#007AFF",
+			OverlayPosition:          "bottom-right",
+			OverlaySize:              "medium",
+			PhotoOfDayTime:           "06:00",
+			PlaylistOrder:            "sequential",
+			AutoOrganize:             "off",
+			DisplayTransform:         "normal",
+			DisplayScale:             1,
+			GuestbookDays:            7,
+			AudioVolume:              50,
 		}
 		if err := d.UpsertAppSettings(defaults); err != nil {
 			return nil, err
@@ -214,33 +962,174 @@ func (d *Database) GetAppSettings() (*AppSettings, error) {
 		return nil, fmt.Errorf("get app settings: %w", err)
 	}
 
+	var albumWeights map[string]int
+	if err := json.Unmarshal([]byte(albumWeightsJSON), &albumWeights); err != nil {
+		return nil, fmt.Errorf("parse album weights: %w", err)
+	}
+
 	settings := &AppSettings{
 		SlideshowIntervalSeconds: interval,
 		IncludeSurprise:          includeSurpriseInt != 0,
 		ShuffleEnabled:           shuffleEnabledInt != 0,
+		ShowUploader:             showUploaderInt != 0,
+		Language:                 language,
+		Theme:                    theme,
+		AccentColor:              accentColor,
+		ShowFilename:             showFilenameInt != 0,
+		ShowCaption:              showCaptionInt != 0,
+		ShowDateTaken:            showDateTakenInt != 0,
+		OverlayPosition:          overlayPosition,
+		OverlaySize:              overlaySize,
+		PhotoOfDayEnabled:        photoOfDayEnabledInt != 0,
+		PhotoOfDayTime:           photoOfDayTime,
+		PhotoOfDayID:             photoOfDayID,
+		PlaylistOrder:            playlistOrder,
+		AlbumWeights:             albumWeights,
+		AutoOrganize:             autoOrganize,
+		DisplayTransform:         displayTransform,
+		DisplayMode:              displayMode,
+		DisplayScale:             displayScale,
+		StripExif:                stripExifInt != 0,
+		WatermarkText:            watermarkText,
+		WatermarkUploader:        watermarkUploaderInt != 0,
+		ApproveSurprise:          approveSurpriseInt != 0,
+		IntervalJitterSeconds:    intervalJitterSeconds,
+		Crossfade:                crossfadeInt != 0,
+		Collages:                 collagesInt != 0,
+		ShowReactions:            showReactionsInt != 0,
+		GuestbookDays:            guestbookDays,
+		PlayMotion:               playMotionInt != 0,
+		PanPanoramas:             panPanoramasInt != 0,
+		AudioEnabled:             audioEnabledInt != 0,
+		AudioSource:              audioSource,
+		AudioVolume:              audioVolume,
 	}
 	return settings, nil
 }
 
 func (d *Database) UpsertAppSettings(s *AppSettings) error {
-	const stmt = `
-		INSERT INTO app_settings (
-			singleton,
+	albumWeights, err := json.Marshal(s.AlbumWeights)
+	if err != nil {
+		return fmt.Errorf("encode album weights: %w", err)
+	}
+	if s.AlbumWeights == nil {
+		albumWeights = []byte("{}")
+	}
+
+	const stmt = `
+		INSERT INTO app_settings (
+			singleton,
 			slideshow_interval_seconds,
 			include_surprise,
-			shuffle_enabled
-		) VALUES (1, ?, ?, ?)
+			shuffle_enabled,
+			show_uploader,
+			language,
+			theme,
+			accent_color,
+			show_filename,
+			show_caption,
+			show_date_taken,
+			overlay_position,
+			overlay_size,
+			photo_of_day_enabled,
+			photo_of_day_time,
+			photo_of_day_id,
+			playlist_order,
+			album_weights,
+			auto_organize,
+			display_transform,
+			display_mode,
+			display_scale,
+			strip_exif,
+			watermark_text,
+			watermark_uploader,
+			approve_surprise,
+			interval_jitter_seconds,
+			crossfade,
+			collages,
+			show_reactions,
+			guestbook_days,
+			play_motion,
+			pan_panoramas,
+			audio_enabled,
+			audio_source,
+			audio_volume
+		) VALUES (1, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(singleton) DO UPDATE SET
 			slideshow_interval_seconds = excluded.slideshow_interval_seconds,
 			include_surprise           = excluded.include_surprise,
-			shuffle_enabled            = excluded.shuffle_enabled
+			shuffle_enabled            = excluded.shuffle_enabled,
+			show_uploader              = excluded.show_uploader,
+			language                   = excluded.language,
+			theme                      = excluded.theme,
+			accent_color               = excluded.accent_color,
+			show_filename              = excluded.show_filename,
+			show_caption               = excluded.show_caption,
+			show_date_taken            = excluded.show_date_taken,
+			overlay_position           = excluded.overlay_position,
+			overlay_size               = excluded.overlay_size,
+			photo_of_day_enabled       = excluded.photo_of_day_enabled,
+			photo_of_day_time          = excluded.photo_of_day_time,
+			photo_of_day_id            = excluded.photo_of_day_id,
+			playlist_order             = excluded.playlist_order,
+			album_weights              = excluded.album_weights,
+			auto_organize              = excluded.auto_organize,
+			display_transform          = excluded.display_transform,
+			display_mode               = excluded.display_mode,
+			display_scale              = excluded.display_scale,
+			strip_exif                 = excluded.strip_exif,
+			watermark_text             = excluded.watermark_text,
+			watermark_uploader         = excluded.watermark_uploader,
+			approve_surprise           = excluded.approve_surprise,
+			interval_jitter_seconds    = excluded.interval_jitter_seconds,
+			crossfade                  = excluded.crossfade,
+			collages                   = excluded.collages,
+			show_reactions             = excluded.show_reactions,
+			guestbook_days             = excluded.guestbook_days,
+			play_motion                = excluded.play_motion,
+			pan_panoramas              = excluded.pan_panoramas,
+			audio_enabled              = excluded.audio_enabled,
+			audio_source               = excluded.audio_source,
+			audio_volume               = excluded.audio_volume
 	`
 
-	_, err := d.db.Exec(
+	_, err = d.db.Exec(
 		stmt,
 		s.SlideshowIntervalSeconds,
 		boolToInt(s.IncludeSurprise),
 		boolToInt(s.ShuffleEnabled),
+		boolToInt(s.ShowUploader),
+		s.Language,
+		s.Theme,
+		s.AccentColor,
+		boolToInt(s.ShowFilename),
+		boolToInt(s.ShowCaption),
+		boolToInt(s.ShowDateTaken),
+		s.OverlayPosition,
+		s.OverlaySize,
+		boolToInt(s.PhotoOfDayEnabled),
+		s.PhotoOfDayTime,
+		s.PhotoOfDayID,
+		s.PlaylistOrder,
+		string(albumWeights),
+		s.AutoOrganize,
+		s.DisplayTransform,
+		s.DisplayMode,
+		s.DisplayScale,
+		boolToInt(s.StripExif),
+		s.WatermarkText,
+		boolToInt(s.WatermarkUploader),
+		boolToInt(s.ApproveSurprise),
+		s.IntervalJitterSeconds,
+		boolToInt(s.Crossfade),
+		boolToInt(s.Collages),
+		boolToInt(s.ShowReactions),
+		s.GuestbookDays,
+		boolToInt(s.PlayMotion),
+		boolToInt(s.PanPanoramas),
+		boolToInt(s.AudioEnabled),
+		s.AudioSource,
+		s.AudioVolume,
 	)
 	if err != nil {
 		return fmt.Errorf("upsert app settings: %w", err)
@@ -248,25 +1137,43 @@ func (d *Database) UpsertAppSettings(s *AppSettings) error {
 	return nil
 }
 
+// DefaultQuietHours turns the display off outside of the schedule
+var DefaultQuietHours = QuietHours{
+	Action:              "off",
+	DimPercent:          20,
+	SlowIntervalSeconds: 300,
+}
+
 func (d *Database) GetSchedule() (*Schedule, error) {
 	const query = `
 		SELECT enabled,
 		       start,
-		       end
+		       end,
+		       active_profile,
+		       today_profile,
+		       today_date,
+		       action,
+		       dim_percent,
+		       slow_interval_seconds
 		FROM schedule 
 		WHERE singleton = 1
 	`
 
 	var enabled bool
-	var start, end string
+	var start, end, activeProfile, todayProfile, todayDate string
+	var quiet QuietHours
 
-	err := d.db.QueryRow(query).Scan(&enabled, &start, &end)
+	err := d.db.QueryRow(query).Scan(
+		&enabled, &start, &end, &activeProfile, &todayProfile, &todayDate,
+		&quiet.Action, &quiet.DimPercent, &quiet.SlowIntervalSeconds,
+	)
 	if err == sql.ErrNoRows {
 		// Bootstrap defaults if no settings row exists yet
 		defaults := &Schedule{
-			Enabled: true,
-			Start:   "06:00",
-			End:     "23:00",
+			Enabled:    true,
+			Start:      "06:00",
+			End:        "23:00",
+			QuietHours: DefaultQuietHours,
 		}
 		if err := d.UpsertSchedule(defaults); err != nil {
 			return nil, err
@@ -278,9 +1185,13 @@ func (d *Database) GetSchedule() (*Schedule, error) {
 	}
 
 	schedule := &Schedule{
-		Enabled: enabled,
-		Start:   start,
-		End:     end,
+		Enabled:       enabled,
+		Start:         start,
+		End:           end,
+		ActiveProfile: activeProfile,
+		TodayProfile:  todayProfile,
+		TodayDate:     todayDate,
+		QuietHours:    quiet,
 	}
 	return schedule, nil
 }
@@ -291,12 +1202,24 @@ func (d *Database) UpsertSchedule(s *Schedule) error {
 			singleton,
 			enabled,
 			start,
-			end
-		) VALUES (1, ?, ?, ?)
+			end,
+			active_profile,
+			today_profile,
+			today_date,
+			action,
+			dim_percent,
+			slow_interval_seconds
+		) VALUES (1, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(singleton) DO UPDATE SET
-			enabled = excluded.enabled,
-			start   = excluded.start,
-			end     = excluded.end
+			enabled               = excluded.enabled,
+			start                 = excluded.start,
+			end                   = excluded.end,
+			active_profile        = excluded.active_profile,
+			today_profile         = excluded.today_profile,
+			today_date            = excluded.today_date,
+			action                = excluded.action,
+			dim_percent           = excluded.dim_percent,
+			slow_interval_seconds = excluded.slow_interval_seconds
 	`
 
 	_, err := d.db.Exec(
@@ -304,6 +1227,12 @@ func (d *Database) UpsertSchedule(s *Schedule) error {
 		boolToInt(s.Enabled),
 		s.Start,
 		s.End,
+		s.ActiveProfile,
+		s.TodayProfile,
+		s.TodayDate,
+		s.Action,
+		s.DimPercent,
+		s.SlowIntervalSeconds,
 	)
 	if err != nil {
 		return fmt.Errorf("upsert schedule: %w", err)
@@ -311,11 +1240,1272 @@ func (d *Database) UpsertSchedule(s *Schedule) error {
 	return nil
 }
 
-func boolToInt(b bool) int {
-	if b {
-		return 1
+// UpsertScheduleProfile creates the profile or replaces the one with the same name
+func (d *Database) UpsertScheduleProfile(p *ScheduleProfile) error {
+	const stmt = `
+		INSERT INTO schedule_profiles (name, start, end, days, action, dim_percent, slow_interval_seconds)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET
+			start                 = excluded.start,
+			end                   = excluded.end,
+			days                  = excluded.days,
+			action                = excluded.action,
+			dim_percent           = excluded.dim_percent,
+			slow_interval_seconds = excluded.slow_interval_seconds
+	`
+	_, err := d.db.Exec(
+		stmt,
+		p.Name, p.Start, p.End, strings.Join(p.Days, ","),
+		p.Action, p.DimPercent, p.SlowIntervalSeconds,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert schedule profile: %w", err)
 	}
-	return 0
+	return nil
+}
+
+func (d *Database) GetScheduleProfiles() ([]ScheduleProfile, error) {
+	const query = `
+		SELECT name, start, end, days, action, dim_percent, slow_interval_seconds
+		FROM schedule_profiles
+		ORDER BY name
+	`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schedule profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var profiles []ScheduleProfile
+	for rows.Next() {
+		var p ScheduleProfile
+		var days string
+		if err := rows.Scan(&p.Name, &p.Start, &p.End, &days, &p.Action, &p.DimPercent, &p.SlowIntervalSeconds); err != nil {
+			return nil, fmt.Errorf("failed to scan schedule profile: %w", err)
+		}
+		p.Days = []string{}
+		if days != "" {
+			p.Days = strings.Split(days, ",")
+		}
+		profiles = append(profiles, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// DeleteScheduleProfile removes the profile, returning false if it did not exist
+func (d *Database) DeleteScheduleProfile(name string) (bool, error) {
+	const stmt = `DELETE FROM schedule_profiles WHERE name = ?`
+	res, err := d.db.Exec(stmt, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete schedule profile: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check deleted schedule profile: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (d *Database) InsertShareLink(link *ShareLink) error {
+	const stmt = `INSERT INTO share_links (token, photo_id, expires_at) VALUES (?, ?, ?)`
+	_, err := d.db.Exec(stmt, link.Token, link.PhotoID, link.ExpiresAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to insert share link: %w", err)
+	}
+	return nil
+}
+
+// GetShareLink returns the share link for token, or nil if it does not exist, has expired, or
+// its photo was deleted
+func (d *Database) GetShareLink(token string) (*ShareLink, error) {
+	const query = `
+		SELECT l.token, l.photo_id, p.photo_name, p.category, l.expires_at
+		FROM share_links l
+		JOIN photos p ON p.id = l.photo_id
+		WHERE l.token = ? AND l.expires_at > ?
+	`
+
+	var link ShareLink
+	var expiresAt int64
+	err := d.db.QueryRow(query, token, time.Now().Unix()).Scan(&link.Token, &link.PhotoID, &link.PhotoName, &link.Category, &expiresAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get share link: %w", err)
+	}
+	link.ExpiresAt = time.Unix(expiresAt, 0)
+	return &link, nil
+}
+
+func (d *Database) DeleteExpiredShareLinks() error {
+	const stmt = `DELETE FROM share_links WHERE expires_at <= ?`
+	if _, err := d.db.Exec(stmt, time.Now().Unix()); err != nil {
+		return fmt.Errorf("failed to delete expired share links: %w", err)
+	}
+	return nil
+}
+
+func (d *Database) InsertUploadToken(t *UploadToken) error {
+	const stmt = `INSERT INTO upload_tokens (token, label, expires_at) VALUES (?, ?, ?)`
+	_, err := d.db.Exec(stmt, t.Token, t.Label, t.ExpiresAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to insert upload token: %w", err)
+	}
+	return nil
+}
+
+// GetUploadToken returns the upload token, or nil if it does not exist or has expired
+func (d *Database) GetUploadToken(token string) (*UploadToken, error) {
+	const query = `
+		SELECT token, label, expires_at
+		FROM upload_tokens
+		WHERE token = ? AND expires_at > ?
+	`
+
+	var t UploadToken
+	var expiresAt int64
+	err := d.db.QueryRow(query, token, time.Now().Unix()).Scan(&t.Token, &t.Label, &expiresAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get upload token: %w", err)
+	}
+	t.ExpiresAt = time.Unix(expiresAt, 0)
+	return &t, nil
+}
+
+func (d *Database) DeleteExpiredUploadTokens() error {
+	const stmt = `DELETE FROM upload_tokens WHERE expires_at <= ?`
+	if _, err := d.db.Exec(stmt, time.Now().Unix()); err != nil {
+		return fmt.Errorf("failed to delete expired upload tokens: %w", err)
+	}
+	return nil
+}
+
+func (d *Database) InsertSeasonalRule(r *SeasonalRule) error {
+	const stmt = `INSERT INTO seasonal_rules (album, label, start, end) VALUES (?, ?, ?, ?)`
+	res, err := d.db.Exec(stmt, r.Album, r.Label, r.Start, r.End)
+	if err != nil {
+		return fmt.Errorf("failed to insert seasonal rule: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get seasonal rule id: %w", err)
+	}
+	r.ID = id
+	return nil
+}
+
+func (d *Database) GetSeasonalRules() ([]SeasonalRule, error) {
+	const query = `
+		SELECT id, album, label, start, end
+		FROM seasonal_rules
+		ORDER BY album, start
+	`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query seasonal rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []SeasonalRule
+	for rows.Next() {
+		var r SeasonalRule
+		if err := rows.Scan(&r.ID, &r.Album, &r.Label, &r.Start, &r.End); err != nil {
+			return nil, fmt.Errorf("failed to scan seasonal rule: %w", err)
+		}
+		rules = append(rules, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return rules, nil
+}
+
+// DeleteSeasonalRule removes the rule, returning false if it did not exist
+func (d *Database) DeleteSeasonalRule(id int64) (bool, error) {
+	const stmt = `DELETE FROM seasonal_rules WHERE id = ?`
+	res, err := d.db.Exec(stmt, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete seasonal rule: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check deleted seasonal rule: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (d *Database) InsertAnnouncement(a *Announcement) error {
+	const stmt = `INSERT INTO announcements (text, start, end) VALUES (?, ?, ?)`
+	res, err := d.db.Exec(stmt, a.Text, a.Start, a.End)
+	if err != nil {
+		return fmt.Errorf("failed to insert announcement: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get announcement id: %w", err)
+	}
+	a.ID = id
+	return nil
+}
+
+func (d *Database) GetAnnouncements() ([]Announcement, error) {
+	const query = `
+		SELECT id, text, start, end
+		FROM announcements
+		ORDER BY start, id
+	`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query announcements: %w", err)
+	}
+	defer rows.Close()
+
+	var announcements []Announcement
+	for rows.Next() {
+		var a Announcement
+		if err := rows.Scan(&a.ID, &a.Text, &a.Start, &a.End); err != nil {
+			return nil, fmt.Errorf("failed to scan announcement: %w", err)
+		}
+		announcements = append(announcements, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return announcements, nil
+}
+
+// DeleteAnnouncement removes the announcement, returning false if it did not exist
+func (d *Database) DeleteAnnouncement(id int64) (bool, error) {
+	const stmt = `DELETE FROM announcements WHERE id = ?`
+	res, err := d.db.Exec(stmt, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete announcement: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check deleted announcement: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (d *Database) InsertSpecialDate(sd *SpecialDate) error {
+	const stmt = `INSERT INTO special_dates (name, date, type, boost) VALUES (?, ?, ?, ?)`
+	res, err := d.db.Exec(stmt, sd.Name, sd.Date, sd.Type, boolToInt(sd.Boost))
+	if err != nil {
+		return fmt.Errorf("failed to insert special date: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get special date id: %w", err)
+	}
+	sd.ID = id
+	return nil
+}
+
+func (d *Database) GetSpecialDates() ([]SpecialDate, error) {
+	const query = `
+		SELECT id, name, date, type, boost
+		FROM special_dates
+		ORDER BY date, id
+	`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query special dates: %w", err)
+	}
+	defer rows.Close()
+
+	var dates []SpecialDate
+	for rows.Next() {
+		var sd SpecialDate
+		var boostInt int
+		if err := rows.Scan(&sd.ID, &sd.Name, &sd.Date, &sd.Type, &boostInt); err != nil {
+			return nil, fmt.Errorf("failed to scan special date: %w", err)
+		}
+		sd.Boost = boostInt != 0
+		dates = append(dates, sd)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return dates, nil
+}
+
+// UpdateSpecialDate replaces the special date with the same id, returning false if it did not exist
+func (d *Database) UpdateSpecialDate(sd *SpecialDate) (bool, error) {
+	const stmt = `UPDATE special_dates SET name = ?, date = ?, type = ?, boost = ? WHERE id = ?`
+	res, err := d.db.Exec(stmt, sd.Name, sd.Date, sd.Type, boolToInt(sd.Boost), sd.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to update special date: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check updated special date: %w", err)
+	}
+	return n > 0, nil
+}
+
+// DeleteSpecialDate removes the special date, returning false if it did not exist
+func (d *Database) DeleteSpecialDate(id int64) (bool, error) {
+	const stmt = `DELETE FROM special_dates WHERE id = ?`
+	res, err := d.db.Exec(stmt, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete special date: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check deleted special date: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (d *Database) InsertAlbumSchedule(as *AlbumSchedule) error {
+	const stmt = `INSERT INTO album_schedules (album, start, end, exclusive) VALUES (?, ?, ?, ?)`
+	res, err := d.db.Exec(stmt, as.Album, as.Start, as.End, boolToInt(as.Exclusive))
+	if err != nil {
+		return fmt.Errorf("failed to insert album schedule: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get album schedule id: %w", err)
+	}
+	as.ID = id
+	return nil
+}
+
+func (d *Database) GetAlbumSchedules() ([]AlbumSchedule, error) {
+	const query = `
+		SELECT id, album, start, end, exclusive
+		FROM album_schedules
+		ORDER BY start, album, id
+	`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query album schedules: %w", err)
+	}
+	defer rows.Close()
+
+	var schedules []AlbumSchedule
+	for rows.Next() {
+		var as AlbumSchedule
+		var exclusiveInt int
+		if err := rows.Scan(&as.ID, &as.Album, &as.Start, &as.End, &exclusiveInt); err != nil {
+			return nil, fmt.Errorf("failed to scan album schedule: %w", err)
+		}
+		as.Exclusive = exclusiveInt != 0
+		schedules = append(schedules, as)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// UpdateAlbumSchedule replaces the album schedule with the same id, returning false if it did not
+// exist
+func (d *Database) UpdateAlbumSchedule(as *AlbumSchedule) (bool, error) {
+	const stmt = `UPDATE album_schedules SET album = ?, start = ?, end = ?, exclusive = ? WHERE id = ?`
+	res, err := d.db.Exec(stmt, as.Album, as.Start, as.End, boolToInt(as.Exclusive), as.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to update album schedule: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check updated album schedule: %w", err)
+	}
+	return n > 0, nil
+}
+
+// DeleteAlbumSchedule removes the album schedule, returning false if it did not exist
+func (d *Database) DeleteAlbumSchedule(id int64) (bool, error) {
+	const stmt = `DELETE FROM album_schedules WHERE id = ?`
+	res, err := d.db.Exec(stmt, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete album schedule: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check deleted album schedule: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (d *Database) InsertRetentionRule(r *RetentionRule) error {
+	const stmt = `INSERT INTO retention_rules (album, days, action, created_at) VALUES (?, ?, ?, ?)`
+	res, err := d.db.Exec(stmt, r.Album, r.Days, r.Action, r.CreatedAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to insert retention rule: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get retention rule id: %w", err)
+	}
+	r.ID = id
+	return nil
+}
+
+func (d *Database) GetRetentionRules() ([]RetentionRule, error) {
+	const query = `
+		SELECT id, album, days, action, created_at
+		FROM retention_rules
+		ORDER BY album, days, id
+	`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query retention rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []RetentionRule
+	for rows.Next() {
+		var r RetentionRule
+		var createdAt int64
+		if err := rows.Scan(&r.ID, &r.Album, &r.Days, &r.Action, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan retention rule: %w", err)
+		}
+		r.CreatedAt = time.Unix(createdAt, 0)
+		rules = append(rules, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return rules, nil
+}
+
+// DeleteRetentionRule removes the rule, returning false if it did not exist
+func (d *Database) DeleteRetentionRule(id int64) (bool, error) {
+	const stmt = `DELETE FROM retention_rules WHERE id = ?`
+	res, err := d.db.Exec(stmt, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete retention rule: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check deleted retention rule: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (d *Database) InsertSharedAlbum(sa *SharedAlbum) error {
+	const stmt = `INSERT INTO shared_albums (album, token, created_at) VALUES (?, ?, ?)`
+	res, err := d.db.Exec(stmt, sa.Album, sa.Token, sa.CreatedAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to insert shared album: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get shared album id: %w", err)
+	}
+	sa.ID = id
+	return nil
+}
+
+func (d *Database) GetSharedAlbums() ([]SharedAlbum, error) {
+	const query = `
+		SELECT id, album, token, created_at
+		FROM shared_albums
+		ORDER BY album, id
+	`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query shared albums: %w", err)
+	}
+	defer rows.Close()
+
+	var albums []SharedAlbum
+	for rows.Next() {
+		var sa SharedAlbum
+		var createdAt int64
+		if err := rows.Scan(&sa.ID, &sa.Album, &sa.Token, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan shared album: %w", err)
+		}
+		sa.CreatedAt = time.Unix(createdAt, 0)
+		albums = append(albums, sa)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return albums, nil
+}
+
+// GetSharedAlbum returns the album shared with token, or nil if it isn't shared
+func (d *Database) GetSharedAlbum(token string) (*SharedAlbum, error) {
+	const query = `
+		SELECT id, album, token, created_at
+		FROM shared_albums
+		WHERE token = ?
+	`
+
+	var sa SharedAlbum
+	var createdAt int64
+	err := d.db.QueryRow(query, token).Scan(&sa.ID, &sa.Album, &sa.Token, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get shared album: %w", err)
+	}
+	sa.CreatedAt = time.Unix(createdAt, 0)
+	return &sa, nil
+}
+
+// DeleteSharedAlbum stops sharing the album, returning false if it was not shared
+func (d *Database) DeleteSharedAlbum(id int64) (bool, error) {
+	const stmt = `DELETE FROM shared_albums WHERE id = ?`
+	res, err := d.db.Exec(stmt, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete shared album: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check deleted shared album: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (d *Database) InsertAlbumPairing(p *AlbumPairing) error {
+	const stmt = `INSERT INTO album_pairings (url, token, album) VALUES (?, ?, ?)`
+	res, err := d.db.Exec(stmt, p.URL, p.Token, p.Album)
+	if err != nil {
+		return fmt.Errorf("failed to insert album pairing: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get album pairing id: %w", err)
+	}
+	p.ID = id
+	return nil
+}
+
+func (d *Database) GetAlbumPairings() ([]AlbumPairing, error) {
+	const query = `
+		SELECT id, url, token, album, last_synced_at
+		FROM album_pairings
+		ORDER BY album, id
+	`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query album pairings: %w", err)
+	}
+	defer rows.Close()
+
+	var pairings []AlbumPairing
+	for rows.Next() {
+		var p AlbumPairing
+		var lastSyncedAt int64
+		if err := rows.Scan(&p.ID, &p.URL, &p.Token, &p.Album, &lastSyncedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan album pairing: %w", err)
+		}
+		if lastSyncedAt > 0 {
+			p.LastSyncedAt = time.Unix(lastSyncedAt, 0)
+		}
+		pairings = append(pairings, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return pairings, nil
+}
+
+// UpdateAlbumPairingSynced records when the pairing last copied the other frame's album
+func (d *Database) UpdateAlbumPairingSynced(id int64, syncedAt time.Time) error {
+	const stmt = `UPDATE album_pairings SET last_synced_at = ? WHERE id = ?`
+	if _, err := d.db.Exec(stmt, syncedAt.Unix(), id); err != nil {
+		return fmt.Errorf("failed to update album pairing: %w", err)
+	}
+	return nil
+}
+
+// DeleteAlbumPairing removes the pairing, returning false if it did not exist. The photos it copied
+// are kept, along with the record of them so they still aren't shared back.
+func (d *Database) DeleteAlbumPairing(id int64) (bool, error) {
+	const stmt = `DELETE FROM album_pairings WHERE id = ?`
+	res, err := d.db.Exec(stmt, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete album pairing: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check deleted album pairing: %w", err)
+	}
+	return n > 0, nil
+}
+
+// InsertPairedPhoto records that the pairing copied the other frame's photo with the remote id,
+// as the photo id on this frame or empty when this frame already had it
+func (d *Database) InsertPairedPhoto(pairingID int64, remoteID, photoID string) error {
+	const stmt = `INSERT OR IGNORE INTO paired_photos (pairing_id, remote_id, photo_id) VALUES (?, ?, ?)`
+	if _, err := d.db.Exec(stmt, pairingID, remoteID, photoID); err != nil {
+		return fmt.Errorf("failed to insert paired photo: %w", err)
+	}
+	return nil
+}
+
+// GetPairedPhotoIDs returns the ids of the other frame's photos the pairing already copied
+func (d *Database) GetPairedPhotoIDs(pairingID int64) ([]string, error) {
+	const query = `SELECT remote_id FROM paired_photos WHERE pairing_id = ?`
+	return d.queryPairedPhotos(query, pairingID)
+}
+
+// GetCopiedPhotoIDs returns the ids of the photos in My Photos copied from other frames
+func (d *Database) GetCopiedPhotoIDs() ([]string, error) {
+	const query = `SELECT photo_id FROM paired_photos WHERE photo_id != ''`
+	return d.queryPairedPhotos(query)
+}
+
+// IsCopiedPhoto reports whether the photo with the id was copied from another frame
+func (d *Database) IsCopiedPhoto(id string) (bool, error) {
+	var n int
+	err := d.db.QueryRow(`SELECT COUNT(*) FROM paired_photos WHERE photo_id = ?`, id).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("failed to check copied photo: %w", err)
+	}
+	return n > 0, nil
+}
+
+// queryPairedPhotos returns the single column of paired photos the query selects
+func (d *Database) queryPairedPhotos(query string, args ...any) ([]string, error) {
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query paired photos: %w", err)
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, fmt.Errorf("failed to scan paired photo: %w", err)
+		}
+		values = append(values, value)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return values, nil
+}
+
+// AddDisplayOnTime adds to how long the display was on during the day, formatted as 2006-01-02
+func (d *Database) AddDisplayOnTime(day string, seconds int64) error {
+	const stmt = `
+		INSERT INTO display_usage (day, on_seconds) VALUES (?, ?)
+		ON CONFLICT(day) DO UPDATE SET on_seconds = on_seconds + excluded.on_seconds
+	`
+	if _, err := d.db.Exec(stmt, day, seconds); err != nil {
+		return fmt.Errorf("failed to add display on time: %w", err)
+	}
+	return nil
+}
+
+// GetDisplayUsage returns how long the display was on each day starting with since, formatted
+// as 2006-01-02, oldest first
+func (d *Database) GetDisplayUsage(since string) ([]DisplayUsage, error) {
+	const query = `
+		SELECT day, on_seconds
+		FROM display_usage
+		WHERE day >= ?
+		ORDER BY day
+	`
+
+	rows, err := d.db.Query(query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query display usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []DisplayUsage
+	for rows.Next() {
+		var u DisplayUsage
+		if err := rows.Scan(&u.Day, &u.OnSeconds); err != nil {
+			return nil, fmt.Errorf("failed to scan display usage: %w", err)
+		}
+		usage = append(usage, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return usage, nil
+}
+
+// GetTotalDisplayOnTime returns how long the display has been on since usage was first tracked
+func (d *Database) GetTotalDisplayOnTime() (int64, error) {
+	const query = `SELECT COALESCE(SUM(on_seconds), 0) FROM display_usage`
+	var total int64
+	if err := d.db.QueryRow(query).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to get total display on time: %w", err)
+	}
+	return total, nil
+}
+
+// InsertSettingsVersion records a version of the settings or schedule, dropping the oldest
+// versions of its kind beyond the newest keep
+func (d *Database) InsertSettingsVersion(v *SettingsVersion, keep int) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	const insertStmt = `INSERT INTO settings_history (kind, changed_at, changed_by, data) VALUES (?, ?, ?, ?)`
+	res, err := tx.Exec(insertStmt, v.Kind, v.ChangedAt.Unix(), v.ChangedBy, string(v.Data))
+	if err != nil {
+		return fmt.Errorf("failed to insert settings version: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get settings version id: %w", err)
+	}
+
+	const pruneStmt = `
+		DELETE FROM settings_history
+		WHERE kind = ? AND id NOT IN (
+			SELECT id FROM settings_history WHERE kind = ? ORDER BY id DESC LIMIT ?
+		)
+	`
+	if _, err := tx.Exec(pruneStmt, v.Kind, v.Kind, keep); err != nil {
+		return fmt.Errorf("failed to prune settings history: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit settings version: %w", err)
+	}
+	v.ID = id
+	return nil
+}
+
+// GetSettingsHistory returns up to limit versions of the kind, newest first, or of every kind when
+// kind is empty
+func (d *Database) GetSettingsHistory(kind string, limit int) ([]SettingsVersion, error) {
+	const query = `
+		SELECT id, kind, changed_at, changed_by, data
+		FROM settings_history
+		WHERE ? = '' OR kind = ?
+		ORDER BY id DESC
+		LIMIT ?
+	`
+
+	rows, err := d.db.Query(query, kind, kind, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query settings history: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []SettingsVersion
+	for rows.Next() {
+		var v SettingsVersion
+		var changedAt int64
+		var data string
+		if err := rows.Scan(&v.ID, &v.Kind, &changedAt, &v.ChangedBy, &data); err != nil {
+			return nil, fmt.Errorf("failed to scan settings version: %w", err)
+		}
+		v.ChangedAt = time.Unix(changedAt, 0)
+		v.Data = json.RawMessage(data)
+		versions = append(versions, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return versions, nil
+}
+
+// GetSettingsVersion returns the version with the id, or nil if it does not exist
+func (d *Database) GetSettingsVersion(id int64) (*SettingsVersion, error) {
+	const query = `
+		SELECT id, kind, changed_at, changed_by, data
+		FROM settings_history
+		WHERE id = ?
+	`
+
+	var v SettingsVersion
+	var changedAt int64
+	var data string
+	err := d.db.QueryRow(query, id).Scan(&v.ID, &v.Kind, &changedAt, &v.ChangedBy, &data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get settings version: %w", err)
+	}
+	v.ChangedAt = time.Unix(changedAt, 0)
+	v.Data = json.RawMessage(data)
+	return &v, nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Checkpoint flushes any pending writes in the sqlite write ahead log into the database file
+func (d *Database) Checkpoint() error {
+	if _, err := d.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return fmt.Errorf("failed to checkpoint database: %w", err)
+	}
+	return nil
+}
+
+// InsertEvent records something that changed on the frame, dropping the oldest events beyond the
+// newest keep
+func (d *Database) InsertEvent(e *Event, keep int) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	const insertStmt = `INSERT INTO events (kind, occurred_at, actor, count, detail) VALUES (?, ?, ?, ?, ?)`
+	res, err := tx.Exec(insertStmt, e.Kind, e.OccurredAt.Unix(), e.Actor, e.Count, e.Detail)
+	if err != nil {
+		return fmt.Errorf("failed to insert event: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get event id: %w", err)
+	}
+
+	const pruneStmt = `DELETE FROM events WHERE id NOT IN (SELECT id FROM events ORDER BY id DESC LIMIT ?)`
+	if _, err := tx.Exec(pruneStmt, keep); err != nil {
+		return fmt.Errorf("failed to prune events: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit event: %w", err)
+	}
+	e.ID = id
+	return nil
+}
+
+// GetEvents returns a page of the events, newest first
+func (d *Database) GetEvents(limit int, offset int) ([]Event, error) {
+	const query = `
+		SELECT id, kind, occurred_at, actor, count, detail
+		FROM events
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := d.db.Query(query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		var e Event
+		var occurredAt int64
+		if err := rows.Scan(&e.ID, &e.Kind, &occurredAt, &e.Actor, &e.Count, &e.Detail); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		e.OccurredAt = time.Unix(occurredAt, 0)
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return events, nil
+}
+
+func (d *Database) GetEventCount() (int, error) {
+	var count int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM events`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count events: %w", err)
+	}
+	return count, nil
+}
+
+func (d *Database) InsertGuestbookEntry(e *GuestbookEntry) error {
+	const stmt = `INSERT INTO guestbook (name, message, selfie, created_at) VALUES (?, ?, ?, ?)`
+	res, err := d.db.Exec(stmt, e.Name, e.Message, boolToInt(e.Selfie), e.CreatedAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to insert guestbook entry: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get guestbook entry id: %w", err)
+	}
+	e.ID = id
+	return nil
+}
+
+// GetGuestbookEntries returns the messages left in the guestbook, newest first
+func (d *Database) GetGuestbookEntries() ([]GuestbookEntry, error) {
+	const query = `
+		SELECT id, name, message, selfie, created_at
+		FROM guestbook
+		ORDER BY created_at DESC, id DESC
+	`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query guestbook: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []GuestbookEntry
+	for rows.Next() {
+		var e GuestbookEntry
+		var selfieInt int
+		var createdAt int64
+		if err := rows.Scan(&e.ID, &e.Name, &e.Message, &selfieInt, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan guestbook entry: %w", err)
+		}
+		e.Selfie = selfieInt != 0
+		e.CreatedAt = time.Unix(createdAt, 0)
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return entries, nil
+}
+
+// DeleteGuestbookEntry removes a guestbook message, returning false if it did not exist
+func (d *Database) DeleteGuestbookEntry(id int64) (bool, error) {
+	res, err := d.db.Exec(`DELETE FROM guestbook WHERE id = ?`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete guestbook entry: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check deleted guestbook entry: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (d *Database) InsertReaction(r *Reaction) error {
+	const stmt = `
+		INSERT INTO reactions (photo_id, name, heart, comment, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	res, err := d.db.Exec(stmt, r.PhotoID, r.Name, boolToInt(r.Heart), r.Comment, r.CreatedAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to insert reaction: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get reaction id: %w", err)
+	}
+	r.ID = id
+	return nil
+}
+
+// GetReactions returns the reactions left on a photo, newest first
+func (d *Database) GetReactions(photoID string) ([]Reaction, error) {
+	const query = `
+		SELECT r.id, r.photo_id, p.photo_name, p.category, r.name, r.heart, r.comment, r.created_at
+		FROM reactions r
+		JOIN photos p ON p.id = r.photo_id
+		WHERE r.photo_id = ?
+		ORDER BY r.created_at DESC, r.id DESC
+	`
+
+	rows, err := d.db.Query(query, photoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reactions: %w", err)
+	}
+	defer rows.Close()
+
+	var reactions []Reaction
+	for rows.Next() {
+		var r Reaction
+		var heartInt int
+		var createdAt int64
+		if err := rows.Scan(&r.ID, &r.PhotoID, &r.PhotoName, &r.Category, &r.Name, &heartInt, &r.Comment, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan reaction: %w", err)
+		}
+		r.Heart = heartInt != 0
+		r.CreatedAt = time.Unix(createdAt, 0)
+		reactions = append(reactions, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return reactions, nil
+}
+
+// DeleteReaction removes a reaction, returning the id of the photo it was left on or empty if it
+// did not exist
+func (d *Database) DeleteReaction(id int64) (string, error) {
+	var photoID string
+	err := d.db.QueryRow(`DELETE FROM reactions WHERE id = ? RETURNING photo_id`, id).Scan(&photoID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to delete reaction: %w", err)
+	}
+	return photoID, nil
+}
+
+// GetReactionCounts returns how many hearts and comments each photo with reactions has
+func (d *Database) GetReactionCounts() ([]ReactionCount, error) {
+	const query = `
+		SELECT photo_id, SUM(heart), SUM(comment != '')
+		FROM reactions
+		GROUP BY photo_id
+	`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reaction counts: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []ReactionCount
+	for rows.Next() {
+		var rc ReactionCount
+		if err := rows.Scan(&rc.PhotoID, &rc.Hearts, &rc.Comments); err != nil {
+			return nil, fmt.Errorf("failed to scan reaction count: %w", err)
+		}
+		counts = append(counts, rc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (d *Database) InsertPerson(p *Person) error {
+	const stmt = `INSERT INTO people (name, exclusive) VALUES (?, ?)`
+	res, err := d.db.Exec(stmt, p.Name, boolToInt(p.Exclusive))
+	if err != nil {
+		return fmt.Errorf("failed to insert person: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get person id: %w", err)
+	}
+	p.ID = id
+	return nil
+}
+
+func (d *Database) GetPeople() ([]Person, error) {
+	const query = `
+		SELECT id, name, exclusive
+		FROM people
+		ORDER BY name, id
+	`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query people: %w", err)
+	}
+	defer rows.Close()
+
+	var people []Person
+	for rows.Next() {
+		var p Person
+		var exclusiveInt int
+		if err := rows.Scan(&p.ID, &p.Name, &exclusiveInt); err != nil {
+			return nil, fmt.Errorf("failed to scan person: %w", err)
+		}
+		p.Exclusive = exclusiveInt != 0
+		people = append(people, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return people, nil
+}
+
+// UpdatePerson replaces the person with the same id, returning false if they did not exist
+func (d *Database) UpdatePerson(p *Person) (bool, error) {
+	const stmt = `UPDATE people SET name = ?, exclusive = ? WHERE id = ?`
+	res, err := d.db.Exec(stmt, p.Name, boolToInt(p.Exclusive), p.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to update person: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check updated person: %w", err)
+	}
+	return n > 0, nil
+}
+
+// DeletePerson removes the person and the photos they were tagged in, returning false if they did
+// not exist
+func (d *Database) DeletePerson(id int64) (bool, error) {
+	res, err := d.db.Exec(`DELETE FROM people WHERE id = ?`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete person: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check deleted person: %w", err)
+	}
+	if _, err := d.db.Exec(`DELETE FROM photo_people WHERE person_id = ?`, id); err != nil {
+		return false, fmt.Errorf("failed to delete person's photos: %w", err)
+	}
+	return n > 0, nil
+}
+
+// SetPhotoPeople replaces the people tagged in a photo
+func (d *Database) SetPhotoPeople(photoID string, personIDs []int64) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM photo_people WHERE photo_id = ?`, photoID); err != nil {
+		return fmt.Errorf("failed to clear photo people: %w", err)
+	}
+	for _, id := range personIDs {
+		const stmt = `INSERT OR IGNORE INTO photo_people (person_id, photo_id) VALUES (?, ?)`
+		if _, err := tx.Exec(stmt, id, photoID); err != nil {
+			return fmt.Errorf("failed to tag person in photo: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetPhotoPeople returns every person tagged in a photo
+func (d *Database) GetPhotoPeople() ([]PhotoPerson, error) {
+	const query = `
+		SELECT person_id, photo_id
+		FROM photo_people
+		ORDER BY person_id, photo_id
+	`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query photo people: %w", err)
+	}
+	defer rows.Close()
+
+	var tagged []PhotoPerson
+	for rows.Next() {
+		var pp PhotoPerson
+		if err := rows.Scan(&pp.PersonID, &pp.PhotoID); err != nil {
+			return nil, fmt.Errorf("failed to scan photo person: %w", err)
+		}
+		tagged = append(tagged, pp)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return tagged, nil
+}
+
+// GetSlideshowState returns what the slideshow was last playing, or nil if it hasn't played yet
+func (d *Database) GetSlideshowState() (*SlideshowState, error) {
+	const query = `
+		SELECT playlist_id, playlist, position, paused, started_at, saved_at
+		FROM slideshow_state
+		WHERE singleton = 1
+	`
+
+	var state SlideshowState
+	var playlist string
+	var startedAt, savedAt int64
+	err := d.db.QueryRow(query).Scan(&state.PlaylistID, &playlist, &state.Index, &state.Paused, &startedAt, &savedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get slideshow state: %w", err)
+	}
+	if err := json.Unmarshal([]byte(playlist), &state.Playlist); err != nil {
+		return nil, fmt.Errorf("parse slideshow playlist: %w", err)
+	}
+	state.StartedAt = time.Unix(startedAt, 0)
+	state.SavedAt = time.Unix(savedAt, 0)
+	return &state, nil
+}
+
+// SaveSlideshowState replaces the slideshow's state, such as when it's restarted with a new
+// playlist
+func (d *Database) SaveSlideshowState(state *SlideshowState) error {
+	playlist, err := json.Marshal(state.Playlist)
+	if err != nil {
+		return fmt.Errorf("marshal slideshow playlist: %w", err)
+	}
+
+	const stmt = `
+		INSERT INTO slideshow_state (singleton, playlist_id, playlist, position, paused, started_at, saved_at)
+		VALUES (1, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(singleton) DO UPDATE SET
+			playlist_id = excluded.playlist_id,
+			playlist    = excluded.playlist,
+			position    = excluded.position,
+			paused      = excluded.paused,
+			started_at  = excluded.started_at,
+			saved_at    = excluded.saved_at
+	`
+	if _, err := d.db.Exec(stmt, state.PlaylistID, string(playlist), state.Index, boolToInt(state.Paused), state.StartedAt.Unix(), state.SavedAt.Unix()); err != nil {
+		return fmt.Errorf("save slideshow state: %w", err)
+	}
+	return nil
+}
+
+// UpdateSlideshowPosition records the 1-based index in the playlist of the photo on screen,
+// leaving the playlist as it was saved
+func (d *Database) UpdateSlideshowPosition(index int, savedAt time.Time) error {
+	const stmt = `UPDATE slideshow_state SET position = ?, saved_at = ? WHERE singleton = 1`
+	if _, err := d.db.Exec(stmt, index, savedAt.Unix()); err != nil {
+		return fmt.Errorf("update slideshow position: %w", err)
+	}
+	return nil
+}
+
+// UpdateSlideshowPaused records whether the slideshow is paused
+func (d *Database) UpdateSlideshowPaused(paused bool) error {
+	const stmt = `UPDATE slideshow_state SET paused = ? WHERE singleton = 1`
+	if _, err := d.db.Exec(stmt, boolToInt(paused)); err != nil {
+		return fmt.Errorf("update slideshow paused: %w", err)
+	}
+	return nil
 }
 
 func (d *Database) Close() error {
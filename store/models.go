@@ -1,19 +1,381 @@
 package store
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type Photo struct {
-	PhotoName string `json:"photo_name"`
-	Category  int    `json:"category"`
-	Order     int    `json:"order"`
+	// ID identifies the photo in the api and ui and is never reused, while the name is the file
+	// it's stored as
+	ID string `json:"id"`
+
+	PhotoName  string `json:"photo_name"`
+	Category   int    `json:"category"`
+	Order      int    `json:"order"`
+	UploadedBy string `json:"uploaded_by"`
+	Caption    string `json:"caption"`
+
+	// Tags are keywords such as the people in the photo, imported from sidecar files
+	Tags []string `json:"tags,omitempty"`
+
+	// Album groups photos, such as from a trip or an event, and is empty for photos outside one
+	Album   string    `json:"album"`
+	TakenAt time.Time `json:"taken_at,omitzero"`
+
+	// AddedAt is when the photo was registered, unknown for photos registered before it was
+	// recorded
+	AddedAt time.Time `json:"added_at,omitzero"`
+
+	// Hidden keeps the photo in the library but out of every playlist
+	Hidden bool `json:"hidden"`
+
+	// Pending photos were synced while approval was required and aren't played until approved
+	Pending bool `json:"pending"`
+
+	// Pinned photos are in every playlist, whatever the order, shuffle, and category settings
+	Pinned bool `json:"pinned"`
+
+	// ShowFrom and ShowUntil limit the days, inclusive and formatted as 2006-01-02, the photo is
+	// played, such as for a holiday or event. Either is empty for no limit.
+	ShowFrom  string `json:"show_from,omitempty"`
+	ShowUntil string `json:"show_until,omitempty"`
+
+	// Motion is the extension of the short video captured with a motion or live photo, such as
+	// .mp4 or .mov, and empty for a still photo
+	Motion string `json:"motion,omitempty"`
+
+	// ExpiresAt is when a retention rule removes or archives the photo, worked out when photos are
+	// listed rather than stored
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+
+	// Hearts and Comments count the reactions left on the photo, also worked out when photos are
+	// listed
+	Hearts   int `json:"hearts,omitempty"`
+	Comments int `json:"comments,omitempty"`
+}
+
+// PhotoFilter picks the photos of a category, narrowed down by when they were added, who
+// uploaded them, and how they're tagged when set
+type PhotoFilter struct {
+	Category int
+
+	// AddedAfter includes photos added at or after it, AddedBefore those added before it
+	AddedAfter  time.Time
+	AddedBefore time.Time
+
+	// UploadedBy matches the uploader's name ignoring case
+	UploadedBy string
+
+	// Tag matches photos with the tag, ignoring case
+	Tag string
 }
 
 type AppSettings struct {
-	SlideshowIntervalSeconds int  `json:"slideshow_interval_seconds"`
-	IncludeSurprise          bool `json:"include_surprise"`
-	ShuffleEnabled           bool `json:"shuffle_enabled"`
+	SlideshowIntervalSeconds int    `json:"slideshow_interval_seconds"`
+	IncludeSurprise          bool   `json:"include_surprise"`
+	ShuffleEnabled           bool   `json:"shuffle_enabled"`
+	PlaylistOrder            string `json:"playlist_order"`
+	ShowUploader             bool   `json:"show_uploader"`
+	Language                 string `json:"language"`
+	Theme                    string `json:"theme"`
+	AccentColor              string `json:"accent_color"`
+
+	// IntervalJitterSeconds varies each photo's time on screen randomly by up to this many seconds
+	// either side of the interval, or is 0 to advance at a steady pace
+	IntervalJitterSeconds int `json:"interval_jitter_seconds"`
+
+	// Crossfade blends each photo into the next instead of cutting between them
+	Crossfade bool `json:"crossfade"`
+
+	// Collages mixes slides of 2 or 4 photos from the same album or day into the playlist
+	Collages bool `json:"collages"`
+
+	// AlbumWeights makes each photo in an album appear this many times as often in shuffled
+	// playback. Albums without a weight and photos outside an album use 1, and a weight of 0 leaves
+	// the album out.
+	AlbumWeights map[string]int `json:"album_weights"`
+
+	// AutoOrganize groups new photos into albums by month or by trip, or is off
+	AutoOrganize string `json:"auto_organize"`
+
+	// DisplayTransform rotates the screen output for how the frame is mounted and is reapplied
+	// on startup
+	DisplayTransform string `json:"display_transform"`
+
+	// DisplayMode is the resolution and refresh rate the screen is driven at, formatted like
+	// 1920x1080@60.000, or empty to use whatever the panel picks. DisplayScale is the output
+	// scale. Both are reapplied on startup.
+	DisplayMode  string  `json:"display_mode"`
+	DisplayScale float64 `json:"display_scale"`
+
+	// StripExif erases EXIF metadata such as where a photo was taken from the slideshow copies
+	// and from photos served over share links, leaving the originals untouched
+	StripExif bool `json:"strip_exif"`
+
+	// WatermarkText is stamped faintly in a corner of every photo on screen, and WatermarkUploader
+	// credits the uploader alongside it, for frames displayed where others can see them
+	WatermarkText     string `json:"watermark_text"`
+	WatermarkUploader bool   `json:"watermark_uploader"`
+
+	// ApproveSurprise holds new surprise photos synced from remote storage, along with photos
+	// uploaded by guests, for approval before they are played
+	ApproveSurprise bool `json:"approve_surprise"`
+
+	// on screen overlay shown during the slideshow
+	ShowFilename    bool   `json:"show_filename"`
+	ShowCaption     bool   `json:"show_caption"`
+	ShowDateTaken   bool   `json:"show_date_taken"`
+	ShowReactions   bool   `json:"show_reactions"`
+	OverlayPosition string `json:"overlay_position"`
+	OverlaySize     string `json:"overlay_size"`
+
+	// show a single photo all day instead of the slideshow, changing at PhotoOfDayTime. The
+	// photo with PhotoOfDayID is pinned when set, otherwise photos are rotated daily.
+	PhotoOfDayEnabled bool   `json:"photo_of_day_enabled"`
+	PhotoOfDayTime    string `json:"photo_of_day_time"`
+	PhotoOfDayID      string `json:"photo_of_day_id"`
+
+	// GuestbookDays is how many days a guestbook message is shown in the slideshow after it's left
+	GuestbookDays int `json:"guestbook_days"`
+
+	// PlayMotion plays the video of motion and live photos before their still where video can be
+	// played, which is the browser slideshow since the frame's image viewer only shows the still
+	PlayMotion bool `json:"play_motion"`
+
+	// PanPanoramas slowly pans across very wide panoramas on the frame instead of shrinking them to
+	// fit the screen
+	PanPanoramas bool `json:"pan_panoramas"`
+
+	// AudioEnabled plays AudioSource, a local music file, directory, or playlist or an internet
+	// radio stream, during the schedule's hours at AudioVolume percent
+	AudioEnabled bool   `json:"audio_enabled"`
+	AudioSource  string `json:"audio_source"`
+	AudioVolume  int    `json:"audio_volume"`
 }
 
 type Schedule struct {
 	Enabled bool   `json:"enabled"`
 	Start   string `json:"start"`
 	End     string `json:"end"`
+
+	// ActiveProfile names the profile whose times are used instead of Start and End. When empty
+	// the profile for the day of the week is used if there is one.
+	ActiveProfile string `json:"active_profile"`
+
+	// TodayProfile overrides the other profiles for TodayDate only, formatted as 2006-01-02, for
+	// one off days like working from home
+	TodayProfile string `json:"today_profile"`
+	TodayDate    string `json:"today_date"`
+
+	QuietHours
+}
+
+// QuietHours is what happens to the frame between a schedule's end and its next start. Action
+// is off to turn off the display, dim to lower its brightness to DimPercent, or slow to keep
+// the slideshow going but change photos every SlowIntervalSeconds.
+type QuietHours struct {
+	Action              string `json:"action"`
+	DimPercent          int    `json:"dim_percent"`
+	SlowIntervalSeconds int    `json:"slow_interval_seconds"`
+}
+
+// ScheduleProfile is a named pair of times to turn the display on and off. Days are the
+// weekdays, named mon through sun, the profile is used on when no profile is picked.
+type ScheduleProfile struct {
+	Name  string   `json:"name"`
+	Start string   `json:"start"`
+	End   string   `json:"end"`
+	Days  []string `json:"days"`
+
+	QuietHours
+}
+
+// ShareLink is a public link to one photo. PhotoName and Category are those of the photo when
+// the link is read back.
+type ShareLink struct {
+	Token     string    `json:"token"`
+	PhotoID   string    `json:"photo_id"`
+	PhotoName string    `json:"photo_name"`
+	Category  int       `json:"category"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// UploadToken grants upload only access to guests until it expires
+type UploadToken struct {
+	Token     string    `json:"token"`
+	Label     string    `json:"label"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SeasonalRule limits an album to a range of dates each year. Start and End are MM-DD and the
+// range wraps around the new year when End is before Start.
+type SeasonalRule struct {
+	ID    int64  `json:"id"`
+	Album string `json:"album"`
+	Label string `json:"label"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Announcement is a slide of text shown between photos from Start to End, inclusive, both
+// formatted as 2006-01-02
+type Announcement struct {
+	ID    int64  `json:"id"`
+	Text  string `json:"text"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// SpecialDate is a birthday or anniversary celebrated each year on its MM-DD date with a greeting
+// slide
+type SpecialDate struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Date string `json:"date"`
+	Type string `json:"type"`
+
+	// Boost plays photos of the person twice as often on the day
+	Boost bool `json:"boost"`
+}
+
+// AlbumSchedule plays an album only from Start to End each year, both MM-DD, wrapping around the
+// new year when End is before Start
+type AlbumSchedule struct {
+	ID    int64  `json:"id"`
+	Album string `json:"album"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+
+	// Exclusive plays only the album, and others scheduled at the same time, while it's on
+	Exclusive bool `json:"exclusive"`
+}
+
+// GuestbookEntry is a message left by a visitor through a guest link, shown as a slide for a while
+// after it's left. Selfie is set when a photo of them was left with it.
+type GuestbookEntry struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Message   string    `json:"message"`
+	Selfie    bool      `json:"selfie"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Reaction is a heart or short comment left on a photo by someone viewing it in the ui, with the
+// name they left it under if they gave one
+type Reaction struct {
+	ID        int64     `json:"id"`
+	PhotoID   string    `json:"photo_id"`
+	PhotoName string    `json:"photo_name"`
+	Category  int       `json:"category"`
+	Name      string    `json:"name"`
+	Heart     bool      `json:"heart"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReactionCount is how many hearts and comments a photo has
+type ReactionCount struct {
+	PhotoID  string
+	Hearts   int
+	Comments int
+}
+
+// Person is someone who appears in photos, either tagged in them on the frame or named in the tags
+// they were imported with. Exclusive plays only photos of them, and of the others marked exclusive.
+type Person struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Exclusive bool   `json:"exclusive"`
+}
+
+// PhotoPerson is a person tagged in a photo on the frame
+type PhotoPerson struct {
+	PersonID int64
+	PhotoID  string
+}
+
+// RetentionRule removes or archives the photos in an album Days after they were added, or after
+// the rule was created for photos already in the album. Action is delete or archive.
+type RetentionRule struct {
+	ID        int64     `json:"id"`
+	Album     string    `json:"album"`
+	Days      int       `json:"days"`
+	Action    string    `json:"action"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SharedAlbum lets frames paired with this one using Token copy the photos in an album of My Photos
+type SharedAlbum struct {
+	ID        int64     `json:"id"`
+	Album     string    `json:"album"`
+	Token     string    `json:"token"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AlbumPairing copies the photos in an album another frame shares into Album in My Photos. URL is
+// the other frame's base url and Token the one it shares the album with.
+type AlbumPairing struct {
+	ID           int64     `json:"id"`
+	URL          string    `json:"url"`
+	Token        string    `json:"token"`
+	Album        string    `json:"album"`
+	LastSyncedAt time.Time `json:"last_synced_at,omitzero"`
+}
+
+// DisplayUsage is how long the display was on during a day, formatted as 2006-01-02
+type DisplayUsage struct {
+	Day       string `json:"day"`
+	OnSeconds int64  `json:"on_seconds"`
+}
+
+// kinds of settings kept in the settings history
+const (
+	HistorySettings = "settings"
+	HistorySchedule = "schedule"
+)
+
+// kinds of events in the activity feed
+const (
+	EventUpload     = "upload"
+	EventApproved   = "approved"
+	EventRejected   = "rejected"
+	EventSettings   = "settings"
+	EventSchedule   = "schedule"
+	EventSync       = "sync"
+	EventSyncFailed = "sync_failed"
+)
+
+// Event is something that changed on the frame. Actor is who or what made the change, such as an
+// uploader or the source photos were synced from, Count how many photos it involved, and Detail
+// the photo or error it was about.
+type Event struct {
+	ID         int64     `json:"id"`
+	Kind       string    `json:"kind"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Actor      string    `json:"actor"`
+	Count      int       `json:"count"`
+	Detail     string    `json:"detail"`
+}
+
+// SlideshowState is what the slideshow is playing, kept by the slideshow controller so it picks up
+// where it left off after a restart. PlaylistID changes whenever the photos in the playlist or
+// their order do, and Index is the 1-based position in Playlist of the photo shown at SavedAt.
+type SlideshowState struct {
+	PlaylistID string    `json:"playlist_id"`
+	Playlist   []string  `json:"playlist"`
+	Index      int       `json:"index"`
+	Paused     bool      `json:"paused"`
+	StartedAt  time.Time `json:"started_at"`
+	SavedAt    time.Time `json:"saved_at"`
+}
+
+// SettingsVersion is the app settings or schedule, as JSON in Data, as of a change made by
+// ChangedBy. A version with an empty ChangedBy is how things were before the history was kept.
+type SettingsVersion struct {
+	ID        int64           `json:"id"`
+	Kind      string          `json:"kind"`
+	ChangedAt time.Time       `json:"changed_at"`
+	ChangedBy string          `json:"changed_by"`
+	Data      json.RawMessage `json:"data"`
 }
@@ -0,0 +1,115 @@
+// Package thermal watches the CPU temperature and load so bulk image processing can back off
+// before a passively cooled pi throttles and the slideshow stutters
+package thermal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// WarmCelsius halves the workers processing images and HotCelsius leaves just one, well
+	// below the 80°C where the pi starts throttling itself
+	WarmCelsius = 65.0
+	HotCelsius  = 75.0
+
+	// busyLoad is the load average per core past which the workers are halved again
+	busyLoad = 1.5
+
+	// checkInterval is how often a paused worker checks whether it can carry on
+	checkInterval = 5 * time.Second
+)
+
+// zonePath is the kernel's reading of the CPU temperature in thousandths of a degree, and
+// loadPath its load averages
+var (
+	zonePath = "/sys/class/thermal/thermal_zone0/temp"
+	loadPath = "/proc/loadavg"
+)
+
+// Disable stops reading the host's temperature and load, such as when not running on the frame,
+// so processing is never held back
+func Disable() {
+	zonePath = ""
+	loadPath = ""
+}
+
+// Temperature returns the CPU temperature in degrees Celsius
+func Temperature() (float64, error) {
+	if zonePath == "" {
+		return 0, errors.New("temperature is not available")
+	}
+	content, err := os.ReadFile(zonePath)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read %s, %w", zonePath, err)
+	}
+	milli, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse %s, %w", zonePath, err)
+	}
+	return float64(milli) / 1000, nil
+}
+
+// Load returns the load average over the last minute per CPU core
+func Load() (float64, error) {
+	if loadPath == "" {
+		return 0, errors.New("load is not available")
+	}
+	content, err := os.ReadFile(loadPath)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read %s, %w", loadPath, err)
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("unable to parse %s", loadPath)
+	}
+	load, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse %s, %w", loadPath, err)
+	}
+	return load / float64(runtime.NumCPU()), nil
+}
+
+// Hot reports whether the CPU is hot enough that processing which can wait should wait
+func Hot() bool {
+	temp, err := Temperature()
+	return err == nil && temp >= HotCelsius
+}
+
+// Workers returns how many of limit workers should process images at the current temperature
+// and load, never fewer than one. Without readings all of them run.
+func Workers(limit int) int {
+	n := limit
+	if temp, err := Temperature(); err == nil {
+		switch {
+		case temp >= HotCelsius:
+			n = 1
+		case temp >= WarmCelsius:
+			n = limit / 2
+		}
+	}
+	if load, err := Load(); err == nil && load >= busyLoad {
+		n /= 2
+	}
+	return max(n, 1)
+}
+
+// Wait blocks the worker in slot, counting from 0, while fewer than slot+1 of limit workers
+// should run, returning the context's error if it's done first. A lone worker can't be cut back,
+// so it waits while the CPU is hot instead.
+func Wait(ctx context.Context, slot, limit int) error {
+	for slot >= Workers(limit) || (limit == 1 && Hot()) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(checkInterval):
+		}
+	}
+	return nil
+}
@@ -1,9 +1,35 @@
 // Package util is a set of utility variables or methods
 package util
 
-import mapset "github.com/deckarep/golang-set/v2"
+import (
+	"crypto/rand"
+	"encoding/base64"
+
+	mapset "github.com/deckarep/golang-set/v2"
+)
 
 var SupportedExt = mapset.NewSet(
 	".jpeg", ".jpg", ".JPEG", ".JPG",
 	".png", ".PNG",
 )
+
+// SidecarExt are the extensions of metadata files kept alongside photos
+var SidecarExt = mapset.NewSet(
+	".json", ".JSON",
+	".xmp", ".XMP",
+)
+
+// MotionExt are the extensions of the videos of live photos kept alongside their still
+var MotionExt = mapset.NewSet(
+	".mov", ".MOV",
+	".mp4", ".MP4",
+)
+
+// NewToken returns a random url safe token suitable for unguessable public links
+func NewToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}